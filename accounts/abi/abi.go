@@ -18,11 +18,13 @@ package abi
 
 import (
 	"bytes"
+	"cmp"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"math/big"
+	"slices"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -137,6 +139,63 @@ func (abi ABI) UnpackIntoMap(v map[string]interface{}, name string, data []byte)
 	return args.UnpackIntoMap(v, data)
 }
 
+// UnpackLogWithPreimages unpacks both the data and the topics of a log emitted by the
+// named event into a map. The topics include the event signature unless the event is
+// anonymous.
+//
+// Indexed parameters of dynamic type (string, bytes, arrays and slices) are stored in
+// the log as the keccak256 hash of their value. For string and bytes parameters, the
+// value is recovered from the given preimages when a matching entry exists. Parameters
+// whose value cannot be recovered are stored as UnresolvedTopic.
+func (abi ABI) UnpackLogWithPreimages(event string, topics []common.Hash, data []byte, preimages map[common.Hash][]byte) (map[string]interface{}, error) {
+	ev, ok := abi.Events[event]
+	if !ok {
+		return nil, fmt.Errorf("abi: could not locate named event: %s", event)
+	}
+	if !ev.Anonymous {
+		if len(topics) == 0 || topics[0] != ev.ID {
+			return nil, errors.New("abi: event signature mismatch")
+		}
+		topics = topics[1:]
+	}
+	out := make(map[string]interface{})
+	if len(data) > 0 {
+		if err := ev.Inputs.UnpackIntoMap(out, data); err != nil {
+			return nil, err
+		}
+	}
+	var indexed Arguments
+	for _, arg := range ev.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if err := ParseTopicsIntoMap(out, indexed, topics); err != nil {
+		return nil, err
+	}
+	// Substitute the known preimages of hashed parameters.
+	for _, arg := range indexed {
+		hash, ok := out[arg.Name].(common.Hash)
+		if !ok || !isHashedTopic(arg.Type) {
+			continue
+		}
+		preimage, ok := preimages[hash]
+		if !ok || (arg.Type.T != StringTy && arg.Type.T != BytesTy) {
+			out[arg.Name] = UnresolvedTopic{Hash: hash}
+			continue
+		}
+		if crypto.Keccak256Hash(preimage) != hash {
+			return nil, fmt.Errorf("abi: invalid preimage for indexed argument %q", arg.Name)
+		}
+		if arg.Type.T == StringTy {
+			out[arg.Name] = string(preimage)
+		} else {
+			out[arg.Name] = common.CopyBytes(preimage)
+		}
+	}
+	return out, nil
+}
+
 // UnmarshalJSON implements json.Unmarshaler interface.
 func (abi *ABI) UnmarshalJSON(data []byte) error {
 	var fields []struct {
@@ -201,6 +260,142 @@ func (abi *ABI) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// fieldJSON is the JSON representation of an ABI entry.
+type fieldJSON struct {
+	Type            string          `json:"type"`
+	Name            string          `json:"name,omitempty"`
+	Inputs          *[]argumentJSON `json:"inputs,omitempty"`
+	Outputs         *[]argumentJSON `json:"outputs,omitempty"`
+	StateMutability string          `json:"stateMutability,omitempty"`
+	Constant        bool            `json:"constant,omitempty"`
+	Payable         bool            `json:"payable,omitempty"`
+	Anonymous       *bool           `json:"anonymous,omitempty"`
+}
+
+// argumentJSON is the JSON representation of an argument.
+type argumentJSON struct {
+	Name         string         `json:"name"`
+	Type         string         `json:"type"`
+	InternalType string         `json:"internalType,omitempty"`
+	Components   []argumentJSON `json:"components,omitempty"`
+	Indexed      *bool          `json:"indexed,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler interface.
+//
+// The output is canonical: the constructor, fallback and receive functions come first,
+// followed by the functions, events and errors, each sorted by name. Overloaded
+// functions and events are ordered such that parsing the output yields the same names.
+// Solidity struct names are preserved in the internal type of tuples, other internal
+// types are not retained by the parser and thus not included.
+func (abi ABI) MarshalJSON() ([]byte, error) {
+	fields := make([]fieldJSON, 0, len(abi.Methods)+len(abi.Events)+len(abi.Errors)+3)
+	if abi.Constructor.String() != "" {
+		fields = append(fields, methodJSON("constructor", abi.Constructor))
+	}
+	if abi.HasFallback() {
+		fields = append(fields, methodJSON("fallback", abi.Fallback))
+	}
+	if abi.HasReceive() {
+		fields = append(fields, methodJSON("receive", abi.Receive))
+	}
+	methods := make([]Method, 0, len(abi.Methods))
+	for _, method := range abi.Methods {
+		methods = append(methods, method)
+	}
+	slices.SortFunc(methods, func(a, b Method) int { return compareOverloads(a.RawName, a.Name, b.RawName, b.Name) })
+	for _, method := range methods {
+		fields = append(fields, methodJSON("function", method))
+	}
+	events := make([]Event, 0, len(abi.Events))
+	for _, event := range abi.Events {
+		events = append(events, event)
+	}
+	slices.SortFunc(events, func(a, b Event) int { return compareOverloads(a.RawName, a.Name, b.RawName, b.Name) })
+	for _, event := range events {
+		inputs := argumentsJSON(event.Inputs, true)
+		anonymous := event.Anonymous
+		fields = append(fields, fieldJSON{Type: "event", Name: event.RawName, Inputs: &inputs, Anonymous: &anonymous})
+	}
+	for _, name := range sortedKeys(abi.Errors) {
+		inputs := argumentsJSON(abi.Errors[name].Inputs, false)
+		fields = append(fields, fieldJSON{Type: "error", Name: abi.Errors[name].Name, Inputs: &inputs})
+	}
+	return json.Marshal(fields)
+}
+
+// compareOverloads orders entries by their raw name. Overloads, which share the raw name,
+// are ordered by their resolved name's numeric suffix, i.e. "foo", "foo0", ..., "foo10".
+func compareOverloads(rawA, nameA, rawB, nameB string) int {
+	if c := cmp.Compare(rawA, rawB); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(len(nameA), len(nameB)); c != 0 {
+		return c
+	}
+	return cmp.Compare(nameA, nameB)
+}
+
+func methodJSON(typ string, method Method) fieldJSON {
+	field := fieldJSON{
+		Type:            typ,
+		StateMutability: method.StateMutability,
+	}
+	if method.StateMutability == "" {
+		// Legacy ABI without state mutability.
+		field.Constant, field.Payable = method.Constant, method.Payable
+	}
+	switch method.Type {
+	case Function:
+		inputs, outputs := argumentsJSON(method.Inputs, false), argumentsJSON(method.Outputs, false)
+		field.Name, field.Inputs, field.Outputs = method.RawName, &inputs, &outputs
+	case Constructor:
+		inputs := argumentsJSON(method.Inputs, false)
+		field.Inputs = &inputs
+	}
+	return field
+}
+
+func argumentsJSON(args Arguments, event bool) []argumentJSON {
+	out := make([]argumentJSON, len(args))
+	for i, arg := range args {
+		out[i] = typeJSON(arg.Name, arg.Type)
+		if event {
+			indexed := arg.Indexed
+			out[i].Indexed = &indexed
+		}
+	}
+	return out
+}
+
+// typeJSON reconstructs the JSON type description of t.
+func typeJSON(name string, t Type) argumentJSON {
+	switch t.T {
+	case SliceTy, ArrayTy:
+		arg := typeJSON(name, *t.Elem)
+		suffix := "[]"
+		if t.T == ArrayTy {
+			suffix = fmt.Sprintf("[%d]", t.Size)
+		}
+		arg.Type += suffix
+		if arg.InternalType != "" {
+			arg.InternalType += suffix
+		}
+		return arg
+	case TupleTy:
+		arg := argumentJSON{Name: name, Type: "tuple", Components: make([]argumentJSON, len(t.TupleElems))}
+		for i, elem := range t.TupleElems {
+			arg.Components[i] = typeJSON(t.TupleRawNames[i], *elem)
+		}
+		if t.TupleRawName != "" {
+			arg.InternalType = "struct " + t.TupleRawName
+		}
+		return arg
+	default:
+		return argumentJSON{Name: name, Type: t.stringKind}
+	}
+}
+
 // MethodById looks up a method by the 4-byte id,
 // returns nil if none found.
 func (abi *ABI) MethodById(sigdata []byte) (*Method, error) {
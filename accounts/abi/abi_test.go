@@ -19,6 +19,7 @@ package abi
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
@@ -822,6 +823,65 @@ func TestUnpackEvent(t *testing.T) {
 	}
 }
 
+func TestUnpackLogWithPreimages(t *testing.T) {
+	t.Parallel()
+	const abiJSON = `[{"anonymous":false,"inputs":[{"indexed":true,"name":"name","type":"string"},{"indexed":true,"name":"blob","type":"bytes"},{"indexed":true,"name":"ids","type":"uint256[]"},{"indexed":false,"name":"value","type":"uint256"}],"name":"named","type":"event"}]`
+	abi, err := JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		name     = "vitalik.eth"
+		blob     = []byte{0xde, 0xad, 0xbe, 0xef}
+		nameHash = crypto.Keccak256Hash([]byte(name))
+		blobHash = crypto.Keccak256Hash(blob)
+		idsHash  = common.HexToHash("0x01")
+	)
+	data, err := abi.Events["named"].Inputs.NonIndexed().Pack(big.NewInt(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	topics := []common.Hash{abi.Events["named"].ID, nameHash, blobHash, idsHash}
+
+	// Without preimages, all hashed parameters are unresolved.
+	out, err := abi.UnpackLogWithPreimages("named", topics, data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"name":  UnresolvedTopic{Hash: nameHash},
+		"blob":  UnresolvedTopic{Hash: blobHash},
+		"ids":   UnresolvedTopic{Hash: idsHash},
+		"value": big.NewInt(42),
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("wrong output without preimages:\nhave %v\nwant %v", out, want)
+	}
+
+	// With preimages, string and bytes values are recovered.
+	preimages := map[common.Hash][]byte{nameHash: []byte(name), blobHash: blob}
+	out, err = abi.UnpackLogWithPreimages("named", topics, data, preimages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want["name"] = name
+	want["blob"] = blob
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("wrong output with preimages:\nhave %v\nwant %v", out, want)
+	}
+
+	// Preimages which don't match the hash are rejected.
+	preimages[nameHash] = []byte("bad")
+	if _, err := abi.UnpackLogWithPreimages("named", topics, data, preimages); err == nil {
+		t.Error("expected error for invalid preimage")
+	}
+	// Logs of other events are rejected.
+	topics[0] = common.Hash{}
+	if _, err := abi.UnpackLogWithPreimages("named", topics, data, nil); err == nil {
+		t.Error("expected error for event signature mismatch")
+	}
+}
+
 func TestUnpackEventIntoMap(t *testing.T) {
 	t.Parallel()
 	const abiJSON = `[{"constant":false,"inputs":[{"name":"memo","type":"bytes"}],"name":"receive","outputs":[],"payable":true,"stateMutability":"payable","type":"function"},{"anonymous":false,"inputs":[{"indexed":false,"name":"sender","type":"address"},{"indexed":false,"name":"amount","type":"uint256"},{"indexed":false,"name":"memo","type":"bytes"}],"name":"received","type":"event"},{"anonymous":false,"inputs":[{"indexed":false,"name":"sender","type":"address"}],"name":"receivedAddr","type":"event"}]`
@@ -1257,3 +1317,51 @@ func TestInternalContractType(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestABIMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	const def = `[
+		{"type":"error","name":"Unauthorized","inputs":[{"name":"caller","type":"address"}]},
+		{"type":"event","name":"Transfer","anonymous":true,"inputs":[
+			{"name":"from","type":"address","indexed":true},
+			{"name":"value","type":"uint256","indexed":false}]},
+		{"type":"event","name":"Transfer","inputs":[{"name":"id","type":"bytes32","indexed":true}]},
+		{"type":"function","name":"submit","stateMutability":"payable","inputs":[
+			{"name":"orders","type":"tuple[2][]","internalType":"struct Exchange.Order[2][]","components":[
+				{"name":"maker","type":"address"},
+				{"name":"amounts","type":"uint256[]"},
+				{"name":"meta","type":"tuple","internalType":"struct Meta","components":[{"name":"tag","type":"bytes4"}]}]}],
+			"outputs":[{"name":"","type":"bool"}]},
+		{"type":"function","name":"get","stateMutability":"view","inputs":[{"name":"a","type":"uint8"}],"outputs":[]},
+		{"type":"function","name":"get","constant":true,"inputs":[],"outputs":[{"name":"v","type":"int64"}]},
+		{"type":"receive","stateMutability":"payable"},
+		{"type":"fallback","stateMutability":"nonpayable"},
+		{"type":"constructor","stateMutability":"nonpayable","inputs":[{"name":"owner","type":"address"}]}
+	]`
+	parsed, err := JSON(strings.NewReader(def))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reparsed, err := JSON(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("can't parse marshaled ABI: %v\n%s", err, out)
+	}
+	if !reflect.DeepEqual(parsed, reparsed) {
+		t.Fatalf("ABI changed after round trip:\n%s", out)
+	}
+	out2, err := json.Marshal(reparsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, out2) {
+		t.Fatalf("marshaling not idempotent:\n%s\n%s", out, out2)
+	}
+	if reparsed.Methods["submit"].Inputs[0].Type.Elem.Elem.TupleRawName != "ExchangeOrder" {
+		t.Error("struct name lost")
+	}
+}
@@ -225,6 +225,13 @@ func (arguments Arguments) Pack(args ...interface{}) ([]byte, error) {
 	if len(args) != len(abiArgs) {
 		return nil, fmt.Errorf("argument count mismatch: got %d for %d", len(args), len(abiArgs))
 	}
+	// Check the kinds of all arguments up front, so that mismatches are reported
+	// with the name of the offending parameter.
+	for i, a := range args {
+		if err := argumentCheck(abiArgs[i], i, a); err != nil {
+			return nil, err
+		}
+	}
 	// variable input is the output appended at the end of packed
 	// output. This is used for strings and bytes types input.
 	var variableInput []byte
@@ -240,6 +247,12 @@ func (arguments Arguments) Pack(args ...interface{}) ([]byte, error) {
 		// pack the input
 		packed, err := input.Type.pack(reflect.ValueOf(a))
 		if err != nil {
+			if pe, ok := err.(*packPathError); ok {
+				pe.arg = input.Name
+				if pe.arg == "" {
+					pe.arg = fmt.Sprintf("#%d", i)
+				}
+			}
 			return nil, err
 		}
 		// check for dynamic types
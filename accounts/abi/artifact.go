@@ -0,0 +1,167 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// artifactContract is the contract-level object of compiler outputs. It covers solc
+// standard JSON (evm.bytecode.object), solc --combined-json (bin) and the artifacts
+// of Hardhat (bytecode as string) and Foundry (bytecode.object).
+type artifactContract struct {
+	ABI      json.RawMessage `json:"abi"`
+	Bin      string          `json:"bin"`
+	Bytecode json.RawMessage `json:"bytecode"`
+	EVM      struct {
+		Bytecode struct {
+			Object string `json:"object"`
+		} `json:"bytecode"`
+	} `json:"evm"`
+}
+
+// FromStandardJSON reads the ABI and deployment bytecode of a contract from compiler
+// output. The artifact can either be the object of a single contract, as found in
+// Hardhat and Foundry artifacts, or a whole solc output (standard JSON or
+// --combined-json) containing exactly one contract.
+//
+// The returned bytecode is nil if the artifact has none, e.g. for interfaces.
+// Bytecode with unlinked library references is rejected.
+func FromStandardJSON(artifact []byte) (ABI, []byte, error) {
+	var top struct {
+		artifactContract
+		Contracts map[string]json.RawMessage `json:"contracts"`
+	}
+	if err := json.Unmarshal(artifact, &top); err != nil {
+		return ABI{}, nil, fmt.Errorf("abi: invalid artifact: %v", err)
+	}
+	contract := &top.artifactContract
+	if top.ABI == nil {
+		if top.Contracts == nil {
+			return ABI{}, nil, errors.New("abi: artifact has no 'abi' or 'contracts' key")
+		}
+		contracts, err := collectArtifactContracts(top.Contracts)
+		if err != nil {
+			return ABI{}, nil, err
+		}
+		if len(contracts) != 1 {
+			names := make([]string, 0, len(contracts))
+			for name := range contracts {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return ABI{}, nil, fmt.Errorf("abi: artifact contains %d contracts, need exactly one: %s", len(contracts), strings.Join(names, ", "))
+		}
+		for _, c := range contracts {
+			contract = c
+		}
+	}
+	abi, err := contract.parseABI()
+	if err != nil {
+		return ABI{}, nil, err
+	}
+	code, err := contract.bytecode()
+	if err != nil {
+		return ABI{}, nil, err
+	}
+	return abi, code, nil
+}
+
+// collectArtifactContracts gathers the contracts of a solc output. In combined-json
+// output, contracts are keyed by "path:Name". In standard JSON output, they are
+// grouped by source file first.
+func collectArtifactContracts(entries map[string]json.RawMessage) (map[string]*artifactContract, error) {
+	contracts := make(map[string]*artifactContract)
+	for key, raw := range entries {
+		var c artifactContract
+		if err := json.Unmarshal(raw, &c); err != nil {
+			return nil, fmt.Errorf("abi: invalid contract %s: %v", key, err)
+		}
+		if c.ABI != nil {
+			contracts[key] = &c
+			continue
+		}
+		var file map[string]*artifactContract
+		if err := json.Unmarshal(raw, &file); err != nil {
+			return nil, fmt.Errorf("abi: invalid contracts of %s: %v", key, err)
+		}
+		for name, c := range file {
+			if c == nil || c.ABI == nil {
+				return nil, fmt.Errorf("abi: contract %s:%s has no 'abi' key", key, name)
+			}
+			contracts[key+":"+name] = c
+		}
+	}
+	return contracts, nil
+}
+
+// parseABI decodes the ABI of the contract. Older versions of solc encode the ABI as
+// a JSON string in --combined-json output.
+func (c *artifactContract) parseABI() (ABI, error) {
+	def := []byte(c.ABI)
+	if bytes.HasPrefix(bytes.TrimSpace(def), []byte(`"`)) {
+		var s string
+		if err := json.Unmarshal(def, &s); err != nil {
+			return ABI{}, fmt.Errorf("abi: invalid ABI string: %v", err)
+		}
+		def = []byte(s)
+	}
+	abi, err := JSON(bytes.NewReader(def))
+	if err != nil {
+		return ABI{}, fmt.Errorf("abi: invalid ABI definition: %v", err)
+	}
+	return abi, nil
+}
+
+// bytecode returns the deployment bytecode of the contract.
+func (c *artifactContract) bytecode() ([]byte, error) {
+	code := c.EVM.Bytecode.Object
+	if code == "" {
+		code = c.Bin
+	}
+	if code == "" && len(c.Bytecode) > 0 {
+		var s string
+		if err := json.Unmarshal(c.Bytecode, &s); err != nil {
+			var obj struct {
+				Object string `json:"object"`
+			}
+			if err := json.Unmarshal(c.Bytecode, &obj); err != nil {
+				return nil, fmt.Errorf("abi: invalid bytecode: %v", err)
+			}
+			s = obj.Object
+		}
+		code = s
+	}
+	code = strings.TrimPrefix(code, "0x")
+	if code == "" {
+		return nil, nil
+	}
+	if strings.Contains(code, "__") {
+		return nil, errors.New("abi: bytecode contains unlinked library references")
+	}
+	dec, err := hex.DecodeString(code)
+	if err != nil {
+		return nil, fmt.Errorf("abi: invalid bytecode: %v", err)
+	}
+	return dec, nil
+}
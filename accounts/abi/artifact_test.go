@@ -0,0 +1,74 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+)
+
+const artifactABI = `[{"type":"function","name":"get","inputs":[],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"}]`
+
+func TestFromStandardJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		artifact string
+		code     []byte
+	}{
+		{"hardhat", `{"contractName":"C","abi":` + artifactABI + `,"bytecode":"0x6080"}`, []byte{0x60, 0x80}},
+		{"foundry", `{"abi":` + artifactABI + `,"bytecode":{"object":"0x6080","linkReferences":{}}}`, []byte{0x60, 0x80}},
+		{"solc contract", `{"abi":` + artifactABI + `,"evm":{"bytecode":{"object":"6080"}}}`, []byte{0x60, 0x80}},
+		{"interface", `{"abi":` + artifactABI + `,"evm":{"bytecode":{"object":""}}}`, nil},
+		{"standard json", `{"contracts":{"c.sol":{"C":{"abi":` + artifactABI + `,"evm":{"bytecode":{"object":"6080"}}}}},"sources":{}}`, []byte{0x60, 0x80}},
+		{"combined json", `{"contracts":{"c.sol:C":{"abi":` + artifactABI + `,"bin":"6080"}},"version":"0.8.28"}`, []byte{0x60, 0x80}},
+		{"combined json string abi", `{"contracts":{"c.sol:C":{"abi":` + strconv.Quote(artifactABI) + `,"bin":"6080"}}}`, []byte{0x60, 0x80}},
+	}
+	for _, test := range tests {
+		abi, code, err := FromStandardJSON([]byte(test.artifact))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if _, ok := abi.Methods["get"]; !ok {
+			t.Errorf("%s: method missing from ABI", test.name)
+		}
+		if !bytes.Equal(code, test.code) {
+			t.Errorf("%s: wrong bytecode %x, want %x", test.name, code, test.code)
+		}
+	}
+}
+
+func TestFromStandardJSONErrors(t *testing.T) {
+	tests := []struct {
+		artifact string
+		err      string
+	}{
+		{`{"bytecode":"0x6080"}`, "abi: artifact has no 'abi' or 'contracts' key"},
+		{`{"contracts":{"c.sol":{"C":{"evm":{}}}}}`, "abi: contract c.sol:C has no 'abi' key"},
+		{`{"contracts":{"c.sol:A":{"abi":[]},"c.sol:B":{"abi":[]}}}`, "abi: artifact contains 2 contracts, need exactly one: c.sol:A, c.sol:B"},
+		{`{"abi":[],"bytecode":"0x60__$lib$__"}`, "abi: bytecode contains unlinked library references"},
+		{`{"abi":[],"bytecode":"0xzz"}`, "abi: invalid bytecode: encoding/hex: invalid byte: U+007A 'z'"},
+		{`{`, "abi: invalid artifact: unexpected end of JSON input"},
+	}
+	for _, test := range tests {
+		_, _, err := FromStandardJSON([]byte(test.artifact))
+		if err == nil || err.Error() != test.err {
+			t.Errorf("artifact %s: wrong error %v, want %q", test.artifact, err, test.err)
+		}
+	}
+}
@@ -54,6 +54,13 @@ type CallOpts struct {
 
 // TransactOpts is the collection of authorization data required to create a
 // valid Ethereum transaction.
+//
+// Transactions are created as EIP-1559 dynamic fee transactions if the chain has a
+// base fee, with missing fee caps filled in from the backend. Setting GasPrice forces
+// a legacy transaction. See FillGasFees for filling in the fee caps ahead of time.
+//
+// Context bounds all backend requests made while creating and sending the transaction.
+// The transaction is not sent if the context is done by the time it has been signed.
 type TransactOpts struct {
 	From   common.Address // Ethereum account to send the transaction from
 	Nonce  *big.Int       // Nonce to use for the transaction execution (nil = use pending state)
@@ -260,43 +267,27 @@ func (c *BoundContract) Transfer(opts *TransactOpts) (*types.Transaction, error)
 	return c.transact(opts, &c.address, nil)
 }
 
-func (c *BoundContract) createDynamicTx(opts *TransactOpts, contract *common.Address, input []byte, head *types.Header) (*types.Transaction, error) {
+func (c *BoundContract) createDynamicTx(ctx context.Context, opts *TransactOpts, contract *common.Address, input []byte, head *types.Header) (*types.Transaction, error) {
 	// Normalize value
 	value := opts.Value
 	if value == nil {
 		value = new(big.Int)
 	}
-	// Estimate TipCap
-	gasTipCap := opts.GasTipCap
-	if gasTipCap == nil {
-		tip, err := c.transactor.SuggestGasTipCap(ensureContext(opts.Context))
-		if err != nil {
-			return nil, err
-		}
-		gasTipCap = tip
-	}
-	// Estimate FeeCap
-	gasFeeCap := opts.GasFeeCap
-	if gasFeeCap == nil {
-		gasFeeCap = new(big.Int).Add(
-			gasTipCap,
-			new(big.Int).Mul(head.BaseFee, big.NewInt(basefeeWiggleMultiplier)),
-		)
-	}
-	if gasFeeCap.Cmp(gasTipCap) < 0 {
-		return nil, fmt.Errorf("maxFeePerGas (%v) < maxPriorityFeePerGas (%v)", gasFeeCap, gasTipCap)
+	// Estimate TipCap and FeeCap
+	gasTipCap, gasFeeCap, err := dynamicFees(ctx, c.transactor, opts.GasTipCap, opts.GasFeeCap, head)
+	if err != nil {
+		return nil, err
 	}
 	// Estimate GasLimit
 	gasLimit := opts.GasLimit
 	if opts.GasLimit == 0 {
-		var err error
-		gasLimit, err = c.estimateGasLimit(opts, contract, input, nil, gasTipCap, gasFeeCap, value)
+		gasLimit, err = c.estimateGasLimit(ctx, opts, contract, input, nil, gasTipCap, gasFeeCap, value)
 		if err != nil {
 			return nil, err
 		}
 	}
 	// create the transaction
-	nonce, err := c.getNonce(opts)
+	nonce, err := c.getNonce(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -313,7 +304,60 @@ func (c *BoundContract) createDynamicTx(opts *TransactOpts, contract *common.Add
 	return types.NewTx(baseTx), nil
 }
 
-func (c *BoundContract) createLegacyTx(opts *TransactOpts, contract *common.Address, input []byte) (*types.Transaction, error) {
+// dynamicFees returns the tip and fee caps of a dynamic fee transaction. A missing tip
+// cap is taken from the backend's suggestion, a missing fee cap is derived from the tip
+// cap and the base fee of head.
+func dynamicFees(ctx context.Context, transactor ContractTransactor, gasTipCap, gasFeeCap *big.Int, head *types.Header) (*big.Int, *big.Int, error) {
+	if gasTipCap == nil {
+		tip, err := transactor.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		gasTipCap = tip
+	}
+	if gasFeeCap == nil {
+		gasFeeCap = new(big.Int).Add(
+			gasTipCap,
+			new(big.Int).Mul(head.BaseFee, big.NewInt(basefeeWiggleMultiplier)),
+		)
+	}
+	if gasFeeCap.Cmp(gasTipCap) < 0 {
+		return nil, nil, fmt.Errorf("maxFeePerGas (%v) < maxPriorityFeePerGas (%v)", gasFeeCap, gasTipCap)
+	}
+	return gasTipCap, gasFeeCap, nil
+}
+
+// FillGasFees sets the missing GasTipCap and GasFeeCap fields of opts the same way
+// Transact does: the tip cap is suggested by the backend and the fee cap allows for
+// the base fee of the latest block to double. This is useful to fix the fees of
+// multiple transactions, or to inspect them before sending.
+//
+// If opts.GasPrice is set, a legacy transaction was requested and opts is left
+// unchanged. An error is returned if the chain doesn't support dynamic fees yet.
+func FillGasFees(opts *TransactOpts, transactor ContractTransactor) error {
+	if opts.GasPrice != nil || (opts.GasTipCap != nil && opts.GasFeeCap != nil) {
+		return nil
+	}
+	ctx := ensureContext(opts.Context)
+	var head *types.Header
+	if opts.GasFeeCap == nil {
+		var err error
+		if head, err = transactor.HeaderByNumber(ctx, nil); err != nil {
+			return err
+		}
+		if head.BaseFee == nil {
+			return errors.New("london is not active yet")
+		}
+	}
+	gasTipCap, gasFeeCap, err := dynamicFees(ctx, transactor, opts.GasTipCap, opts.GasFeeCap, head)
+	if err != nil {
+		return err
+	}
+	opts.GasTipCap, opts.GasFeeCap = gasTipCap, gasFeeCap
+	return nil
+}
+
+func (c *BoundContract) createLegacyTx(ctx context.Context, opts *TransactOpts, contract *common.Address, input []byte) (*types.Transaction, error) {
 	if opts.GasFeeCap != nil || opts.GasTipCap != nil || opts.AccessList != nil {
 		return nil, errors.New("maxFeePerGas or maxPriorityFeePerGas or accessList specified but london is not active yet")
 	}
@@ -325,7 +369,7 @@ func (c *BoundContract) createLegacyTx(opts *TransactOpts, contract *common.Addr
 	// Estimate GasPrice
 	gasPrice := opts.GasPrice
 	if gasPrice == nil {
-		price, err := c.transactor.SuggestGasPrice(ensureContext(opts.Context))
+		price, err := c.transactor.SuggestGasPrice(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -335,13 +379,13 @@ func (c *BoundContract) createLegacyTx(opts *TransactOpts, contract *common.Addr
 	gasLimit := opts.GasLimit
 	if opts.GasLimit == 0 {
 		var err error
-		gasLimit, err = c.estimateGasLimit(opts, contract, input, gasPrice, nil, nil, value)
+		gasLimit, err = c.estimateGasLimit(ctx, opts, contract, input, gasPrice, nil, nil, value)
 		if err != nil {
 			return nil, err
 		}
 	}
 	// create the transaction
-	nonce, err := c.getNonce(opts)
+	nonce, err := c.getNonce(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -356,10 +400,10 @@ func (c *BoundContract) createLegacyTx(opts *TransactOpts, contract *common.Addr
 	return types.NewTx(baseTx), nil
 }
 
-func (c *BoundContract) estimateGasLimit(opts *TransactOpts, contract *common.Address, input []byte, gasPrice, gasTipCap, gasFeeCap, value *big.Int) (uint64, error) {
+func (c *BoundContract) estimateGasLimit(ctx context.Context, opts *TransactOpts, contract *common.Address, input []byte, gasPrice, gasTipCap, gasFeeCap, value *big.Int) (uint64, error) {
 	if contract != nil {
 		// Gas estimation cannot succeed without code for method invocations.
-		if code, err := c.transactor.PendingCodeAt(ensureContext(opts.Context), c.address); err != nil {
+		if code, err := c.transactor.PendingCodeAt(ctx, c.address); err != nil {
 			return 0, err
 		} else if len(code) == 0 {
 			return 0, ErrNoCode
@@ -374,41 +418,43 @@ func (c *BoundContract) estimateGasLimit(opts *TransactOpts, contract *common.Ad
 		Value:     value,
 		Data:      input,
 	}
-	return c.transactor.EstimateGas(ensureContext(opts.Context), msg)
+	return c.transactor.EstimateGas(ctx, msg)
 }
 
-func (c *BoundContract) getNonce(opts *TransactOpts) (uint64, error) {
+func (c *BoundContract) getNonce(ctx context.Context, opts *TransactOpts) (uint64, error) {
 	if opts.Nonce == nil {
-		return c.transactor.PendingNonceAt(ensureContext(opts.Context), opts.From)
+		return c.transactor.PendingNonceAt(ctx, opts.From)
 	} else {
 		return opts.Nonce.Uint64(), nil
 	}
 }
 
 // transact executes an actual transaction invocation, first deriving any missing
-// authorization fields, and then scheduling the transaction for execution.
+// authorization fields, and then scheduling the transaction for execution. All
+// backend requests are made with the context of opts.
 func (c *BoundContract) transact(opts *TransactOpts, contract *common.Address, input []byte) (*types.Transaction, error) {
 	if opts.GasPrice != nil && (opts.GasFeeCap != nil || opts.GasTipCap != nil) {
 		return nil, errors.New("both gasPrice and (maxFeePerGas or maxPriorityFeePerGas) specified")
 	}
 	// Create the transaction
 	var (
+		ctx   = ensureContext(opts.Context)
 		rawTx *types.Transaction
 		err   error
 	)
 	if opts.GasPrice != nil {
-		rawTx, err = c.createLegacyTx(opts, contract, input)
+		rawTx, err = c.createLegacyTx(ctx, opts, contract, input)
 	} else if opts.GasFeeCap != nil && opts.GasTipCap != nil {
-		rawTx, err = c.createDynamicTx(opts, contract, input, nil)
+		rawTx, err = c.createDynamicTx(ctx, opts, contract, input, nil)
 	} else {
 		// Only query for basefee if gasPrice not specified
-		if head, errHead := c.transactor.HeaderByNumber(ensureContext(opts.Context), nil); errHead != nil {
+		if head, errHead := c.transactor.HeaderByNumber(ctx, nil); errHead != nil {
 			return nil, errHead
 		} else if head.BaseFee != nil {
-			rawTx, err = c.createDynamicTx(opts, contract, input, head)
+			rawTx, err = c.createDynamicTx(ctx, opts, contract, input, head)
 		} else {
 			// Chain is not London ready -> use legacy transaction
-			rawTx, err = c.createLegacyTx(opts, contract, input)
+			rawTx, err = c.createLegacyTx(ctx, opts, contract, input)
 		}
 	}
 	if err != nil {
@@ -425,7 +471,12 @@ func (c *BoundContract) transact(opts *TransactOpts, contract *common.Address, i
 	if opts.NoSend {
 		return signedTx, nil
 	}
-	if err := c.transactor.SendTransaction(ensureContext(opts.Context), signedTx); err != nil {
+	// Signing may take a while, e.g. with an external signer. Don't publish the
+	// transaction if the caller gave up in the meantime.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := c.transactor.SendTransaction(ctx, signedTx); err != nil {
 		return nil, err
 	}
 	return signedTx, nil
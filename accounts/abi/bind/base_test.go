@@ -23,6 +23,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -374,6 +375,109 @@ func TestTransactGasFee(t *testing.T) {
 	assert.True(mt.suggestGasPriceCalled)
 }
 
+func TestFillGasFees(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	// Missing fee caps are filled in.
+	mt := &mockTransactor{baseFee: big.NewInt(100), gasTipCap: big.NewInt(5)}
+	opts := &bind.TransactOpts{Signer: mockSign}
+	assert.Nil(bind.FillGasFees(opts, mt))
+	assert.Equal(big.NewInt(5), opts.GasTipCap)
+	assert.Equal(big.NewInt(205), opts.GasFeeCap)
+
+	// Transact uses the filled in fee caps.
+	mt.gasTipCap = big.NewInt(6)
+	mt.suggestGasTipCapCalled = false
+	bc := bind.NewBoundContract(common.Address{}, abi.ABI{}, nil, mt, nil)
+	tx, err := bc.Transact(opts, "")
+	assert.Nil(err)
+	assert.Equal(types.DynamicFeeTxType, int(tx.Type()))
+	assert.Equal(big.NewInt(5), tx.GasTipCap())
+	assert.Equal(big.NewInt(205), tx.GasFeeCap())
+	assert.False(mt.suggestGasTipCapCalled)
+
+	// An explicit tip cap is kept.
+	opts = &bind.TransactOpts{Signer: mockSign, GasTipCap: big.NewInt(1)}
+	assert.Nil(bind.FillGasFees(opts, mt))
+	assert.Equal(big.NewInt(1), opts.GasTipCap)
+	assert.Equal(big.NewInt(201), opts.GasFeeCap)
+
+	// A fee cap below the tip cap is rejected.
+	opts = &bind.TransactOpts{Signer: mockSign, GasFeeCap: big.NewInt(1)}
+	assert.NotNil(bind.FillGasFees(opts, mt))
+
+	// A gas price forces a legacy transaction.
+	opts = &bind.TransactOpts{Signer: mockSign, GasPrice: big.NewInt(10)}
+	assert.Nil(bind.FillGasFees(opts, mt))
+	assert.Nil(opts.GasTipCap)
+	assert.Nil(opts.GasFeeCap)
+	tx, err = bc.Transact(opts, "")
+	assert.Nil(err)
+	assert.Equal(types.LegacyTxType, int(tx.Type()))
+
+	// Chains without base fee don't support dynamic fees.
+	mt = &mockTransactor{gasTipCap: big.NewInt(5)}
+	opts = &bind.TransactOpts{Signer: mockSign}
+	assert.NotNil(bind.FillGasFees(opts, mt))
+	assert.Nil(opts.GasTipCap)
+}
+
+// hangingBackend simulates a node which never answers calls and gas estimations.
+type hangingBackend struct {
+	mockTransactor
+	mockCaller
+	sent bool
+}
+
+func (b *hangingBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (b *hangingBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	<-ctx.Done()
+	return 0, ctx.Err()
+}
+
+func (b *hangingBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.sent = true
+	return nil
+}
+
+func TestContextDeadline(t *testing.T) {
+	t.Parallel()
+
+	backend := &hangingBackend{mockTransactor: mockTransactor{baseFee: big.NewInt(100), gasTipCap: big.NewInt(5)}}
+	bc := bind.NewBoundContract(common.Address{}, abi.ABI{}, backend, backend, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := bc.Call(&bind.CallOpts{Context: ctx}, nil, ""); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("wrong call error: %v", err)
+	}
+	if _, err := bc.Transact(&bind.TransactOpts{Context: ctx, Signer: mockSign}, ""); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("wrong transact error: %v", err)
+	}
+
+	// The transaction isn't sent if the context is canceled while signing.
+	ctx, cancel = context.WithCancel(context.Background())
+	opts := &bind.TransactOpts{
+		Context:  ctx,
+		GasLimit: 21000,
+		Signer: func(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			cancel()
+			return tx, nil
+		},
+	}
+	if _, err := bc.Transact(opts, ""); !errors.Is(err, context.Canceled) {
+		t.Fatalf("wrong transact error: %v", err)
+	}
+	if backend.sent {
+		t.Fatal("transaction sent after cancellation")
+	}
+}
+
 func unpackAndCheck(t *testing.T, bc *bind.BoundContract, expected map[string]interface{}, mockLog types.Log) {
 	received := make(map[string]interface{})
 	if err := bc.UnpackLogIntoMap(received, "received", mockLog); err != nil {
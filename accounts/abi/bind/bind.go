@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"go/format"
 	"regexp"
+	"slices"
 	"strings"
 	"text/template"
 	"unicode"
@@ -77,11 +78,25 @@ func isKeyWord(arg string) bool {
 	return true
 }
 
+// BindOptions contains optional code generation settings.
+type BindOptions struct {
+	// Multicall enables the generation of Aggregate* helpers for all read-only
+	// methods, which create calls that can be executed in a single request via
+	// AggregateCalls.
+	Multicall bool
+}
+
 // Bind generates a Go wrapper around a contract ABI. This wrapper isn't meant
 // to be used as is in client code, but rather as an intermediate struct which
 // enforces compile time type safety and naming convention as opposed to having to
 // manually maintain hard coded strings that break on runtime.
 func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]string, pkg string, lang Lang, libs map[string]string, aliases map[string]string) (string, error) {
+	return BindWithOptions(types, abis, bytecodes, fsigs, pkg, lang, libs, aliases, BindOptions{})
+}
+
+// BindWithOptions generates a Go wrapper around a contract ABI like Bind, using the
+// given code generation options.
+func BindWithOptions(types []string, abis []string, bytecodes []string, fsigs []map[string]string, pkg string, lang Lang, libs map[string]string, aliases map[string]string, opts BindOptions) (string, error) {
 	var (
 		// contracts is the map of each individual contract requested binding
 		contracts = make(map[string]*tmplContract)
@@ -111,16 +126,18 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 			calls     = make(map[string]*tmplMethod)
 			transacts = make(map[string]*tmplMethod)
 			events    = make(map[string]*tmplEvent)
+			errs      = make(map[string]*tmplError)
 			fallback  *tmplMethod
 			receive   *tmplMethod
 
-			// identifiers are used to detect duplicated identifiers of functions
-			// and events. For all calls, transacts and events, abigen will generate
-			// corresponding bindings. However we have to ensure there is no
-			// identifier collisions in the bindings of these categories.
+			// identifiers are used to detect duplicated identifiers of functions,
+			// events and errors. For all calls, transacts, events and errors, abigen
+			// will generate corresponding bindings. However we have to ensure there
+			// is no identifier collisions in the bindings of these categories.
 			callIdentifiers     = make(map[string]bool)
 			transactIdentifiers = make(map[string]bool)
 			eventIdentifiers    = make(map[string]bool)
+			errorIdentifiers    = make(map[string]bool)
 		)
 
 		for _, input := range evmABI.Constructor.Inputs {
@@ -150,6 +167,13 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 				return "", fmt.Errorf("duplicated identifier \"%s\"(normalized \"%s\"), use --alias for renaming", original.Name, normalizedName)
 			}
 			identifiers[normalizedName] = true
+			if opts.Multicall && original.IsConstant() {
+				// The aggregate call helpers share the namespace of the call bindings.
+				if identifiers["Aggregate"+normalizedName] {
+					return "", fmt.Errorf("duplicated identifier \"%s\"(normalized \"Aggregate%s\"), use --alias for renaming", original.Name, normalizedName)
+				}
+				identifiers["Aggregate"+normalizedName] = true
+			}
 
 			normalized.Name = normalizedName
 			normalized.Inputs = make([]abi.Argument, len(original.Inputs))
@@ -226,6 +250,40 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 			// Append the event to the accumulator list
 			events[original.Name] = &tmplEvent{Original: original, Normalized: normalized}
 		}
+		for _, original := range evmABI.Errors {
+			// Normalize the error for capital cases and non-anonymous fields
+			normalized := original
+
+			// Ensure there is no duplicated identifier
+			normalizedName := methodNormalizer[lang](alias(aliases, original.Name))
+			if errorIdentifiers[normalizedName] {
+				return "", fmt.Errorf("duplicated identifier \"%s\"(normalized \"%s\"), use --alias for renaming", original.Name, normalizedName)
+			}
+			errorIdentifiers[normalizedName] = true
+			normalized.Name = normalizedName
+
+			used := make(map[string]bool)
+			normalized.Inputs = make([]abi.Argument, len(original.Inputs))
+			copy(normalized.Inputs, original.Inputs)
+			for j, input := range normalized.Inputs {
+				if input.Name == "" || isKeyWord(input.Name) {
+					normalized.Inputs[j].Name = fmt.Sprintf("arg%d", j)
+				}
+				// The error is bound to a struct, ensure there is
+				// no camel-case-style name conflict of its fields.
+				for index := 0; ; index++ {
+					if !used[capitalise(normalized.Inputs[j].Name)] {
+						used[capitalise(normalized.Inputs[j].Name)] = true
+						break
+					}
+					normalized.Inputs[j].Name = fmt.Sprintf("%s%d", normalized.Inputs[j].Name, index)
+				}
+				if hasStruct(input.Type) {
+					bindStructType[lang](input.Type, structs)
+				}
+			}
+			errs[original.Name] = &tmplError{Original: original, Normalized: normalized}
+		}
 		// Add two special fallback functions if they exist
 		if evmABI.HasFallback() {
 			fallback = &tmplMethod{Original: evmABI.Fallback}
@@ -243,6 +301,7 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 			Fallback:    fallback,
 			Receive:     receive,
 			Events:      events,
+			Errors:      errs,
 			Libraries:   make(map[string]string),
 		}
 		// Function 4-byte signatures are stored in the same sequence
@@ -270,12 +329,14 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 		_, ok := isLib[types[i]]
 		contracts[types[i]].Library = ok
 	}
+	resolveErrorTypeNames(types, contracts, structs)
 	// Generate the contract template data content and render it
 	data := &tmplData{
 		Package:   pkg,
 		Contracts: contracts,
 		Libraries: libs,
 		Structs:   structs,
+		Multicall: opts.Multicall,
 	}
 	buffer := new(bytes.Buffer)
 
@@ -302,6 +363,37 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 	return buffer.String(), nil
 }
 
+// resolveErrorTypeNames ensures that the types generated for custom errors, which are
+// named <contract><error>Error, don't collide with the types generated for events or
+// structs, or with each other. Conflicting errors are renamed by adding a number.
+func resolveErrorTypeNames(types []string, contracts map[string]*tmplContract, structs map[string]*tmplStruct) {
+	taken := make(map[string]bool)
+	for _, s := range structs {
+		taken[s.Name] = true
+	}
+	for _, contract := range contracts {
+		for _, ev := range contract.Events {
+			taken[contract.Type+ev.Normalized.Name] = true
+			taken[contract.Type+ev.Normalized.Name+"Iterator"] = true
+		}
+	}
+	for _, typ := range types {
+		contract := contracts[typ]
+		names := make([]string, 0, len(contract.Errors))
+		for name := range contract.Errors {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			e := contract.Errors[name]
+			e.Normalized.Name = abi.ResolveNameConflict(e.Normalized.Name, func(name string) bool {
+				return taken[contract.Type+name+"Error"]
+			})
+			taken[contract.Type+e.Normalized.Name+"Error"] = true
+		}
+	}
+}
+
 // bindType is a set of type binders that convert Solidity types to some supported
 // programming language types.
 var bindType = map[Lang]func(kind abi.Type, structs map[string]*tmplStruct) string{
@@ -1877,13 +1877,16 @@ var bindTests = []struct {
 		[]string{`[{"inputs":[{"internalType":"uint256","name":"","type":"uint256"}],"name":"MyError","type":"error"},{"inputs":[{"internalType":"uint256","name":"","type":"uint256"}],"name":"MyError1","type":"error"},{"inputs":[{"internalType":"uint256","name":"","type":"uint256"},{"internalType":"uint256","name":"","type":"uint256"}],"name":"MyError2","type":"error"},{"inputs":[{"internalType":"uint256","name":"a","type":"uint256"},{"internalType":"uint256","name":"b","type":"uint256"},{"internalType":"uint256","name":"c","type":"uint256"}],"name":"MyError3","type":"error"},{"inputs":[],"name":"Error","outputs":[],"stateMutability":"pure","type":"function"}]`},
 		`
 			"context"
+			"errors"
 			"math/big"
 	
 			"github.com/ethereum/go-ethereum/accounts/abi/bind"
 			"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+			"github.com/ethereum/go-ethereum/common"
 			"github.com/ethereum/go-ethereum/core/types"
 			"github.com/ethereum/go-ethereum/crypto"
 			"github.com/ethereum/go-ethereum/eth/ethconfig"
+			"github.com/ethereum/go-ethereum/rpc"
 	   `,
 		`
 			var (
@@ -1902,11 +1905,41 @@ var bindTests = []struct {
 			if err != nil {
 				t.Error(err)
 			}
-			if err := contract.Error(new(bind.CallOpts)); err == nil {
+			err = contract.Error(new(bind.CallOpts))
+			if err == nil {
 				t.Fatalf("expected contract to throw error")
 			}
-			// TODO (MariusVanDerWijden unpack error using abigen
-			// once that is implemented
+			var dataErr rpc.DataError
+			if !errors.As(err, &dataErr) {
+				t.Fatalf("error has no revert data: %v", err)
+			}
+			revert, ok := contract.ParseError(common.FromHex(dataErr.ErrorData().(string)))
+			if !ok {
+				t.Fatalf("failed to parse revert data %v", dataErr.ErrorData())
+			}
+			myErr, ok := revert.(*NewErrorsMyError3Error)
+			if !ok {
+				t.Fatalf("wrong error type %T", revert)
+			}
+			if myErr.A.Uint64() != 1 || myErr.B.Uint64() != 2 || myErr.C.Uint64() != 3 {
+				t.Fatalf("wrong error fields: %v", myErr)
+			}
+			if have, want := myErr.Error(), "MyError3(a: 1, b: 2, c: 3)"; have != want {
+				t.Fatalf("wrong error message: have %q, want %q", have, want)
+			}
+			// Unnamed fields are bound like unnamed event fields.
+			var myErr2 = &NewErrorsMyError2Error{Arg0: big.NewInt(4), Arg1: big.NewInt(5)}
+			if have, want := myErr2.Error(), "MyError2(arg0: 4, arg1: 5)"; have != want {
+				t.Fatalf("wrong error message: have %q, want %q", have, want)
+			}
+			// Standard reverts are decoded too.
+			reason := common.FromHex("0x08c379a0000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000046f6f707300000000000000000000000000000000000000000000000000000000")
+			if revert, ok := contract.ParseError(reason); !ok || revert.Error() != "oops" {
+				t.Fatalf("wrong standard revert: %v", revert)
+			}
+			if _, ok := contract.ParseError([]byte{1, 2, 3, 4}); ok {
+				t.Fatal("unknown error selector parsed")
+			}
 	   `,
 		nil,
 		nil,
@@ -2068,6 +2101,78 @@ var bindTests = []struct {
 			}
 `,
 	},
+	// Test that error types don't collide with event and struct types
+	{
+		name: `ErrorNameCollision`,
+		contract: `
+		struct ErrorNameCollisionBarError { uint256 a; }
+
+		contract ErrorNameCollision {
+			error Foo(uint256 a);
+			error Bar();
+			event FooError(uint256 a);
+			function baz(ErrorNameCollisionBarError memory s) public pure {}
+		}
+		`,
+		bytecode: []string{``},
+		abi:      []string{`[{"inputs":[{"name":"a","type":"uint256"}],"name":"Foo","type":"error"},{"inputs":[],"name":"Bar","type":"error"},{"anonymous":false,"inputs":[{"indexed":false,"name":"a","type":"uint256"}],"name":"FooError","type":"event"},{"inputs":[{"components":[{"name":"a","type":"uint256"}],"internalType":"struct ErrorNameCollisionBarError","name":"s","type":"tuple"}],"name":"baz","outputs":[],"stateMutability":"pure","type":"function"}]`},
+		imports: `
+			"math/big"
+
+			"github.com/ethereum/go-ethereum/core/types"
+		`,
+		tester: `
+			var (
+				_ error = &ErrorNameCollisionFoo0Error{A: big.NewInt(1)}
+				_ error = new(ErrorNameCollisionBar0Error)
+				_       = ErrorNameCollisionFooError{A: big.NewInt(1), Raw: types.Log{}}
+				_       = ErrorNameCollisionBarError{A: big.NewInt(1)}
+			)
+		`,
+	},
+	// Test that aggregate call helpers are generated for read-only methods
+	{
+		name: `Multicall`,
+		contract: `
+		contract Multicall {
+			function double(uint256 a) public pure returns (uint256) { return 2 * a; }
+			function pair() public pure returns (uint256 a, bool b) { return (1, true); }
+			function set(uint256 a) public {}
+		}
+		`,
+		bytecode: []string{``},
+		abi:      []string{`[{"inputs":[{"name":"a","type":"uint256"}],"name":"double","outputs":[{"name":"","type":"uint256"}],"stateMutability":"pure","type":"function"},{"inputs":[],"name":"pair","outputs":[{"name":"a","type":"uint256"},{"name":"b","type":"bool"}],"stateMutability":"pure","type":"function"},{"inputs":[{"name":"a","type":"uint256"}],"name":"set","outputs":[],"stateMutability":"nonpayable","type":"function"}]`},
+		imports: `
+			"errors"
+			"math/big"
+
+			"github.com/ethereum/go-ethereum/accounts/abi/bind"
+			"github.com/ethereum/go-ethereum/common"
+		`,
+		tester: `
+			c, err := NewMulticallCaller(common.Address{}, nil)
+			if err != nil {
+				t.Fatalf("failed to create caller: %v", err)
+			}
+			doubleCall, double := c.AggregateDouble(big.NewInt(21))
+			pairCall, pair := c.AggregatePair()
+			if doubleCall == nil || pairCall == nil {
+				t.Fatal("nil aggregate call")
+			}
+			if _, err := double(); !errors.Is(err, bind.ErrAggregateCallPending) {
+				t.Fatalf("wrong error before execution: %v", err)
+			}
+			if _, err := pair(); !errors.Is(err, bind.ErrAggregateCallPending) {
+				t.Fatalf("wrong error before execution: %v", err)
+			}
+		`,
+	},
+}
+
+// bindTestOptions contains the code generation options of bind tests which don't use
+// the defaults, keyed by test name.
+var bindTestOptions = map[string]BindOptions{
+	"Multicall": {Multicall: true},
 }
 
 // Tests that packages generated by the binder can be successfully compiled and
@@ -2096,7 +2201,7 @@ func TestGolangBindings(t *testing.T) {
 				types = []string{tt.name}
 			}
 			// Generate the binding and create a Go source file in the workspace
-			bind, err := Bind(types, tt.abi, tt.bytecode, tt.fsigs, "bindtest", LangGo, tt.libs, tt.aliases)
+			bind, err := BindWithOptions(types, tt.abi, tt.bytecode, tt.fsigs, "bindtest", LangGo, tt.libs, tt.aliases, bindTestOptions[tt.name])
 			if err != nil {
 				t.Fatalf("test %d: failed to generate binding: %v", i, err)
 			}
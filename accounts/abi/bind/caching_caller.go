@@ -0,0 +1,156 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// cachingCallerLimit is the maximum number of results held by a CachingCaller.
+const cachingCallerLimit = 1024
+
+// CachingCaller is a ContractCaller which memoizes the results of contract calls,
+// avoiding repeated requests for static data such as token decimals or symbols.
+//
+// Only plain calls as made by BoundContract.Call are cached, i.e. calls with a
+// recipient and without gas, value or fee parameters. Other calls and failed calls
+// are forwarded to the backend every time.
+//
+// Results of calls against the latest block are kept until the ttl expires, or
+// until a newer head is reported through NewHead. Results of calls against a block
+// number are kept until they're evicted from the cache. Note this means a result is
+// not updated if the block is reorged, so only non-final blocks should be pinned if
+// this is acceptable. Calls against the pending block aren't cached.
+type CachingCaller struct {
+	backend ContractCaller
+	ttl     time.Duration
+	clock   mclock.Clock
+
+	mu    sync.Mutex
+	cache lru.BasicLRU[cachingCallerKey, cachedCall]
+	head  uint64 // number of the latest reported head block
+}
+
+type cachingCallerKey struct {
+	from, to common.Address
+	latest   bool
+	number   uint64
+	data     string
+}
+
+type cachedCall struct {
+	result []byte
+	time   mclock.AbsTime // time of the call against the latest block
+	head   uint64         // head at the time of the call against the latest block
+}
+
+// NewCachingCaller creates a caching caller on top of the given backend. The ttl
+// is the maximum age of results of calls against the latest block, zero means they
+// are only invalidated by NewHead.
+func NewCachingCaller(backend ContractCaller, ttl time.Duration) *CachingCaller {
+	return &CachingCaller{
+		backend: backend,
+		ttl:     ttl,
+		clock:   mclock.System{},
+		cache:   lru.NewBasicLRU[cachingCallerKey, cachedCall](cachingCallerLimit),
+	}
+}
+
+// NewHead reports a new head block, invalidating the results of previous calls
+// against the latest block.
+func (c *CachingCaller) NewHead(head *types.Header) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if number := head.Number.Uint64(); number > c.head {
+		c.head = number
+	}
+}
+
+// Purge drops all cached results.
+func (c *CachingCaller) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cache.Purge()
+}
+
+// CodeAt implements ContractCaller. Code is not cached.
+func (c *CachingCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return c.backend.CodeAt(ctx, contract, blockNumber)
+}
+
+// CallContract implements ContractCaller.
+func (c *CachingCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	key, ok := cachingKey(call, blockNumber)
+	if !ok {
+		return c.backend.CallContract(ctx, call, blockNumber)
+	}
+	c.mu.Lock()
+	head := c.head
+	if cached, ok := c.cache.Get(key); ok {
+		if !key.latest || (cached.head == head && (c.ttl == 0 || c.clock.Now().Sub(cached.time) < c.ttl)) {
+			c.mu.Unlock()
+			return common.CopyBytes(cached.result), nil
+		}
+		c.cache.Remove(key)
+	}
+	c.mu.Unlock()
+
+	now := c.clock.Now()
+	result, err := c.backend.CallContract(ctx, call, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.cache.Add(key, cachedCall{result: common.CopyBytes(result), time: now, head: head})
+	c.mu.Unlock()
+	return result, nil
+}
+
+// cachingKey returns the cache key of a call, or false if the call can't be cached.
+func cachingKey(call ethereum.CallMsg, blockNumber *big.Int) (cachingCallerKey, bool) {
+	if call.To == nil || call.Gas != 0 || !isZero(call.Value) || !isZero(call.GasPrice) ||
+		!isZero(call.GasFeeCap) || !isZero(call.GasTipCap) || call.AccessList != nil ||
+		call.BlobGasFeeCap != nil || call.BlobHashes != nil {
+		return cachingCallerKey{}, false
+	}
+	key := cachingCallerKey{from: call.From, to: *call.To, data: string(call.Data)}
+	switch {
+	case blockNumber == nil || blockNumber.Cmp(big.NewInt(int64(rpc.LatestBlockNumber))) == 0:
+		key.latest = true
+	case blockNumber.Sign() >= 0 && blockNumber.IsUint64():
+		key.number = blockNumber.Uint64()
+	default:
+		return cachingCallerKey{}, false
+	}
+	return key, true
+}
+
+func isZero(v *big.Int) bool {
+	return v == nil || v.Sign() == 0
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// countingCaller returns the number of calls made so far as the call result.
+type countingCaller struct {
+	calls int
+	err   error
+}
+
+func (c *countingCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (c *countingCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return []byte{byte(c.calls)}, nil
+}
+
+func TestCachingCaller(t *testing.T) {
+	var (
+		backend = new(countingCaller)
+		clock   = new(mclock.Simulated)
+		caller  = NewCachingCaller(backend, time.Minute)
+		addr    = common.HexToAddress("0x01")
+		ctx     = context.Background()
+	)
+	caller.clock = clock
+
+	check := func(call ethereum.CallMsg, number *big.Int, want byte) {
+		t.Helper()
+		result, err := caller.CallContract(ctx, call, number)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result) != 1 || result[0] != want {
+			t.Fatalf("wrong result %x, want %x", result, want)
+		}
+	}
+	decimals := ethereum.CallMsg{To: &addr, Data: []byte{0x31, 0x3c, 0xe5, 0x67}}
+	symbol := ethereum.CallMsg{To: &addr, Data: []byte{0x95, 0xd8, 0x9b, 0x41}}
+
+	// Calls against the latest block are cached per input.
+	check(decimals, nil, 1)
+	check(decimals, nil, 1)
+	check(decimals, big.NewInt(int64(rpc.LatestBlockNumber)), 1)
+	check(symbol, nil, 2)
+
+	// They expire after the ttl and on new heads.
+	clock.Run(time.Minute)
+	check(decimals, nil, 3)
+	check(decimals, nil, 3)
+	caller.NewHead(&types.Header{Number: big.NewInt(1)})
+	check(decimals, nil, 4)
+	check(decimals, nil, 4)
+
+	// Calls against a block number are kept.
+	check(decimals, big.NewInt(1), 5)
+	clock.Run(time.Hour)
+	caller.NewHead(&types.Header{Number: big.NewInt(2)})
+	check(decimals, big.NewInt(1), 5)
+	check(decimals, big.NewInt(2), 6)
+
+	// Pending calls and calls with value aren't cached.
+	check(decimals, big.NewInt(int64(rpc.PendingBlockNumber)), 7)
+	check(decimals, big.NewInt(int64(rpc.PendingBlockNumber)), 8)
+	payable := ethereum.CallMsg{To: &addr, Value: big.NewInt(1)}
+	check(payable, nil, 9)
+	check(payable, nil, 10)
+
+	// Errors aren't cached.
+	backend.err = errors.New("failed")
+	if _, err := caller.CallContract(ctx, symbol, big.NewInt(3)); err == nil {
+		t.Fatal("expected error")
+	}
+	backend.err = nil
+	check(symbol, big.NewInt(3), 12)
+
+	// The cache is bounded.
+	for i := 0; i < cachingCallerLimit; i++ {
+		check(ethereum.CallMsg{To: &addr, Data: big.NewInt(int64(i)).Bytes()}, big.NewInt(3), byte(13+i))
+	}
+	if n := caller.cache.Len(); n != cachingCallerLimit {
+		t.Fatalf("wrong cache size %d", n)
+	}
+	calls := 13 + cachingCallerLimit
+	check(symbol, big.NewInt(3), byte(calls))
+}
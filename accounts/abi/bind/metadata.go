@@ -0,0 +1,88 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ContractMetadata is the language independent description of a bound contract.
+// Methods and errors map the signatures to their 4-byte selectors, events map the
+// signatures of non-anonymous events to their topic.
+type ContractMetadata struct {
+	Name     string            `json:"name"`
+	ABI      json.RawMessage   `json:"abi"`
+	Bytecode string            `json:"bytecode,omitempty"`
+	Methods  map[string]string `json:"methods"`
+	Events   map[string]string `json:"events"`
+	Errors   map[string]string `json:"errors"`
+}
+
+// BindMetadata generates the JSON metadata of the contracts passed to Bind, for use
+// by tooling in other languages. The output is deterministic: contracts are sorted by
+// name, and the ABI is re-encoded with sorted object keys.
+func BindMetadata(types []string, abis []string, bytecodes []string) ([]byte, error) {
+	contracts := make([]*ContractMetadata, len(types))
+	for i := range types {
+		evmABI, err := abi.JSON(strings.NewReader(abis[i]))
+		if err != nil {
+			return nil, fmt.Errorf("contract %s: %v", types[i], err)
+		}
+		var canonical any
+		if err := json.Unmarshal([]byte(abis[i]), &canonical); err != nil {
+			return nil, fmt.Errorf("contract %s: %v", types[i], err)
+		}
+		enc, err := json.Marshal(canonical)
+		if err != nil {
+			return nil, err
+		}
+		contract := &ContractMetadata{
+			Name:    types[i],
+			ABI:     enc,
+			Methods: make(map[string]string),
+			Events:  make(map[string]string),
+			Errors:  make(map[string]string),
+		}
+		if i < len(bytecodes) {
+			if code := strings.TrimPrefix(strings.TrimSpace(bytecodes[i]), "0x"); code != "" {
+				contract.Bytecode = "0x" + code
+			}
+		}
+		for _, method := range evmABI.Methods {
+			contract.Methods[method.Sig] = hexutil.Encode(method.ID)
+		}
+		for _, event := range evmABI.Events {
+			if !event.Anonymous {
+				contract.Events[event.Sig] = event.ID.Hex()
+			}
+		}
+		for _, e := range evmABI.Errors {
+			contract.Errors[e.Sig] = hexutil.Encode(e.ID[:4])
+		}
+		contracts[i] = contract
+	}
+	sort.SliceStable(contracts, func(i, j int) bool {
+		return contracts[i].Name < contracts[j].Name
+	})
+	return json.MarshalIndent(contracts, "", "  ")
+}
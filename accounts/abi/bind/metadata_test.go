@@ -0,0 +1,82 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBindMetadata(t *testing.T) {
+	var (
+		token = `[
+			{"type": "function", "name": "transfer", "stateMutability": "nonpayable",
+			 "inputs": [{"name": "to", "type": "address"}, {"name": "value", "type": "uint256"}],
+			 "outputs": [{"name": "", "type": "bool"}]},
+			{"type": "event", "name": "Transfer", "anonymous": false, "inputs": [
+				{"name": "from", "type": "address", "indexed": true},
+				{"name": "to", "type": "address", "indexed": true},
+				{"name": "value", "type": "uint256", "indexed": false}]},
+			{"type": "event", "name": "Hidden", "anonymous": true, "inputs": []},
+			{"type": "error", "name": "Error", "inputs": [{"name": "", "type": "string"}]}
+		]`
+		empty = `[]`
+	)
+	out, err := BindMetadata([]string{"Token", "Empty"}, []string{token, empty}, []string{" 6000\n", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := BindMetadata([]string{"Token", "Empty"}, []string{token, empty}, []string{" 6000\n", ""})
+	if !bytes.Equal(out, again) {
+		t.Fatalf("metadata not deterministic:\n%s\n%s", out, again)
+	}
+	var contracts []ContractMetadata
+	if err := json.Unmarshal(out, &contracts); err != nil {
+		t.Fatal(err)
+	}
+	if len(contracts) != 2 || contracts[0].Name != "Empty" || contracts[1].Name != "Token" {
+		t.Fatalf("wrong contracts: %s", out)
+	}
+	if c := contracts[0]; c.Bytecode != "" || len(c.Methods) != 0 || len(c.Events) != 0 || len(c.Errors) != 0 {
+		t.Errorf("wrong metadata of empty contract: %+v", c)
+	}
+	c := contracts[1]
+	if c.Bytecode != "0x6000" {
+		t.Errorf("wrong bytecode %q", c.Bytecode)
+	}
+	wantMethods := map[string]string{"transfer(address,uint256)": "0xa9059cbb"}
+	if !reflect.DeepEqual(c.Methods, wantMethods) {
+		t.Errorf("wrong methods %v", c.Methods)
+	}
+	wantEvents := map[string]string{"Transfer(address,address,uint256)": "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"}
+	if !reflect.DeepEqual(c.Events, wantEvents) {
+		t.Errorf("wrong events %v", c.Events)
+	}
+	wantErrors := map[string]string{"Error(string)": "0x08c379a0"}
+	if !reflect.DeepEqual(c.Errors, wantErrors) {
+		t.Errorf("wrong errors %v", c.Errors)
+	}
+	// The ABI keys are sorted regardless of the input order.
+	var abi bytes.Buffer
+	json.Compact(&abi, c.ABI)
+	if !strings.HasPrefix(abi.String(), `[{"inputs":[{"name":"to","type":"address"}`) {
+		t.Errorf("ABI not canonical: %s", abi.String())
+	}
+}
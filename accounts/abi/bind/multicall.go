@@ -0,0 +1,148 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Multicall3Address is the address of the Multicall3 contract, which is deployed at
+// the same address on most EVM chains.
+var Multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+var (
+	// ErrAggregateCallFailed is returned when retrieving the result of an aggregate
+	// call which reverted.
+	ErrAggregateCallFailed = errors.New("aggregate call failed")
+
+	// ErrAggregateCallPending is returned when retrieving the result of an aggregate
+	// call which was not executed yet.
+	ErrAggregateCallPending = errors.New("aggregate call not executed")
+)
+
+// multicall3ABI is the subset of the Multicall3 ABI used by AggregateCalls.
+const multicall3ABI = `[{"inputs":[{"components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}],"name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":[{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}],"name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"}]`
+
+var parsedMulticall3ABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(multicall3ABI))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// multicall3Call is the Call3 struct of Multicall3.
+type multicall3Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// multicall3Result is the Result struct of Multicall3.
+type multicall3Result struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// AggregateCall is a read-only contract call which is executed together with other
+// calls in a single request by AggregateCalls.
+type AggregateCall struct {
+	// AllowFailure controls whether a failure of this call is tolerated. If false, a
+	// revert of the call fails the entire aggregate call.
+	AllowFailure bool
+
+	contract *BoundContract
+	method   string
+	input    []byte
+	err      error // error packing the input
+
+	executed bool
+	success  bool
+	output   []byte
+}
+
+// NewAggregateCall creates a call of the given method, for execution via AggregateCalls.
+// Errors packing the parameters are reported by AggregateCalls.
+func (c *BoundContract) NewAggregateCall(method string, params ...interface{}) *AggregateCall {
+	input, err := c.abi.Pack(method, params...)
+	return &AggregateCall{contract: c, method: method, input: input, err: err}
+}
+
+// Success reports whether the call was executed successfully.
+func (call *AggregateCall) Success() bool {
+	return call.executed && call.success
+}
+
+// Unpack unpacks the result of the call into results, in the same way as
+// BoundContract.Call. It returns ErrAggregateCallFailed if the call reverted.
+func (call *AggregateCall) Unpack(results *[]interface{}) error {
+	if !call.executed {
+		return ErrAggregateCallPending
+	}
+	if !call.success {
+		return fmt.Errorf("%w: %s", ErrAggregateCallFailed, call.method)
+	}
+	if len(*results) == 0 {
+		res, err := call.contract.abi.Unpack(call.method, call.output)
+		*results = res
+		return err
+	}
+	res := *results
+	return call.contract.abi.UnpackIntoInterface(res[0], call.method, call.output)
+}
+
+// AggregateCalls executes the given calls in a single eth_call using the aggregate3
+// method of the Multicall3 contract deployed at the given address. After it returns
+// successfully, the result of each call can be retrieved using its Unpack method.
+//
+// Note that all calls are executed by the multicall contract, so msg.sender of the
+// individual calls is the multicall address rather than opts.From.
+func AggregateCalls(opts *CallOpts, caller ContractCaller, multicall common.Address, calls ...*AggregateCall) error {
+	args := make([]multicall3Call, len(calls))
+	for i, call := range calls {
+		if call.err != nil {
+			return fmt.Errorf("call %d (%s): %w", i, call.method, call.err)
+		}
+		args[i] = multicall3Call{
+			Target:       call.contract.address,
+			AllowFailure: call.AllowFailure,
+			CallData:     call.input,
+		}
+	}
+	var (
+		contract = NewBoundContract(multicall, parsedMulticall3ABI, caller, nil, nil)
+		out      []interface{}
+	)
+	if err := contract.Call(opts, &out, "aggregate3", args); err != nil {
+		return err
+	}
+	results := *abi.ConvertType(out[0], new([]multicall3Result)).(*[]multicall3Result)
+	if len(results) != len(calls) {
+		return fmt.Errorf("multicall result count mismatch: have %d, want %d", len(results), len(calls))
+	}
+	for i, call := range calls {
+		call.executed = true
+		call.success = results[i].Success
+		call.output = results[i].ReturnData
+	}
+	return nil
+}
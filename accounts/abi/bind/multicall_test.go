@@ -0,0 +1,139 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const multicallTestABI = `[{"inputs":[{"name":"a","type":"uint256"}],"name":"double","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+
+// multicallCaller emulates the Multicall3 aggregate3 method. Calls to the test
+// contract return twice their argument, calls with an argument of zero revert.
+type multicallCaller struct {
+	abi abi.ABI
+}
+
+func (mc *multicallCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (mc *multicallCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	method := parsedMulticall3ABI.Methods["aggregate3"]
+	args, err := method.Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	calls := *abi.ConvertType(args[0], new([]multicall3Call)).(*[]multicall3Call)
+	results := make([]multicall3Result, len(calls))
+	for i, c := range calls {
+		in, err := mc.abi.Methods["double"].Inputs.Unpack(c.CallData[4:])
+		if err != nil {
+			return nil, err
+		}
+		if a := in[0].(*big.Int); a.Sign() != 0 {
+			results[i].Success = true
+			results[i].ReturnData, _ = mc.abi.Methods["double"].Outputs.Pack(new(big.Int).Lsh(a, 1))
+		} else if !c.AllowFailure {
+			return nil, errors.New("execution reverted")
+		}
+	}
+	return method.Outputs.Pack(results)
+}
+
+func TestAggregateCalls(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := abi.JSON(strings.NewReader(multicallTestABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		caller   = &multicallCaller{abi: parsed}
+		contract = NewBoundContract(common.Address{1}, parsed, caller, nil, nil)
+		ok       = contract.NewAggregateCall("double", big.NewInt(21))
+		failing  = contract.NewAggregateCall("double", big.NewInt(0))
+	)
+	failing.AllowFailure = true
+
+	var out []interface{}
+	if err := ok.Unpack(&out); !errors.Is(err, ErrAggregateCallPending) {
+		t.Fatalf("wrong error before execution: %v", err)
+	}
+	if err := AggregateCalls(nil, caller, Multicall3Address, ok, failing); err != nil {
+		t.Fatalf("aggregate call failed: %v", err)
+	}
+	if !ok.Success() {
+		t.Fatal("call not successful")
+	}
+	if err := ok.Unpack(&out); err != nil {
+		t.Fatalf("unpack failed: %v", err)
+	}
+	if res := out[0].(*big.Int); res.Cmp(big.NewInt(42)) != 0 {
+		t.Fatalf("wrong result: %v", res)
+	}
+	if failing.Success() {
+		t.Fatal("failing call reported success")
+	}
+	if err := failing.Unpack(&out); !errors.Is(err, ErrAggregateCallFailed) {
+		t.Fatalf("wrong error for failed call: %v", err)
+	}
+
+	// Failures of calls which don't allow them fail the entire aggregate call.
+	failing.AllowFailure = false
+	if err := AggregateCalls(nil, caller, Multicall3Address, ok, failing); err == nil {
+		t.Fatal("expected error for disallowed failure")
+	}
+	// Packing errors are reported before executing.
+	invalid := contract.NewAggregateCall("double", "not a number")
+	if err := AggregateCalls(nil, caller, Multicall3Address, ok, invalid); err == nil {
+		t.Fatal("expected packing error")
+	}
+}
+
+func TestBindMulticall(t *testing.T) {
+	t.Parallel()
+
+	abis := []string{multicallTestABI}
+	code, err := BindWithOptions([]string{"Test"}, abis, []string{""}, nil, "bindtest", LangGo, nil, nil, BindOptions{Multicall: true})
+	if err != nil {
+		t.Fatalf("failed to generate binding: %v", err)
+	}
+	if !strings.Contains(code, "func (_Test *TestCaller) AggregateDouble(a *big.Int) (*bind.AggregateCall, func() (*big.Int, error))") {
+		t.Fatalf("aggregate helper not generated:\n%s", code)
+	}
+	code, err = Bind([]string{"Test"}, abis, []string{""}, nil, "bindtest", LangGo, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to generate binding: %v", err)
+	}
+	if strings.Contains(code, "AggregateDouble") {
+		t.Fatal("aggregate helper generated without multicall option")
+	}
+	// Aggregate helpers must not collide with other methods.
+	collision := `[{"inputs":[],"name":"aggregateFoo","outputs":[],"stateMutability":"view","type":"function"},{"inputs":[],"name":"foo","outputs":[],"stateMutability":"view","type":"function"}]`
+	if _, err := BindWithOptions([]string{"Test"}, []string{collision}, []string{""}, nil, "bindtest", LangGo, nil, nil, BindOptions{Multicall: true}); err == nil {
+		t.Fatal("expected duplicated identifier error")
+	}
+}
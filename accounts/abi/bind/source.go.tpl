@@ -4,6 +4,7 @@
 package {{.Package}}
 
 import (
+	"fmt"
 	"math/big"
 	"strings"
 	"errors"
@@ -19,6 +20,7 @@ import (
 // Reference imports to suppress errors if they are not otherwise used.
 var (
 	_ = errors.New
+	_ = fmt.Sprintf
 	_ = big.NewInt
 	_ = strings.NewReader
 	_ = ethereum.NotFound
@@ -270,6 +272,39 @@ var (
 		func (_{{$contract.Type}} *{{$contract.Type}}CallerSession) {{.Normalized.Name}}({{range $i, $_ := .Normalized.Inputs}}{{if ne $i 0}},{{end}} {{.Name}} {{bindtype .Type $structs}} {{end}}) ({{if .Structured}}struct{ {{range .Normalized.Outputs}}{{.Name}} {{bindtype .Type $structs}};{{end}} }, {{else}} {{range .Normalized.Outputs}}{{bindtype .Type $structs}},{{end}} {{end}} error) {
 		  return _{{$contract.Type}}.Contract.{{.Normalized.Name}}(&_{{$contract.Type}}.CallOpts {{range .Normalized.Inputs}}, {{.Name}}{{end}})
 		}
+
+		{{if $.Multicall}}
+		// Aggregate{{.Normalized.Name}} creates a call of the contract method 0x{{printf "%x" .Original.ID}}
+		// for execution via bind.AggregateCalls. The returned function retrieves the
+		// result once the aggregate call has been executed.
+		//
+		// Solidity: {{.Original.String}}
+		func (_{{$contract.Type}} *{{$contract.Type}}Caller) Aggregate{{.Normalized.Name}}({{range $i, $_ := .Normalized.Inputs}}{{if ne $i 0}},{{end}} {{.Name}} {{bindtype .Type $structs}} {{end}}) (*bind.AggregateCall, func() ({{if .Structured}}struct{ {{range .Normalized.Outputs}}{{.Name}} {{bindtype .Type $structs}};{{end}} },{{else}}{{range .Normalized.Outputs}}{{bindtype .Type $structs}},{{end}}{{end}} error)) {
+			call := _{{$contract.Type}}.contract.NewAggregateCall("{{.Original.Name}}" {{range .Normalized.Inputs}}, {{.Name}}{{end}})
+			return call, func() ({{if .Structured}}struct{ {{range .Normalized.Outputs}}{{.Name}} {{bindtype .Type $structs}};{{end}} },{{else}}{{range .Normalized.Outputs}}{{bindtype .Type $structs}},{{end}}{{end}} error) {
+				var out []interface{}
+				err := call.Unpack(&out)
+				{{if .Structured}}
+				outstruct := new(struct{ {{range .Normalized.Outputs}} {{.Name}} {{bindtype .Type $structs}}; {{end}} })
+				if err != nil {
+					return *outstruct, err
+				}
+				{{range $i, $t := .Normalized.Outputs}}
+				outstruct.{{.Name}} = *abi.ConvertType(out[{{$i}}], new({{bindtype .Type $structs}})).(*{{bindtype .Type $structs}}){{end}}
+
+				return *outstruct, err
+				{{else}}
+				if err != nil {
+					return {{range $i, $_ := .Normalized.Outputs}}*new({{bindtype .Type $structs}}), {{end}} err
+				}
+				{{range $i, $t := .Normalized.Outputs}}
+				out{{$i}} := *abi.ConvertType(out[{{$i}}], new({{bindtype .Type $structs}})).(*{{bindtype .Type $structs}}){{end}}
+
+				return {{range $i, $t := .Normalized.Outputs}}out{{$i}}, {{end}} err
+				{{end}}
+			}
+		}
+		{{end}}
 	{{end}}
 
 	{{range .Transacts}}
@@ -341,6 +376,57 @@ var (
 		}
 	{{end}}
 
+	// ParseError decodes the revert data of a failed call or transaction of the {{.Type}} contract.
+	// Custom errors of the contract are returned as typed errors, the standard Error(string) and
+	// Panic(uint256) reverts as plain errors. The boolean is false if the data can't be decoded.
+	func (_{{$contract.Type}} *{{$contract.Type}}) ParseError(data []byte) (error, bool) {
+		{{if .Errors}}
+		parsed, err := {{.Type}}MetaData.GetAbi()
+		if err != nil || len(data) < 4 {
+			return nil, false
+		}
+		var id [4]byte
+		copy(id[:], data)
+		if e, err := parsed.ErrorByID(id); err == nil {
+			switch e.Name {
+			{{range .Errors}}
+			case "{{.Original.Name}}":
+				{{if .Normalized.Inputs}}
+				values, err := e.Inputs.Unpack(data[4:])
+				if err != nil {
+					return nil, false
+				}
+				return &{{$contract.Type}}{{.Normalized.Name}}Error{ {{range $i, $t := .Normalized.Inputs}}
+					{{capitalise .Name}}: *abi.ConvertType(values[{{$i}}], new({{bindtype .Type $structs}})).(*{{bindtype .Type $structs}}),{{end}}
+				}, true
+				{{else}}
+				return new({{$contract.Type}}{{.Normalized.Name}}Error), true
+				{{end}}
+			{{end}}
+			}
+		}
+		{{end}}
+		reason, err := abi.UnpackRevert(data)
+		if err != nil {
+			return nil, false
+		}
+		return errors.New(reason), true
+	}
+
+	{{range .Errors}}
+		// {{$contract.Type}}{{.Normalized.Name}}Error represents a {{.Normalized.Name}} error raised by the {{$contract.Type}} contract.
+		//
+		// Solidity: {{.Original.String}}
+		type {{$contract.Type}}{{.Normalized.Name}}Error struct { {{range .Normalized.Inputs}}
+			{{capitalise .Name}} {{bindtype .Type $structs}}; {{end}}
+		}
+
+		// Error implements the error interface.
+		func (e *{{$contract.Type}}{{.Normalized.Name}}Error) Error() string {
+			return fmt.Sprintf("{{.Original.Name}}({{range $i, $_ := .Normalized.Inputs}}{{if ne $i 0}}, {{end}}{{.Name}}: %v{{end}})"{{range .Normalized.Inputs}}, e.{{capitalise .Name}}{{end}})
+		}
+	{{end}}
+
 	{{range .Events}}
 		// {{$contract.Type}}{{.Normalized.Name}}Iterator is returned from Filter{{.Normalized.Name}} and is used to iterate over the raw logs and unpacked data for {{.Normalized.Name}} events raised by the {{$contract.Type}} contract.
 		type {{$contract.Type}}{{.Normalized.Name}}Iterator struct {
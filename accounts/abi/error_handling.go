@@ -19,7 +19,9 @@ package abi
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"reflect"
+	"strings"
 )
 
 var (
@@ -60,7 +62,11 @@ func sliceTypeCheck(t Type, val reflect.Value) error {
 		}
 	}
 
-	if val.Type().Elem().Kind() != t.Elem.GetType().Kind() {
+	// Elements given as interfaces or pointers are checked individually when packing.
+	switch elem := val.Type().Elem().Kind(); {
+	case elem == reflect.Interface || (elem == reflect.Ptr && t.Elem.T == TupleTy):
+		return nil
+	case elem != t.Elem.GetType().Kind():
 		return typeErr(formatSliceString(t.Elem.GetType().Kind(), t.Size), val.Type())
 	}
 	return nil
@@ -87,3 +93,84 @@ func typeCheck(t Type, value reflect.Value) error {
 func typeErr(expected, got interface{}) error {
 	return fmt.Errorf("abi: cannot use %v as type %v as argument", got, expected)
 }
+
+// packPathError is a packing error of a value nested in an array or tuple argument.
+// The path locates the value within the argument, e.g. "[1].amount".
+type packPathError struct {
+	arg  string
+	path string
+	err  error
+}
+
+func (e *packPathError) Error() string {
+	msg := strings.TrimPrefix(e.err.Error(), "abi: ")
+	if e.arg == "" {
+		return fmt.Sprintf("abi: invalid value at %s: %s", e.path, msg)
+	}
+	return fmt.Sprintf("abi: invalid argument %s%s: %s", e.arg, e.path, msg)
+}
+
+func (e *packPathError) Unwrap() error {
+	return e.err
+}
+
+// wrapPackPath prepends elem to the path of a packing error.
+func wrapPackPath(err error, elem string) error {
+	if pe, ok := err.(*packPathError); ok {
+		pe.path = elem + pe.path
+		return pe
+	}
+	return &packPathError{path: elem, err: err}
+}
+
+// argumentCheck performs a shallow check that the Go value v can be packed as the
+// type of the given argument. It only validates the kind of the value, detailed
+// checks (integer sizes, array lengths, element types) are performed while packing.
+// The returned error names the parameter and the expected ABI type.
+func argumentCheck(arg Argument, index int, v interface{}) error {
+	name := arg.Name
+	if name == "" {
+		name = fmt.Sprintf("#%d", index)
+	}
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Ptr && val.Type() != reflect.TypeOf(&big.Int{}) {
+		if val.IsNil() {
+			break
+		}
+		val = val.Elem()
+	}
+	if !val.IsValid() || (val.Kind() == reflect.Ptr && val.IsNil()) {
+		return fmt.Errorf("abi: invalid argument %s: nil value for type %v", name, arg.Type)
+	}
+	if !kindMatches(arg.Type, val) {
+		return fmt.Errorf("abi: invalid argument %s: cannot use %T as type %v", name, v, arg.Type)
+	}
+	return nil
+}
+
+// kindMatches reports whether the kind of val is compatible with the ABI type t.
+func kindMatches(t Type, val reflect.Value) bool {
+	switch t.T {
+	case IntTy, UintTy:
+		switch val.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return true
+		}
+		return val.Type() == reflect.TypeOf(&big.Int{}) || val.Type() == reflect.TypeOf(big.Int{})
+	case BoolTy:
+		return val.Kind() == reflect.Bool
+	case StringTy:
+		return val.Kind() == reflect.String
+	case BytesTy:
+		return val.Kind() == reflect.Slice && val.Type().Elem().Kind() == reflect.Uint8
+	case AddressTy, FixedBytesTy, FunctionTy:
+		return val.Kind() == reflect.Array && val.Type().Elem().Kind() == reflect.Uint8
+	case SliceTy, ArrayTy:
+		return val.Kind() == reflect.Slice || val.Kind() == reflect.Array
+	case TupleTy:
+		return val.Kind() == reflect.Struct
+	default:
+		return true
+	}
+}
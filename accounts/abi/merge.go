@@ -0,0 +1,138 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+)
+
+// Merge combines the given ABIs into one, e.g. the ABI of a proxy contract and the
+// ABI of its implementation, so that all calls, events and errors of the combined
+// contract can be decoded.
+//
+// Methods, events and errors are identified by their selector. Entries which occur in
+// multiple ABIs with the same selector are merged if they are identical, i.e. have the
+// same signature and, for methods, the same output types and for events, the same
+// indexed arguments. A selector shared by incompatible entries is an error.
+//
+// Entries with the same name but different signatures are kept as overloads. Names are
+// disambiguated like overloads within a single ABI: the entry of the earliest ABI in the
+// argument list keeps its name, later ones get a numeric suffix (e.g. "foo" and "foo0"),
+// see ResolveNameConflict. The constructor, fallback and receive functions are taken
+// from the first ABI defining them.
+func Merge(abis ...ABI) (ABI, error) {
+	merged := ABI{
+		Methods: make(map[string]Method),
+		Events:  make(map[string]Event),
+		Errors:  make(map[string]Error),
+	}
+	for _, abi := range abis {
+		if merged.Constructor.String() == "" {
+			merged.Constructor = abi.Constructor
+		}
+		if !merged.HasFallback() && abi.HasFallback() {
+			merged.Fallback = abi.Fallback
+		}
+		if !merged.HasReceive() && abi.HasReceive() {
+			merged.Receive = abi.Receive
+		}
+		for _, name := range sortedKeys(abi.Methods) {
+			if err := merged.mergeMethod(abi.Methods[name]); err != nil {
+				return ABI{}, err
+			}
+		}
+		for _, name := range sortedKeys(abi.Events) {
+			if err := merged.mergeEvent(abi.Events[name]); err != nil {
+				return ABI{}, err
+			}
+		}
+		for _, name := range sortedKeys(abi.Errors) {
+			if err := merged.mergeError(name, abi.Errors[name]); err != nil {
+				return ABI{}, err
+			}
+		}
+	}
+	return merged, nil
+}
+
+func (abi *ABI) mergeMethod(method Method) error {
+	for _, have := range abi.Methods {
+		if !bytes.Equal(have.ID, method.ID) {
+			continue
+		}
+		if have.Sig != method.Sig || !sameTypes(have.Outputs, method.Outputs) {
+			return fmt.Errorf("abi: selector collision between methods %q and %q", have.String(), method.String())
+		}
+		return nil
+	}
+	method.Name = ResolveNameConflict(method.RawName, func(s string) bool { _, ok := abi.Methods[s]; return ok })
+	abi.Methods[method.Name] = method
+	return nil
+}
+
+func (abi *ABI) mergeEvent(event Event) error {
+	for _, have := range abi.Events {
+		if have.ID != event.ID {
+			continue
+		}
+		if have.Sig != event.Sig || have.Anonymous != event.Anonymous || !sameIndexed(have.Inputs, event.Inputs) {
+			return fmt.Errorf("abi: topic collision between events %q and %q", have.String(), event.String())
+		}
+		return nil
+	}
+	event.Name = ResolveNameConflict(event.RawName, func(s string) bool { _, ok := abi.Events[s]; return ok })
+	abi.Events[event.Name] = event
+	return nil
+}
+
+func (abi *ABI) mergeError(name string, e Error) error {
+	for _, have := range abi.Errors {
+		if !bytes.Equal(have.ID[:4], e.ID[:4]) {
+			continue
+		}
+		if have.Sig != e.Sig {
+			return fmt.Errorf("abi: selector collision between errors %q and %q", have.String(), e.String())
+		}
+		return nil
+	}
+	// The error itself keeps its name, which is part of the signature.
+	name = ResolveNameConflict(name, func(s string) bool { _, ok := abi.Errors[s]; return ok })
+	abi.Errors[name] = e
+	return nil
+}
+
+// sameTypes reports whether both argument lists have the same types.
+func sameTypes(a, b Arguments) bool {
+	return slices.EqualFunc(a, b, func(x, y Argument) bool { return x.Type.String() == y.Type.String() })
+}
+
+// sameIndexed reports whether both argument lists have the same indexed arguments.
+func sameIndexed(a, b Arguments) bool {
+	return slices.EqualFunc(a, b, func(x, y Argument) bool { return x.Indexed == y.Indexed })
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
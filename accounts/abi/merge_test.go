@@ -0,0 +1,123 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"strings"
+	"testing"
+)
+
+const proxyABI = `[
+	{"type":"constructor","inputs":[{"name":"impl","type":"address"}]},
+	{"type":"fallback","stateMutability":"payable"},
+	{"type":"function","name":"upgradeTo","inputs":[{"name":"impl","type":"address"}],"outputs":[]},
+	{"type":"function","name":"owner","inputs":[],"outputs":[{"name":"","type":"address"}],"stateMutability":"view"},
+	{"type":"event","name":"Upgraded","inputs":[{"name":"impl","type":"address","indexed":true}]},
+	{"type":"error","name":"Unauthorized","inputs":[]}
+]`
+
+const implABI = `[
+	{"type":"constructor","inputs":[]},
+	{"type":"function","name":"owner","inputs":[],"outputs":[{"name":"o","type":"address"}],"stateMutability":"view"},
+	{"type":"function","name":"upgradeTo","inputs":[{"name":"impl","type":"address"},{"name":"data","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"error","name":"Unauthorized","inputs":[]},
+	{"type":"error","name":"InsufficientBalance","inputs":[{"name":"have","type":"uint256"}]}
+]`
+
+func mustParse(t *testing.T, def string) ABI {
+	t.Helper()
+	abi, err := JSON(strings.NewReader(def))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return abi
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	proxy, impl := mustParse(t, proxyABI), mustParse(t, implABI)
+	merged, err := Merge(proxy, impl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Identical duplicates are merged, overloads of the later ABI get a suffix.
+	want := map[string]string{
+		"owner":      "owner()",
+		"transfer":   "transfer(address,uint256)",
+		"upgradeTo":  "upgradeTo(address)",
+		"upgradeTo0": "upgradeTo(address,bytes)",
+	}
+	if len(merged.Methods) != len(want) {
+		t.Fatalf("wrong number of methods: have %d, want %d", len(merged.Methods), len(want))
+	}
+	for name, sig := range want {
+		if m, ok := merged.Methods[name]; !ok || m.Sig != sig || m.Name != name {
+			t.Errorf("method %s: have %+v, want signature %s", name, m, sig)
+		}
+	}
+	if len(merged.Events) != 2 || len(merged.Errors) != 2 {
+		t.Fatalf("wrong number of events/errors: %d/%d", len(merged.Events), len(merged.Errors))
+	}
+	// Calls of both contracts can be decoded.
+	for _, abi := range []ABI{proxy, impl} {
+		for _, m := range abi.Methods {
+			if found, err := merged.MethodById(m.ID); err != nil || found.Sig != m.Sig {
+				t.Errorf("can't find method %s: %v", m.Sig, err)
+			}
+		}
+	}
+	// Special functions are taken from the first ABI.
+	if len(merged.Constructor.Inputs) != 1 {
+		t.Error("wrong constructor")
+	}
+	if !merged.HasFallback() || merged.HasReceive() {
+		t.Error("wrong fallback/receive")
+	}
+	// Merging is deterministic.
+	again, _ := Merge(proxy, impl)
+	for name, m := range merged.Methods {
+		if again.Methods[name].Sig != m.Sig {
+			t.Fatalf("merge not deterministic for %s", name)
+		}
+	}
+}
+
+func TestMergeCollision(t *testing.T) {
+	t.Parallel()
+
+	for i, test := range []struct{ a, b string }{
+		{ // Same signature, different outputs.
+			proxyABI,
+			`[{"type":"function","name":"owner","inputs":[],"outputs":[{"name":"","type":"uint256"}]}]`,
+		},
+		{ // Same event signature, different indexed arguments.
+			proxyABI,
+			`[{"type":"event","name":"Upgraded","inputs":[{"name":"impl","type":"address","indexed":false}]}]`,
+		},
+		{ // Different signatures with the same selector.
+			`[{"type":"function","name":"burn","inputs":[{"name":"","type":"uint256"}],"outputs":[]}]`,
+			`[{"type":"function","name":"collate_propagate_storage","inputs":[{"name":"","type":"bytes16"}],"outputs":[]}]`,
+		},
+	} {
+		if _, err := Merge(mustParse(t, test.a), mustParse(t, test.b)); err == nil {
+			t.Errorf("test %d: expected collision error", i)
+		}
+	}
+}
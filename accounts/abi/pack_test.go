@@ -213,3 +213,160 @@ func TestPackNumber(t *testing.T) {
 		}
 	}
 }
+
+func TestPackArgumentMismatch(t *testing.T) {
+	t.Parallel()
+
+	var nilBig *big.Int
+	tests := []struct {
+		typ   string
+		name  string
+		value interface{}
+		err   string
+	}{
+		{"bytes", "data", 1, "abi: invalid argument data: cannot use int as type bytes"},
+		{"bytes", "data", "0x01", "abi: invalid argument data: cannot use string as type bytes"},
+		{"bytes", "data", [2]byte{1, 2}, "abi: invalid argument data: cannot use [2]uint8 as type bytes"},
+		{"string", "name", []byte("hello"), "abi: invalid argument name: cannot use []uint8 as type string"},
+		{"string", "name", 42, "abi: invalid argument name: cannot use int as type string"},
+		{"uint256", "amount", "100", "abi: invalid argument amount: cannot use string as type uint256"},
+		{"uint256", "amount", nilBig, "abi: invalid argument amount: nil value for type uint256"},
+		{"uint256", "amount", nil, "abi: invalid argument amount: nil value for type uint256"},
+		{"int8", "", true, "abi: invalid argument #0: cannot use bool as type int8"},
+		{"bool", "flag", 1, "abi: invalid argument flag: cannot use int as type bool"},
+		{"address", "to", "0x0000000000000000000000000000000000000001", "abi: invalid argument to: cannot use string as type address"},
+		{"address", "to", []byte{1}, "abi: invalid argument to: cannot use []uint8 as type address"},
+		{"bytes32", "hash", []byte{1}, "abi: invalid argument hash: cannot use []uint8 as type bytes32"},
+		{"uint256[]", "list", big.NewInt(1), "abi: invalid argument list: cannot use *big.Int as type uint256[]"},
+		{"uint256[2]", "list", 1, "abi: invalid argument list: cannot use int as type uint256[2]"},
+		// Valid values must pass the check.
+		{"bytes", "data", []byte{1}, ""},
+		{"string", "name", "hello", ""},
+		{"uint256", "amount", big.NewInt(1), ""},
+		{"uint8", "amount", uint8(1), ""},
+		{"address", "to", common.Address{1}, ""},
+		{"bytes32", "hash", common.Hash{1}, ""},
+		{"bool", "flag", new(bool), ""},
+	}
+	for i, test := range tests {
+		typ, err := NewType(test.typ, "", nil)
+		if err != nil {
+			t.Fatalf("test %d: invalid type %q: %v", i, test.typ, err)
+		}
+		args := Arguments{{Name: test.name, Type: typ}}
+		_, err = args.Pack(test.value)
+		if test.err == "" {
+			if err != nil {
+				t.Errorf("test %d (%s, %T): unexpected error: %v", i, test.typ, test.value, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.err {
+			t.Errorf("test %d (%s, %T): wrong error\nhave: %v\nwant: %s", i, test.typ, test.value, err, test.err)
+		}
+	}
+}
+
+func TestPackTupleSlice(t *testing.T) {
+	t.Parallel()
+
+	const def = `[{"type": "function", "name": "submit", "inputs": [{"name": "orders", "type": "tuple[]", "components": [
+		{"name": "amount", "type": "uint256"},
+		{"name": "legs", "type": "tuple[]", "components": [
+			{"name": "to", "type": "address"},
+			{"name": "memo", "type": "bytes"}
+		]}
+	]}]}]`
+	type leg struct {
+		Recipient common.Address `abi:"to"`
+		Memo      []byte
+	}
+	type order struct {
+		Legs   []leg
+		Amount *big.Int
+	}
+	abi, err := JSON(strings.NewReader(def))
+	if err != nil {
+		t.Fatal(err)
+	}
+	orders := []order{
+		{Amount: big.NewInt(1), Legs: []leg{{common.Address{1}, []byte("a")}}},
+		{Amount: big.NewInt(2), Legs: []leg{{common.Address{2}, nil}, {common.Address{3}, []byte("bc")}}},
+	}
+	want, err := abi.Pack("submit", orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The packed orders must decode to the same values.
+	values, err := abi.Methods["submit"].Inputs.Unpack(want[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := reflect.ValueOf(values[0])
+	if decoded.Len() != 2 {
+		t.Fatalf("wrong number of decoded orders %d", decoded.Len())
+	}
+	second := decoded.Index(1)
+	amount := second.FieldByName("Amount").Interface().(*big.Int)
+	memo := second.FieldByName("Legs").Index(1).FieldByName("Memo").Bytes()
+	if amount.Int64() != 2 || !bytes.Equal(memo, []byte("bc")) {
+		t.Fatalf("wrong decoded order %+v", second.Interface())
+	}
+	// Untyped slices and slices of pointers are packed the same way.
+	for _, v := range []interface{}{
+		[]interface{}{orders[0], &orders[1]},
+		[]*order{&orders[0], &orders[1]},
+	} {
+		packed, err := abi.Pack("submit", v)
+		if err != nil {
+			t.Fatalf("%T: %v", v, err)
+		}
+		if !bytes.Equal(packed, want) {
+			t.Errorf("%T: wrong encoding", v)
+		}
+	}
+	// Errors name the element and field.
+	type badLeg struct {
+		To   string
+		Memo []byte
+	}
+	type badOrder struct {
+		Amount *big.Int
+		Legs   []badLeg
+	}
+	type taggedOrder struct {
+		Amount *big.Int `abi:"value"`
+		Legs   []leg
+	}
+	tests := []struct {
+		value interface{}
+		err   string
+	}{
+		{
+			[]badOrder{{big.NewInt(1), nil}, {big.NewInt(2), []badLeg{{"0x01", nil}}}},
+			"abi: invalid argument orders[1].legs[0].to: cannot use string as type array as argument",
+		},
+		{
+			[]interface{}{orders[0], "order"},
+			"abi: invalid argument orders[1]: cannot use string as type struct as argument",
+		},
+		{
+			[]*order{&orders[0], nil},
+			"abi: invalid argument orders[1]: nil value for type (uint256,(address,bytes)[])",
+		},
+		{
+			[]order{{Legs: orders[0].Legs}},
+			"abi: invalid argument orders[0].amount: nil value for type uint256",
+		},
+		{
+			[]taggedOrder{{big.NewInt(1), nil}},
+			"abi: invalid argument orders[0]: struct: abi tag 'value' defined but not found in abi",
+		},
+	}
+	for i, test := range tests {
+		_, err := abi.Pack("submit", test.value)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("test %d: wrong error\nhave: %v\nwant: %s", i, err, test.err)
+		}
+	}
+}
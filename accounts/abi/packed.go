@@ -0,0 +1,136 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"fmt"
+)
+
+// DecodePacked decodes data in the non-standard packed mode of Solidity's
+// abi.encodePacked. The values are returned as the same Go types as Unpack returns
+// them.
+//
+// In packed mode, values are encoded with their minimal size and dynamic values have
+// no length prefix. Elements of arrays are padded to 32 bytes, but arrays don't have a
+// length prefix either. This makes the encoding ambiguous in general: decoding only
+// works if at most one of the types is dynamic (bytes, string or a slice), which then
+// takes all bytes not used by the static types. Tuples, nested arrays and arrays of
+// dynamic types can't be encoded in packed mode and are rejected.
+func DecodePacked(types []Type, data []byte) ([]interface{}, error) {
+	var (
+		sizes   = make([]int, len(types))
+		static  int
+		dynamic = -1
+	)
+	for i, t := range types {
+		size, err := packedSize(t)
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			if dynamic >= 0 {
+				return nil, fmt.Errorf("abi: ambiguous packed encoding with multiple dynamic types (%s at %d and %s at %d)", types[dynamic], dynamic, t, i)
+			}
+			dynamic = i
+		} else {
+			static += size
+		}
+		sizes[i] = size
+	}
+	// Assign the remaining bytes to the dynamic value.
+	switch {
+	case dynamic < 0 && len(data) != static:
+		return nil, fmt.Errorf("abi: packed data length %d doesn't match type sizes %d", len(data), static)
+	case dynamic >= 0 && len(data) < static:
+		return nil, fmt.Errorf("abi: packed data length %d too short for type sizes %d", len(data), static)
+	case dynamic >= 0:
+		sizes[dynamic] = len(data) - static
+		if types[dynamic].T == SliceTy && sizes[dynamic]%32 != 0 {
+			return nil, fmt.Errorf("abi: packed data length of %s is %d, not a multiple of 32", types[dynamic], sizes[dynamic])
+		}
+	}
+	// Decode all values.
+	values := make([]interface{}, len(types))
+	for i, t := range types {
+		v, err := decodePackedValue(t, data[:sizes[i]])
+		if err != nil {
+			return nil, fmt.Errorf("abi: cannot decode packed %s at %d: %v", t, i, err)
+		}
+		values[i] = v
+		data = data[sizes[i]:]
+	}
+	return values, nil
+}
+
+// packedSize returns the size of t in packed mode, or -1 for dynamic types.
+func packedSize(t Type) (int, error) {
+	switch t.T {
+	case IntTy, UintTy:
+		return t.Size / 8, nil
+	case BoolTy:
+		return 1, nil
+	case AddressTy:
+		return 20, nil
+	case FixedBytesTy:
+		return t.Size, nil
+	case FunctionTy:
+		return 24, nil
+	case StringTy, BytesTy:
+		return -1, nil
+	case ArrayTy, SliceTy:
+		switch t.Elem.T {
+		case IntTy, UintTy, BoolTy, AddressTy, FixedBytesTy, FunctionTy:
+		default:
+			return 0, fmt.Errorf("abi: type %s is not supported in packed mode", t)
+		}
+		if t.T == SliceTy {
+			return -1, nil
+		}
+		return t.Size * 32, nil
+	default:
+		return 0, fmt.Errorf("abi: type %s is not supported in packed mode", t)
+	}
+}
+
+// decodePackedValue decodes a single value from its packed encoding.
+func decodePackedValue(t Type, data []byte) (interface{}, error) {
+	switch t.T {
+	case StringTy:
+		return string(data), nil
+	case BytesTy:
+		return append([]byte{}, data...), nil
+	case ArrayTy, SliceTy:
+		// Array elements are padded like in the standard encoding.
+		return forEachUnpack(t, data, 0, len(data)/32)
+	}
+	// Pad the value to a full word and decode it like a standard value.
+	word := make([]byte, 32)
+	switch t.T {
+	case FixedBytesTy, FunctionTy:
+		copy(word, data)
+	case IntTy:
+		if len(data) > 0 && data[0]&0x80 != 0 {
+			for i := range word[:32-len(data)] {
+				word[i] = 0xff
+			}
+		}
+		copy(word[32-len(data):], data)
+	default:
+		copy(word[32-len(data):], data)
+	}
+	return toGoType(0, t, word)
+}
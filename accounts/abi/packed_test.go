@@ -0,0 +1,118 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func packedTypes(t *testing.T, names ...string) []Type {
+	t.Helper()
+	types := make([]Type, len(names))
+	for i, name := range names {
+		typ, err := NewType(name, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		types[i] = typ
+	}
+	return types
+}
+
+func TestDecodePacked(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		types []string
+		data  string
+		want  []interface{}
+	}{
+		{ // Example from the Solidity documentation.
+			[]string{"int16", "bytes1", "uint16", "string"},
+			"ffff42000348656c6c6f2c20776f726c6421",
+			[]interface{}{int16(-1), [1]byte{0x42}, uint16(3), "Hello, world!"},
+		},
+		{
+			[]string{"address", "bool", "int24", "uint256[2]"},
+			"00000000000000000000000000000000000000aa" + "01" + "fffffe" +
+				strings.Repeat("00", 31) + "05" + strings.Repeat("00", 31) + "06",
+			[]interface{}{common.Address{19: 0xaa}, true, big.NewInt(-2), [2]*big.Int{big.NewInt(5), big.NewInt(6)}},
+		},
+		{ // The dynamic value can be anywhere.
+			[]string{"bytes", "uint8"},
+			"0102037f",
+			[]interface{}{[]byte{1, 2, 3}, uint8(0x7f)},
+		},
+		{
+			[]string{"uint32", "bytes2[]"},
+			"00000001" + "abcd" + strings.Repeat("00", 30) + "ef01" + strings.Repeat("00", 30),
+			[]interface{}{uint32(1), [][2]byte{{0xab, 0xcd}, {0xef, 0x01}}},
+		},
+		{
+			[]string{"string", "int8"},
+			"80",
+			[]interface{}{"", int8(-128)},
+		},
+	}
+	for i, test := range tests {
+		have, err := DecodePacked(packedTypes(t, test.types...), common.FromHex(test.data))
+		if err != nil {
+			t.Errorf("test %d: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(have, test.want) {
+			t.Errorf("test %d: wrong values\nhave %v\nwant %v", i, have, test.want)
+		}
+	}
+}
+
+func TestDecodePackedErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		types []string
+		data  string
+	}{
+		{[]string{"string", "bytes"}, "0102"},   // two dynamic types
+		{[]string{"uint8[]", "string"}, "0102"}, // two dynamic types
+		{[]string{"uint8[2][]"}, ""},            // nested array
+		{[]string{"string[2]"}, ""},             // array of dynamic type
+		{[]string{"uint16", "address"}, "0102"}, // too short
+		{[]string{"uint16"}, "010203"},          // too long
+		{[]string{"uint8", "string"}, ""},       // too short for static part
+		{[]string{"uint256[]"}, "01"},           // partial array element
+		{[]string{"bool"}, "02"},                // invalid bool
+	}
+	for i, test := range tests {
+		if _, err := DecodePacked(packedTypes(t, test.types...), common.FromHex(test.data)); err == nil {
+			t.Errorf("test %d (%v): expected error", i, test.types)
+		}
+	}
+	// Tuples can't be encoded in packed mode.
+	tuple, err := NewType("tuple", "", []ArgumentMarshaling{{Name: "a", Type: "uint8"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DecodePacked([]Type{tuple}, []byte{1}); err == nil {
+		t.Error("expected error for tuple")
+	}
+}
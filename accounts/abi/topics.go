@@ -28,6 +28,22 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
+// UnresolvedTopic is the value of an indexed event parameter of dynamic type whose
+// topic only contains the keccak256 hash of the actual value.
+type UnresolvedTopic struct {
+	Hash common.Hash
+}
+
+// isHashedTopic reports whether indexed values of type t are stored as the hash of
+// their encoding instead of the value itself.
+func isHashedTopic(t Type) bool {
+	switch t.T {
+	case StringTy, BytesTy, SliceTy, ArrayTy:
+		return true
+	}
+	return false
+}
+
 // MakeTopics converts a filter query argument list into a filter topic set.
 func MakeTopics(query ...[]interface{}) ([][]common.Hash, error) {
 	topics := make([][]common.Hash, len(query))
@@ -19,6 +19,7 @@ package abi
 import (
 	"errors"
 	"fmt"
+	"go/format"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -266,6 +267,93 @@ func (t Type) GetType() reflect.Type {
 	}
 }
 
+// GoStructDef returns the Go source of a struct definition for a tuple type, using
+// the given name as the struct name. Fields are named after the tuple components and
+// tagged with their original names, so the struct can be used to unpack values of the
+// tuple. Nested tuples are emitted as additional struct definitions, named after their
+// Solidity struct name if known or after the enclosing struct and field otherwise.
+//
+// An empty string is returned for non-tuple types.
+func (t Type) GoStructDef(name string) string {
+	if t.T != TupleTy {
+		return ""
+	}
+	var defs []string
+	goStructDef(t, ToCamelCase(name), &defs, make(map[string]string))
+
+	src := strings.Join(defs, "\n")
+	if formatted, err := format.Source([]byte(src)); err == nil {
+		src = string(formatted)
+	}
+	return src
+}
+
+// goStructDef appends the struct definition of the tuple type t and any nested
+// tuples to defs, returning the name of the struct. Tuples already present in seen
+// are not defined again.
+func goStructDef(t Type, name string, defs *[]string, seen map[string]string) string {
+	id := t.TupleRawName + t.String()
+	if existing, ok := seen[id]; ok {
+		return existing
+	}
+	seen[id] = name
+
+	// Reserve the slot first so the outer struct precedes nested ones.
+	index := len(*defs)
+	*defs = append(*defs, "")
+
+	var (
+		b    strings.Builder
+		used = make(map[string]bool)
+	)
+	fmt.Fprintf(&b, "type %s struct {\n", name)
+	for i, elem := range t.TupleElems {
+		field := ResolveNameConflict(ToCamelCase(t.TupleRawNames[i]), func(s string) bool { return used[s] })
+		used[field] = true
+		typ := goFieldType(*elem, name+field, defs, seen)
+		fmt.Fprintf(&b, "\t%s %s `abi:\"%s\"`\n", field, typ, t.TupleRawNames[i])
+	}
+	b.WriteString("}\n")
+	(*defs)[index] = b.String()
+	return name
+}
+
+// goFieldType returns the Go type of a struct field holding values of the given
+// ABI type. Nested tuples without a Solidity struct name are named 'name'.
+func goFieldType(t Type, name string, defs *[]string, seen map[string]string) string {
+	switch t.T {
+	case TupleTy:
+		if t.TupleRawName != "" {
+			name = ToCamelCase(t.TupleRawName)
+		}
+		return goStructDef(t, name, defs, seen)
+	case ArrayTy:
+		return fmt.Sprintf("[%d]", t.Size) + goFieldType(*t.Elem, name, defs, seen)
+	case SliceTy:
+		return "[]" + goFieldType(*t.Elem, name, defs, seen)
+	case AddressTy:
+		return "common.Address"
+	case IntTy, UintTy:
+		switch t.Size {
+		case 8, 16, 32, 64:
+			if t.T == UintTy {
+				return fmt.Sprintf("uint%d", t.Size)
+			}
+			return fmt.Sprintf("int%d", t.Size)
+		}
+		return "*big.Int"
+	case FixedBytesTy:
+		return fmt.Sprintf("[%d]byte", t.Size)
+	case BytesTy:
+		return "[]byte"
+	case FunctionTy:
+		return "[24]byte"
+	default:
+		// string, bool types
+		return t.String()
+	}
+}
+
 // String implements Stringer.
 func (t Type) String() (out string) {
 	return t.stringKind
@@ -295,9 +383,16 @@ func (t Type) pack(v reflect.Value) ([]byte, error) {
 		}
 		var tail []byte
 		for i := 0; i < v.Len(); i++ {
-			val, err := t.Elem.pack(v.Index(i))
+			elem := v.Index(i)
+			if elem.Kind() == reflect.Interface {
+				elem = elem.Elem()
+			}
+			if !elem.IsValid() || (elem.Kind() == reflect.Ptr && elem.IsNil()) {
+				return nil, wrapPackPath(fmt.Errorf("abi: nil value for type %v", t.Elem), fmt.Sprintf("[%d]", i))
+			}
+			val, err := t.Elem.pack(elem)
 			if err != nil {
-				return nil, err
+				return nil, wrapPackPath(err, fmt.Sprintf("[%d]", i))
 			}
 			if !offsetReq {
 				ret = append(ret, val...)
@@ -333,9 +428,12 @@ func (t Type) pack(v reflect.Value) ([]byte, error) {
 			if !field.IsValid() {
 				return nil, fmt.Errorf("field %s for tuple not found in the given struct", t.TupleRawNames[i])
 			}
+			if field.Kind() == reflect.Ptr && field.IsNil() {
+				return nil, wrapPackPath(fmt.Errorf("abi: nil value for type %v", elem), "."+t.TupleRawNames[i])
+			}
 			val, err := elem.pack(field)
 			if err != nil {
-				return nil, err
+				return nil, wrapPackPath(err, "."+t.TupleRawNames[i])
 			}
 			if isDynamicType(*elem) {
 				ret = append(ret, packNum(reflect.ValueOf(offset))...)
@@ -378,3 +378,52 @@ func TestNewFixedBytesOver32(t *testing.T) {
 		t.Errorf("fixed bytes with size over 32 is not spec'd")
 	}
 }
+
+func TestGoStructDef(t *testing.T) {
+	t.Parallel()
+	typ, err := NewType("tuple", "struct Order", []ArgumentMarshaling{
+		{Name: "maker", Type: "address"},
+		{Name: "amount", Type: "uint256"},
+		{Name: "nonce", Type: "uint64"},
+		{Name: "type", Type: "int8"},
+		{Name: "salt", Type: "bytes32"},
+		{Name: "data", Type: "bytes"},
+		{Name: "asset", Type: "tuple", InternalType: "struct Order.Asset", Components: []ArgumentMarshaling{
+			{Name: "token", Type: "address"},
+			{Name: "ids", Type: "uint256[3]"},
+		}},
+		{Name: "fees", Type: "tuple[]", Components: []ArgumentMarshaling{
+			{Name: "recipient", Type: "address"},
+			{Name: "bps", Type: "uint16"},
+		}},
+		{Name: "_type", Type: "string"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "type Order struct {\n" +
+		"\tMaker  common.Address `abi:\"maker\"`\n" +
+		"\tAmount *big.Int       `abi:\"amount\"`\n" +
+		"\tNonce  uint64         `abi:\"nonce\"`\n" +
+		"\tType   int8           `abi:\"type\"`\n" +
+		"\tSalt   [32]byte       `abi:\"salt\"`\n" +
+		"\tData   []byte         `abi:\"data\"`\n" +
+		"\tAsset  OrderAsset     `abi:\"asset\"`\n" +
+		"\tFees   []OrderFees    `abi:\"fees\"`\n" +
+		"\tType0  string         `abi:\"_type\"`\n" +
+		"}\n\n" +
+		"type OrderAsset struct {\n" +
+		"\tToken common.Address `abi:\"token\"`\n" +
+		"\tIds   [3]*big.Int    `abi:\"ids\"`\n" +
+		"}\n\n" +
+		"type OrderFees struct {\n" +
+		"\tRecipient common.Address `abi:\"recipient\"`\n" +
+		"\tBps       uint16         `abi:\"bps\"`\n" +
+		"}\n"
+	if have := typ.GoStructDef("order"); have != want {
+		t.Errorf("wrong struct definition:\nhave:\n%s\nwant:\n%s", have, want)
+	}
+	if def := (Type{T: UintTy, Size: 256}).GoStructDef("Foo"); def != "" {
+		t.Errorf("expected empty definition for non-tuple type, got %q", def)
+	}
+}
@@ -58,6 +58,34 @@ const KeyStoreScheme = "keystore"
 // Maximum time between wallet refreshes (if filesystem notifications don't work).
 const walletRefreshCycle = 3 * time.Second
 
+// AccountEventType specifies the different event types emitted by the keystore
+// account subscription.
+type AccountEventType int
+
+const (
+	// AccountAdded is fired when a new key file is detected in the keystore, either
+	// created or imported through the keystore or dropped in by an external process.
+	AccountAdded AccountEventType = iota
+
+	// AccountRemoved is fired when a key file disappears from the keystore.
+	AccountRemoved
+
+	// AccountUnlocked is fired when the private key of an account is decrypted and
+	// held in memory.
+	AccountUnlocked
+
+	// AccountLocked is fired when an unlocked private key is dropped from memory,
+	// either explicitly or because its unlock timeout expired.
+	AccountLocked
+)
+
+// AccountEvent is an event fired by the keystore when an account is added or
+// removed, or when its unlock state changes.
+type AccountEvent struct {
+	Account accounts.Account // Account affected by the event
+	Type    AccountEventType // Type of event that happened
+}
+
 // KeyStore manages a key storage directory on disk.
 type KeyStore struct {
 	storage  keyStore                     // Storage backend, might be cleartext or encrypted
@@ -67,9 +95,12 @@ type KeyStore struct {
 
 	wallets     []accounts.Wallet       // Wallet wrappers around the individual key files
 	updateFeed  event.Feed              // Event feed to notify wallet additions/removals
+	accountFeed event.Feed              // Event feed to notify account changes
 	updateScope event.SubscriptionScope // Subscription scope tracking current live listeners
 	updating    bool                    // Whether the event notification loop is running
 
+	provider PassphraseProvider // Passphrase source for accounts used without passphrase
+
 	mu       sync.RWMutex
 	importMu sync.Mutex // Import Mutex locks the import to prevent two insertions from racing
 }
@@ -168,6 +199,12 @@ func (ks *KeyStore) refreshWallets() {
 	// Fire all wallet events and return
 	for _, event := range events {
 		ks.updateFeed.Send(event)
+
+		kind := AccountAdded
+		if event.Kind == accounts.WalletDropped {
+			kind = AccountRemoved
+		}
+		ks.accountFeed.Send(AccountEvent{Account: event.Wallet.Accounts()[0], Type: kind})
 	}
 }
 
@@ -189,6 +226,26 @@ func (ks *KeyStore) Subscribe(sink chan<- accounts.WalletEvent) event.Subscripti
 	return sub
 }
 
+// SubscribeAccounts creates an async subscription to receive notifications when
+// accounts are added to or removed from the keystore, or when they are locked or
+// unlocked. Changes of the key directory made by other processes are detected by
+// the same notification loop that drives wallet events.
+func (ks *KeyStore) SubscribeAccounts(sink chan<- AccountEvent) event.Subscription {
+	// We need the mutex to reliably start/stop the update loop
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	// Subscribe the caller and track the subscriber count
+	sub := ks.updateScope.Track(ks.accountFeed.Subscribe(sink))
+
+	// Subscribers require an active notification loop, start it
+	if !ks.updating {
+		ks.updating = true
+		go ks.updater()
+	}
+	return sub
+}
+
 // updater is responsible for maintaining an up-to-date list of wallets stored in
 // the keystore, and for firing wallet addition/removal events. It listens for
 // account change events from the underlying account cache, and also periodically
@@ -254,12 +311,22 @@ func (ks *KeyStore) Delete(a accounts.Account, passphrase string) error {
 func (ks *KeyStore) SignHash(a accounts.Account, hash []byte) ([]byte, error) {
 	// Look up the key to sign with and abort if it cannot be found
 	ks.mu.RLock()
-	defer ks.mu.RUnlock()
-
 	unlockedKey, found := ks.unlocked[a.Address]
 	if !found {
-		return nil, ErrLocked
+		provider := ks.provider
+		ks.mu.RUnlock()
+		if provider == nil {
+			return nil, ErrLocked
+		}
+		_, key, err := ks.getProvidedKey(a, provider)
+		if err != nil {
+			return nil, err
+		}
+		defer zeroKey(key.PrivateKey)
+		return crypto.Sign(hash, key.PrivateKey)
 	}
+	defer ks.mu.RUnlock()
+
 	// Sign the hash using plain ECDSA operations
 	return crypto.Sign(hash, unlockedKey.PrivateKey)
 }
@@ -268,12 +335,22 @@ func (ks *KeyStore) SignHash(a accounts.Account, hash []byte) ([]byte, error) {
 func (ks *KeyStore) SignTx(a accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
 	// Look up the key to sign with and abort if it cannot be found
 	ks.mu.RLock()
-	defer ks.mu.RUnlock()
-
 	unlockedKey, found := ks.unlocked[a.Address]
 	if !found {
-		return nil, ErrLocked
+		provider := ks.provider
+		ks.mu.RUnlock()
+		if provider == nil {
+			return nil, ErrLocked
+		}
+		_, key, err := ks.getProvidedKey(a, provider)
+		if err != nil {
+			return nil, err
+		}
+		defer zeroKey(key.PrivateKey)
+		return types.SignTx(tx, types.LatestSignerForChainID(chainID), key.PrivateKey)
 	}
+	defer ks.mu.RUnlock()
+
 	// Depending on the presence of the chain ID, sign with 2718 or homestead
 	signer := types.LatestSignerForChainID(chainID)
 	return types.SignTx(tx, signer, unlockedKey.PrivateKey)
@@ -315,7 +392,13 @@ func (ks *KeyStore) Lock(addr common.Address) error {
 	unl, found := ks.unlocked[addr]
 	ks.mu.Unlock()
 	if found {
-		ks.expire(addr, unl, time.Duration(0)*time.Nanosecond)
+		// Resolve the key file for the lock notification, the address alone
+		// is used if the file is gone already.
+		a, err := ks.Find(accounts.Account{Address: addr})
+		if err != nil {
+			a = accounts.Account{Address: addr}
+		}
+		ks.expire(a, unl, time.Duration(0)*time.Nanosecond)
 	}
 	return nil
 }
@@ -332,28 +415,38 @@ func (ks *KeyStore) TimedUnlock(a accounts.Account, passphrase string, timeout t
 	if err != nil {
 		return err
 	}
+	ks.unlock(a, key, timeout)
+	return nil
+}
 
+// unlock stores the decrypted key of an account, see TimedUnlock.
+func (ks *KeyStore) unlock(a accounts.Account, key *Key, timeout time.Duration) {
 	ks.mu.Lock()
-	defer ks.mu.Unlock()
 	u, found := ks.unlocked[a.Address]
 	if found {
 		if u.abort == nil {
 			// The address was unlocked indefinitely, so unlocking
 			// it with a timeout would be confusing.
+			ks.mu.Unlock()
 			zeroKey(key.PrivateKey)
-			return nil
+			return
 		}
 		// Terminate the expire goroutine and replace it below.
 		close(u.abort)
 	}
 	if timeout > 0 {
 		u = &unlocked{Key: key, abort: make(chan struct{})}
-		go ks.expire(a.Address, u, timeout)
+		go ks.expire(a, u, timeout)
 	} else {
 		u = &unlocked{Key: key}
 	}
 	ks.unlocked[a.Address] = u
-	return nil
+	ks.mu.Unlock()
+
+	// Only notify about the transition, not about timeout changes
+	if !found {
+		ks.accountFeed.Send(AccountEvent{Account: a, Type: AccountUnlocked})
+	}
 }
 
 // Find resolves the given account into a unique entry in the keystore.
@@ -374,7 +467,7 @@ func (ks *KeyStore) getDecryptedKey(a accounts.Account, auth string) (accounts.A
 	return a, key, err
 }
 
-func (ks *KeyStore) expire(addr common.Address, u *unlocked, timeout time.Duration) {
+func (ks *KeyStore) expire(a accounts.Account, u *unlocked, timeout time.Duration) {
 	t := time.NewTimer(timeout)
 	defer t.Stop()
 	select {
@@ -386,11 +479,16 @@ func (ks *KeyStore) expire(addr common.Address, u *unlocked, timeout time.Durati
 		// was launched with. we can check that using pointer equality
 		// because the map stores a new pointer every time the key is
 		// unlocked.
-		if ks.unlocked[addr] == u {
+		dropped := ks.unlocked[a.Address] == u
+		if dropped {
 			zeroKey(u.PrivateKey)
-			delete(ks.unlocked, addr)
+			delete(ks.unlocked, a.Address)
 		}
 		ks.mu.Unlock()
+
+		if dropped {
+			ks.accountFeed.Send(AccountEvent{Account: a, Type: AccountLocked})
+		}
 	}
 }
 
@@ -476,6 +574,21 @@ func (ks *KeyStore) Update(a accounts.Account, passphrase, newPassphrase string)
 	return ks.storage.StoreKey(a.URL.Path, key, newPassphrase)
 }
 
+// Reencrypt changes the passphrase of an existing account and re-encrypts its key
+// file with the given scrypt parameters. The new key file is written to a temporary
+// location and verified before it atomically replaces the old one.
+func (ks *KeyStore) Reencrypt(a accounts.Account, passphrase, newPassphrase string, scryptN, scryptP int) error {
+	a, key, err := ks.getDecryptedKey(a, passphrase)
+	if key != nil && key.PrivateKey != nil {
+		defer zeroKey(key.PrivateKey)
+	}
+	if err != nil {
+		return err
+	}
+	store := &keyStorePassphrase{filepath.Dir(a.URL.Path), scryptN, scryptP, false}
+	return store.StoreKey(a.URL.Path, key, newPassphrase)
+}
+
 // ImportPreSaleKey decrypts the given Ethereum presale wallet and stores
 // a key file in the key directory. The key file is encrypted with the same passphrase.
 func (ks *KeyStore) ImportPreSaleKey(keyJSON []byte, passphrase string) (accounts.Account, error) {
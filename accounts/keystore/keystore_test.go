@@ -17,6 +17,8 @@
 package keystore
 
 import (
+	crand "crypto/rand"
+	"encoding/json"
 	"math/rand"
 	"os"
 	"runtime"
@@ -70,6 +72,51 @@ func TestKeyStore(t *testing.T) {
 	}
 }
 
+func TestKeyStoreReencrypt(t *testing.T) {
+	t.Parallel()
+	_, ks := tmpKeyStore(t)
+
+	a, err := ks.NewAccount("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := ks.Export(a, "foo", "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldKey, err := DecryptKey(before, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Reencrypt(a, "bar", "baz", LightScryptN, LightScryptP); err != ErrDecrypt {
+		t.Fatalf("wrong error for bad passphrase: have %v, want %v", err, ErrDecrypt)
+	}
+	if err := ks.Reencrypt(a, "foo", "bar", LightScryptN, LightScryptP); err != nil {
+		t.Fatalf("Reencrypt error: %v", err)
+	}
+	keyjson, err := os.ReadFile(a.URL.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var enc encryptedKeyJSONV3
+	if err := json.Unmarshal(keyjson, &enc); err != nil {
+		t.Fatal(err)
+	}
+	if n := enc.Crypto.KDFParams["n"]; n != float64(LightScryptN) {
+		t.Errorf("wrong scrypt N: have %v, want %d", n, LightScryptN)
+	}
+	if _, err := DecryptKey(keyjson, "foo"); err != ErrDecrypt {
+		t.Errorf("old passphrase still decrypts the key: %v", err)
+	}
+	newKey, err := DecryptKey(keyjson, "bar")
+	if err != nil {
+		t.Fatalf("can't decrypt with new passphrase: %v", err)
+	}
+	if newKey.Address != oldKey.Address || newKey.PrivateKey.D.Cmp(oldKey.PrivateKey.D) != 0 {
+		t.Fatal("key changed after re-encryption")
+	}
+}
+
 func TestSign(t *testing.T) {
 	t.Parallel()
 	_, ks := tmpKeyStore(t)
@@ -343,6 +390,71 @@ func TestWalletNotifications(t *testing.T) {
 	checkEvents(t, wantEvents, events)
 }
 
+// Tests that account notifications are fired when accounts are added, removed,
+// unlocked or locked, including key files added by other processes.
+func TestAccountNotifications(t *testing.T) {
+	t.Parallel()
+	dir, ks := tmpKeyStore(t)
+
+	events := make(chan AccountEvent, 16)
+	sub := ks.SubscribeAccounts(events)
+	defer sub.Unsubscribe()
+
+	expect := func(typ AccountEventType, addr common.Address) {
+		t.Helper()
+		select {
+		case ev := <-events:
+			if ev.Type != typ || ev.Account.Address != addr {
+				t.Fatalf("wrong event: have %v/%x, want %v/%x", ev.Type, ev.Account.Address, typ, addr)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timeout waiting for event %v/%x", typ, addr)
+		}
+	}
+	// Created and imported accounts
+	a1, err := ks.NewAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(AccountAdded, a1.Address)
+
+	key, _ := crypto.GenerateKey()
+	a2, err := ks.ImportECDSA(key, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(AccountAdded, a2.Address)
+
+	// Unlocking and locking, both explicit and by timeout
+	if err := ks.Unlock(a1, ""); err != nil {
+		t.Fatal(err)
+	}
+	expect(AccountUnlocked, a1.Address)
+	if err := ks.Lock(a1.Address); err != nil {
+		t.Fatal(err)
+	}
+	expect(AccountLocked, a1.Address)
+
+	if err := ks.TimedUnlock(a2, "", 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	expect(AccountUnlocked, a2.Address)
+	expect(AccountLocked, a2.Address)
+
+	// Removed accounts
+	if err := ks.Delete(a1, ""); err != nil {
+		t.Fatal(err)
+	}
+	expect(AccountRemoved, a1.Address)
+
+	// Key files created outside of the keystore
+	_, a3, err := storeNewKey(&keyStorePassphrase{dir, veryLightScryptN, veryLightScryptP, false}, crand.Reader, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(AccountAdded, a3.Address)
+}
+
 // TestImportECDSA tests the import functionality of a keystore.
 func TestImportECDSA(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,136 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+)
+
+// ErrNoPassphrase is returned by passphrase providers which don't know the
+// passphrase of an account.
+var ErrNoPassphrase = errors.New("no passphrase available for account")
+
+var errNoProvider = errors.New("no passphrase provider set")
+
+// PassphraseProvider supplies the passphrases of keystore accounts. When set on a
+// keystore, it is consulted when a locked account is used for signing without an
+// explicit passphrase, and by KeyStore.UnlockWithProvider.
+type PassphraseProvider interface {
+	// Passphrase returns the passphrase of the given account. The account is
+	// resolved in the keystore, i.e. its URL points to the key file.
+	Passphrase(account accounts.Account) (string, error)
+}
+
+// PassphraseProviderFunc is an adapter to allow the use of ordinary functions as
+// passphrase providers.
+type PassphraseProviderFunc func(account accounts.Account) (string, error)
+
+// Passphrase calls f(account).
+func (f PassphraseProviderFunc) Passphrase(account accounts.Account) (string, error) {
+	return f(account)
+}
+
+// EnvPassphraseProvider reads passphrases from environment variables. The
+// passphrase of an account is stored in the variable named by Prefix followed by
+// the upper-case hex address without 0x prefix, e.g. KEYSTORE_PASSPHRASE_7EF5A6....
+type EnvPassphraseProvider struct {
+	Prefix string
+}
+
+// Passphrase implements PassphraseProvider.
+func (p EnvPassphraseProvider) Passphrase(account accounts.Account) (string, error) {
+	name := p.Prefix + strings.ToUpper(hex.EncodeToString(account.Address[:]))
+	passphrase, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("%w: %s not set", ErrNoPassphrase, name)
+	}
+	return passphrase, nil
+}
+
+// FilePassphraseProvider reads passphrases from files in a directory, as used by
+// secret managers which mount secrets into the filesystem. The passphrase of an
+// account is stored in the file named by the lower-case hex address without 0x
+// prefix. Trailing newlines are ignored.
+type FilePassphraseProvider struct {
+	Dir string
+}
+
+// Passphrase implements PassphraseProvider.
+func (p FilePassphraseProvider) Passphrase(account accounts.Account) (string, error) {
+	path := filepath.Join(p.Dir, hex.EncodeToString(account.Address[:]))
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: %s does not exist", ErrNoPassphrase, path)
+	} else if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// SetPassphraseProvider sets the provider consulted for the passphrase of locked
+// accounts. Passing nil removes the provider.
+//
+// With a provider set, SignHash and SignTx no longer fail for locked accounts, but
+// decrypt the key for the duration of the operation. Accounts can also be unlocked
+// using the provider via UnlockWithProvider. Methods taking a passphrase never
+// consult the provider, an empty passphrase is used as is.
+func (ks *KeyStore) SetPassphraseProvider(provider PassphraseProvider) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.provider = provider
+}
+
+// UnlockWithProvider unlocks the given account like TimedUnlock, using the passphrase
+// returned by the passphrase provider.
+func (ks *KeyStore) UnlockWithProvider(a accounts.Account, timeout time.Duration) error {
+	ks.mu.RLock()
+	provider := ks.provider
+	ks.mu.RUnlock()
+
+	if provider == nil {
+		return errNoProvider
+	}
+	a, key, err := ks.getProvidedKey(a, provider)
+	if err != nil {
+		return err
+	}
+	ks.unlock(a, key, timeout)
+	return nil
+}
+
+// getProvidedKey decrypts the key of the given account using the passphrase returned
+// by provider.
+func (ks *KeyStore) getProvidedKey(a accounts.Account, provider PassphraseProvider) (accounts.Account, *Key, error) {
+	a, err := ks.Find(a)
+	if err != nil {
+		return a, nil, err
+	}
+	passphrase, err := provider.Passphrase(a)
+	if err != nil {
+		return a, nil, err
+	}
+	key, err := ks.storage.GetKey(a.Address, a.URL.Path, passphrase)
+	return a, key, err
+}
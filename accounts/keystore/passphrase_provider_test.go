@@ -0,0 +1,159 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestPassphraseProvider(t *testing.T) {
+	t.Parallel()
+	_, ks := tmpKeyStore(t)
+
+	pass := "foo"
+	acc, err := ks.NewAccount(pass)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without a provider, locked accounts can't sign.
+	if _, err := ks.SignHash(accounts.Account{Address: acc.Address}, testSigData); err != ErrLocked {
+		t.Fatalf("wrong error signing without provider: %v", err)
+	}
+	var calls int
+	ks.SetPassphraseProvider(PassphraseProviderFunc(func(a accounts.Account) (string, error) {
+		calls++
+		if a.URL != acc.URL {
+			t.Errorf("provider called with unresolved account %v", a.URL)
+		}
+		return pass, nil
+	}))
+	if _, err := ks.SignHash(accounts.Account{Address: acc.Address}, testSigData); err != nil {
+		t.Fatal("signing with provider failed:", err)
+	}
+	tx := types.NewTransaction(0, common.Address{}, new(big.Int), 21000, new(big.Int), nil)
+	if _, err := ks.SignTx(accounts.Account{Address: acc.Address}, tx, big.NewInt(1)); err != nil {
+		t.Fatal("signing tx with provider failed:", err)
+	}
+	if _, unlocked := ks.unlocked[acc.Address]; unlocked {
+		t.Fatal("signing with provider unlocked the account")
+	}
+	// Explicit passphrases take precedence over the provider.
+	if _, err := ks.SignHashWithPassphrase(acc, "bar", testSigData); err != ErrDecrypt {
+		t.Fatalf("wrong error signing with wrong explicit passphrase: %v", err)
+	}
+	if _, err := ks.SignHashWithPassphrase(acc, pass, testSigData); err != nil {
+		t.Fatal("signing with explicit passphrase failed:", err)
+	}
+	if calls != 2 {
+		t.Fatalf("provider called %d times, want 2", calls)
+	}
+	// Unlocking with an explicit passphrase doesn't use the provider, even if
+	// it is empty.
+	if err := ks.Unlock(acc, ""); err != ErrDecrypt {
+		t.Fatalf("wrong error unlocking with empty passphrase: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("provider called %d times, want 2", calls)
+	}
+	// UnlockWithProvider uses the provider.
+	if err := ks.UnlockWithProvider(acc, 0); err != nil {
+		t.Fatal("unlock with provider failed:", err)
+	}
+	if _, unlocked := ks.unlocked[acc.Address]; !unlocked {
+		t.Fatal("account not unlocked")
+	}
+	if calls != 3 {
+		t.Fatalf("provider called %d times, want 3", calls)
+	}
+	// Errors of the provider are passed on.
+	ks.Lock(acc.Address)
+	ks.SetPassphraseProvider(PassphraseProviderFunc(func(a accounts.Account) (string, error) {
+		return "", ErrNoPassphrase
+	}))
+	if _, err := ks.SignHash(acc, testSigData); !errors.Is(err, ErrNoPassphrase) {
+		t.Fatalf("wrong error with failing provider: %v", err)
+	}
+}
+
+// This test checks that accounts with an empty passphrase can be used explicitly
+// while a passphrase provider is set.
+func TestPassphraseProviderEmptyPassphrase(t *testing.T) {
+	t.Parallel()
+	_, ks := tmpKeyStore(t)
+
+	acc, err := ks.NewAccount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks.SetPassphraseProvider(PassphraseProviderFunc(func(a accounts.Account) (string, error) {
+		return "", ErrNoPassphrase
+	}))
+	if _, err := ks.SignHashWithPassphrase(acc, "", testSigData); err != nil {
+		t.Fatal("signing with empty passphrase failed:", err)
+	}
+	if err := ks.Unlock(acc, ""); err != nil {
+		t.Fatal("unlock with empty passphrase failed:", err)
+	}
+	if _, err := ks.SignHash(acc, testSigData); err != nil {
+		t.Fatal("signing with unlocked account failed:", err)
+	}
+}
+
+func TestEnvPassphraseProvider(t *testing.T) {
+	var (
+		addr     = common.HexToAddress("0x7ef5a6135f1fd6a02593eedc869c6d41d934aef8")
+		provider = EnvPassphraseProvider{Prefix: "KEYSTORE_TEST_PASSPHRASE_"}
+	)
+	if _, err := provider.Passphrase(accounts.Account{Address: addr}); !errors.Is(err, ErrNoPassphrase) {
+		t.Fatalf("wrong error for missing variable: %v", err)
+	}
+	t.Setenv("KEYSTORE_TEST_PASSPHRASE_7EF5A6135F1FD6A02593EEDC869C6D41D934AEF8", "foo bar")
+	pass, err := provider.Passphrase(accounts.Account{Address: addr})
+	if err != nil || pass != "foo bar" {
+		t.Fatalf("wrong passphrase %q, err %v", pass, err)
+	}
+}
+
+func TestFilePassphraseProvider(t *testing.T) {
+	t.Parallel()
+	var (
+		dir      = t.TempDir()
+		addr     = common.HexToAddress("0x7ef5a6135f1fd6a02593eedc869c6d41d934aef8")
+		provider = FilePassphraseProvider{Dir: dir}
+	)
+	if _, err := provider.Passphrase(accounts.Account{Address: addr}); !errors.Is(err, ErrNoPassphrase) {
+		t.Fatalf("wrong error for missing file: %v", err)
+	}
+	file := filepath.Join(dir, strings.ToLower(hex.EncodeToString(addr[:])))
+	if err := os.WriteFile(file, []byte("foo bar\r\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	pass, err := provider.Passphrase(accounts.Account{Address: addr})
+	if err != nil || pass != "foo bar" {
+		t.Fatalf("wrong passphrase %q, err %v", pass, err)
+	}
+}
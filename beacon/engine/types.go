@@ -315,7 +315,7 @@ func BlockToExecutableData(block *types.Block, fees *big.Int, sidecars []*types.
 		Transactions:     encodeTransactions(block.Transactions()),
 		Random:           block.MixDigest(),
 		ExtraData:        block.Extra(),
-		Withdrawals:      block.Withdrawals(),
+		Withdrawals:      block.Body().Withdrawals,
 		BlobGasUsed:      block.BlobGasUsed(),
 		ExcessBlobGas:    block.ExcessBlobGas(),
 		ExecutionWitness: block.ExecutionWitness(),
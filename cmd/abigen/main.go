@@ -72,6 +72,14 @@ var (
 		Name:  "alias",
 		Usage: "Comma separated aliases for function and event renaming, e.g. original1=alias1, original2=alias2",
 	}
+	multicallFlag = &cli.BoolFlag{
+		Name:  "multicall",
+		Usage: "Generate aggregate call helpers for read-only methods, for use with Multicall3",
+	}
+	metadataFlag = &cli.StringFlag{
+		Name:  "metadata",
+		Usage: "Output file for the JSON metadata of the bound contracts (ABI, selectors, event topics, bytecode)",
+	}
 )
 
 var app = flags.NewApp("Ethereum ABI wrapper code generator")
@@ -88,6 +96,8 @@ func init() {
 		outFlag,
 		langFlag,
 		aliasFlag,
+		multicallFlag,
+		metadataFlag,
 	}
 	app.Action = abigen
 }
@@ -216,10 +226,21 @@ func abigen(c *cli.Context) error {
 		}
 	}
 	// Generate the contract binding
-	code, err := bind.Bind(types, abis, bins, sigs, c.String(pkgFlag.Name), lang, libs, aliases)
+	opts := bind.BindOptions{Multicall: c.Bool(multicallFlag.Name)}
+	code, err := bind.BindWithOptions(types, abis, bins, sigs, c.String(pkgFlag.Name), lang, libs, aliases, opts)
 	if err != nil {
 		utils.Fatalf("Failed to generate ABI binding: %v", err)
 	}
+	// Write the metadata for other languages if requested
+	if c.IsSet(metadataFlag.Name) {
+		metadata, err := bind.BindMetadata(types, abis, bins)
+		if err != nil {
+			utils.Fatalf("Failed to generate contract metadata: %v", err)
+		}
+		if err := os.WriteFile(c.String(metadataFlag.Name), append(metadata, '\n'), 0600); err != nil {
+			utils.Fatalf("Failed to write contract metadata: %v", err)
+		}
+	}
 	// Either flush it out to a file or display on the standard output
 	if !c.IsSet(outFlag.Name) {
 		fmt.Printf("%s\n", code)
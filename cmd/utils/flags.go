@@ -570,6 +570,11 @@ var (
 		Usage:    "Path to a JWT secret to use for authenticated RPC endpoints",
 		Category: flags.APICategory,
 	}
+	ManifestFileFlag = &flags.DirectoryFlag{
+		Name:     "manifest",
+		Usage:    "Path of a JSON file describing the endpoints of the running node (relative to the instance directory)",
+		Category: flags.APICategory,
+	}
 
 	// Logging and debug settings
 	EthStatsURLFlag = &cli.StringFlag{
@@ -643,6 +648,22 @@ var (
 		Value:    "",
 		Category: flags.APICategory,
 	}
+	HTTPShutdownGraceFlag = &cli.DurationFlag{
+		Name:     "http.shutdowngrace",
+		Usage:    "Time to drain in-flight HTTP-RPC requests on shutdown, new requests are rejected with status 503",
+		Category: flags.APICategory,
+	}
+	HTTPPprofTokenFlag = &cli.StringFlag{
+		Name:     "http.pprof.token",
+		Usage:    "Serve the pprof handlers on the HTTP-RPC server, protected by the given bearer token",
+		Category: flags.APICategory,
+	}
+	HTTPPprofPathFlag = &cli.StringFlag{
+		Name:     "http.pprof.path",
+		Usage:    "HTTP path on which the pprof handlers are served",
+		Value:    node.DefaultPprofPath,
+		Category: flags.APICategory,
+	}
 	GraphQLEnabledFlag = &cli.BoolFlag{
 		Name:     "graphql",
 		Usage:    "Enable GraphQL on the HTTP-RPC server. Note that GraphQL can only be started if an HTTP server is started as well.",
@@ -799,6 +820,11 @@ var (
 		Value:    30303,
 		Category: flags.NetworkingCategory,
 	}
+	DiscoveryAddrFlag = &cli.StringFlag{
+		Name:     "discovery.addr",
+		Usage:    "Use a custom UDP listening interface for P2P discovery",
+		Category: flags.NetworkingCategory,
+	}
 
 	// Console
 	JSpathFlag = &flags.DirectoryFlag{
@@ -1071,8 +1097,15 @@ func setListenAddress(ctx *cli.Context, cfg *p2p.Config) {
 	if ctx.IsSet(ListenPortFlag.Name) {
 		cfg.ListenAddr = fmt.Sprintf(":%d", ctx.Int(ListenPortFlag.Name))
 	}
-	if ctx.IsSet(DiscoveryPortFlag.Name) {
-		cfg.DiscAddr = fmt.Sprintf(":%d", ctx.Int(DiscoveryPortFlag.Name))
+	if ctx.IsSet(DiscoveryPortFlag.Name) || ctx.IsSet(DiscoveryAddrFlag.Name) {
+		// Discovery uses the TCP listening port unless a custom one is given.
+		port := strconv.Itoa(ctx.Int(DiscoveryPortFlag.Name))
+		if !ctx.IsSet(DiscoveryPortFlag.Name) {
+			if _, p, err := net.SplitHostPort(cfg.ListenAddr); err == nil {
+				port = p
+			}
+		}
+		cfg.DiscAddr = net.JoinHostPort(ctx.String(DiscoveryAddrFlag.Name), port)
 	}
 }
 
@@ -1142,6 +1175,15 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(HTTPPathPrefixFlag.Name) {
 		cfg.HTTPPathPrefix = ctx.String(HTTPPathPrefixFlag.Name)
 	}
+	if ctx.IsSet(HTTPShutdownGraceFlag.Name) {
+		cfg.HTTPShutdownGracePeriod = ctx.Duration(HTTPShutdownGraceFlag.Name)
+	}
+	if ctx.IsSet(HTTPPprofTokenFlag.Name) {
+		cfg.HTTPPprofToken = ctx.String(HTTPPprofTokenFlag.Name)
+	}
+	if ctx.IsSet(HTTPPprofPathFlag.Name) {
+		cfg.HTTPPprofPath = ctx.String(HTTPPprofPathFlag.Name)
+	}
 	if ctx.IsSet(AllowUnprotectedTxs.Name) {
 		cfg.AllowUnprotectedTxs = ctx.Bool(AllowUnprotectedTxs.Name)
 	}
@@ -1332,6 +1374,9 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(JWTSecretFlag.Name) {
 		cfg.JWTSecret = ctx.String(JWTSecretFlag.Name)
 	}
+	if ctx.IsSet(ManifestFileFlag.Name) {
+		cfg.ManifestFile = ctx.String(ManifestFileFlag.Name)
+	}
 	if ctx.IsSet(EnablePersonal.Name) {
 		log.Warn(fmt.Sprintf("Option --%s is deprecated. The 'personal' RPC namespace has been removed.", EnablePersonal.Name))
 	}
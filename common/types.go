@@ -237,6 +237,25 @@ func IsHexAddress(s string) bool {
 	return len(s) == 2*AddressLength && isHex(s)
 }
 
+// ParseAddressStrict parses a hex-encoded address, with or without 0x prefix. In
+// contrast to HexToAddress, the input must have the exact length of an address.
+// Mixed-case input must carry a valid EIP-55 checksum, while all-lowercase and
+// all-uppercase input is accepted as is.
+func ParseAddressStrict(s string) (Address, error) {
+	if !IsHexAddress(s) {
+		return Address{}, fmt.Errorf("invalid address %q", s)
+	}
+	hex := s
+	if has0xPrefix(hex) {
+		hex = hex[2:]
+	}
+	addr := BytesToAddress(Hex2Bytes(hex))
+	if hex != strings.ToLower(hex) && hex != strings.ToUpper(hex) && hex != addr.Hex()[2:] {
+		return Address{}, fmt.Errorf("invalid address checksum %q", s)
+	}
+	return addr, nil
+}
+
 // Cmp compares two addresses.
 func (a Address) Cmp(other Address) int {
 	return bytes.Compare(a[:], other[:])
@@ -65,6 +65,41 @@ func TestIsHexAddress(t *testing.T) {
 	}
 }
 
+func TestParseAddressStrict(t *testing.T) {
+	tests := []struct {
+		str string
+		ok  bool
+	}{
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", true},
+		{"5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", true},
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", true},
+		{"0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED", true},
+		{"0X5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED", true},
+		{"0x0000000000000000000000000000000000000000", true},
+		// wrong checksum
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", false},
+		{"0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", false},
+		// invalid hex
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beae", false},
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed00", false},
+		{"0xxaaeb6053f3e94c9b9a09f33669435e7ef1beaed", false},
+		{"", false},
+	}
+	want := HexToAddress("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	for _, test := range tests {
+		addr, err := ParseAddressStrict(test.str)
+		if test.ok && err != nil {
+			t.Errorf("ParseAddressStrict(%s) failed: %v", test.str, err)
+		}
+		if !test.ok && err == nil {
+			t.Errorf("ParseAddressStrict(%s) succeeded, expected error", test.str)
+		}
+		if err == nil && addr != want && addr != (Address{}) {
+			t.Errorf("ParseAddressStrict(%s) == %v", test.str, addr)
+		}
+	}
+}
+
 func TestHashJsonValidation(t *testing.T) {
 	var tests = []struct {
 		Prefix string
@@ -0,0 +1,80 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// CreateAccessList computes the EIP-2930 access list of the given message by
+// repeatedly executing it on top of the EVM's state, feeding the addresses and
+// storage slots touched by each run into the next one until the list no longer
+// changes. The sender, the recipient (or created contract) and the active
+// precompiles are never included, since they are warm by definition.
+//
+// Every execution is reverted, the state of the EVM is left untouched. Besides the
+// access list, the gas used by the final run and its EVM-level error, if any, are
+// returned. A non-nil err indicates the message could not be applied at all.
+func CreateAccessList(ctx context.Context, evm *vm.EVM, msg *Message) (acl types.AccessList, gasUsed uint64, vmErr error, err error) {
+	var to common.Address
+	if msg.To != nil {
+		to = *msg.To
+	} else {
+		to = crypto.CreateAddress(msg.From, msg.Nonce)
+	}
+	// Retrieve the precompiles since they don't need to be added to the access list
+	rules := evm.ChainConfig().Rules(evm.Context.BlockNumber, evm.Context.Random != nil, evm.Context.Time)
+	precompiles := vm.ActivePrecompiles(rules)
+
+	// Restore the original tracer once done
+	defer evm.SetTracer(evm.Config.Tracer)
+
+	// Create an initial tracer
+	prevTracer := vm.NewAccessListTracer(msg.AccessList, msg.From, to, precompiles)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, 0, nil, err
+		}
+		// Retrieve the current access list to expand
+		accessList := prevTracer.AccessList()
+		log.Trace("Creating access list", "input", accessList)
+
+		// Apply the message with the current access list, tracing any new accesses
+		run := *msg
+		run.AccessList = accessList
+
+		tracer := vm.NewAccessListTracer(accessList, msg.From, to, precompiles)
+		evm.SetTracer(tracer.Hooks())
+
+		snapshot := evm.StateDB.Snapshot()
+		res, err := ApplyMessage(evm, &run, new(GasPool).AddGas(run.GasLimit))
+		evm.StateDB.RevertToSnapshot(snapshot)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		if tracer.Equal(prevTracer) {
+			return accessList, res.UsedGas, res.Err, nil
+		}
+		prevTracer = tracer
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+func TestCreateAccessList(t *testing.T) {
+	var (
+		sender   = common.HexToAddress("0xaa")
+		other    = common.HexToAddress("0xbb")
+		contract = common.HexToAddress("0xcc")
+		// SLOAD(1), BALANCE(0xbb), BALANCE(ecrecover), BALANCE(sender), STOP
+		code = []byte{
+			byte(vm.PUSH1), 0x01, byte(vm.SLOAD), byte(vm.POP),
+			byte(vm.PUSH1), 0xbb, byte(vm.BALANCE), byte(vm.POP),
+			byte(vm.PUSH1), 0x01, byte(vm.BALANCE), byte(vm.POP),
+			byte(vm.PUSH1), 0xaa, byte(vm.BALANCE), byte(vm.POP),
+			byte(vm.STOP),
+		}
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	statedb.SetBalance(sender, uint256.NewInt(params.Ether), tracing.BalanceChangeUnspecified)
+	statedb.SetCode(contract, code)
+
+	blockCtx := vm.BlockContext{
+		CanTransfer: CanTransfer,
+		Transfer:    Transfer,
+		BlockNumber: new(big.Int),
+		BaseFee:     new(big.Int),
+		Random:      &common.Hash{},
+		GasLimit:    30_000_000,
+	}
+	evm := vm.NewEVM(blockCtx, statedb, params.MergedTestChainConfig, vm.Config{})
+	msg := &Message{
+		From:      sender,
+		To:        &contract,
+		Value:     new(big.Int),
+		GasLimit:  100_000,
+		GasPrice:  new(big.Int),
+		GasFeeCap: new(big.Int),
+		GasTipCap: new(big.Int),
+		// Precompiles in the initial access list are dropped.
+		AccessList: types.AccessList{{Address: common.BytesToAddress([]byte{0x02})}},
+	}
+	acl, gasUsed, vmErr, err := CreateAccessList(context.Background(), evm, msg)
+	if err != nil {
+		t.Fatalf("failed to create access list: %v", err)
+	}
+	if vmErr != nil {
+		t.Fatalf("unexpected execution error: %v", vmErr)
+	}
+	slices.SortFunc(acl, func(a, b types.AccessTuple) int { return a.Address.Cmp(b.Address) })
+	want := types.AccessList{
+		{Address: other, StorageKeys: []common.Hash{}},
+		{Address: contract, StorageKeys: []common.Hash{common.BigToHash(big.NewInt(1))}},
+	}
+	if len(acl) != len(want) {
+		t.Fatalf("wrong access list length: have %d, want %d", len(acl), len(want))
+	}
+	for i := range want {
+		if acl[i].Address != want[i].Address || !slices.Equal(acl[i].StorageKeys, want[i].StorageKeys) {
+			t.Errorf("access list entry %d mismatch: have %v, want %v", i, acl[i], want[i])
+		}
+	}
+	// Intrinsic gas, the access list cost, four PUSH1/POP pairs and four warm accesses.
+	wantGas := params.TxGas + params.TxAccessListAddressGas*2 + params.TxAccessListStorageKeyGas +
+		4*(3+2) + 4*params.WarmStorageReadCostEIP2929
+	if gasUsed != wantGas {
+		t.Errorf("wrong gas used: have %d, want %d", gasUsed, wantGas)
+	}
+	// The state should be left untouched.
+	if nonce := statedb.GetNonce(sender); nonce != 0 {
+		t.Errorf("sender nonce modified: have %d, want 0", nonce)
+	}
+	if evm.Config.Tracer != nil {
+		t.Error("tracer not restored")
+	}
+}
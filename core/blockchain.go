@@ -268,6 +268,14 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 	if cacheConfig == nil {
 		cacheConfig = defaultCacheConfig
 	}
+	// Execution limits meant for simulations alter the execution results, so they
+	// would make the chain reject valid blocks.
+	if vmConfig.MaxSteps != 0 {
+		return nil, errors.New("vm step limit not allowed for block processing")
+	}
+	if vmConfig.MaxMemorySize != 0 {
+		return nil, errors.New("vm memory limit not allowed for block processing")
+	}
 	// Open trie database with provided config
 	enableVerkle, err := EnableVerkleAtGenesis(db, genesis)
 	if err != nil {
@@ -125,8 +125,8 @@ var (
 // EIP-7702 state transition errors.
 // Note these are just informational, and do not cause tx execution abort.
 var (
-	ErrAuthorizationWrongChainID       = errors.New("EIP-7702 authorization chain ID mismatch")
-	ErrAuthorizationNonceOverflow      = errors.New("EIP-7702 authorization nonce > 64 bit")
+	ErrAuthorizationWrongChainID       = types.ErrAuthorizationWrongChainID
+	ErrAuthorizationNonceOverflow      = types.ErrAuthorizationNonceOverflow
 	ErrAuthorizationInvalidSignature   = errors.New("EIP-7702 authorization has invalid signature")
 	ErrAuthorizationDestinationHasCode = errors.New("EIP-7702 authorization destination is a contract")
 	ErrAuthorizationNonceMismatch      = errors.New("EIP-7702 authorization nonce does not match current account nonce")
@@ -262,6 +262,9 @@ func (o *ChainOverrides) apply(cfg *params.ChainConfig) (*params.ChainConfig, er
 	if err := cpy.CheckConfigForkOrder(); err != nil {
 		return nil, err
 	}
+	if err := cpy.CheckSizeLimits(); err != nil {
+		return nil, err
+	}
 	return &cpy, nil
 }
 
@@ -513,6 +516,9 @@ func (g *Genesis) Commit(db ethdb.Database, triedb *triedb.Database) (*types.Blo
 	if err := config.CheckConfigForkOrder(); err != nil {
 		return nil, err
 	}
+	if err := config.CheckSizeLimits(); err != nil {
+		return nil, err
+	}
 	if config.Clique != nil && len(g.ExtraData) < 32+crypto.SignatureLength {
 		return nil, errors.New("can't start clique chain without signers")
 	}
@@ -0,0 +1,161 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// AllocDiff is the difference between two genesis allocations. All lists are
+// sorted by address, respectively storage slot.
+type AllocDiff struct {
+	Added   []common.Address `json:"added,omitempty"`   // Accounts only present in the second allocation
+	Removed []common.Address `json:"removed,omitempty"` // Accounts only present in the first allocation
+	Changed []AccountDiff    `json:"changed,omitempty"` // Accounts present in both, but with different content
+}
+
+// Empty reports whether the compared allocations are equivalent.
+func (d *AllocDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// AccountDiff lists the changes of an account present in both compared
+// allocations. Fields which didn't change are nil.
+type AccountDiff struct {
+	Address common.Address  `json:"address"`
+	Balance *BalanceChange  `json:"balance,omitempty"`
+	Nonce   *NonceChange    `json:"nonce,omitempty"`
+	Code    *CodeChange     `json:"code,omitempty"`
+	Storage []StorageChange `json:"storage,omitempty"`
+}
+
+// BalanceChange is a change of an account balance.
+type BalanceChange struct {
+	From *hexutil.Big `json:"from"`
+	To   *hexutil.Big `json:"to"`
+}
+
+// NonceChange is a change of an account nonce.
+type NonceChange struct {
+	From hexutil.Uint64 `json:"from"`
+	To   hexutil.Uint64 `json:"to"`
+}
+
+// CodeChange is a change of the account code, identified by the code hashes.
+type CodeChange struct {
+	From common.Hash `json:"from"`
+	To   common.Hash `json:"to"`
+}
+
+// StorageChange is a change of a storage slot. Slots which are not present in an
+// allocation have the zero value.
+type StorageChange struct {
+	Slot common.Hash `json:"slot"`
+	From common.Hash `json:"from"`
+	To   common.Hash `json:"to"`
+}
+
+// DiffGenesisAlloc compares the genesis allocations a and b. The comparison is
+// based on the resulting state, e.g. a missing balance is equal to a zero balance
+// and a missing storage slot is equal to a slot holding zero. Private keys, which
+// are only used in tests, are ignored.
+func DiffGenesisAlloc(a, b types.GenesisAlloc) AllocDiff {
+	var diff AllocDiff
+	for addr, account := range a {
+		other, ok := b[addr]
+		if !ok {
+			diff.Removed = append(diff.Removed, addr)
+			continue
+		}
+		if change := diffAccount(addr, &account, &other); change != nil {
+			diff.Changed = append(diff.Changed, *change)
+		}
+	}
+	for addr := range b {
+		if _, ok := a[addr]; !ok {
+			diff.Added = append(diff.Added, addr)
+		}
+	}
+	slices.SortFunc(diff.Added, common.Address.Cmp)
+	slices.SortFunc(diff.Removed, common.Address.Cmp)
+	slices.SortFunc(diff.Changed, func(x, y AccountDiff) int {
+		return x.Address.Cmp(y.Address)
+	})
+	return diff
+}
+
+// diffAccount compares two versions of an account, returning nil if they're
+// equivalent.
+func diffAccount(addr common.Address, a, b *types.Account) *AccountDiff {
+	var (
+		diff    = AccountDiff{Address: addr}
+		changed bool
+	)
+	balanceA, balanceB := allocBalance(a), allocBalance(b)
+	if balanceA.Cmp(balanceB) != 0 {
+		diff.Balance = &BalanceChange{From: (*hexutil.Big)(balanceA), To: (*hexutil.Big)(balanceB)}
+		changed = true
+	}
+	if a.Nonce != b.Nonce {
+		diff.Nonce = &NonceChange{From: hexutil.Uint64(a.Nonce), To: hexutil.Uint64(b.Nonce)}
+		changed = true
+	}
+	if codeA, codeB := allocCodeHash(a), allocCodeHash(b); codeA != codeB {
+		diff.Code = &CodeChange{From: codeA, To: codeB}
+		changed = true
+	}
+	for slot, value := range a.Storage {
+		if other := b.Storage[slot]; other != value {
+			diff.Storage = append(diff.Storage, StorageChange{Slot: slot, From: value, To: other})
+		}
+	}
+	for slot, value := range b.Storage {
+		if _, ok := a.Storage[slot]; !ok && value != (common.Hash{}) {
+			diff.Storage = append(diff.Storage, StorageChange{Slot: slot, To: value})
+		}
+	}
+	if len(diff.Storage) > 0 {
+		slices.SortFunc(diff.Storage, func(x, y StorageChange) int {
+			return x.Slot.Cmp(y.Slot)
+		})
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return &diff
+}
+
+func allocBalance(account *types.Account) *big.Int {
+	if account.Balance == nil {
+		return new(big.Int)
+	}
+	return account.Balance
+}
+
+func allocCodeHash(account *types.Account) common.Hash {
+	if len(account.Code) == 0 {
+		return types.EmptyCodeHash
+	}
+	return crypto.Keccak256Hash(account.Code)
+}
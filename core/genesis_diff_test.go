@@ -0,0 +1,85 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestDiffGenesisAlloc(t *testing.T) {
+	var (
+		addr1 = common.HexToAddress("0x01")
+		addr2 = common.HexToAddress("0x02")
+		addr3 = common.HexToAddress("0x03")
+		addr4 = common.HexToAddress("0x04")
+		addr5 = common.HexToAddress("0x05")
+		slot1 = common.HexToHash("0x01")
+		slot2 = common.HexToHash("0x02")
+		slot3 = common.HexToHash("0x03")
+	)
+	a := types.GenesisAlloc{
+		addr1: {Balance: big.NewInt(1)},
+		addr2: {Balance: big.NewInt(1), Nonce: 1, Code: []byte{0x00}, Storage: map[common.Hash]common.Hash{
+			slot1: common.HexToHash("0x01"),
+			slot2: common.HexToHash("0x02"),
+		}},
+		addr3: {Storage: map[common.Hash]common.Hash{slot1: {}}},
+		addr5: {Balance: big.NewInt(1)},
+	}
+	b := types.GenesisAlloc{
+		addr2: {Balance: big.NewInt(2), Nonce: 1, Code: []byte{0x01}, Storage: map[common.Hash]common.Hash{
+			slot1: common.HexToHash("0x01"),
+			slot3: common.HexToHash("0x03"),
+		}},
+		addr3: {Balance: new(big.Int), Storage: map[common.Hash]common.Hash{slot2: {}}},
+		addr4: {Balance: big.NewInt(1)},
+		addr5: {Balance: big.NewInt(1), Nonce: 2},
+	}
+	diff := DiffGenesisAlloc(a, b)
+	if diff.Empty() {
+		t.Fatal("diff is empty")
+	}
+	enc, err := json.Marshal(diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		from = crypto.Keccak256Hash([]byte{0x00}).Hex()
+		to   = crypto.Keccak256Hash([]byte{0x01}).Hex()
+		want = `{"added":["0x0000000000000000000000000000000000000004"],` +
+			`"removed":["0x0000000000000000000000000000000000000001"],` +
+			`"changed":[{"address":"0x0000000000000000000000000000000000000002",` +
+			`"balance":{"from":"0x1","to":"0x2"},` +
+			`"code":{"from":"` + from + `","to":"` + to + `"},` +
+			`"storage":[` +
+			`{"slot":"0x0000000000000000000000000000000000000000000000000000000000000002","from":"0x0000000000000000000000000000000000000000000000000000000000000002","to":"0x0000000000000000000000000000000000000000000000000000000000000000"},` +
+			`{"slot":"0x0000000000000000000000000000000000000000000000000000000000000003","from":"0x0000000000000000000000000000000000000000000000000000000000000000","to":"0x0000000000000000000000000000000000000000000000000000000000000003"}]},` +
+			`{"address":"0x0000000000000000000000000000000000000005","nonce":{"from":"0x0","to":"0x2"}}]}`
+	)
+	if string(enc) != want {
+		t.Fatalf("wrong diff:\nhave %s\nwant %s", enc, want)
+	}
+	if diff := DiffGenesisAlloc(b, b); !diff.Empty() {
+		t.Fatalf("diff of identical allocations not empty: %+v", diff)
+	}
+}
@@ -75,10 +75,16 @@ func (s *hookedStateDB) GetCodeSize(addr common.Address) int {
 
 func (s *hookedStateDB) AddRefund(u uint64) {
 	s.inner.AddRefund(u)
+	if s.hooks.OnGasRefundChange != nil && u != 0 {
+		s.hooks.OnGasRefundChange(int64(u), s.inner.GetRefund())
+	}
 }
 
 func (s *hookedStateDB) SubRefund(u uint64) {
 	s.inner.SubRefund(u)
+	if s.hooks.OnGasRefundChange != nil && u != 0 {
+		s.hooks.OnGasRefundChange(-int64(u), s.inner.GetRefund())
+	}
 }
 
 func (s *hookedStateDB) GetRefund() uint64 {
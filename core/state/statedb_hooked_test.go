@@ -90,6 +90,8 @@ func TestHooks(t *testing.T) {
 		"0xaa00000000000000000000000000000000000000.storage slot 0x0000000000000000000000000000000000000000000000000000000000000001: 0x0000000000000000000000000000000000000000000000000000000000000000 ->0x0000000000000000000000000000000000000000000000000000000000000011",
 		"0xaa00000000000000000000000000000000000000.storage slot 0x0000000000000000000000000000000000000000000000000000000000000001: 0x0000000000000000000000000000000000000000000000000000000000000011 ->0x0000000000000000000000000000000000000000000000000000000000000022",
 		"log 100",
+		"refund: 4800 (total 4800)",
+		"refund: -2800 (total 2000)",
 	}
 	emitF := func(format string, a ...any) {
 		result = append(result, fmt.Sprintf(format, a...))
@@ -110,6 +112,9 @@ func TestHooks(t *testing.T) {
 		OnLog: func(log *types.Log) {
 			emitF("log %v", log.TxIndex)
 		},
+		OnGasRefundChange: func(delta int64, total uint64) {
+			emitF("refund: %d (total %d)", delta, total)
+		},
 	})
 	sdb.AddBalance(common.Address{0xaa}, uint256.NewInt(100), tracing.BalanceChangeUnspecified)
 	sdb.SubBalance(common.Address{0xaa}, uint256.NewInt(50), tracing.BalanceChangeTransfer)
@@ -122,6 +127,12 @@ func TestHooks(t *testing.T) {
 	sdb.AddLog(&types.Log{
 		Address: common.Address{0xbb},
 	})
+	sdb.AddRefund(4800)
+	sdb.SubRefund(2800)
+	sdb.AddRefund(0)
+	if len(result) != len(wants) {
+		t.Fatalf("wrong number of events: have %d, want %d", len(result), len(wants))
+	}
 	for i, want := range wants {
 		if have := result[i]; have != want {
 			t.Fatalf("error event %d, have\n%v\nwant%v\n", i, have, want)
@@ -445,3 +445,55 @@ func GenerateBadBlock(parent *types.Block, engine consensus.Engine, txs types.Tr
 	}
 	return types.NewBlock(header, body, receipts, trie.NewStackTrie(nil))
 }
+
+// TestStateProcessorExecutionLimits checks that the execution limits of the vm config,
+// which are meant for simulations, don't affect block processing.
+func TestStateProcessorExecutionLimits(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr     = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.HexToAddress("0xaaaa")
+		gspec    = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				addr: {Balance: big.NewInt(params.Ether)},
+				// mstore(0x1000, 1)
+				contract: {Code: []byte{byte(vm.PUSH1), 1, byte(vm.PUSH2), 0x10, 0x00, byte(vm.MSTORE)}},
+			},
+		}
+		signer = types.LatestSigner(gspec.Config)
+	)
+	_, blocks, receipts := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 1, func(i int, b *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(0, contract, new(big.Int), 100000, b.BaseFee(), nil), signer, key)
+		b.AddTx(tx)
+	})
+	if receipts[0][0].Status != types.ReceiptStatusSuccessful {
+		t.Fatal("transaction failed")
+	}
+	// The limits are rejected by the blockchain.
+	for _, cfg := range []vm.Config{{MaxSteps: 1}, {MaxMemorySize: 32}} {
+		if _, err := NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), cfg, nil); err == nil {
+			t.Errorf("config %+v not rejected", cfg)
+		}
+	}
+	// The limits are ignored by the state processor.
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	statedb, err := chain.State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := chain.processor.Process(blocks[0], statedb, vm.Config{MaxSteps: 1, MaxMemorySize: 32})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := res.Receipts[0].Status, receipts[0][0].Status; have != want {
+		t.Errorf("wrong receipt status %d, want %d", have, want)
+	}
+	if have, want := res.GasUsed, blocks[0].GasUsed(); have != want {
+		t.Errorf("wrong gas used %d, want %d", have, want)
+	}
+}
@@ -37,6 +37,10 @@ type ExecutionResult struct {
 	RefundedGas uint64 // Total gas refunded after execution
 	Err         error  // Any error encountered during the execution(listed in core/vm/errors.go)
 	ReturnData  []byte // Returned data from evm(function result or data supplied with revert opcode)
+
+	// AccessSet contains all accounts and storage slots accessed by the message,
+	// if recording is enabled with vm.Config.RecordAccessSet.
+	AccessSet *types.AccessSet
 }
 
 // Unwrap returns the internal evm error which allows us for further
@@ -248,31 +252,26 @@ func (st *stateTransition) to() common.Address {
 func (st *stateTransition) buyGas() error {
 	mgval := new(big.Int).SetUint64(st.msg.GasLimit)
 	mgval.Mul(mgval, st.msg.GasPrice)
-	balanceCheck := new(big.Int).Set(mgval)
+	var (
+		feeCap  = st.msg.GasPrice
+		blobGas uint64
+	)
 	if st.msg.GasFeeCap != nil {
-		balanceCheck.SetUint64(st.msg.GasLimit)
-		balanceCheck = balanceCheck.Mul(balanceCheck, st.msg.GasFeeCap)
+		feeCap = st.msg.GasFeeCap
 	}
-	balanceCheck.Add(balanceCheck, st.msg.Value)
-
 	if st.evm.ChainConfig().IsCancun(st.evm.Context.BlockNumber, st.evm.Context.Time) {
-		if blobGas := st.blobGasUsed(); blobGas > 0 {
-			// Check that the user has enough funds to cover blobGasUsed * tx.BlobGasFeeCap
-			blobBalanceCheck := new(big.Int).SetUint64(blobGas)
-			blobBalanceCheck.Mul(blobBalanceCheck, st.msg.BlobGasFeeCap)
-			balanceCheck.Add(balanceCheck, blobBalanceCheck)
+		if blobGas = st.blobGasUsed(); blobGas > 0 {
 			// Pay for blobGasUsed * actual blob fee
 			blobFee := new(big.Int).SetUint64(blobGas)
 			blobFee.Mul(blobFee, st.evm.Context.BlobBaseFee)
 			mgval.Add(mgval, blobFee)
 		}
 	}
-	balanceCheckU256, overflow := uint256.FromBig(balanceCheck)
-	if overflow {
-		return fmt.Errorf("%w: address %v required balance exceeds 256 bits", ErrInsufficientFunds, st.msg.From.Hex())
-	}
-	if have, want := st.state.GetBalance(st.msg.From), balanceCheckU256; have.Cmp(want) < 0 {
-		return fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, st.msg.From.Hex(), have, want)
+	// Check that the user has enough funds to cover gasLimit * gasFeeCap + value,
+	// plus blobGasUsed * blobGasFeeCap.
+	balanceCheck := maxTxCost(st.msg.GasLimit, feeCap, st.msg.Value, blobGas, st.msg.BlobGasFeeCap)
+	if err := checkBalance(st.state, st.msg.From, balanceCheck); err != nil {
+		return err
 	}
 	if err := st.gp.SubGas(st.msg.GasLimit); err != nil {
 		return err
@@ -294,24 +293,14 @@ func (st *stateTransition) preCheck() error {
 	msg := st.msg
 	if !msg.SkipNonceChecks {
 		// Make sure this transaction's nonce is correct.
-		stNonce := st.state.GetNonce(msg.From)
-		if msgNonce := msg.Nonce; stNonce < msgNonce {
-			return fmt.Errorf("%w: address %v, tx: %d state: %d", ErrNonceTooHigh,
-				msg.From.Hex(), msgNonce, stNonce)
-		} else if stNonce > msgNonce {
-			return fmt.Errorf("%w: address %v, tx: %d state: %d", ErrNonceTooLow,
-				msg.From.Hex(), msgNonce, stNonce)
-		} else if stNonce+1 < stNonce {
-			return fmt.Errorf("%w: address %v, nonce: %d", ErrNonceMax,
-				msg.From.Hex(), stNonce)
+		if err := checkNonce(st.state, msg.From, msg.Nonce); err != nil {
+			return err
 		}
 	}
 	if !msg.SkipFromEOACheck {
 		// Make sure the sender is an EOA
-		code := st.state.GetCode(msg.From)
-		_, delegated := types.ParseDelegation(code)
-		if len(code) > 0 && !delegated {
-			return fmt.Errorf("%w: address %v, len(code): %d", ErrSenderNoEOA, msg.From.Hex(), len(code))
+		if err := checkSenderEOA(st.state, msg.From); err != nil {
+			return err
 		}
 	}
 	// Make sure that transaction gasFeeCap is greater than the baseFee (post london)
@@ -319,23 +308,10 @@ func (st *stateTransition) preCheck() error {
 		// Skip the checks if gas fields are zero and baseFee was explicitly disabled (eth_call)
 		skipCheck := st.evm.Config.NoBaseFee && msg.GasFeeCap.BitLen() == 0 && msg.GasTipCap.BitLen() == 0
 		if !skipCheck {
-			if l := msg.GasFeeCap.BitLen(); l > 256 {
-				return fmt.Errorf("%w: address %v, maxFeePerGas bit length: %d", ErrFeeCapVeryHigh,
-					msg.From.Hex(), l)
-			}
-			if l := msg.GasTipCap.BitLen(); l > 256 {
-				return fmt.Errorf("%w: address %v, maxPriorityFeePerGas bit length: %d", ErrTipVeryHigh,
-					msg.From.Hex(), l)
-			}
-			if msg.GasFeeCap.Cmp(msg.GasTipCap) < 0 {
-				return fmt.Errorf("%w: address %v, maxPriorityFeePerGas: %s, maxFeePerGas: %s", ErrTipAboveFeeCap,
-					msg.From.Hex(), msg.GasTipCap, msg.GasFeeCap)
-			}
-			// This will panic if baseFee is nil, but basefee presence is verified
-			// as part of header validation.
-			if msg.GasFeeCap.Cmp(st.evm.Context.BaseFee) < 0 {
-				return fmt.Errorf("%w: address %v, maxFeePerGas: %s, baseFee: %s", ErrFeeCapTooLow,
-					msg.From.Hex(), msg.GasFeeCap, st.evm.Context.BaseFee)
+			// The base fee is never nil here, its presence is verified as part
+			// of header validation.
+			if err := checkFeeCaps(msg.From, msg.GasFeeCap, msg.GasTipCap, st.evm.Context.BaseFee); err != nil {
+				return err
 			}
 		}
 	}
@@ -383,6 +359,80 @@ func (st *stateTransition) preCheck() error {
 	return st.buyGas()
 }
 
+// checkNonce checks that nonce is the next nonce of the sender account.
+func checkNonce(state vm.StateDB, from common.Address, nonce uint64) error {
+	stNonce := state.GetNonce(from)
+	if stNonce < nonce {
+		return fmt.Errorf("%w: address %v, tx: %d state: %d", ErrNonceTooHigh,
+			from.Hex(), nonce, stNonce)
+	} else if stNonce > nonce {
+		return fmt.Errorf("%w: address %v, tx: %d state: %d", ErrNonceTooLow,
+			from.Hex(), nonce, stNonce)
+	} else if stNonce+1 < stNonce {
+		return fmt.Errorf("%w: address %v, nonce: %d", ErrNonceMax,
+			from.Hex(), stNonce)
+	}
+	return nil
+}
+
+// checkSenderEOA checks that the sender has no code, other than a delegation.
+func checkSenderEOA(state vm.StateDB, from common.Address) error {
+	code := state.GetCode(from)
+	_, delegated := types.ParseDelegation(code)
+	if len(code) > 0 && !delegated {
+		return fmt.Errorf("%w: address %v, len(code): %d", ErrSenderNoEOA, from.Hex(), len(code))
+	}
+	return nil
+}
+
+// checkFeeCaps sanity checks the fee caps of a transaction and ensures that the fee
+// cap covers the base fee. The base fee check is skipped if baseFee is nil.
+func checkFeeCaps(from common.Address, feeCap, tipCap, baseFee *big.Int) error {
+	if l := feeCap.BitLen(); l > 256 {
+		return fmt.Errorf("%w: address %v, maxFeePerGas bit length: %d", ErrFeeCapVeryHigh,
+			from.Hex(), l)
+	}
+	if l := tipCap.BitLen(); l > 256 {
+		return fmt.Errorf("%w: address %v, maxPriorityFeePerGas bit length: %d", ErrTipVeryHigh,
+			from.Hex(), l)
+	}
+	if feeCap.Cmp(tipCap) < 0 {
+		return fmt.Errorf("%w: address %v, maxPriorityFeePerGas: %s, maxFeePerGas: %s", ErrTipAboveFeeCap,
+			from.Hex(), tipCap, feeCap)
+	}
+	if baseFee != nil && feeCap.Cmp(baseFee) < 0 {
+		return fmt.Errorf("%w: address %v, maxFeePerGas: %s, baseFee: %s", ErrFeeCapTooLow,
+			from.Hex(), feeCap, baseFee)
+	}
+	return nil
+}
+
+// maxTxCost returns the balance required to buy the gas of a transaction, which is
+// gasLimit * feeCap + value + blobGas * blobFeeCap.
+func maxTxCost(gasLimit uint64, feeCap, value *big.Int, blobGas uint64, blobFeeCap *big.Int) *big.Int {
+	cost := new(big.Int).SetUint64(gasLimit)
+	cost.Mul(cost, feeCap)
+	cost.Add(cost, value)
+	if blobGas > 0 {
+		blobCost := new(big.Int).SetUint64(blobGas)
+		blobCost.Mul(blobCost, blobFeeCap)
+		cost.Add(cost, blobCost)
+	}
+	return cost
+}
+
+// checkBalance checks that the balance of the sender covers cost.
+func checkBalance(state vm.StateDB, from common.Address, cost *big.Int) error {
+	want, overflow := uint256.FromBig(cost)
+	if overflow {
+		return fmt.Errorf("%w: address %v required balance exceeds 256 bits", ErrInsufficientFunds, from.Hex())
+	}
+	if have := state.GetBalance(from); have.Cmp(want) < 0 {
+		return fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, from.Hex(), have, want)
+	}
+	return nil
+}
+
 // execute will transition the state by applying the current message and
 // returning the evm execution result with following fields.
 //
@@ -447,8 +497,8 @@ func (st *stateTransition) execute() (*ExecutionResult, error) {
 	}
 
 	// Check whether the init code size has been exceeded.
-	if rules.IsShanghai && contractCreation && len(msg.Data) > params.MaxInitCodeSize {
-		return nil, fmt.Errorf("%w: code size %v limit %v", ErrMaxInitCodeSizeExceeded, len(msg.Data), params.MaxInitCodeSize)
+	if limit := st.evm.ChainConfig().InitCodeSizeLimit(); rules.IsShanghai && contractCreation && len(msg.Data) > limit {
+		return nil, fmt.Errorf("%w: code size %v limit %v", ErrMaxInitCodeSizeExceeded, len(msg.Data), limit)
 	}
 
 	// Execute the preparatory steps for state transition which includes:
@@ -524,18 +574,15 @@ func (st *stateTransition) execute() (*ExecutionResult, error) {
 		RefundedGas: gasRefund,
 		Err:         vmerr,
 		ReturnData:  ret,
+		AccessSet:   st.evm.AccessSet(),
 	}, nil
 }
 
 // validateAuthorization validates an EIP-7702 authorization against the state.
 func (st *stateTransition) validateAuthorization(auth *types.SetCodeAuthorization) (authority common.Address, err error) {
-	// Verify chain ID is null or equal to current chain ID.
-	if !auth.ChainID.IsZero() && auth.ChainID.CmpBig(st.evm.ChainConfig().ChainID) != 0 {
-		return authority, ErrAuthorizationWrongChainID
-	}
-	// Limit nonce to 2^64-1 per EIP-2681.
-	if auth.Nonce+1 < auth.Nonce {
-		return authority, ErrAuthorizationNonceOverflow
+	// Verify chain ID and nonce bounds.
+	if err := auth.Validate(st.evm.ChainConfig().ChainID); err != nil {
+		return authority, err
 	}
 	// Validate signature values and recover authority.
 	authority, err = auth.Authority()
@@ -109,6 +109,40 @@ type (
 	// GasChangeHook is invoked when the gas changes.
 	GasChangeHook = func(old, new uint64, reason GasChangeReason)
 
+	// GasRefundChangeHook is invoked when the refund counter of the transaction
+	// changes, e.g. when a storage slot is cleared. `delta` is the signed change and
+	// `total` the counter after the change. The counter is uncapped: the refund limit
+	// (EIP-3529 from London on) is applied at the end of the transaction, and the
+	// refund actually granted is reported via OnGasChange with GasChangeTxRefunds.
+	// Restoring the counter when a call frame reverts is not reported.
+	GasRefundChangeHook = func(delta int64, total uint64)
+
+	// SelfDestructHook is invoked when a contract executes SELFDESTRUCT, with the
+	// balance transferred to the beneficiary. `destroyed` reports whether the account
+	// is actually destroyed, which post-Cancun (EIP-6780) is only the case if the
+	// contract was created in the same transaction.
+	SelfDestructHook = func(contract, beneficiary common.Address, balance *big.Int, destroyed bool)
+
+	// TransientStorageReadHook is invoked when a contract reads its transient storage
+	// using TLOAD (EIP-1153).
+	TransientStorageReadHook = func(addr common.Address, slot common.Hash, value common.Hash)
+
+	// TransientStorageWriteHook is invoked when a contract writes its transient storage
+	// using TSTORE (EIP-1153). Transient storage is discarded at the end of the
+	// transaction, so these writes are not reported via OnStorageChange.
+	TransientStorageWriteHook = func(addr common.Address, slot common.Hash, prev, new common.Hash)
+
+	// BlobHashReadHook is invoked when a contract reads a versioned blob hash of the
+	// transaction using BLOBHASH (EIP-4844). The hash is zero if the index is out of
+	// range. Indices which don't fit into 64 bits are reported as math.MaxUint64.
+	BlobHashReadHook = func(index uint64, hash common.Hash)
+
+	// ContractCreationHook is invoked when a new contract is created by CREATE, CREATE2
+	// or a contract creation transaction (reported as CREATE), just before its init
+	// code runs. It isn't invoked if the creation fails before that, e.g. because
+	// of an address collision.
+	ContractCreationHook = func(creator, newAddr common.Address, initCodeHash common.Hash, opcode byte)
+
 	/*
 		- Chain events -
 	*/
@@ -176,13 +210,19 @@ type (
 
 type Hooks struct {
 	// VM events
-	OnTxStart   TxStartHook
-	OnTxEnd     TxEndHook
-	OnEnter     EnterHook
-	OnExit      ExitHook
-	OnOpcode    OpcodeHook
-	OnFault     FaultHook
-	OnGasChange GasChangeHook
+	OnTxStart               TxStartHook
+	OnTxEnd                 TxEndHook
+	OnEnter                 EnterHook
+	OnExit                  ExitHook
+	OnOpcode                OpcodeHook
+	OnFault                 FaultHook
+	OnGasChange             GasChangeHook
+	OnGasRefundChange       GasRefundChangeHook
+	OnSelfDestruct          SelfDestructHook
+	OnTransientStorageRead  TransientStorageReadHook
+	OnTransientStorageWrite TransientStorageWriteHook
+	OnBlobHashRead          BlobHashReadHook
+	OnContractCreation      ContractCreationHook
 	// Chain events
 	OnBlockchainInit    BlockchainInitHook
 	OnClose             CloseHook
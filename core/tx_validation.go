@@ -0,0 +1,85 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ValidateTxAgainstState checks whether tx sent by from would pass the pre-execution
+// checks of the state transition on top of the given state, i.e. whether it can be
+// included in a block with the given base fee. It checks that the transaction type
+// is enabled by the rules, the nonce matches the account nonce, the sender is an
+// EOA, the gas limit covers the intrinsic gas, the fee cap covers the base fee and
+// the balance covers the value plus the maximum gas and blob gas cost.
+//
+// The base fee check is skipped if baseFee is nil. The returned error wraps the
+// error of the failed check, e.g. ErrNonceTooLow or ErrInsufficientFunds.
+func ValidateTxAgainstState(tx *types.Transaction, from common.Address, state vm.StateDB, baseFee *big.Int, rules params.Rules) error {
+	if err := validateTxType(tx, rules); err != nil {
+		return err
+	}
+	// Make sure the nonce is correct and the sender is an EOA.
+	if err := checkNonce(state, from, tx.Nonce()); err != nil {
+		return err
+	}
+	if err := checkSenderEOA(state, from); err != nil {
+		return err
+	}
+	// Ensure the gas limit covers the intrinsic gas.
+	gas, err := IntrinsicGas(tx.Data(), tx.AccessList(), tx.SetCodeAuthorizations(), tx.To() == nil, rules.IsHomestead, rules.IsIstanbul, rules.IsShanghai)
+	if err != nil {
+		return err
+	}
+	if tx.Gas() < gas {
+		return fmt.Errorf("%w: have %d, want %d", ErrIntrinsicGas, tx.Gas(), gas)
+	}
+	// Sanity check the fee caps and ensure the fee cap covers the base fee.
+	if err := checkFeeCaps(from, tx.GasFeeCap(), tx.GasTipCap(), baseFee); err != nil {
+		return err
+	}
+	// Ensure the balance covers the value and the maximum cost of gas.
+	cost := maxTxCost(tx.Gas(), tx.GasFeeCap(), tx.Value(), tx.BlobGas(), tx.BlobGasFeeCap())
+	return checkBalance(state, from, cost)
+}
+
+// validateTxType checks that the type of tx is enabled by the rules.
+func validateTxType(tx *types.Transaction, rules params.Rules) error {
+	var enabled bool
+	switch tx.Type() {
+	case types.LegacyTxType:
+		enabled = true
+	case types.AccessListTxType:
+		enabled = rules.IsBerlin
+	case types.DynamicFeeTxType:
+		enabled = rules.IsLondon
+	case types.BlobTxType:
+		enabled = rules.IsCancun
+	case types.SetCodeTxType:
+		enabled = rules.IsPrague
+	}
+	if !enabled {
+		return fmt.Errorf("%w: type %d", ErrTxTypeNotSupported, tx.Type())
+	}
+	return nil
+}
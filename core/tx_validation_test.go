@@ -0,0 +1,90 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+func TestValidateTxAgainstState(t *testing.T) {
+	var (
+		from     = common.HexToAddress("0x1000")
+		contract = common.HexToAddress("0x2000")
+		to       = common.HexToAddress("0x3000")
+		rules    = params.TestChainConfig.Rules(common.Big0, true, 0)
+		london   = params.TestChainConfig.Rules(common.Big0, false, 0)
+	)
+	london.IsCancun, london.IsPrague, london.IsShanghai = false, false, false
+	london.IsBerlin, london.IsLondon = true, true
+	preLondon := london
+	preLondon.IsLondon = false
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	statedb.SetNonce(from, 5)
+	statedb.SetBalance(from, uint256.NewInt(21000*100+1000), tracing.BalanceChangeUnspecified)
+	statedb.SetNonce(contract, 5)
+	statedb.SetBalance(contract, uint256.NewInt(1e18), tracing.BalanceChangeUnspecified)
+	statedb.SetCode(contract, []byte{0x00})
+
+	dynamic := func(nonce, gas uint64, tip, feeCap, value int64) *types.Transaction {
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:   params.TestChainConfig.ChainID,
+			Nonce:     nonce,
+			Gas:       gas,
+			GasTipCap: big.NewInt(tip),
+			GasFeeCap: big.NewInt(feeCap),
+			To:        &to,
+			Value:     big.NewInt(value),
+		})
+	}
+	tests := []struct {
+		name    string
+		tx      *types.Transaction
+		from    common.Address
+		baseFee *big.Int
+		rules   params.Rules
+		err     error
+	}{
+		{"valid", dynamic(5, 21000, 1, 100, 1000), from, big.NewInt(100), rules, nil},
+		{"valid without base fee", dynamic(5, 21000, 1, 100, 1000), from, nil, rules, nil},
+		{"nonce too low", dynamic(4, 21000, 1, 100, 0), from, big.NewInt(100), rules, ErrNonceTooLow},
+		{"nonce too high", dynamic(6, 21000, 1, 100, 0), from, big.NewInt(100), rules, ErrNonceTooHigh},
+		{"insufficient funds for value", dynamic(5, 21000, 1, 100, 1001), from, big.NewInt(100), rules, ErrInsufficientFunds},
+		{"insufficient funds for gas", dynamic(5, 21001, 1, 100, 1000), from, big.NewInt(100), rules, ErrInsufficientFunds},
+		{"intrinsic gas too low", dynamic(5, 20999, 1, 100, 0), from, big.NewInt(100), rules, ErrIntrinsicGas},
+		{"fee cap too low", dynamic(5, 21000, 1, 100, 0), from, big.NewInt(101), rules, ErrFeeCapTooLow},
+		{"tip above fee cap", dynamic(5, 21000, 101, 100, 0), from, big.NewInt(100), rules, ErrTipAboveFeeCap},
+		{"sender not an eoa", dynamic(5, 21000, 1, 100, 0), contract, big.NewInt(100), rules, ErrSenderNoEOA},
+		{"dynamic fee tx before london", dynamic(5, 21000, 1, 100, 0), from, nil, preLondon, ErrTxTypeNotSupported},
+		{"dynamic fee tx in london", dynamic(5, 21000, 1, 100, 0), from, big.NewInt(100), london, nil},
+	}
+	for _, test := range tests {
+		err := ValidateTxAgainstState(test.tx, test.from, statedb, test.baseFee, test.rules)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: wrong error %v, want %v", test.name, err, test.err)
+		}
+	}
+}
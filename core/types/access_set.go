@@ -0,0 +1,139 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AccessSet is a set of accounts and storage slots, e.g. the ones touched by a
+// transaction. Unlike AccessList, it contains every account and slot at most once.
+// The zero value is not usable, create sets with NewAccessSet.
+type AccessSet struct {
+	accounts map[common.Address]map[common.Hash]struct{}
+	slots    int
+}
+
+// NewAccessSet creates an empty set.
+func NewAccessSet() *AccessSet {
+	return &AccessSet{accounts: make(map[common.Address]map[common.Hash]struct{})}
+}
+
+// NewAccessSetFromList creates a set containing the accounts and slots of the given
+// access list. Duplicate entries of the list are merged.
+func NewAccessSetFromList(al AccessList) *AccessSet {
+	s := NewAccessSet()
+	for _, tuple := range al {
+		s.AddAddress(tuple.Address)
+		for _, slot := range tuple.StorageKeys {
+			s.AddSlot(tuple.Address, slot)
+		}
+	}
+	return s
+}
+
+// AddAddress adds an account to the set. It reports whether the account was added,
+// i.e. it wasn't present before.
+func (s *AccessSet) AddAddress(addr common.Address) bool {
+	if _, ok := s.accounts[addr]; ok {
+		return false
+	}
+	s.accounts[addr] = make(map[common.Hash]struct{})
+	return true
+}
+
+// AddSlot adds a storage slot of an account to the set. The account is added as well
+// if it isn't present. It reports whether the slot was added.
+func (s *AccessSet) AddSlot(addr common.Address, slot common.Hash) bool {
+	s.AddAddress(addr)
+	slots := s.accounts[addr]
+	if _, ok := slots[slot]; ok {
+		return false
+	}
+	slots[slot] = struct{}{}
+	s.slots++
+	return true
+}
+
+// HasAddress reports whether the account is in the set.
+func (s *AccessSet) HasAddress(addr common.Address) bool {
+	_, ok := s.accounts[addr]
+	return ok
+}
+
+// HasSlot reports whether the storage slot of the account is in the set.
+func (s *AccessSet) HasSlot(addr common.Address, slot common.Hash) bool {
+	_, ok := s.accounts[addr][slot]
+	return ok
+}
+
+// Addresses returns the number of accounts in the set.
+func (s *AccessSet) Addresses() int {
+	return len(s.accounts)
+}
+
+// StorageKeys returns the total number of storage slots in the set.
+func (s *AccessSet) StorageKeys() int {
+	return s.slots
+}
+
+// Equal reports whether both sets contain the same accounts and slots.
+func (s *AccessSet) Equal(other *AccessSet) bool {
+	if len(s.accounts) != len(other.accounts) || s.slots != other.slots {
+		return false
+	}
+	for addr, slots := range s.accounts {
+		otherSlots, ok := other.accounts[addr]
+		if !ok || !maps.Equal(slots, otherSlots) {
+			return false
+		}
+	}
+	return true
+}
+
+// Copy returns a deep copy of the set.
+func (s *AccessSet) Copy() *AccessSet {
+	cpy := &AccessSet{
+		accounts: make(map[common.Address]map[common.Hash]struct{}, len(s.accounts)),
+		slots:    s.slots,
+	}
+	for addr, slots := range s.accounts {
+		cpy.accounts[addr] = maps.Clone(slots)
+	}
+	return cpy
+}
+
+// ToAccessList converts the set to an access list. The output is canonical: tuples
+// are sorted by address and the storage keys of each tuple are sorted as well.
+func (s *AccessSet) ToAccessList() AccessList {
+	al := make(AccessList, 0, len(s.accounts))
+	for addr, slots := range s.accounts {
+		keys := make([]common.Hash, 0, len(slots))
+		for slot := range slots {
+			keys = append(keys, slot)
+		}
+		slices.SortFunc(keys, common.Hash.Cmp)
+		al = append(al, AccessTuple{Address: addr, StorageKeys: keys})
+	}
+	slices.SortFunc(al, func(a, b AccessTuple) int {
+		return a.Address.Cmp(b.Address)
+	})
+	return al
+}
@@ -0,0 +1,130 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestAccessSet(t *testing.T) {
+	var (
+		addr1 = common.HexToAddress("0x01")
+		addr2 = common.HexToAddress("0x02")
+		slot1 = common.HexToHash("0x01")
+		slot2 = common.HexToHash("0x02")
+	)
+	s := NewAccessSet()
+	if !s.AddAddress(addr2) || s.AddAddress(addr2) {
+		t.Fatal("wrong AddAddress result")
+	}
+	if !s.AddSlot(addr1, slot2) || !s.AddSlot(addr1, slot1) || s.AddSlot(addr1, slot1) {
+		t.Fatal("wrong AddSlot result")
+	}
+	if !s.HasAddress(addr1) || !s.HasAddress(addr2) || s.HasAddress(common.Address{}) {
+		t.Fatal("wrong HasAddress result")
+	}
+	if !s.HasSlot(addr1, slot1) || s.HasSlot(addr2, slot1) || s.HasSlot(common.Address{}, slot1) {
+		t.Fatal("wrong HasSlot result")
+	}
+	if s.Addresses() != 2 || s.StorageKeys() != 2 {
+		t.Fatalf("wrong size: %d addresses, %d slots", s.Addresses(), s.StorageKeys())
+	}
+	want := AccessList{
+		{Address: addr1, StorageKeys: []common.Hash{slot1, slot2}},
+		{Address: addr2, StorageKeys: []common.Hash{}},
+	}
+	if al := s.ToAccessList(); !reflect.DeepEqual(al, want) {
+		t.Fatalf("wrong access list: %v", al)
+	}
+
+	// Conversion from a list merges duplicates.
+	dup := AccessList{
+		{Address: addr2},
+		{Address: addr1, StorageKeys: []common.Hash{slot2}},
+		{Address: addr1, StorageKeys: []common.Hash{slot1, slot2}},
+	}
+	if !NewAccessSetFromList(dup).Equal(s) {
+		t.Fatal("set from list not equal")
+	}
+	cpy := s.Copy()
+	cpy.AddSlot(addr2, slot1)
+	if s.Equal(cpy) || s.HasSlot(addr2, slot1) {
+		t.Fatal("copy not independent")
+	}
+}
+
+// Tests that the access list output doesn't depend on map iteration or insertion order.
+func TestAccessSetToAccessListStable(t *testing.T) {
+	al := randomAccessList(50, 10)
+	want := NewAccessSetFromList(al).ToAccessList()
+	for i := 0; i < 20; i++ {
+		rand.Shuffle(len(al), func(i, j int) { al[i], al[j] = al[j], al[i] })
+		for _, tuple := range al {
+			rand.Shuffle(len(tuple.StorageKeys), func(i, j int) {
+				tuple.StorageKeys[i], tuple.StorageKeys[j] = tuple.StorageKeys[j], tuple.StorageKeys[i]
+			})
+		}
+		if got := NewAccessSetFromList(al).ToAccessList(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("run %d: access list changed", i)
+		}
+	}
+}
+
+func randomAccessList(accounts, slots int) AccessList {
+	al := make(AccessList, accounts)
+	for i := range al {
+		binary.BigEndian.PutUint64(al[i].Address[12:], rand.Uint64())
+		al[i].StorageKeys = make([]common.Hash, slots)
+		for j := range al[i].StorageKeys {
+			binary.BigEndian.PutUint64(al[i].StorageKeys[j][24:], rand.Uint64())
+		}
+	}
+	return al
+}
+
+func BenchmarkAccessSetAdd(b *testing.B) {
+	al := randomAccessList(1000, 100)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewAccessSetFromList(al)
+	}
+}
+
+func BenchmarkAccessSetHas(b *testing.B) {
+	al := randomAccessList(1000, 100)
+	s := NewAccessSetFromList(al)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tuple := al[i%len(al)]
+		s.HasSlot(tuple.Address, tuple.StorageKeys[i%len(tuple.StorageKeys)])
+	}
+}
+
+func BenchmarkAccessSetToAccessList(b *testing.B) {
+	s := NewAccessSetFromList(randomAccessList(1000, 100))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.ToAccessList()
+	}
+}
@@ -359,7 +359,16 @@ func (b *Block) Body() *Body {
 
 func (b *Block) Uncles() []*Header          { return b.uncles }
 func (b *Block) Transactions() Transactions { return b.transactions }
-func (b *Block) Withdrawals() Withdrawals   { return b.withdrawals }
+
+// Withdrawals returns the withdrawals contained in the block. Blocks created before the
+// Shanghai fork have no withdrawals list, in which case an empty (non-nil) list is
+// returned. Use WithdrawalsRoot or Body to tell the two cases apart.
+func (b *Block) Withdrawals() Withdrawals {
+	if b.withdrawals == nil {
+		return Withdrawals{}
+	}
+	return b.withdrawals
+}
 
 func (b *Block) Transaction(hash common.Hash) *Transaction {
 	for _, transaction := range b.transactions {
@@ -402,8 +411,9 @@ func (b *Block) BaseFee() *big.Int {
 	return new(big.Int).Set(b.header.BaseFee)
 }
 
-func (b *Block) BeaconRoot() *common.Hash   { return b.header.ParentBeaconRoot }
-func (b *Block) RequestsHash() *common.Hash { return b.header.RequestsHash }
+func (b *Block) BeaconRoot() *common.Hash      { return b.header.ParentBeaconRoot }
+func (b *Block) RequestsHash() *common.Hash    { return b.header.RequestsHash }
+func (b *Block) WithdrawalsRoot() *common.Hash { return b.header.WithdrawalsHash }
 
 func (b *Block) ExcessBlobGas() *uint64 {
 	var excessBlobGas *uint64
@@ -438,6 +448,47 @@ func (b *Block) Size() uint64 {
 	return uint64(c)
 }
 
+// EncodedSize returns the exact length of the block's RLP encoding. Unlike Size,
+// it doesn't encode the whole block, instead summing up the encoded sizes of the
+// header, transactions, uncles and withdrawals.
+func (b *Block) EncodedSize() uint64 {
+	size := encodedHeaderSize(b.header)
+
+	var txs uint64
+	for _, tx := range b.transactions {
+		if tx.Type() == LegacyTxType {
+			txs += tx.Size()
+		} else {
+			// Typed transactions are embedded as an RLP string. The string header
+			// has the same length as a list header for the same content size.
+			txs += rlp.ListSize(tx.Size())
+		}
+	}
+	size += rlp.ListSize(txs)
+
+	var uncles uint64
+	for _, uncle := range b.uncles {
+		uncles += encodedHeaderSize(uncle)
+	}
+	size += rlp.ListSize(uncles)
+
+	if b.withdrawals != nil {
+		var ws uint64
+		for _, w := range b.withdrawals {
+			ws += rlp.ListSize(w.encodedSize())
+		}
+		size += rlp.ListSize(ws)
+	}
+	return rlp.ListSize(size)
+}
+
+// encodedHeaderSize returns the length of the header's RLP encoding.
+func encodedHeaderSize(h *Header) uint64 {
+	c := writeCounter(0)
+	rlp.Encode(&c, h)
+	return uint64(c)
+}
+
 // SanityCheck can be used to prevent that unbounded fields are
 // stuffed with junk data to add processing overhead
 func (b *Block) SanityCheck() error {
@@ -18,6 +18,7 @@ package types
 
 import (
 	"bytes"
+	"encoding/json"
 	gomath "math"
 	"math/big"
 	"reflect"
@@ -203,6 +204,79 @@ func TestUncleHash(t *testing.T) {
 	}
 }
 
+func TestWithdrawalsHash(t *testing.T) {
+	if h := CalcWithdrawalsHash(nil, blocktest.NewHasher()); h != EmptyWithdrawalsHash {
+		t.Fatalf("empty withdrawals hash is wrong, got %x != %x", h, EmptyWithdrawalsHash)
+	}
+	ws := Withdrawals{{Index: 1, Validator: 2, Address: common.Address{0xaa}, Amount: 3}}
+	want := DeriveSha(ws, blocktest.NewHasher())
+	if h := CalcWithdrawalsHash(ws, blocktest.NewHasher()); h != want {
+		t.Fatalf("withdrawals hash is wrong, got %x != %x", h, want)
+	}
+}
+
+// This test checks that blocks without withdrawals and blocks with an empty withdrawals
+// list remain distinguishable across RLP and header JSON round-trips.
+func TestBlockWithdrawalsPresence(t *testing.T) {
+	header := &Header{Number: big.NewInt(1), Difficulty: big.NewInt(0)}
+	tests := []struct {
+		name        string
+		withdrawals []*Withdrawal
+		wantRoot    *common.Hash
+	}{
+		{"pre-shanghai", nil, nil},
+		{"empty", []*Withdrawal{}, &EmptyWithdrawalsHash},
+		{"non-empty", []*Withdrawal{{Index: 1, Amount: 10}}, nil},
+	}
+	for _, test := range tests {
+		block := NewBlock(header, &Body{Withdrawals: test.withdrawals}, nil, blocktest.NewHasher())
+		if block.Withdrawals() == nil {
+			t.Errorf("%s: Withdrawals() returned nil", test.name)
+		}
+		if len(block.Withdrawals()) != len(test.withdrawals) {
+			t.Errorf("%s: wrong withdrawals count %d, want %d", test.name, len(block.Withdrawals()), len(test.withdrawals))
+		}
+		if test.wantRoot != nil && *block.WithdrawalsRoot() != *test.wantRoot {
+			t.Errorf("%s: wrong withdrawals root %x, want %x", test.name, *block.WithdrawalsRoot(), *test.wantRoot)
+		}
+		if (test.withdrawals == nil) != (block.WithdrawalsRoot() == nil) {
+			t.Errorf("%s: withdrawals root presence mismatch", test.name)
+		}
+
+		// RLP round-trip.
+		enc, err := rlp.EncodeToBytes(block)
+		if err != nil {
+			t.Fatalf("%s: encode error: %v", test.name, err)
+		}
+		var dec Block
+		if err := rlp.DecodeBytes(enc, &dec); err != nil {
+			t.Fatalf("%s: decode error: %v", test.name, err)
+		}
+		if (dec.Body().Withdrawals == nil) != (test.withdrawals == nil) {
+			t.Errorf("%s: body withdrawals presence changed after RLP round-trip", test.name)
+		}
+		if (dec.WithdrawalsRoot() == nil) != (test.withdrawals == nil) {
+			t.Errorf("%s: withdrawals root presence changed after RLP round-trip", test.name)
+		}
+		if dec.Hash() != block.Hash() {
+			t.Errorf("%s: hash changed after RLP round-trip", test.name)
+		}
+
+		// Header JSON round-trip.
+		jsonEnc, err := json.Marshal(block.Header())
+		if err != nil {
+			t.Fatalf("%s: JSON encode error: %v", test.name, err)
+		}
+		var decHeader Header
+		if err := json.Unmarshal(jsonEnc, &decHeader); err != nil {
+			t.Fatalf("%s: JSON decode error: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(decHeader.WithdrawalsHash, block.WithdrawalsRoot()) {
+			t.Errorf("%s: withdrawals root changed after JSON round-trip: %v != %v", test.name, decHeader.WithdrawalsHash, block.WithdrawalsRoot())
+		}
+	}
+}
+
 var benchBuffer = bytes.NewBuffer(make([]byte, 0, 32000))
 
 func BenchmarkEncodeBlock(b *testing.B) {
@@ -217,6 +291,55 @@ func BenchmarkEncodeBlock(b *testing.B) {
 	}
 }
 
+func TestBlockEncodedSize(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := LatestSigner(params.TestChainConfig)
+	sign := func(inner TxData) *Transaction {
+		tx, err := SignNewTx(key, signer, inner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	var (
+		to      = common.HexToAddress("0x0100000000000000000000000000000000000000")
+		legacy  = sign(&LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000, To: &to, Value: big.NewInt(1)})
+		small   = sign(&DynamicFeeTx{ChainID: big.NewInt(1), GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), Gas: 21000})
+		large   = sign(&AccessListTx{ChainID: big.NewInt(1), Gas: 1 << 20, Data: make([]byte, 70000)})
+		blob    = createEmptyBlobTx(key, false)
+		sidecar = createEmptyBlobTx(key, true)
+	)
+	header := &Header{Number: big.NewInt(1), Difficulty: big.NewInt(0), Extra: []byte("encoded size")}
+	uncle := &Header{Number: big.NewInt(0), Difficulty: big.NewInt(1 << 20), GasLimit: 12345678}
+	tests := []struct {
+		name string
+		body *Body
+	}{
+		{"empty", &Body{}},
+		{"legacy", &Body{Transactions: []*Transaction{legacy}}},
+		{"typed", &Body{Transactions: []*Transaction{legacy, small, large}}},
+		{"blob", &Body{Transactions: []*Transaction{blob, sidecar}}},
+		{"uncles", &Body{Transactions: []*Transaction{small}, Uncles: []*Header{uncle, uncle}}},
+		{"empty-withdrawals", &Body{Withdrawals: []*Withdrawal{}}},
+		{"withdrawals", &Body{Withdrawals: []*Withdrawal{
+			{},
+			{Index: 1, Validator: 127, Address: to, Amount: 128},
+			{Index: gomath.MaxUint64, Validator: 1 << 40, Amount: 1 << 32},
+		}}},
+		{"bench", makeBenchBlock().Body()},
+	}
+	for _, test := range tests {
+		block := NewBlock(header, test.body, nil, blocktest.NewHasher())
+		enc, err := rlp.EncodeToBytes(block)
+		if err != nil {
+			t.Fatalf("%s: encode error: %v", test.name, err)
+		}
+		if size := block.EncodedSize(); size != uint64(len(enc)) {
+			t.Errorf("%s: wrong encoded size %d, want %d", test.name, size, len(enc))
+		}
+	}
+}
+
 func makeBenchBlock() *Block {
 	var (
 		key, _   = crypto.GenerateKey()
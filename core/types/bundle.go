@@ -0,0 +1,134 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	ErrBundleEmpty         = errors.New("bundle has no transactions")
+	ErrBundleNilTx         = errors.New("bundle contains nil transaction")
+	ErrBundleDuplicateTx   = errors.New("bundle contains duplicate transaction")
+	ErrBundleNegativeValue = errors.New("bundle override is negative")
+)
+
+// Bundle is an ordered list of transactions which is meant to be simulated atomically,
+// in the given order, on top of a block. The optional overrides replace the values of
+// the block the bundle is simulated in.
+//
+// The JSON encoding of a bundle is
+//
+//	{
+//	  "txs":         ["0x<signed tx>", ...], // binary encoded transactions, see MarshalBinary
+//	  "blockNumber": "0x<number>",           // optional
+//	  "timestamp":   "0x<seconds>",          // optional
+//	  "baseFee":     "0x<wei>"               // optional
+//	}
+//
+// Bundles are not validated when decoded, call Validate before using them.
+type Bundle struct {
+	Transactions []*Transaction
+	Number       *big.Int // block number override
+	Time         *uint64  // block timestamp override
+	BaseFee      *big.Int // block base fee override
+}
+
+type bundleJSON struct {
+	Txs     []hexutil.Bytes `json:"txs"`
+	Number  *hexutil.Big    `json:"blockNumber,omitempty"`
+	Time    *hexutil.Uint64 `json:"timestamp,omitempty"`
+	BaseFee *hexutil.Big    `json:"baseFee,omitempty"`
+}
+
+// Validate checks that the bundle is well-formed: it must contain at least one
+// transaction, no transaction may occur twice and overrides may not be negative.
+// It doesn't check the transactions against any state.
+func (b *Bundle) Validate() error {
+	if len(b.Transactions) == 0 {
+		return ErrBundleEmpty
+	}
+	seen := make(map[common.Hash]struct{}, len(b.Transactions))
+	for i, tx := range b.Transactions {
+		if tx == nil {
+			return fmt.Errorf("%w at index %d", ErrBundleNilTx, i)
+		}
+		hash := tx.Hash()
+		if _, ok := seen[hash]; ok {
+			return fmt.Errorf("%w %v at index %d", ErrBundleDuplicateTx, hash, i)
+		}
+		seen[hash] = struct{}{}
+	}
+	if b.Number != nil && b.Number.Sign() < 0 {
+		return fmt.Errorf("%w: block number %v", ErrBundleNegativeValue, b.Number)
+	}
+	if b.BaseFee != nil && b.BaseFee.Sign() < 0 {
+		return fmt.Errorf("%w: base fee %v", ErrBundleNegativeValue, b.BaseFee)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the bundle into the JSON format documented on Bundle.
+func (b Bundle) MarshalJSON() ([]byte, error) {
+	enc := bundleJSON{
+		Txs:     make([]hexutil.Bytes, len(b.Transactions)),
+		Number:  (*hexutil.Big)(b.Number),
+		Time:    (*hexutil.Uint64)(b.Time),
+		BaseFee: (*hexutil.Big)(b.BaseFee),
+	}
+	for i, tx := range b.Transactions {
+		if tx == nil {
+			return nil, fmt.Errorf("%w at index %d", ErrBundleNilTx, i)
+		}
+		data, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		enc.Txs[i] = data
+	}
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON decodes a bundle from the JSON format documented on Bundle.
+func (b *Bundle) UnmarshalJSON(input []byte) error {
+	var dec bundleJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.Txs == nil {
+		return errors.New("missing required field 'txs' for Bundle")
+	}
+	txs := make([]*Transaction, len(dec.Txs))
+	for i, data := range dec.Txs {
+		tx := new(Transaction)
+		if err := tx.UnmarshalBinary(data); err != nil {
+			return fmt.Errorf("invalid transaction at index %d: %v", i, err)
+		}
+		txs[i] = tx
+	}
+	b.Transactions = txs
+	b.Number = (*big.Int)(dec.Number)
+	b.Time = (*uint64)(dec.Time)
+	b.BaseFee = (*big.Int)(dec.BaseFee)
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func newBundleTxs(n int) []*Transaction {
+	key, _ := crypto.GenerateKey()
+	signer := LatestSignerForChainID(big.NewInt(1))
+	txs := make([]*Transaction, n)
+	for i := range txs {
+		txs[i] = MustSignNewTx(key, signer, &DynamicFeeTx{
+			ChainID:   big.NewInt(1),
+			Nonce:     uint64(i),
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: big.NewInt(10),
+			Gas:       21000,
+			To:        &testAddr,
+			Value:     big.NewInt(1),
+		})
+	}
+	return txs
+}
+
+func TestBundleJSON(t *testing.T) {
+	time := uint64(1700000000)
+	bundle := Bundle{
+		Transactions: newBundleTxs(2),
+		Number:       big.NewInt(100),
+		Time:         &time,
+		BaseFee:      big.NewInt(7),
+	}
+	enc, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec Bundle
+	if err := json.Unmarshal(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if len(dec.Transactions) != 2 {
+		t.Fatalf("wrong number of transactions: %d", len(dec.Transactions))
+	}
+	for i, tx := range dec.Transactions {
+		if tx.Hash() != bundle.Transactions[i].Hash() {
+			t.Errorf("transaction %d changed", i)
+		}
+	}
+	if dec.Number.Cmp(bundle.Number) != 0 || *dec.Time != time || dec.BaseFee.Cmp(bundle.BaseFee) != 0 {
+		t.Errorf("wrong overrides: %v %v %v", dec.Number, dec.Time, dec.BaseFee)
+	}
+
+	// Overrides are optional.
+	if err := json.Unmarshal([]byte(`{"txs":[]}`), &dec); err != nil {
+		t.Fatal(err)
+	}
+	if dec.Number != nil || dec.Time != nil || dec.BaseFee != nil {
+		t.Error("overrides set without input")
+	}
+	if enc, _ := json.Marshal(Bundle{}); string(enc) != `{"txs":[]}` {
+		t.Errorf("wrong encoding of empty bundle: %s", enc)
+	}
+	for _, input := range []string{`{}`, `{"txs":["0x01"]}`, `{"txs":[],"baseFee":"0x"}`} {
+		if err := json.Unmarshal([]byte(input), &dec); err == nil {
+			t.Errorf("no error decoding %s", input)
+		}
+	}
+}
+
+func TestBundleValidate(t *testing.T) {
+	txs := newBundleTxs(2)
+	tests := []struct {
+		bundle Bundle
+		want   error
+	}{
+		{Bundle{Transactions: txs}, nil},
+		{Bundle{Transactions: txs, Number: big.NewInt(0), BaseFee: big.NewInt(0)}, nil},
+		{Bundle{}, ErrBundleEmpty},
+		{Bundle{Transactions: []*Transaction{txs[0], nil}}, ErrBundleNilTx},
+		{Bundle{Transactions: []*Transaction{txs[0], txs[1], txs[0]}}, ErrBundleDuplicateTx},
+		{Bundle{Transactions: txs, Number: big.NewInt(-1)}, ErrBundleNegativeValue},
+		{Bundle{Transactions: txs, BaseFee: big.NewInt(-1)}, ErrBundleNegativeValue},
+	}
+	for i, test := range tests {
+		if err := test.bundle.Validate(); !errors.Is(err, test.want) {
+			t.Errorf("test %d: wrong error %v, want %v", i, err, test.want)
+		}
+	}
+}
@@ -17,6 +17,8 @@
 package types
 
 import (
+	"fmt"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 )
@@ -59,3 +61,48 @@ type logMarshaling struct {
 	TxIndex     hexutil.Uint
 	Index       hexutil.Uint
 }
+
+// EnrichedLog is a log together with the context of the block it was emitted in.
+type EnrichedLog struct {
+	Log            *Log
+	BlockHash      common.Hash
+	BlockNumber    uint64
+	BlockTimestamp uint64
+	Removed        bool // true if the block was removed from the chain by a reorg
+}
+
+// EnrichLogs returns the logs contained in the receipts of the given block along with
+// their block context. The returned logs are copies with all derived fields filled in,
+// the logs of the receipts are not modified. Set removed if the block is being removed
+// from the canonical chain.
+func EnrichLogs(block *Block, receipts Receipts, removed bool) ([]*EnrichedLog, error) {
+	txs := block.Transactions()
+	if len(txs) != len(receipts) {
+		return nil, fmt.Errorf("transaction and receipt count mismatch: %d != %d", len(txs), len(receipts))
+	}
+	var (
+		hash   = block.Hash()
+		number = block.NumberU64()
+		time   = block.Time()
+		logs   []*EnrichedLog
+	)
+	for i, receipt := range receipts {
+		for _, l := range receipt.Logs {
+			log := *l
+			log.BlockNumber = number
+			log.BlockHash = hash
+			log.TxHash = txs[i].Hash()
+			log.TxIndex = uint(i)
+			log.Index = uint(len(logs))
+			log.Removed = removed
+			logs = append(logs, &EnrichedLog{
+				Log:            &log,
+				BlockHash:      hash,
+				BlockNumber:    number,
+				BlockTimestamp: time,
+				Removed:        removed,
+			})
+		}
+	}
+	return logs, nil
+}
@@ -19,12 +19,14 @@ package types
 import (
 	"encoding/json"
 	"errors"
+	"math/big"
 	"reflect"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/internal/blocktest"
 )
 
 var unmarshalLogTests = map[string]struct {
@@ -130,3 +132,52 @@ func checkError(t *testing.T, testname string, got, want error) bool {
 	}
 	return false
 }
+
+func TestEnrichLogs(t *testing.T) {
+	txs := []*Transaction{
+		NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil),
+		NewTransaction(1, common.Address{2}, big.NewInt(1), 21000, big.NewInt(1), nil),
+		NewTransaction(2, common.Address{3}, big.NewInt(1), 21000, big.NewInt(1), nil),
+	}
+	receipts := Receipts{
+		{Logs: []*Log{{Address: common.Address{0x11}}, {Address: common.Address{0x12}}}},
+		{Logs: []*Log{}},
+		{Logs: []*Log{{Address: common.Address{0x31}}}},
+	}
+	header := &Header{Number: big.NewInt(42), Time: 1700000000}
+	block := NewBlock(header, &Body{Transactions: txs}, nil, blocktest.NewHasher())
+
+	logs, err := EnrichLogs(block, receipts, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		addr    common.Address
+		txIndex uint
+	}{
+		{common.Address{0x11}, 0},
+		{common.Address{0x12}, 0},
+		{common.Address{0x31}, 2},
+	}
+	if len(logs) != len(want) {
+		t.Fatalf("wrong number of logs: have %d, want %d", len(logs), len(want))
+	}
+	for i, l := range logs {
+		if l.BlockHash != block.Hash() || l.BlockNumber != 42 || l.BlockTimestamp != 1700000000 || !l.Removed {
+			t.Errorf("log %d: wrong block context %+v", i, l)
+		}
+		if l.Log.Address != want[i].addr || l.Log.TxIndex != want[i].txIndex || l.Log.Index != uint(i) {
+			t.Errorf("log %d: wrong position %+v", i, l.Log)
+		}
+		if l.Log.TxHash != txs[want[i].txIndex].Hash() || l.Log.BlockHash != block.Hash() || l.Log.BlockNumber != 42 || !l.Log.Removed {
+			t.Errorf("log %d: wrong derived fields %+v", i, l.Log)
+		}
+	}
+	// The logs of the receipts are left untouched.
+	if receipts[0].Logs[0].Removed || receipts[2].Logs[0].Index != 0 {
+		t.Error("receipt logs modified")
+	}
+	if _, err := EnrichLogs(block, receipts[:2], false); err == nil {
+		t.Error("expected error for receipt count mismatch")
+	}
+}
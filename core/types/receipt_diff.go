@@ -0,0 +1,87 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// FieldDiff is a field which differs between two compared values.
+type FieldDiff struct {
+	Path   string // Path of the field, e.g. "logs[1].topics[0]"
+	Local  string // Local value of the field
+	Remote string // Remote value of the field
+}
+
+// String implements fmt.Stringer.
+func (d FieldDiff) String() string {
+	return fmt.Sprintf("%s: local %s, remote %s", d.Path, d.Local, d.Remote)
+}
+
+// DiffReceipts compares the consensus fields of two receipts, i.e. the fields which
+// go into the receipt root, and returns the differing fields. The logs are compared
+// one by one. If the number of logs differs, only the common logs are compared.
+func DiffReceipts(local, remote *Receipt) []FieldDiff {
+	var diffs []FieldDiff
+	add := func(path string, local, remote any) {
+		diffs = append(diffs, FieldDiff{Path: path, Local: fmt.Sprint(local), Remote: fmt.Sprint(remote)})
+	}
+	if local.Type != remote.Type {
+		add("type", local.Type, remote.Type)
+	}
+	if !bytes.Equal(local.PostState, remote.PostState) {
+		add("root", hexutil.Bytes(local.PostState), hexutil.Bytes(remote.PostState))
+	}
+	// The status is only encoded if there is no post state.
+	if len(local.PostState) == 0 && len(remote.PostState) == 0 && local.Status != remote.Status {
+		add("status", local.Status, remote.Status)
+	}
+	if local.CumulativeGasUsed != remote.CumulativeGasUsed {
+		add("cumulativeGasUsed", local.CumulativeGasUsed, remote.CumulativeGasUsed)
+	}
+	if local.Bloom != remote.Bloom {
+		add("logsBloom", hexutil.Bytes(local.Bloom[:]), hexutil.Bytes(remote.Bloom[:]))
+	}
+	if len(local.Logs) != len(remote.Logs) {
+		add("logs.length", len(local.Logs), len(remote.Logs))
+	}
+	for i := 0; i < len(local.Logs) && i < len(remote.Logs); i++ {
+		var (
+			path = "logs[" + strconv.Itoa(i) + "]"
+			l, r = local.Logs[i], remote.Logs[i]
+		)
+		if l.Address != r.Address {
+			add(path+".address", l.Address.Hex(), r.Address.Hex())
+		}
+		if len(l.Topics) != len(r.Topics) {
+			add(path+".topics.length", len(l.Topics), len(r.Topics))
+		}
+		for j := 0; j < len(l.Topics) && j < len(r.Topics); j++ {
+			if l.Topics[j] != r.Topics[j] {
+				add(path+".topics["+strconv.Itoa(j)+"]", l.Topics[j].Hex(), r.Topics[j].Hex())
+			}
+		}
+		if !bytes.Equal(l.Data, r.Data) {
+			add(path+".data", hexutil.Bytes(l.Data), hexutil.Bytes(r.Data))
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestDiffReceipts(t *testing.T) {
+	receipt := func(status uint64, gas uint64, logs ...*Log) *Receipt {
+		r := &Receipt{Type: DynamicFeeTxType, Status: status, CumulativeGasUsed: gas, Logs: logs}
+		r.Bloom = CreateBloom(Receipts{r})
+		return r
+	}
+	var (
+		addr   = common.HexToAddress("0x01")
+		topic1 = common.HexToHash("0x01")
+		topic2 = common.HexToHash("0x02")
+		topic3 = common.HexToHash("0x03")
+	)
+	if diffs := DiffReceipts(receipt(1, 100), receipt(1, 100)); len(diffs) != 0 {
+		t.Fatalf("equal receipts have diffs: %v", diffs)
+	}
+	local := receipt(1, 100,
+		&Log{Address: addr, Topics: []common.Hash{topic1}, Data: []byte{1}},
+		&Log{Address: addr, Topics: []common.Hash{topic1, topic2}},
+	)
+	remote := receipt(0, 200,
+		&Log{Address: addr, Topics: []common.Hash{topic1}, Data: []byte{2}},
+		&Log{Address: addr, Topics: []common.Hash{topic3}},
+		&Log{Address: addr},
+	)
+	var paths []string
+	for _, d := range DiffReceipts(local, remote) {
+		paths = append(paths, d.Path)
+	}
+	want := []string{
+		"status",
+		"cumulativeGasUsed",
+		"logsBloom",
+		"logs.length",
+		"logs[0].data",
+		"logs[1].topics.length",
+		"logs[1].topics[0]",
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("wrong diff paths %q, want %q", paths, want)
+	}
+	diffs := DiffReceipts(local, remote)
+	if s := diffs[4].String(); s != "logs[0].data: local 0x01, remote 0x02" {
+		t.Errorf("wrong diff string %q", s)
+	}
+}
@@ -18,10 +18,12 @@ package types
 
 import (
 	"bytes"
+	"cmp"
 	"errors"
 	"fmt"
 	"io"
 	"math/big"
+	"slices"
 	"sync/atomic"
 	"time"
 
@@ -40,6 +42,7 @@ var (
 	errInvalidYParity       = errors.New("'yParity' field must be 0 or 1")
 	errVYParityMismatch     = errors.New("'v' and 'yParity' fields do not match")
 	errVYParityMissing      = errors.New("missing 'yParity' or 'v' field in transaction")
+	errTxTooLarge           = errors.New("transaction too large")
 )
 
 // Transaction types.
@@ -193,6 +196,90 @@ func (tx *Transaction) UnmarshalBinary(b []byte) error {
 	return nil
 }
 
+// maxReadTransactionSize is the largest transaction payload accepted by
+// ReadTransaction. It is well above the size of a blob transaction with sidecar.
+const maxReadTransactionSize = 16 * 1024 * 1024
+
+// ReadTransaction reads a single transaction in the canonical encoding of
+// MarshalBinary from r, i.e. an EIP-2718 envelope for typed transactions and plain
+// RLP for legacy transactions. It consumes exactly the bytes of the transaction,
+// so it can be called repeatedly to read a stream of transactions written by
+// WriteTransaction. At the end of the stream, io.EOF is returned.
+func ReadTransaction(r io.Reader) (*Transaction, error) {
+	// The first byte is either the transaction type or the
+	// list header of a legacy transaction. The buffer has room
+	// for the type, the list header and an 8-byte size.
+	buf := make([]byte, 1, 10)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	if buf[0] <= 0x7f {
+		buf = buf[:2]
+		if _, err := io.ReadFull(r, buf[1:]); err != nil {
+			return nil, noEOF(err)
+		}
+	}
+	// Read the list header to find the size of the payload.
+	var (
+		head = buf[len(buf)-1]
+		size uint64
+	)
+	switch {
+	case head < 0xc0:
+		return nil, rlp.ErrExpectedList
+	case head < 0xf8:
+		size = uint64(head - 0xc0)
+	default:
+		start, n := len(buf), int(head-0xf7)
+		if n > 8 || start+n > cap(buf) {
+			return nil, rlp.ErrValueTooLarge
+		}
+		buf = buf[:start+n]
+		if _, err := io.ReadFull(r, buf[start:]); err != nil {
+			return nil, noEOF(err)
+		}
+		for _, b := range buf[start:] {
+			size = size<<8 | uint64(b)
+		}
+	}
+	if size > maxReadTransactionSize {
+		return nil, errTxTooLarge
+	}
+	// Read the payload without trusting the size for allocation.
+	payload, err := io.ReadAll(io.LimitReader(r, int64(size)))
+	if err != nil {
+		return nil, noEOF(err)
+	}
+	if uint64(len(payload)) != size {
+		return nil, io.ErrUnexpectedEOF
+	}
+	tx := new(Transaction)
+	if err := tx.UnmarshalBinary(append(buf, payload...)); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// WriteTransaction writes the canonical encoding of tx to w, the same encoding as
+// returned by MarshalBinary. The transaction can be read back with ReadTransaction.
+func WriteTransaction(w io.Writer, tx *Transaction) error {
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// noEOF converts io.EOF to io.ErrUnexpectedEOF. It is used when
+// the stream ends in the middle of a transaction.
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
 // decodeTyped decodes a typed transaction from the canonical format.
 func (tx *Transaction) decodeTyped(b []byte) (TxData, error) {
 	if len(b) <= 1 {
@@ -618,6 +705,37 @@ func (s TxByNonce) Len() int           { return len(s) }
 func (s TxByNonce) Less(i, j int) bool { return s[i].Nonce() < s[j].Nonce() }
 func (s TxByNonce) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
 
+// TxByEffectiveTip returns a comparison function ordering transactions by their
+// effective miner tip at the given base fee, highest first. Transactions paying
+// the same tip are ordered by nonce, lowest first, and then by hash, yielding a
+// deterministic order for any input. The result can be used with
+// slices.SortFunc.
+//
+// Transactions whose fee cap is below the base fee have a negative effective
+// tip and are thus ordered last.
+func TxByEffectiveTip(baseFee *big.Int) func(a, b *Transaction) int {
+	return func(a, b *Transaction) int {
+		if cmp := a.EffectiveGasTipCmp(b, baseFee); cmp != 0 {
+			return -cmp
+		}
+		if c := cmp.Compare(a.Nonce(), b.Nonce()); c != 0 {
+			return c
+		}
+		ah, bh := a.Hash(), b.Hash()
+		return bytes.Compare(ah[:], bh[:])
+	}
+}
+
+// SortByEffectiveTip sorts the given transactions in place the way the miner
+// prioritises them, using TxByEffectiveTip.
+//
+// Note that this function does not take nonces into account: if the list
+// contains multiple transactions from the same sender, they may not be in nonce
+// order after sorting.
+func SortByEffectiveTip(txs []*Transaction, baseFee *big.Int) {
+	slices.SortFunc(txs, TxByEffectiveTip(baseFee))
+}
+
 // copyAddressPtr copies an address.
 func copyAddressPtr(a *common.Address) *common.Address {
 	if a == nil {
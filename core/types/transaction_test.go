@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"maps"
 	"math/big"
 	"reflect"
@@ -30,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/uint256"
 )
 
 // The values in those tests are from the Transaction Tests
@@ -404,6 +406,106 @@ func encodeDecodeBinary(tx *Transaction) (*Transaction, error) {
 	return parsedTx, nil
 }
 
+// Tests that a stream of mixed transaction types can be written and read back.
+func TestReadWriteTransactionStream(t *testing.T) {
+	key, _ := defaultTestKey()
+	signer := NewPragueSigner(big.NewInt(1))
+	txs := []*Transaction{
+		MustSignNewTx(key, HomesteadSigner{}, &LegacyTx{Nonce: 1, Gas: 21000, To: &testAddr, Value: big.NewInt(10), GasPrice: big.NewInt(1)}),
+		MustSignNewTx(key, signer, &LegacyTx{Nonce: 2, Gas: 21000, To: &testAddr, Value: big.NewInt(10), GasPrice: big.NewInt(1)}),
+		MustSignNewTx(key, signer, &AccessListTx{
+			ChainID:    big.NewInt(1),
+			Nonce:      3,
+			Gas:        30000,
+			GasPrice:   big.NewInt(1),
+			AccessList: AccessList{{Address: testAddr, StorageKeys: []common.Hash{{1}}}},
+		}),
+		MustSignNewTx(key, signer, &DynamicFeeTx{
+			ChainID:   big.NewInt(1),
+			Nonce:     4,
+			Gas:       21000,
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: big.NewInt(2),
+			Data:      make([]byte, 100), // long list header
+		}),
+		createEmptyBlobTx(key, false),
+		createEmptyBlobTx(key, true),
+		MustSignNewTx(key, signer, &SetCodeTx{
+			ChainID:   uint256.NewInt(1),
+			Nonce:     6,
+			Gas:       50000,
+			GasTipCap: uint256.NewInt(1),
+			GasFeeCap: uint256.NewInt(2),
+			To:        testAddr,
+			AuthList:  []SetCodeAuthorization{{ChainID: *uint256.NewInt(1), Address: testAddr, Nonce: 1}},
+		}),
+	}
+	var stream bytes.Buffer
+	for _, tx := range txs {
+		if err := WriteTransaction(&stream, tx); err != nil {
+			t.Fatalf("failed to write tx type %d: %v", tx.Type(), err)
+		}
+	}
+	enc := stream.Bytes()
+
+	r := bytes.NewReader(enc)
+	for i, want := range txs {
+		tx, err := ReadTransaction(r)
+		if err != nil {
+			t.Fatalf("tx %d: read failed: %v", i, err)
+		}
+		if err := assertEqual(want, tx); err != nil {
+			t.Fatalf("tx %d: %v", i, err)
+		}
+		if (want.BlobTxSidecar() == nil) != (tx.BlobTxSidecar() == nil) {
+			t.Fatalf("tx %d: sidecar lost", i)
+		}
+	}
+	if _, err := ReadTransaction(r); err != io.EOF {
+		t.Fatalf("wrong error at end of stream: %v", err)
+	}
+
+	// Truncated transactions are rejected.
+	for _, tx := range []*Transaction{txs[0], txs[3], txs[len(txs)-1]} {
+		enc, _ := tx.MarshalBinary()
+		for _, n := range []int{1, 2, 3, len(enc) - 1} {
+			if _, err := ReadTransaction(bytes.NewReader(enc[:n])); err != io.ErrUnexpectedEOF {
+				t.Errorf("tx type %d truncated to %d bytes: wrong error %v", tx.Type(), n, err)
+			}
+		}
+	}
+	if _, err := ReadTransaction(bytes.NewReader([]byte{0x80})); err != rlp.ErrExpectedList {
+		t.Errorf("wrong error for non-list input: %v", err)
+	}
+}
+
+func TestReadTransactionMalformed(t *testing.T) {
+	// Non-canonical 8-byte size of a typed transaction.
+	if _, err := ReadTransaction(bytes.NewReader([]byte{0x02, 0xff, 0, 0, 0, 0, 0, 0, 0, 1, 0})); err == nil {
+		t.Error("expected error for non-canonical size")
+	}
+	tests := []struct {
+		input []byte
+		err   error
+	}{
+		// Typed transaction with an 8-byte size.
+		{[]byte{0x02, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, errTxTooLarge},
+		// Legacy transaction with an 8-byte size.
+		{[]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, errTxTooLarge},
+		// Size just above the limit.
+		{[]byte{0x02, 0xfb, 0x01, 0x00, 0x00, 0x01}, errTxTooLarge},
+		// Truncated size.
+		{[]byte{0x02, 0xff, 0, 0, 0}, io.ErrUnexpectedEOF},
+		// Size larger than the remaining input.
+		{[]byte{0x02, 0xf9, 0x01, 0x00, 0xc0}, io.ErrUnexpectedEOF},
+	}
+	for i, test := range tests {
+		if _, err := ReadTransaction(bytes.NewReader(test.input)); err != test.err {
+			t.Errorf("test %d (%x): wrong error %v, want %v", i, test.input, err, test.err)
+		}
+	}
+}
+
 func assertEqual(orig *Transaction, cpy *Transaction) error {
 	// compare nonce, price, gaslimit, recipient, amount, payload, V, R, S
 	if want, got := orig.Hash(), cpy.Hash(); want != got {
@@ -576,3 +678,40 @@ func TestYParityJSONUnmarshalling(t *testing.T) {
 		}
 	}
 }
+
+func TestSortByEffectiveTip(t *testing.T) {
+	var (
+		baseFee = big.NewInt(10)
+		legacy  = func(nonce uint64, price int64) *Transaction {
+			return NewTx(&LegacyTx{Nonce: nonce, GasPrice: big.NewInt(price), Gas: 21000})
+		}
+		dynamic = func(nonce uint64, feeCap, tip int64) *Transaction {
+			return NewTx(&DynamicFeeTx{Nonce: nonce, GasFeeCap: big.NewInt(feeCap), GasTipCap: big.NewInt(tip), Gas: 21000})
+		}
+	)
+	var (
+		tip5   = legacy(0, 15)     // effective tip 5
+		tip3   = dynamic(1, 30, 3) // effective tip 3
+		tip2   = dynamic(3, 12, 5) // effective tip 2, capped by fee cap
+		tip2a  = legacy(2, 12)     // effective tip 2, lower nonce
+		under  = legacy(4, 5)      // fee cap below base fee
+		tieA   = legacy(5, 11)     // effective tip 1
+		tieB   = dynamic(5, 11, 1) // effective tip 1, same nonce
+		sorted = []*Transaction{tip5, tip3, tip2a, tip2}
+	)
+	// Equal tip and nonce are ordered by hash.
+	if h1, h2 := tieA.Hash(), tieB.Hash(); bytes.Compare(h1[:], h2[:]) < 0 {
+		sorted = append(sorted, tieA, tieB)
+	} else {
+		sorted = append(sorted, tieB, tieA)
+	}
+	sorted = append(sorted, under)
+
+	txs := []*Transaction{under, tieB, tip2, tieA, tip5, tip2a, tip3}
+	SortByEffectiveTip(txs, baseFee)
+	for i := range txs {
+		if txs[i] != sorted[i] {
+			t.Fatalf("wrong order at index %d: have nonce %d, want nonce %d", i, txs[i].Nonce(), sorted[i].Nonce())
+		}
+	}
+}
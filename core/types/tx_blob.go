@@ -19,6 +19,7 @@ package types
 import (
 	"bytes"
 	"crypto/sha256"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -69,6 +70,36 @@ func (sc *BlobTxSidecar) BlobHashes() []common.Hash {
 	return h
 }
 
+// Verify checks that the sidecar matches the given versioned blob hashes of a
+// transaction and that all blob proofs are valid.
+func (sc *BlobTxSidecar) Verify(versionedHashes []common.Hash) error {
+	if len(sc.Blobs) != len(versionedHashes) {
+		return fmt.Errorf("invalid number of %d blobs compared to %d blob hashes", len(sc.Blobs), len(versionedHashes))
+	}
+	if len(sc.Commitments) != len(versionedHashes) {
+		return fmt.Errorf("invalid number of %d blob commitments compared to %d blob hashes", len(sc.Commitments), len(versionedHashes))
+	}
+	if len(sc.Proofs) != len(versionedHashes) {
+		return fmt.Errorf("invalid number of %d blob proofs compared to %d blob hashes", len(sc.Proofs), len(versionedHashes))
+	}
+	// Blob quantities match up, validate that the commitments match with the
+	// versioned hashes before getting to the cryptography.
+	hasher := sha256.New()
+	for i, vhash := range versionedHashes {
+		computed := kzg4844.CalcBlobHashV1(hasher, &sc.Commitments[i])
+		if vhash != computed {
+			return fmt.Errorf("blob %d: computed hash %#x mismatches transaction one %#x", i, computed, vhash)
+		}
+	}
+	// Blob commitments match with the hashes, verify the blobs themselves via KZG.
+	for i := range sc.Blobs {
+		if err := kzg4844.VerifyBlobProof(&sc.Blobs[i], sc.Commitments[i], sc.Proofs[i]); err != nil {
+			return fmt.Errorf("invalid blob %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
 // encodedSize computes the RLP size of the sidecar elements. This does NOT return the
 // encoded size of the BlobTxSidecar, it's just a helper for tx.Size().
 func (sc *BlobTxSidecar) encodedSize() uint64 {
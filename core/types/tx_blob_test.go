@@ -58,6 +58,65 @@ func TestBlobTxSize(t *testing.T) {
 	}
 }
 
+func TestBlobTxSidecarVerify(t *testing.T) {
+	blob := new(kzg4844.Blob)
+	blob[31] = 1
+	commit, _ := kzg4844.BlobToCommitment(blob)
+	proof, _ := kzg4844.ComputeBlobProof(blob, commit)
+
+	valid := func() *BlobTxSidecar {
+		return &BlobTxSidecar{
+			Blobs:       []kzg4844.Blob{*emptyBlob, *blob},
+			Commitments: []kzg4844.Commitment{emptyBlobCommit, commit},
+			Proofs:      []kzg4844.Proof{emptyBlobProof, proof},
+		}
+	}
+	hashes := valid().BlobHashes()
+	if err := valid().Verify(hashes); err != nil {
+		t.Fatalf("valid sidecar rejected: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(sc *BlobTxSidecar, hashes []common.Hash) []common.Hash
+	}{
+		{"missing blob", func(sc *BlobTxSidecar, hashes []common.Hash) []common.Hash {
+			sc.Blobs = sc.Blobs[:1]
+			return hashes
+		}},
+		{"missing proof", func(sc *BlobTxSidecar, hashes []common.Hash) []common.Hash {
+			sc.Proofs = sc.Proofs[:1]
+			return hashes
+		}},
+		{"extra hash", func(sc *BlobTxSidecar, hashes []common.Hash) []common.Hash {
+			return append(hashes, hashes[0])
+		}},
+		{"swapped hashes", func(sc *BlobTxSidecar, hashes []common.Hash) []common.Hash {
+			return []common.Hash{hashes[1], hashes[0]}
+		}},
+		{"modified blob", func(sc *BlobTxSidecar, hashes []common.Hash) []common.Hash {
+			sc.Blobs[1][63] = 1
+			return hashes
+		}},
+		{"swapped proofs", func(sc *BlobTxSidecar, hashes []common.Hash) []common.Hash {
+			sc.Proofs[0], sc.Proofs[1] = sc.Proofs[1], sc.Proofs[0]
+			return hashes
+		}},
+		{"swapped blobs and commitments", func(sc *BlobTxSidecar, hashes []common.Hash) []common.Hash {
+			sc.Blobs[0], sc.Blobs[1] = sc.Blobs[1], sc.Blobs[0]
+			sc.Commitments[0], sc.Commitments[1] = sc.Commitments[1], sc.Commitments[0]
+			return hashes
+		}},
+	}
+	for _, test := range tests {
+		sc := valid()
+		vhashes := test.tamper(sc, append([]common.Hash{}, hashes...))
+		if err := sc.Verify(vhashes); err == nil {
+			t.Errorf("%s: tampered sidecar accepted", test.name)
+		}
+	}
+}
+
 var (
 	emptyBlob          = new(kzg4844.Blob)
 	emptyBlobCommit, _ = kzg4844.BlobToCommitment(emptyBlob)
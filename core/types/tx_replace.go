@@ -0,0 +1,98 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrReplaceNonceMismatch  = errors.New("replacement has different nonce")
+	ErrReplaceSenderMismatch = errors.New("replacement has different sender")
+	ErrReplaceBlobMismatch   = errors.New("blob and non-blob transactions can't replace each other")
+)
+
+// CanReplace reports whether tx may replace the pending transaction old according
+// to the replacement rules of the transaction pool. The replacement must have the
+// same sender and nonce, and both its fee cap and tip cap must exceed the values of
+// old by at least minBumpPercent. For legacy transactions, both caps are the gas
+// price. Blob transactions can only be replaced by blob transactions, which must bump
+// the blob fee cap as well.
+//
+// If baseFee is non-nil, tx must also be executable at that base fee, i.e. its fee
+// cap may not be lower.
+//
+// An error is returned if tx isn't a replacement candidate for old at all. If it is,
+// but the fees are insufficient, the result is false without error.
+func CanReplace(old, tx *Transaction, minBumpPercent uint64, baseFee *big.Int) (bool, error) {
+	if old.Nonce() != tx.Nonce() {
+		return false, ErrReplaceNonceMismatch
+	}
+	if (old.Type() == BlobTxType) != (tx.Type() == BlobTxType) {
+		return false, ErrReplaceBlobMismatch
+	}
+	oldFrom, err := replaceSender(old)
+	if err != nil {
+		return false, err
+	}
+	from, err := replaceSender(tx)
+	if err != nil {
+		return false, err
+	}
+	if oldFrom != from {
+		return false, ErrReplaceSenderMismatch
+	}
+	if baseFee != nil && tx.GasFeeCapIntCmp(baseFee) < 0 {
+		return false, nil
+	}
+	// We have to ensure that the new fees are higher than the old ones as well
+	// as checking the percentage threshold, to be accurate for low (Wei-level)
+	// gas price replacements.
+	if !priceBumped(old.GasFeeCap(), tx.GasFeeCap(), minBumpPercent) ||
+		!priceBumped(old.GasTipCap(), tx.GasTipCap(), minBumpPercent) {
+		return false, nil
+	}
+	if tx.Type() == BlobTxType && !priceBumped(old.BlobGasFeeCap(), tx.BlobGasFeeCap(), minBumpPercent) {
+		return false, nil
+	}
+	return true, nil
+}
+
+// priceBumped reports whether price is higher than old and at least
+// old * (100 + bump) / 100.
+func priceBumped(old, price *big.Int, bump uint64) bool {
+	if price.Cmp(old) <= 0 {
+		return false
+	}
+	threshold := new(big.Int).SetUint64(bump)
+	threshold.Add(threshold, big.NewInt(100))
+	threshold.Mul(threshold, old)
+	threshold.Div(threshold, big.NewInt(100))
+	return price.Cmp(threshold) >= 0
+}
+
+// replaceSender returns the sender of tx. A previously derived sender is reused,
+// regardless of the signer it was derived with.
+func replaceSender(tx *Transaction) (common.Address, error) {
+	if sc := tx.from.Load(); sc != nil {
+		return sc.from, nil
+	}
+	return Sender(LatestSignerForChainID(tx.ChainId()), tx)
+}
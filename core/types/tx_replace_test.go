@@ -0,0 +1,76 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+)
+
+func TestCanReplace(t *testing.T) {
+	var (
+		key, _   = crypto.GenerateKey()
+		other, _ = crypto.GenerateKey()
+		signer   = LatestSignerForChainID(big.NewInt(1))
+	)
+	legacy := func(nonce uint64, price int64) *Transaction {
+		return MustSignNewTx(key, signer, &LegacyTx{Nonce: nonce, GasPrice: big.NewInt(price), Gas: 21000, To: &testAddr})
+	}
+	dynamic := func(key *ecdsa.PrivateKey, nonce uint64, tip, feeCap int64) *Transaction {
+		return MustSignNewTx(key, signer, &DynamicFeeTx{ChainID: big.NewInt(1), Nonce: nonce, GasTipCap: big.NewInt(tip), GasFeeCap: big.NewInt(feeCap), Gas: 21000, To: &testAddr})
+	}
+	blob := func(nonce uint64, tip, feeCap, blobFeeCap uint64) *Transaction {
+		return MustSignNewTx(key, signer, &BlobTx{ChainID: uint256.NewInt(1), Nonce: nonce, GasTipCap: uint256.NewInt(tip), GasFeeCap: uint256.NewInt(feeCap), BlobFeeCap: uint256.NewInt(blobFeeCap), Gas: 21000, To: testAddr})
+	}
+	tests := []struct {
+		name    string
+		old, tx *Transaction
+		baseFee *big.Int
+		want    bool
+		err     error
+	}{
+		{"legacy bump", legacy(0, 100), legacy(0, 110), nil, true, nil},
+		{"legacy insufficient bump", legacy(0, 100), legacy(0, 109), nil, false, nil},
+		{"legacy wei-level bump", legacy(0, 1), legacy(0, 1), nil, false, nil},
+		{"legacy by dynamic", legacy(0, 100), dynamic(key, 0, 110, 110), nil, true, nil},
+		{"dynamic tip not bumped", dynamic(key, 0, 10, 100), dynamic(key, 0, 10, 200), nil, false, nil},
+		{"dynamic fee cap not bumped", dynamic(key, 0, 10, 100), dynamic(key, 0, 20, 105), nil, false, nil},
+		{"dynamic bump", dynamic(key, 0, 10, 100), dynamic(key, 0, 11, 110), nil, true, nil},
+		{"below base fee", dynamic(key, 0, 10, 100), dynamic(key, 0, 11, 110), big.NewInt(111), false, nil},
+		{"at base fee", dynamic(key, 0, 10, 100), dynamic(key, 0, 11, 110), big.NewInt(110), true, nil},
+		{"blob bump", blob(0, 10, 100, 10), blob(0, 11, 110, 11), nil, true, nil},
+		{"blob fee cap not bumped", blob(0, 10, 100, 10), blob(0, 11, 110, 10), nil, false, nil},
+		{"blob by dynamic", blob(0, 10, 100, 10), dynamic(key, 0, 20, 200), nil, false, ErrReplaceBlobMismatch},
+		{"dynamic by blob", dynamic(key, 0, 10, 100), blob(0, 20, 200, 20), nil, false, ErrReplaceBlobMismatch},
+		{"nonce mismatch", legacy(0, 100), legacy(1, 200), nil, false, ErrReplaceNonceMismatch},
+		{"sender mismatch", dynamic(key, 0, 10, 100), dynamic(other, 0, 20, 200), nil, false, ErrReplaceSenderMismatch},
+	}
+	for _, test := range tests {
+		replace, err := CanReplace(test.old, test.tx, 10, test.baseFee)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: wrong error %v, want %v", test.name, err, test.err)
+		}
+		if replace != test.want {
+			t.Errorf("%s: wrong result %t, want %t", test.name, replace, test.want)
+		}
+	}
+}
@@ -29,6 +29,16 @@ import (
 	"github.com/holiman/uint256"
 )
 
+var (
+	// ErrAuthorizationWrongChainID is returned if the chain ID of an authorization is
+	// neither zero nor the chain ID it is validated against.
+	ErrAuthorizationWrongChainID = errors.New("EIP-7702 authorization chain ID mismatch")
+
+	// ErrAuthorizationNonceOverflow is returned if the nonce of an authorization can't
+	// be incremented without exceeding 2^64-1.
+	ErrAuthorizationNonceOverflow = errors.New("EIP-7702 authorization nonce > 64 bit")
+)
+
 // DelegationPrefix is used by code to denote the account is delegating to
 // another account.
 var DelegationPrefix = []byte{0xef, 0x01, 0x00}
@@ -113,6 +123,21 @@ func (a *SetCodeAuthorization) sigHash() common.Hash {
 	})
 }
 
+// Validate performs the stateless checks of an authorization for use on the chain with
+// the given ID. A chain ID of zero in the authorization is valid on all chains. Note the
+// signature is not checked, Authority verifies it when recovering the signer.
+func (a *SetCodeAuthorization) Validate(chainID *big.Int) error {
+	// Verify chain ID is null or equal to current chain ID.
+	if !a.ChainID.IsZero() && a.ChainID.CmpBig(chainID) != 0 {
+		return ErrAuthorizationWrongChainID
+	}
+	// Limit nonce to 2^64-1 per EIP-2681.
+	if a.Nonce+1 < a.Nonce {
+		return ErrAuthorizationNonceOverflow
+	}
+	return nil
+}
+
 // Authority recovers the the authorizing account of an authorization.
 func (a *SetCodeAuthorization) Authority() (common.Address, error) {
 	sighash := a.sigHash()
@@ -17,9 +17,15 @@
 package types
 
 import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
 )
 
 // TestParseDelegation tests a few possible delegation designator values and
@@ -68,3 +74,131 @@ func TestParseDelegation(t *testing.T) {
 		}
 	}
 }
+
+// TestSetCodeAuthorizationSignature checks that the authority of a signed
+// authorization is the signing key, and that modifications are detected.
+func TestSetCodeAuthorizationSignature(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	auth, err := SignSetCode(key, SetCodeAuthorization{
+		ChainID: *uint256.NewInt(1),
+		Address: common.Address{0x42},
+		Nonce:   7,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authority, err := auth.Authority(); err != nil {
+		t.Fatalf("failed to recover authority: %v", err)
+	} else if authority != addr {
+		t.Fatalf("wrong authority: have %x, want %x", authority, addr)
+	}
+	// Changing a signed field yields a different authority.
+	modified := auth
+	modified.Nonce++
+	if authority, err := modified.Authority(); err == nil && authority == addr {
+		t.Fatal("modified authorization recovered the original authority")
+	}
+	// Invalid signature values are rejected.
+	invalid := auth
+	invalid.V = 2
+	if _, err := invalid.Authority(); !errors.Is(err, ErrInvalidSig) {
+		t.Fatalf("wrong error for invalid signature: %v", err)
+	}
+	invalid = auth
+	invalid.S.Set(new(uint256.Int).Sub(uint256.MustFromBig(crypto.S256().Params().N), &auth.S))
+	if _, err := invalid.Authority(); !errors.Is(err, ErrInvalidSig) {
+		t.Fatalf("wrong error for malleable signature: %v", err)
+	}
+}
+
+// TestSetCodeAuthorizationValidate checks the stateless validation of the chain ID
+// and nonce fields.
+func TestSetCodeAuthorizationValidate(t *testing.T) {
+	for i, tt := range []struct {
+		chainID uint64
+		nonce   uint64
+		want    error
+	}{
+		{chainID: 0, nonce: 0},
+		{chainID: 1, nonce: 1},
+		{chainID: 2, nonce: 0, want: ErrAuthorizationWrongChainID},
+		{chainID: 1, nonce: math.MaxUint64 - 1},
+		{chainID: 1, nonce: math.MaxUint64, want: ErrAuthorizationNonceOverflow},
+	} {
+		auth := SetCodeAuthorization{ChainID: *uint256.NewInt(tt.chainID), Nonce: tt.nonce}
+		if err := auth.Validate(big.NewInt(1)); err != tt.want {
+			t.Errorf("test %d: wrong error: have %v, want %v", i, err, tt.want)
+		}
+	}
+}
+
+// TestSetCodeTxEncoding checks that the authorization list survives RLP and JSON
+// encoding of a signed set code transaction.
+func TestSetCodeTxEncoding(t *testing.T) {
+	var (
+		key, _    = crypto.GenerateKey()
+		signer    = NewPragueSigner(big.NewInt(1))
+		sender    = crypto.PubkeyToAddress(key.PublicKey)
+		auth, err = SignSetCode(key, SetCodeAuthorization{
+			ChainID: *uint256.NewInt(1),
+			Address: common.Address{0x42},
+			Nonce:   1,
+		})
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := MustSignNewTx(key, signer, &SetCodeTx{
+		ChainID:   uint256.NewInt(1),
+		Nonce:     0,
+		GasTipCap: uint256.NewInt(1),
+		GasFeeCap: uint256.NewInt(10),
+		Gas:       100000,
+		To:        common.Address{0x01},
+		Value:     uint256.NewInt(0),
+		AuthList:  []SetCodeAuthorization{auth},
+	})
+
+	check := func(name string, have *Transaction) {
+		t.Helper()
+		if have.Hash() != tx.Hash() {
+			t.Fatalf("%s: hash mismatch: have %x, want %x", name, have.Hash(), tx.Hash())
+		}
+		if from, err := Sender(signer, have); err != nil || from != sender {
+			t.Fatalf("%s: wrong sender %x: %v", name, from, err)
+		}
+		auths := have.SetCodeAuthorizations()
+		if len(auths) != 1 || auths[0] != auth {
+			t.Fatalf("%s: authorization list mismatch: %+v", name, auths)
+		}
+		if authority, err := auths[0].Authority(); err != nil || authority != sender {
+			t.Fatalf("%s: wrong authority %x: %v", name, authority, err)
+		}
+	}
+	blob, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec Transaction
+	if err := dec.UnmarshalBinary(blob); err != nil {
+		t.Fatal(err)
+	}
+	check("rlp", &dec)
+
+	js, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decjs Transaction
+	if err := json.Unmarshal(js, &decjs); err != nil {
+		t.Fatal(err)
+	}
+	check("json", &decjs)
+
+	// Set code transactions are rejected by signers before Prague.
+	if _, err := Sender(NewCancunSigner(big.NewInt(1)), tx); !errors.Is(err, ErrTxTypeNotSupported) {
+		t.Fatalf("wrong error from pre-Prague signer: %v", err)
+	}
+}
@@ -36,6 +36,12 @@ type Withdrawal struct {
 	Amount    uint64         `json:"amount"`         // value of withdrawal in Gwei
 }
 
+// encodedSize returns the size of the withdrawal's RLP encoding, excluding
+// the list header.
+func (w *Withdrawal) encodedSize() uint64 {
+	return uint64(rlp.IntSize(w.Index)+rlp.IntSize(w.Validator)+rlp.IntSize(w.Amount)) + 1 + common.AddressLength
+}
+
 // field type overrides for gencodec
 type withdrawalMarshaling struct {
 	Index     hexutil.Uint64
@@ -61,3 +67,12 @@ func (s Withdrawals) Size() int {
 func (s Withdrawals) EncodeIndex(i int, w *bytes.Buffer) {
 	rlp.Encode(w, s[i])
 }
+
+// CalcWithdrawalsHash computes the withdrawals root of the given list, as stored in
+// the WithdrawalsHash field of post-Shanghai headers.
+func CalcWithdrawalsHash(ws Withdrawals, hasher TrieHasher) common.Hash {
+	if len(ws) == 0 {
+		return EmptyWithdrawalsHash
+	}
+	return DeriveSha(ws, hasher)
+}
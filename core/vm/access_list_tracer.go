@@ -0,0 +1,96 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// AccessListTracer is a tracer that accumulates touched accounts and storage
+// slots into an internal set.
+type AccessListTracer struct {
+	excl map[common.Address]struct{} // Set of account to exclude from the list
+	list *types.AccessSet            // Set of accounts and storage slots touched
+}
+
+// NewAccessListTracer creates a new tracer that can generate AccessLists.
+// An optional AccessList can be specified to occupy slots and addresses in
+// the resulting accesslist. The sender, the recipient and the precompiles are
+// excluded from the list, unless storage slots of them are accessed.
+func NewAccessListTracer(acl types.AccessList, from, to common.Address, precompiles []common.Address) *AccessListTracer {
+	excl := map[common.Address]struct{}{
+		from: {}, to: {},
+	}
+	for _, addr := range precompiles {
+		excl[addr] = struct{}{}
+	}
+	list := types.NewAccessSet()
+	for _, al := range acl {
+		if _, ok := excl[al.Address]; !ok {
+			list.AddAddress(al.Address)
+		}
+		for _, slot := range al.StorageKeys {
+			list.AddSlot(al.Address, slot)
+		}
+	}
+	return &AccessListTracer{
+		excl: excl,
+		list: list,
+	}
+}
+
+func (a *AccessListTracer) Hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnOpcode: a.OnOpcode,
+	}
+}
+
+// OnOpcode captures all opcodes that touch storage or addresses and adds them to the accesslist.
+func (a *AccessListTracer) OnOpcode(pc uint64, opcode byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+	var (
+		stack = scope.StackData()
+		op    = OpCode(opcode)
+		addr  common.Address
+	)
+	switch {
+	case (op == SLOAD || op == SSTORE) && len(stack) >= 1:
+		a.list.AddSlot(scope.Address(), stack[len(stack)-1].Bytes32())
+		return
+	case (op == EXTCODECOPY || op == EXTCODEHASH || op == EXTCODESIZE || op == BALANCE || op == SELFDESTRUCT) && len(stack) >= 1:
+		addr = stack[len(stack)-1].Bytes20()
+	case (op == DELEGATECALL || op == CALL || op == STATICCALL || op == CALLCODE) && len(stack) >= 5:
+		addr = stack[len(stack)-2].Bytes20()
+	default:
+		return
+	}
+	if _, ok := a.excl[addr]; !ok {
+		a.list.AddAddress(addr)
+	}
+}
+
+// AccessList returns the current accesslist maintained by the tracer, sorted by
+// address and storage slot.
+func (a *AccessListTracer) AccessList() types.AccessList {
+	return a.list.ToAccessList()
+}
+
+// Equal returns if the content of two access list traces are equal.
+func (a *AccessListTracer) Equal(other *AccessListTracer) bool {
+	return a.list.Equal(other.list)
+}
@@ -0,0 +1,137 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+)
+
+// accessRecorder is a StateDB which records all accounts and storage slots read or
+// written through it. Accesses are not journaled, i.e. the accesses of reverted
+// call frames remain in the set. Transient storage and the EIP-2929 access list
+// are not part of the state and thus not recorded.
+type accessRecorder struct {
+	StateDB
+	set *types.AccessSet
+}
+
+func newAccessRecorder(db StateDB) *accessRecorder {
+	return &accessRecorder{StateDB: db, set: types.NewAccessSet()}
+}
+
+func (r *accessRecorder) CreateAccount(addr common.Address) {
+	r.set.AddAddress(addr)
+	r.StateDB.CreateAccount(addr)
+}
+
+func (r *accessRecorder) CreateContract(addr common.Address) {
+	r.set.AddAddress(addr)
+	r.StateDB.CreateContract(addr)
+}
+
+func (r *accessRecorder) SubBalance(addr common.Address, amount *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
+	r.set.AddAddress(addr)
+	return r.StateDB.SubBalance(addr, amount, reason)
+}
+
+func (r *accessRecorder) AddBalance(addr common.Address, amount *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
+	r.set.AddAddress(addr)
+	return r.StateDB.AddBalance(addr, amount, reason)
+}
+
+func (r *accessRecorder) GetBalance(addr common.Address) *uint256.Int {
+	r.set.AddAddress(addr)
+	return r.StateDB.GetBalance(addr)
+}
+
+func (r *accessRecorder) GetNonce(addr common.Address) uint64 {
+	r.set.AddAddress(addr)
+	return r.StateDB.GetNonce(addr)
+}
+
+func (r *accessRecorder) SetNonce(addr common.Address, nonce uint64) {
+	r.set.AddAddress(addr)
+	r.StateDB.SetNonce(addr, nonce)
+}
+
+func (r *accessRecorder) GetCodeHash(addr common.Address) common.Hash {
+	r.set.AddAddress(addr)
+	return r.StateDB.GetCodeHash(addr)
+}
+
+func (r *accessRecorder) GetCode(addr common.Address) []byte {
+	r.set.AddAddress(addr)
+	return r.StateDB.GetCode(addr)
+}
+
+func (r *accessRecorder) SetCode(addr common.Address, code []byte) []byte {
+	r.set.AddAddress(addr)
+	return r.StateDB.SetCode(addr, code)
+}
+
+func (r *accessRecorder) GetCodeSize(addr common.Address) int {
+	r.set.AddAddress(addr)
+	return r.StateDB.GetCodeSize(addr)
+}
+
+func (r *accessRecorder) GetCommittedState(addr common.Address, slot common.Hash) common.Hash {
+	r.set.AddSlot(addr, slot)
+	return r.StateDB.GetCommittedState(addr, slot)
+}
+
+func (r *accessRecorder) GetState(addr common.Address, slot common.Hash) common.Hash {
+	r.set.AddSlot(addr, slot)
+	return r.StateDB.GetState(addr, slot)
+}
+
+func (r *accessRecorder) SetState(addr common.Address, slot common.Hash, value common.Hash) common.Hash {
+	r.set.AddSlot(addr, slot)
+	return r.StateDB.SetState(addr, slot, value)
+}
+
+func (r *accessRecorder) GetStorageRoot(addr common.Address) common.Hash {
+	r.set.AddAddress(addr)
+	return r.StateDB.GetStorageRoot(addr)
+}
+
+func (r *accessRecorder) SelfDestruct(addr common.Address) uint256.Int {
+	r.set.AddAddress(addr)
+	return r.StateDB.SelfDestruct(addr)
+}
+
+func (r *accessRecorder) HasSelfDestructed(addr common.Address) bool {
+	r.set.AddAddress(addr)
+	return r.StateDB.HasSelfDestructed(addr)
+}
+
+func (r *accessRecorder) SelfDestruct6780(addr common.Address) (uint256.Int, bool) {
+	r.set.AddAddress(addr)
+	return r.StateDB.SelfDestruct6780(addr)
+}
+
+func (r *accessRecorder) Exist(addr common.Address) bool {
+	r.set.AddAddress(addr)
+	return r.StateDB.Exist(addr)
+}
+
+func (r *accessRecorder) Empty(addr common.Address) bool {
+	r.set.AddAddress(addr)
+	return r.StateDB.Empty(addr)
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+func TestRecordAccessSet(t *testing.T) {
+	var (
+		caller   = common.HexToAddress("0xca11")
+		outer    = common.HexToAddress("0xaa")
+		inner    = common.HexToAddress("0xbb")
+		balance  = common.HexToAddress("0xcc")
+		identity = common.BytesToAddress([]byte{4})
+		vmctx    = BlockContext{
+			BlockNumber: new(big.Int),
+			CanTransfer: func(StateDB, common.Address, *uint256.Int) bool { return true },
+			Transfer:    func(StateDB, common.Address, common.Address, *uint256.Int) {},
+		}
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	// The outer contract loads slot 1, calls the inner contract and the identity
+	// precompile.
+	statedb.SetCode(outer, common.FromHex(
+		"60015450"+ // SLOAD(1)
+			"6000600060006000600060bb5af150"+ // CALL(0xbb)
+			"600060006000600060045afa50", // STATICCALL(identity)
+	))
+	// The inner contract stores slot 2, reads the balance of 0xcc and reverts.
+	statedb.SetCode(inner, common.FromHex("600160025560cc315060006000fd"))
+	statedb.Finalise(true)
+
+	evm := NewEVM(vmctx, statedb, params.AllEthashProtocolChanges, Config{RecordAccessSet: true})
+	evm.SetTxContext(TxContext{Origin: caller})
+	if _, _, err := evm.Call(AccountRef(caller), outer, nil, 1000000, new(uint256.Int)); err != nil {
+		t.Fatal(err)
+	}
+	set := evm.AccessSet()
+	for _, addr := range []common.Address{outer, inner, balance, identity} {
+		if !set.HasAddress(addr) {
+			t.Errorf("address %v not recorded", addr)
+		}
+	}
+	if !set.HasSlot(outer, common.HexToHash("0x01")) {
+		t.Error("loaded slot not recorded")
+	}
+	if !set.HasSlot(inner, common.HexToHash("0x02")) {
+		t.Error("stored slot of reverted call not recorded")
+	}
+	if set.Addresses() != 4 || set.StorageKeys() != 2 {
+		t.Errorf("wrong access set size: %d addresses, %d slots", set.Addresses(), set.StorageKeys())
+	}
+	// The set is reset for every transaction.
+	evm.SetTxContext(TxContext{Origin: caller})
+	if set := evm.AccessSet(); set.Addresses() != 0 {
+		t.Errorf("access set not reset: %v", set.ToAccessList())
+	}
+	// Recording is disabled by default.
+	if evm := NewEVM(vmctx, statedb, params.AllEthashProtocolChanges, Config{}); evm.AccessSet() != nil {
+		t.Error("access set recorded without config flag")
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/holiman/uint256"
+)
+
+// Instruction is a disassembled EVM instruction.
+type Instruction struct {
+	PC  uint64
+	Op  OpCode
+	Arg []byte // immediate data of PUSH instructions
+}
+
+// String returns the instruction in the format used by the disassembler.
+func (ins Instruction) String() string {
+	if len(ins.Arg) > 0 {
+		return fmt.Sprintf("%05x: %v %#x", ins.PC, ins.Op, ins.Arg)
+	}
+	return fmt.Sprintf("%05x: %v", ins.PC, ins.Op)
+}
+
+// BasicBlock is a sequence of instructions which is only entered at the first and
+// only left after the last instruction.
+type BasicBlock struct {
+	Start        uint64        // PC of the first instruction
+	Instructions []Instruction // instructions of the block, never empty
+	Succs        []uint64      // start of the successor blocks
+	IndirectJump bool          // whether the block ends with a jump to a dynamic destination
+	Reachable    bool          // whether the block may be executed
+}
+
+// CFG is the control-flow graph of legacy EVM code.
+type CFG struct {
+	Blocks []*BasicBlock // all blocks, ordered by start
+}
+
+// Block returns the block starting at pc, or nil if there is none.
+func (cfg *CFG) Block(pc uint64) *BasicBlock {
+	for _, b := range cfg.Blocks {
+		if b.Start == pc {
+			return b
+		}
+	}
+	return nil
+}
+
+// DisassembleCFG disassembles legacy EVM code into its control-flow graph.
+//
+// Jump destinations are resolved if they're pushed immediately before the jump.
+// Blocks ending with any other jump are marked as indirect jumps. Since these can
+// target any JUMPDEST, all blocks starting with JUMPDEST are considered reachable
+// if an indirect jump is reachable.
+func DisassembleCFG(code []byte) (*CFG, error) {
+	var (
+		cfg   = new(CFG)
+		bits  bitvec
+		block *BasicBlock
+	)
+	for pc := uint64(0); pc < uint64(len(code)); {
+		ins := Instruction{PC: pc, Op: OpCode(code[pc])}
+		pc++
+		if ins.Op.IsPush() {
+			size := uint64(ins.Op - PUSH0)
+			if pc+size > uint64(len(code)) {
+				return nil, fmt.Errorf("incomplete instruction at %v", ins.PC)
+			}
+			ins.Arg = code[pc : pc+size]
+			pc += size
+		}
+		// Jump destinations start a new block.
+		if ins.Op == JUMPDEST && block != nil {
+			block.Succs = []uint64{ins.PC}
+			block = nil
+		}
+		if block == nil {
+			block = &BasicBlock{Start: ins.PC}
+			cfg.Blocks = append(cfg.Blocks, block)
+		}
+		block.Instructions = append(block.Instructions, ins)
+
+		switch {
+		case ins.Op == JUMP || ins.Op == JUMPI:
+			if dest, ok := staticJumpDest(block); !ok {
+				block.IndirectJump = true
+			} else {
+				if bits == nil {
+					bits = codeBitmap(code)
+				}
+				if isJumpDest(code, bits, dest) {
+					block.Succs = append(block.Succs, dest)
+				}
+			}
+			if ins.Op == JUMPI && pc < uint64(len(code)) {
+				block.Succs = append(block.Succs, pc)
+			}
+			block = nil
+		case isTerminator(ins.Op):
+			block = nil
+		}
+	}
+	cfg.markReachable()
+	return cfg, nil
+}
+
+// staticJumpDest returns the destination of the jump ending the given block if it
+// was pushed by the preceding instruction.
+func staticJumpDest(block *BasicBlock) (uint64, bool) {
+	n := len(block.Instructions)
+	if n < 2 || !block.Instructions[n-2].Op.IsPush() {
+		return 0, false
+	}
+	dest := new(uint256.Int).SetBytes(block.Instructions[n-2].Arg)
+	if !dest.IsUint64() {
+		return 0, true // invalid, but static destination
+	}
+	return dest.Uint64(), true
+}
+
+// isJumpDest reports whether dest is a JUMPDEST instruction of the code.
+func isJumpDest(code []byte, bits bitvec, dest uint64) bool {
+	if dest >= uint64(len(code)) || OpCode(code[dest]) != JUMPDEST {
+		return false
+	}
+	return bits.codeSegment(dest)
+}
+
+// isTerminator reports whether op ends the execution of the current call.
+func isTerminator(op OpCode) bool {
+	switch op {
+	case STOP, RETURN, REVERT, INVALID, SELFDESTRUCT:
+		return true
+	}
+	return opCodeToString[op] == ""
+}
+
+// markReachable marks the blocks reachable from the start of the code.
+func (cfg *CFG) markReachable() {
+	if len(cfg.Blocks) == 0 {
+		return
+	}
+	var (
+		index    = make(map[uint64]*BasicBlock, len(cfg.Blocks))
+		queue    = []*BasicBlock{cfg.Blocks[0]}
+		indirect bool
+	)
+	for _, b := range cfg.Blocks {
+		index[b.Start] = b
+	}
+	cfg.Blocks[0].Reachable = true
+	for len(queue) > 0 {
+		b := queue[0]
+		queue = queue[1:]
+		if b.IndirectJump && !indirect {
+			indirect = true
+			for _, target := range cfg.Blocks {
+				if target.Instructions[0].Op == JUMPDEST && !target.Reachable {
+					target.Reachable = true
+					queue = append(queue, target)
+				}
+			}
+		}
+		for _, succ := range b.Succs {
+			if next := index[succ]; next != nil && !next.Reachable {
+				next.Reachable = true
+				queue = append(queue, next)
+			}
+		}
+	}
+}
+
+// DOT returns the graph in the Graphviz DOT format. Unreachable blocks are drawn
+// dashed, blocks ending with an indirect jump have a dotted edge to a pseudo node.
+func (cfg *CFG) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph cfg {\n\tnode [shape=box fontname=monospace];\n")
+	var indirect bool
+	for _, block := range cfg.Blocks {
+		var label strings.Builder
+		for _, ins := range block.Instructions {
+			label.WriteString(ins.String())
+			label.WriteString(`\l`)
+		}
+		style := ""
+		if !block.Reachable {
+			style = " style=dashed color=gray"
+		}
+		fmt.Fprintf(&b, "\tb%d [label=\"%s\"%s];\n", block.Start, label.String(), style)
+		for _, succ := range block.Succs {
+			fmt.Fprintf(&b, "\tb%d -> b%d;\n", block.Start, succ)
+		}
+		if block.IndirectJump {
+			indirect = true
+			fmt.Fprintf(&b, "\tb%d -> indirect [style=dotted];\n", block.Start)
+		}
+	}
+	if indirect {
+		b.WriteString("\tindirect [label=\"indirect jump\" shape=ellipse];\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestDisassembleCFG(t *testing.T) {
+	type block struct {
+		start     uint64
+		succs     []uint64
+		indirect  bool
+		reachable bool
+	}
+	tests := []struct {
+		code   string
+		blocks []block
+	}{
+		// PUSH1 4, JUMPI, STOP, JUMPDEST, STOP
+		{"60045700" + "5b00", []block{
+			{0, []uint64{4, 3}, false, true},
+			{3, nil, false, true},
+			{4, nil, false, true},
+		}},
+		// PUSH1 5, JUMP, JUMPDEST, PUSH1 0x5b, STOP: jump into push data and dead code
+		{"600556" + "5b605b00", []block{
+			{0, nil, false, true},
+			{3, nil, false, false},
+		}},
+		// CALLDATASIZE, JUMP, JUMPDEST, STOP, ADDRESS, JUMPDEST, STOP
+		{"3656" + "5b00" + "30" + "5b00", []block{
+			{0, nil, true, true},
+			{2, nil, false, true},
+			{4, []uint64{5}, false, false},
+			{5, nil, false, true},
+		}},
+		// PUSH1 2, JUMPDEST, DUP1, JUMP: loop falling through into the jump destination
+		{"6002" + "5b8056", []block{
+			{0, []uint64{2}, false, true},
+			{2, nil, true, true},
+		}},
+		// PUSH1 3, JUMP, JUMPDEST, 0x0c, JUMPDEST, STOP: undefined opcode ends the block
+		{"600356" + "5b0c" + "5b00", []block{
+			{0, []uint64{3}, false, true},
+			{3, nil, false, true},
+			{5, nil, false, false},
+		}},
+	}
+	for i, test := range tests {
+		cfg, err := DisassembleCFG(common.FromHex(test.code))
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		var blocks []block
+		for _, b := range cfg.Blocks {
+			blocks = append(blocks, block{b.Start, b.Succs, b.IndirectJump, b.Reachable})
+		}
+		if !reflect.DeepEqual(blocks, test.blocks) {
+			t.Errorf("test %d: wrong blocks\n%+v\nwant\n%+v", i, blocks, test.blocks)
+		}
+	}
+}
+
+func TestDisassembleCFGIncomplete(t *testing.T) {
+	if _, err := DisassembleCFG(common.FromHex("600160")); err == nil || err.Error() != "incomplete instruction at 2" {
+		t.Fatalf("wrong error %v", err)
+	}
+}
+
+func TestCFGDOT(t *testing.T) {
+	cfg, err := DisassembleCFG(common.FromHex("6005573656" + "5b00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph cfg {
+	node [shape=box fontname=monospace];
+	b0 [label="00000: PUSH1 0x05\l00002: JUMPI\l"];
+	b0 -> b5;
+	b0 -> b3;
+	b3 [label="00003: CALLDATASIZE\l00004: JUMP\l"];
+	b3 -> indirect [style=dotted];
+	b5 [label="00005: JUMPDEST\l00006: STOP\l"];
+	indirect [label="indirect jump" shape=ellipse];
+}
+`
+	if dot := cfg.DOT(); dot != want {
+		t.Fatalf("wrong DOT output:\n%s\nwant:\n%s", dot, want)
+	}
+	if cfg.Block(5) == nil || cfg.Block(4) != nil {
+		t.Fatal("wrong block lookup")
+	}
+}
@@ -47,6 +47,18 @@ type PrecompiledContract interface {
 	Run(input []byte) ([]byte, error) // Run runs the precompiled contract
 }
 
+// PrecompiledContractWithGas is an optional interface for experimental precompiled
+// contracts which meter their gas use dynamically. Instead of being charged
+// RequiredGas upfront, RunWithGas receives the gas available to the call and returns
+// the amount of gas used.
+//
+// The interface is only honored on non-canonical networks (see params.NetworkNames).
+// On canonical networks, such contracts are run like regular precompiles.
+type PrecompiledContractWithGas interface {
+	PrecompiledContract
+	RunWithGas(input []byte, gas uint64) (ret []byte, gasUsed uint64, err error)
+}
+
 // PrecompiledContracts contains the precompiled contracts supported at the given fork.
 type PrecompiledContracts map[common.Address]PrecompiledContract
 
@@ -221,6 +233,16 @@ func ActivePrecompiles(rules params.Rules) []common.Address {
 // - the _remaining_ gas,
 // - any error that occurred
 func RunPrecompiledContract(p PrecompiledContract, input []byte, suppliedGas uint64, logger *tracing.Hooks) (ret []byte, remainingGas uint64, err error) {
+	if mp, ok := p.(PrecompiledContractWithGas); ok {
+		output, gasUsed, err := mp.RunWithGas(input, suppliedGas)
+		if gasUsed > suppliedGas {
+			return nil, 0, ErrOutOfGas
+		}
+		if logger != nil && logger.OnGasChange != nil {
+			logger.OnGasChange(suppliedGas, suppliedGas-gasUsed, tracing.GasChangeCallPrecompiledContract)
+		}
+		return output, suppliedGas - gasUsed, err
+	}
 	gasCost := p.RequiredGas(input)
 	if suppliedGas < gasCost {
 		return nil, 0, ErrOutOfGas
@@ -205,6 +205,9 @@ func opTload(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]by
 	hash := common.Hash(loc.Bytes32())
 	val := interpreter.evm.StateDB.GetTransientState(scope.Contract.Address(), hash)
 	loc.SetBytes(val.Bytes())
+	if tracer := interpreter.evm.Config.Tracer; tracer != nil && tracer.OnTransientStorageRead != nil {
+		tracer.OnTransientStorageRead(scope.Contract.Address(), hash, val)
+	}
 	return nil, nil
 }
 
@@ -213,9 +216,17 @@ func opTstore(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]b
 	if interpreter.readOnly {
 		return nil, ErrWriteProtection
 	}
-	loc := scope.Stack.pop()
-	val := scope.Stack.pop()
-	interpreter.evm.StateDB.SetTransientState(scope.Contract.Address(), loc.Bytes32(), val.Bytes32())
+	var (
+		loc  = scope.Stack.pop()
+		val  = scope.Stack.pop()
+		addr = scope.Contract.Address()
+		slot = common.Hash(loc.Bytes32())
+	)
+	if tracer := interpreter.evm.Config.Tracer; tracer != nil && tracer.OnTransientStorageWrite != nil {
+		prev := interpreter.evm.StateDB.GetTransientState(addr, slot)
+		tracer.OnTransientStorageWrite(addr, slot, prev, val.Bytes32())
+	}
+	interpreter.evm.StateDB.SetTransientState(addr, slot, val.Bytes32())
 	return nil, nil
 }
 
@@ -276,14 +287,26 @@ func opMcopy(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]by
 	return nil, nil
 }
 
+// BlobHashAt returns the versioned blob hash at the given index like the BLOBHASH
+// opcode does, i.e. the zero hash if the index is out of range.
+func BlobHashAt(blobHashes []common.Hash, index uint64) common.Hash {
+	if index < uint64(len(blobHashes)) {
+		return blobHashes[index]
+	}
+	return common.Hash{}
+}
+
 // opBlobHash implements the BLOBHASH opcode
 func opBlobHash(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
 	index := scope.Stack.peek()
-	if index.LtUint64(uint64(len(interpreter.evm.TxContext.BlobHashes))) {
-		blobHash := interpreter.evm.TxContext.BlobHashes[index.Uint64()]
-		index.SetBytes32(blobHash[:])
-	} else {
-		index.Clear()
+	i, overflow := index.Uint64WithOverflow()
+	if overflow {
+		i = math.MaxUint64 // out of range either way
+	}
+	blobHash := BlobHashAt(interpreter.evm.TxContext.BlobHashes, i)
+	index.SetBytes32(blobHash[:])
+	if tracer := interpreter.evm.Config.Tracer; tracer != nil && tracer.OnBlobHashRead != nil {
+		tracer.OnBlobHashRead(i, blobHash)
 	}
 	return nil, nil
 }
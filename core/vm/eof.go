@@ -149,24 +149,30 @@ func (c *Container) MarshalBinary() []byte {
 	return b
 }
 
-// UnmarshalBinary decodes an EOF container.
+// UnmarshalBinary decodes an EOF container, using the default initcode size limit.
 func (c *Container) UnmarshalBinary(b []byte, isInitcode bool) error {
-	return c.unmarshalContainer(b, isInitcode, true)
+	return c.unmarshalContainer(b, isInitcode, true, params.MaxInitCodeSize)
+}
+
+// UnmarshalBinaryWithConfig decodes an EOF container, using the initcode size limit
+// of the given chain config.
+func (c *Container) UnmarshalBinaryWithConfig(b []byte, isInitcode bool, config *params.ChainConfig) error {
+	return c.unmarshalContainer(b, isInitcode, true, config.InitCodeSizeLimit())
 }
 
 // UnmarshalSubContainer decodes an EOF container that is inside another container.
 func (c *Container) UnmarshalSubContainer(b []byte, isInitcode bool) error {
-	return c.unmarshalContainer(b, isInitcode, false)
+	return c.unmarshalContainer(b, isInitcode, false, params.MaxInitCodeSize)
 }
 
-func (c *Container) unmarshalContainer(b []byte, isInitcode bool, topLevel bool) error {
+func (c *Container) unmarshalContainer(b []byte, isInitcode bool, topLevel bool, maxSize int) error {
 	if !hasEOFMagic(b) {
 		return fmt.Errorf("%w: want %x", errInvalidMagic, eofMagic)
 	}
 	if len(b) < 14 {
 		return io.ErrUnexpectedEOF
 	}
-	if len(b) > params.MaxInitCodeSize {
+	if len(b) > maxSize {
 		return ErrMaxInitCodeSizeExceeded
 	}
 	if !isEOFVersion1(b) {
@@ -304,7 +310,7 @@ func (c *Container) unmarshalContainer(b []byte, isInitcode bool, topLevel bool)
 			}
 			subC := new(Container)
 			end := min(idx+size, len(b))
-			if err := subC.unmarshalContainer(b[idx:end], isInitcode, false); err != nil {
+			if err := subC.unmarshalContainer(b[idx:end], isInitcode, false, maxSize); err != nil {
 				if topLevel {
 					return fmt.Errorf("%w in sub container %d", err, i)
 				}
@@ -20,6 +20,8 @@ import (
 	"errors"
 	"fmt"
 	"math"
+
+	"github.com/holiman/uint256"
 )
 
 // List evm execution errors
@@ -38,6 +40,7 @@ var (
 	ErrGasUintOverflow          = errors.New("gas uint64 overflow")
 	ErrInvalidCode              = errors.New("invalid code: must not begin with 0xef")
 	ErrNonceUintOverflow        = errors.New("nonce uint64 overflow")
+	ErrMemoryLimit              = errors.New("memory limit exceeded")
 
 	// errStopToken is an internal token indicating interpreter loop termination,
 	// never returned to outside callers.
@@ -77,10 +80,48 @@ func (e ErrStackOverflow) Unwrap() error {
 // ErrInvalidOpCode wraps an evm error when an invalid opcode is encountered.
 type ErrInvalidOpCode struct {
 	opcode OpCode
+	pc     uint64
 }
 
 func (e *ErrInvalidOpCode) Error() string { return fmt.Sprintf("invalid opcode: %s", e.opcode) }
 
+// OpCode returns the invalid opcode.
+func (e *ErrInvalidOpCode) OpCode() OpCode { return e.opcode }
+
+// PC returns the position of the invalid opcode in the code.
+func (e *ErrInvalidOpCode) PC() uint64 { return e.pc }
+
+// ErrInvalidJumpDest is returned when a jump targets an invalid destination. It
+// wraps ErrInvalidJump, carrying the position of the jump and its destination.
+type ErrInvalidJumpDest struct {
+	pc   uint64
+	dest uint256.Int
+}
+
+func (e *ErrInvalidJumpDest) Error() string { return ErrInvalidJump.Error() }
+
+func (e *ErrInvalidJumpDest) Unwrap() error { return ErrInvalidJump }
+
+// PC returns the position of the jump instruction in the code.
+func (e *ErrInvalidJumpDest) PC() uint64 { return e.pc }
+
+// Destination returns the jump destination.
+func (e *ErrInvalidJumpDest) Destination() uint256.Int { return e.dest }
+
+// ErrStepLimitExceeded is returned when the number of executed opcodes exceeds
+// the limit set in Config.MaxSteps.
+type ErrStepLimitExceeded struct {
+	steps uint64
+	limit uint64
+}
+
+func (e *ErrStepLimitExceeded) Error() string {
+	return fmt.Sprintf("execution step limit exceeded (%d > %d)", e.steps, e.limit)
+}
+
+// Steps returns the number of steps executed when the limit was hit.
+func (e *ErrStepLimitExceeded) Steps() uint64 { return e.steps }
+
 // rpcError is the same interface as the one defined in rpc/errors.go
 // but we do not want to depend on rpc package here so we redefine it.
 //
@@ -147,6 +188,8 @@ const (
 	VMErrorCodeStackUnderflow
 	VMErrorCodeStackOverflow
 	VMErrorCodeInvalidOpCode
+	VMErrorCodeStepLimitExceeded
+	VMErrorCodeMemoryLimit
 
 	// VMErrorCodeUnknown explicitly marks an error as unknown, this is useful when error is converted
 	// from an actual `error` in which case if the mapping is not known, we can use this value to indicate that.
@@ -181,6 +224,8 @@ func vmErrorCodeFromErr(err error) int {
 		return VMErrorCodeInvalidCode
 	case errors.Is(err, ErrNonceUintOverflow):
 		return VMErrorCodeNonceUintOverflow
+	case errors.Is(err, ErrMemoryLimit):
+		return VMErrorCodeMemoryLimit
 
 	default:
 		// Dynamic errors
@@ -196,6 +241,10 @@ func vmErrorCodeFromErr(err error) int {
 			return VMErrorCodeInvalidOpCode
 		}
 
+		if v := (*ErrStepLimitExceeded)(nil); errors.As(err, &v) {
+			return VMErrorCodeStepLimitExceeded
+		}
+
 		return VMErrorCodeUnknown
 	}
 }
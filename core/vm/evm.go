@@ -42,7 +42,36 @@ type (
 
 func (evm *EVM) precompile(addr common.Address) (PrecompiledContract, bool) {
 	p, ok := evm.precompiles[addr]
-	return p, ok
+	if !ok {
+		return nil, false
+	}
+	_, metered := p.(PrecompiledContractWithGas)
+	switch {
+	case evm.canonical && metered:
+		// Dynamic metering is never applied on the canonical networks.
+		p = plainPrecompile{p}
+	case !evm.canonical && !metered && evm.Config.PrecompileGas != nil:
+		// Gas overrides are never applied on the canonical networks.
+		// Metered precompiles don't use RequiredGas, so they aren't repriced.
+		p = &repricedPrecompile{PrecompiledContract: p, addr: addr, gas: evm.Config.PrecompileGas}
+	}
+	return p, true
+}
+
+// plainPrecompile hides the optional interfaces of a precompiled contract.
+type plainPrecompile struct {
+	PrecompiledContract
+}
+
+// repricedPrecompile wraps a precompiled contract, applying Config.PrecompileGas.
+type repricedPrecompile struct {
+	PrecompiledContract
+	addr common.Address
+	gas  func(common.Address, []byte, uint64) uint64
+}
+
+func (p *repricedPrecompile) RequiredGas(input []byte) uint64 {
+	return p.gas(p.addr, input, p.PrecompiledContract.RequiredGas(input))
 }
 
 // BlockContext provides the EVM with auxiliary information. Once provided
@@ -114,6 +143,15 @@ type EVM struct {
 	callGasTemp uint64
 	// precompiles holds the precompiled contracts for the current epoch
 	precompiles map[common.Address]PrecompiledContract
+	// canonical is set if the chain is one of params.NetworkNames, which disables
+	// precompile metering and gas overrides
+	canonical bool
+	// accesses records the state accessed by the current transaction, it is
+	// only set if Config.RecordAccessSet is enabled
+	accesses *accessRecorder
+	// opStats collects per-opcode statistics, it is only set if
+	// Config.EnableOpStats is enabled
+	opStats *OpStats
 }
 
 // NewEVM constructs an EVM instance with the supplied block context, state
@@ -128,7 +166,17 @@ func NewEVM(blockCtx BlockContext, statedb StateDB, chainConfig *params.ChainCon
 		chainConfig: chainConfig,
 		chainRules:  chainConfig.Rules(blockCtx.BlockNumber, blockCtx.Random != nil, blockCtx.Time),
 	}
+	if config.RecordAccessSet {
+		evm.accesses = newAccessRecorder(statedb)
+		evm.StateDB = evm.accesses
+	}
+	if config.EnableOpStats {
+		evm.opStats = new(OpStats)
+	}
 	evm.precompiles = activePrecompiledContracts(evm.chainRules)
+	if chainConfig.ChainID != nil {
+		_, evm.canonical = params.NetworkNames[chainConfig.ChainID.String()]
+	}
 	evm.interpreter = NewEVMInterpreter(evm)
 	return evm
 }
@@ -152,6 +200,27 @@ func (evm *EVM) SetTxContext(txCtx TxContext) {
 		txCtx.AccessEvents = state.NewAccessEvents(evm.StateDB.PointCache())
 	}
 	evm.TxContext = txCtx
+	if evm.accesses != nil {
+		evm.accesses.set = types.NewAccessSet()
+	}
+}
+
+// AccessSet returns all accounts and storage slots read or written since the
+// transaction context was last set. This includes the state accessed outside of
+// the EVM execution, such as the sender and coinbase accounts, as well as the
+// accesses of call frames which were reverted. It returns nil if recording is
+// not enabled in the config.
+func (evm *EVM) AccessSet() *types.AccessSet {
+	if evm.accesses == nil {
+		return nil
+	}
+	return evm.accesses.set
+}
+
+// OpStats returns the per-opcode statistics of all code executed by the EVM. It
+// returns nil if collecting them is not enabled in the config.
+func (evm *EVM) OpStats() *OpStats {
+	return evm.opStats
 }
 
 // Cancel cancels any running EVM operation. This may be called concurrently and
@@ -508,6 +577,9 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	contract.SetCodeOptionalHash(&address, codeAndHash)
 	contract.IsDeployment = true
 
+	if evm.Config.Tracer != nil && evm.Config.Tracer.OnContractCreation != nil {
+		evm.Config.Tracer.OnContractCreation(caller.Address(), address, codeAndHash.Hash(), byte(typ))
+	}
 	ret, err = evm.initNewContract(contract, address, value)
 	if err != nil && (evm.chainRules.IsHomestead || err != ErrCodeStoreOutOfGas) {
 		evm.StateDB.RevertToSnapshot(snapshot)
@@ -527,7 +599,7 @@ func (evm *EVM) initNewContract(contract *Contract, address common.Address, valu
 	}
 
 	// Check whether the max code size has been exceeded, assign err if the case.
-	if evm.chainRules.IsEIP158 && len(ret) > params.MaxCodeSize {
+	if evm.chainRules.IsEIP158 && len(ret) > evm.chainConfig.CodeSizeLimit() {
 		return ret, ErrMaxCodeSizeExceeded
 	}
 
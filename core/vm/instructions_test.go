@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
@@ -752,13 +753,23 @@ func TestBlobHash(t *testing.T) {
 		{name: "out-of-bounds (nil)", idx: 25, expect: zero, hashes: nil},
 	} {
 		var (
-			evm   = NewEVM(BlockContext{}, nil, params.TestChainConfig, Config{})
+			traced []common.Hash
+			tracer = &tracing.Hooks{OnBlobHashRead: func(index uint64, hash common.Hash) {
+				if index != tt.idx {
+					t.Errorf("Testcase %v: traced wrong index %d", tt.name, index)
+				}
+				traced = append(traced, hash)
+			}}
+			evm   = NewEVM(BlockContext{}, nil, params.TestChainConfig, Config{Tracer: tracer})
 			stack = newstack()
 			pc    = uint64(0)
 		)
 		evm.SetTxContext(TxContext{BlobHashes: tt.hashes})
 		stack.push(uint256.NewInt(tt.idx))
 		opBlobHash(&pc, evm.interpreter, &ScopeContext{nil, stack, nil})
+		if len(traced) != 1 || traced[0] != tt.expect {
+			t.Errorf("Testcase %v: wrong traced hashes %v", tt.name, traced)
+		}
 		if len(stack.data) != 1 {
 			t.Errorf("Expected one item on stack after %v, got %d: ", tt.name, len(stack.data))
 		}
@@ -773,6 +784,38 @@ func TestBlobHash(t *testing.T) {
 	}
 }
 
+func TestBlobHashAt(t *testing.T) {
+	hashes := []common.Hash{{1}, {2}}
+	for _, tt := range []struct {
+		index uint64
+		want  common.Hash
+	}{
+		{0, common.Hash{1}},
+		{1, common.Hash{2}},
+		{2, common.Hash{}},
+		{^uint64(0), common.Hash{}},
+	} {
+		if have := BlobHashAt(hashes, tt.index); have != tt.want {
+			t.Errorf("index %d: have %x, want %x", tt.index, have, tt.want)
+		}
+	}
+	if have := BlobHashAt(nil, 0); have != (common.Hash{}) {
+		t.Errorf("nil hashes: have %x, want zero", have)
+	}
+	// Indices overflowing 64 bits are out of range.
+	var (
+		evm   = NewEVM(BlockContext{}, nil, params.TestChainConfig, Config{})
+		stack = newstack()
+		pc    = uint64(0)
+	)
+	evm.SetTxContext(TxContext{BlobHashes: hashes})
+	stack.push(new(uint256.Int).Lsh(uint256.NewInt(1), 64))
+	opBlobHash(&pc, evm.interpreter, &ScopeContext{nil, stack, nil})
+	if have := stack.pop(); !have.IsZero() {
+		t.Errorf("overflowing index: have %x, want zero", have)
+	}
+}
+
 func TestOpMCopy(t *testing.T) {
 	// Test cases from https://eips.ethereum.org/EIPS/eip-5656#test-cases
 	for i, tc := range []struct {
@@ -35,6 +35,44 @@ type Config struct {
 	ExtraEips               []int // Additional EIPS that are to be enabled
 
 	StatelessSelfValidation bool // Generate execution witnesses and self-check against them (testing purpose)
+
+	// MaxSteps limits the number of opcodes executed by a top-level call, across all
+	// nested call frames. Execution is aborted with ErrStepLimitExceeded once the
+	// limit is exceeded. Zero means no limit.
+	//
+	// This is meant to protect simulation endpoints such as eth_call against
+	// pathological inputs. It alters execution results, so it is rejected or
+	// ignored when processing blocks.
+	MaxSteps uint64
+
+	// MaxMemorySize limits the memory size of each call frame in bytes. An opcode
+	// expanding memory beyond the limit fails with ErrMemoryLimit before any gas is
+	// charged for it. Like other errors, this consumes the remaining gas of the call
+	// frame, but doesn't abort the calling frames. Zero means no limit.
+	//
+	// Like MaxSteps, this is meant for simulation environments. It alters execution
+	// results, so it is rejected or ignored when processing blocks.
+	MaxMemorySize uint64
+
+	// PrecompileGas, if set, replaces the gas cost of precompiled contracts. It is
+	// called with the address of the precompile, its input and the regular gas
+	// cost, and returns the gas to charge instead.
+	//
+	// This is meant for evaluating repricing proposals in a sandbox. It alters
+	// execution results and is ignored on the canonical networks.
+	PrecompileGas func(addr common.Address, input []byte, gas uint64) uint64
+
+	// RecordAccessSet enables recording of all accounts and storage slots read or
+	// written by a transaction, see EVM.AccessSet.
+	RecordAccessSet bool
+
+	// OpHandler, if set, receives the decoded operands of the opcodes it has hooks
+	// for. Other opcodes are not affected.
+	OpHandler *OpHandler
+
+	// EnableOpStats enables collecting the number of executions and the gas used
+	// per opcode, see EVM.OpStats.
+	EnableOpStats bool
 }
 
 // ScopeContext contains the things that are per-call, such as stack and memory,
@@ -99,6 +137,10 @@ type EVMInterpreter struct {
 
 	readOnly   bool   // Whether to throw on stateful modifications
 	returnData []byte // Last CALL's return data for subsequent reuse
+	steps      uint64 // Number of opcodes executed by the current top-level call
+
+	decoders     *[256]opDecoder // Operand decoders of Config.OpHandler, nil if unset
+	instrumented bool            // Whether Run needs to use runInstrumented
 }
 
 // NewEVMInterpreter returns a new instance of the Interpreter.
@@ -150,7 +192,12 @@ func NewEVMInterpreter(evm *EVM) *EVMInterpreter {
 		}
 	}
 	evm.Config.ExtraEips = extraEips
-	return &EVMInterpreter{evm: evm, table: table}
+	return &EVMInterpreter{
+		evm:          evm,
+		table:        table,
+		decoders:     newOpDecoders(evm.Config.OpHandler),
+		instrumented: evm.Config.MaxSteps != 0 || evm.Config.MaxMemorySize != 0 || evm.Config.OpHandler != nil || evm.Config.EnableOpStats,
+	}
 }
 
 // Run loops and evaluates the contract's code with the given input data and returns
@@ -180,6 +227,166 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 		return nil, nil
 	}
 
+	if in.instrumented {
+		return in.runInstrumented(contract, input)
+	}
+
+	var (
+		op          OpCode        // current opcode
+		mem         = NewMemory() // bound memory
+		stack       = newstack()  // local stack
+		callContext = &ScopeContext{
+			Memory:   mem,
+			Stack:    stack,
+			Contract: contract,
+		}
+		// For optimisation reason we're using uint64 as the program counter.
+		// It's theoretically possible to go above 2^64. The YP defines the PC
+		// to be uint256. Practically much less so feasible.
+		pc   = uint64(0) // program counter
+		cost uint64
+		// copies used by tracer
+		pcCopy  uint64 // needed for the deferred EVMLogger
+		gasCopy uint64 // for EVMLogger to log gas remaining before execution
+		logged  bool   // deferred EVMLogger should ignore already logged steps
+		res     []byte // result of the opcode execution function
+		debug   = in.evm.Config.Tracer != nil
+	)
+	// Don't move this deferred function, it's placed before the OnOpcode-deferred method,
+	// so that it gets executed _after_: the OnOpcode needs the stacks before
+	// they are returned to the pools
+	defer func() {
+		returnStack(stack)
+		mem.Free()
+	}()
+	contract.Input = input
+
+	if debug {
+		defer func() { // this deferred method handles exit-with-error
+			if err == nil {
+				return
+			}
+			if !logged && in.evm.Config.Tracer.OnOpcode != nil {
+				in.evm.Config.Tracer.OnOpcode(pcCopy, byte(op), gasCopy, cost, callContext, in.returnData, in.evm.depth, VMErrorFromErr(err))
+			}
+			if logged && in.evm.Config.Tracer.OnFault != nil {
+				in.evm.Config.Tracer.OnFault(pcCopy, byte(op), gasCopy, cost, callContext, in.evm.depth, VMErrorFromErr(err))
+			}
+		}()
+	}
+	// The Interpreter main run loop (contextual). This loop runs until either an
+	// explicit STOP, RETURN or SELFDESTRUCT is executed, an error occurred during
+	// the execution of one of the operations or until the done flag is set by the
+	// parent context.
+	for {
+		if debug {
+			// Capture pre-execution values for tracing.
+			logged, pcCopy, gasCopy = false, pc, contract.Gas
+		}
+
+		if in.evm.chainRules.IsEIP4762 && !contract.IsDeployment {
+			// if the PC ends up in a new "chunk" of verkleized code, charge the
+			// associated costs.
+			contractAddr := contract.Address()
+			contract.Gas -= in.evm.TxContext.AccessEvents.CodeChunksRangeGas(contractAddr, pc, 1, uint64(len(contract.Code)), false)
+		}
+
+		// Get the operation from the jump table and validate the stack to ensure there are
+		// enough stack items available to perform the operation.
+		op = contract.GetOp(pc)
+		operation := in.table[op]
+		cost = operation.constantGas // For tracing
+		// Validate stack
+		if sLen := stack.len(); sLen < operation.minStack {
+			return nil, &ErrStackUnderflow{stackLen: sLen, required: operation.minStack}
+		} else if sLen > operation.maxStack {
+			return nil, &ErrStackOverflow{stackLen: sLen, limit: operation.maxStack}
+		}
+		// for tracing: this gas consumption event is emitted below in the debug section.
+		if contract.Gas < cost {
+			return nil, ErrOutOfGas
+		} else {
+			contract.Gas -= cost
+		}
+
+		if operation.dynamicGas != nil {
+			// All ops with a dynamic memory usage also has a dynamic gas cost.
+			var memorySize uint64
+			// calculate the new memory size and expand the memory to fit
+			// the operation
+			// Memory check needs to be done prior to evaluating the dynamic gas portion,
+			// to detect calculation overflows
+			if operation.memorySize != nil {
+				memSize, overflow := operation.memorySize(stack)
+				if overflow {
+					return nil, ErrGasUintOverflow
+				}
+				// memory is expanded in words of 32 bytes. Gas
+				// is also calculated in words.
+				if memorySize, overflow = math.SafeMul(toWordSize(memSize), 32); overflow {
+					return nil, ErrGasUintOverflow
+				}
+			}
+			// Consume the gas and return an error if not enough gas is available.
+			// cost is explicitly set so that the capture state defer method can get the proper cost
+			var dynamicCost uint64
+			dynamicCost, err = operation.dynamicGas(in.evm, contract, stack, mem, memorySize)
+			cost += dynamicCost // for tracing
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrOutOfGas, err)
+			}
+			// for tracing: this gas consumption event is emitted below in the debug section.
+			if contract.Gas < dynamicCost {
+				return nil, ErrOutOfGas
+			} else {
+				contract.Gas -= dynamicCost
+			}
+
+			// Do tracing before memory expansion
+			if debug {
+				if in.evm.Config.Tracer.OnGasChange != nil {
+					in.evm.Config.Tracer.OnGasChange(gasCopy, gasCopy-cost, tracing.GasChangeCallOpCode)
+				}
+				if in.evm.Config.Tracer.OnOpcode != nil {
+					in.evm.Config.Tracer.OnOpcode(pc, byte(op), gasCopy, cost, callContext, in.returnData, in.evm.depth, VMErrorFromErr(err))
+					logged = true
+				}
+			}
+			if memorySize > 0 {
+				mem.Resize(memorySize)
+			}
+		} else if debug {
+			if in.evm.Config.Tracer.OnGasChange != nil {
+				in.evm.Config.Tracer.OnGasChange(gasCopy, gasCopy-cost, tracing.GasChangeCallOpCode)
+			}
+			if in.evm.Config.Tracer.OnOpcode != nil {
+				in.evm.Config.Tracer.OnOpcode(pc, byte(op), gasCopy, cost, callContext, in.returnData, in.evm.depth, VMErrorFromErr(err))
+				logged = true
+			}
+		}
+
+		// execute the operation
+		res, err = operation.execute(&pc, in, callContext)
+		if err != nil {
+			break
+		}
+		pc++
+	}
+
+	if err == errStopToken {
+		err = nil // clear stop token error
+	}
+
+	return res, err
+}
+
+// runInstrumented is the main loop of Run when any of the MaxSteps, MaxMemorySize,
+// OpHandler or EnableOpStats options is set. It is kept apart from the regular
+// loop, so the checks of these options don't slow down the execution without them.
+func (in *EVMInterpreter) runInstrumented(contract *Contract, input []byte) (ret []byte, err error) {
+	if in.evm.depth == 1 {
+		in.steps = 0
+	}
 	var (
 		op          OpCode        // current opcode
 		mem         = NewMemory() // bound memory
@@ -200,6 +407,10 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 		logged  bool   // deferred EVMLogger should ignore already logged steps
 		res     []byte // result of the opcode execution function
 		debug   = in.evm.Config.Tracer != nil
+
+		stats      = in.evm.opStats // nil unless Config.EnableOpStats is set
+		statsGas   uint64           // gas before the current opcode
+		statsTotal uint64           // total of stats before the current opcode
 	)
 	// Don't move this deferred function, it's placed before the OnOpcode-deferred method,
 	// so that it gets executed _after_: the OnOpcode needs the stacks before
@@ -232,6 +443,9 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 			// Capture pre-execution values for tracing.
 			logged, pcCopy, gasCopy = false, pc, contract.Gas
 		}
+		if stats != nil {
+			statsGas, statsTotal = contract.Gas, stats.total
+		}
 
 		if in.evm.chainRules.IsEIP4762 && !contract.IsDeployment {
 			// if the PC ends up in a new "chunk" of verkleized code, charge the
@@ -240,6 +454,11 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 			contract.Gas -= in.evm.TxContext.AccessEvents.CodeChunksRangeGas(contractAddr, pc, 1, uint64(len(contract.Code)), false)
 		}
 
+		if limit := in.evm.Config.MaxSteps; limit != 0 {
+			if in.steps++; in.steps > limit {
+				return nil, &ErrStepLimitExceeded{steps: in.steps, limit: limit}
+			}
+		}
 		// Get the operation from the jump table and validate the stack to ensure there are
 		// enough stack items available to perform the operation.
 		op = contract.GetOp(pc)
@@ -275,6 +494,9 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 				if memorySize, overflow = math.SafeMul(toWordSize(memSize), 32); overflow {
 					return nil, ErrGasUintOverflow
 				}
+				if limit := in.evm.Config.MaxMemorySize; limit != 0 && memorySize > limit {
+					return nil, ErrMemoryLimit
+				}
 			}
 			// Consume the gas and return an error if not enough gas is available.
 			// cost is explicitly set so that the capture state defer method can get the proper cost
@@ -314,8 +536,16 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 			}
 		}
 
+		if in.decoders != nil {
+			if decode := in.decoders[op]; decode != nil {
+				decode(op, callContext)
+			}
+		}
 		// execute the operation
 		res, err = operation.execute(&pc, in, callContext)
+		if stats != nil {
+			stats.record(op, statsGas-contract.Gas, statsTotal)
+		}
 		if err != nil {
 			break
 		}
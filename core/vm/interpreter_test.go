@@ -17,7 +17,10 @@
 package vm
 
 import (
+	"bytes"
+	"errors"
 	"math"
+	"math/big"
 	"testing"
 	"time"
 
@@ -74,3 +77,226 @@ func TestLoopInterrupt(t *testing.T) {
 		}
 	}
 }
+
+func TestMaxSteps(t *testing.T) {
+	address := common.BytesToAddress([]byte("contract"))
+	vmctx := BlockContext{
+		Transfer: func(StateDB, common.Address, common.Address, *uint256.Int) {},
+	}
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	statedb.CreateAccount(address)
+	statedb.SetCode(address, common.Hex2Bytes(loopInterruptTests[0]))
+	statedb.Finalise(true)
+
+	evm := NewEVM(vmctx, statedb, params.AllEthashProtocolChanges, Config{MaxSteps: 1000})
+	// The step count is reset for every top-level call.
+	for i := 0; i < 2; i++ {
+		_, _, err := evm.Call(AccountRef(common.Address{}), address, nil, math.MaxUint64, new(uint256.Int))
+		var stepErr *ErrStepLimitExceeded
+		if !errors.As(err, &stepErr) {
+			t.Fatalf("call %d: wrong error: %v", i, err)
+		}
+		if stepErr.Steps() != 1001 {
+			t.Errorf("call %d: wrong step count: %d", i, stepErr.Steps())
+		}
+		if code := VMErrorFromErr(err).(*VMError).ErrorCode(); code != VMErrorCodeStepLimitExceeded {
+			t.Errorf("call %d: wrong error code: %d", i, code)
+		}
+	}
+}
+
+func TestMaxMemorySize(t *testing.T) {
+	tests := []struct {
+		code  string
+		limit uint64
+		gas   uint64
+		err   error
+	}{
+		{"600162100000" + "5200", 0, 10000000, nil},                                  // mstore(0x100000, 1)
+		{"600162100000" + "5200", 0x100020, 10000000, nil},                           // limit is exactly reached
+		{"600162100000" + "5200", 0x10000, 10000000, ErrMemoryLimit},                 // limit is exceeded
+		{"6001" + "64ffffffffff" + "5200", 0, 100000, ErrOutOfGas},                   // mstore(2^40-1, 1)
+		{"6001" + "64ffffffffff" + "5200", 0x10000, 100000, ErrMemoryLimit},          // limit is checked before gas
+		{"6000" + "64ffffffffff" + "2000", 0x10000, 100000, nil},                     // keccak256 of empty range
+		{"6020" + "6000" + "64ffffffffff" + "3700", 0x10000, 100000, ErrMemoryLimit}, // calldatacopy
+	}
+	address := common.BytesToAddress([]byte("contract"))
+	vmctx := BlockContext{
+		Transfer: func(StateDB, common.Address, common.Address, *uint256.Int) {},
+	}
+	for i, test := range tests {
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+		statedb.CreateAccount(address)
+		statedb.SetCode(address, common.Hex2Bytes(test.code))
+		statedb.Finalise(true)
+
+		evm := NewEVM(vmctx, statedb, params.AllEthashProtocolChanges, Config{MaxMemorySize: test.limit})
+		_, _, err := evm.Call(AccountRef(common.Address{}), address, nil, test.gas, new(uint256.Int))
+		if !errors.Is(err, test.err) {
+			t.Errorf("test %d: wrong error %v, want %v", i, err, test.err)
+		}
+		if test.err == ErrMemoryLimit {
+			if code := VMErrorFromErr(err).(*VMError).ErrorCode(); code != VMErrorCodeMemoryLimit {
+				t.Errorf("test %d: wrong error code: %d", i, code)
+			}
+		}
+	}
+}
+
+func TestInvalidJumpError(t *testing.T) {
+	tests := []struct {
+		code string
+		pc   uint64
+		dest uint64
+	}{
+		{"600456", 2, 4},                     // PUSH1 4, JUMP
+		{"6001600757", 4, 7},                 // PUSH1 1, PUSH1 7, JUMPI
+		{"5b6101005600", 4, 256},             // JUMPDEST, PUSH2 256, JUMP, STOP
+		{"605b600156", 4, 1},                 // PUSH1 0x5b, PUSH1 1, JUMP into push data
+		{"60016401000000005700", 8, 1 << 32}, // destination exceeding the code size
+	}
+	address := common.BytesToAddress([]byte("contract"))
+	vmctx := BlockContext{
+		Transfer: func(StateDB, common.Address, common.Address, *uint256.Int) {},
+	}
+	for i, test := range tests {
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+		statedb.CreateAccount(address)
+		statedb.SetCode(address, common.FromHex(test.code))
+		statedb.Finalise(true)
+
+		evm := NewEVM(vmctx, statedb, params.AllEthashProtocolChanges, Config{})
+		_, left, err := evm.Call(AccountRef(common.Address{}), address, nil, 100000, new(uint256.Int))
+		if !errors.Is(err, ErrInvalidJump) || err.Error() != ErrInvalidJump.Error() {
+			t.Fatalf("test %d: wrong error %v", i, err)
+		}
+		if left != 0 {
+			t.Errorf("test %d: %d gas left after invalid jump", i, left)
+		}
+		var jumpErr *ErrInvalidJumpDest
+		if !errors.As(err, &jumpErr) {
+			t.Fatalf("test %d: error does not carry jump details: %T", i, err)
+		}
+		dest := jumpErr.Destination()
+		if jumpErr.PC() != test.pc || !dest.Eq(uint256.NewInt(test.dest)) {
+			t.Errorf("test %d: wrong jump details pc %d, dest %v, want pc %d, dest %d", i, jumpErr.PC(), &dest, test.pc, test.dest)
+		}
+		if code := VMErrorFromErr(err).(*VMError).ErrorCode(); code != VMErrorCodeInvalidJump {
+			t.Errorf("test %d: wrong error code: %d", i, code)
+		}
+	}
+}
+
+func TestInvalidOpCodeError(t *testing.T) {
+	address := common.BytesToAddress([]byte("contract"))
+	vmctx := BlockContext{
+		Transfer: func(StateDB, common.Address, common.Address, *uint256.Int) {},
+	}
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	statedb.CreateAccount(address)
+	statedb.SetCode(address, common.FromHex("60015b0c")) // PUSH1 1, JUMPDEST, 0x0c
+	statedb.Finalise(true)
+
+	evm := NewEVM(vmctx, statedb, params.AllEthashProtocolChanges, Config{})
+	_, _, err := evm.Call(AccountRef(common.Address{}), address, nil, 100000, new(uint256.Int))
+	var opErr *ErrInvalidOpCode
+	if !errors.As(err, &opErr) {
+		t.Fatalf("wrong error %v", err)
+	}
+	if opErr.PC() != 3 || opErr.OpCode() != 0x0c {
+		t.Errorf("wrong opcode details pc %d, opcode %v", opErr.PC(), opErr.OpCode())
+	}
+	if err.Error() != "invalid opcode: opcode 0xc not defined" {
+		t.Errorf("wrong error message %q", err)
+	}
+}
+
+func TestPrecompileGasOverride(t *testing.T) {
+	var (
+		identity = common.BytesToAddress([]byte{4})
+		input    = make([]byte, 64)
+		override = func(addr common.Address, input []byte, gas uint64) uint64 {
+			if addr != identity {
+				t.Errorf("wrong precompile address %v", addr)
+			}
+			return gas * 2
+		}
+		vmctx = BlockContext{
+			BlockNumber: new(big.Int),
+			Transfer:    func(StateDB, common.Address, common.Address, *uint256.Int) {},
+		}
+	)
+	tests := []struct {
+		config *params.ChainConfig
+		want   uint64
+	}{
+		{params.AllEthashProtocolChanges, 2 * (params.IdentityBaseGas + 2*params.IdentityPerWordGas)},
+		{params.MainnetChainConfig, params.IdentityBaseGas + 2*params.IdentityPerWordGas},
+	}
+	for _, test := range tests {
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+		evm := NewEVM(vmctx, statedb, test.config, Config{PrecompileGas: override})
+		_, left, err := evm.Call(AccountRef(common.Address{}), identity, input, 1000, new(uint256.Int))
+		if err != nil {
+			t.Fatalf("chain %v: call failed: %v", test.config.ChainID, err)
+		}
+		if used := 1000 - left; used != test.want {
+			t.Errorf("chain %v: wrong gas used %d, want %d", test.config.ChainID, used, test.want)
+		}
+	}
+}
+
+// thresholdPrecompile is a metered precompile which refuses to run with less than
+// min gas available.
+type thresholdPrecompile struct {
+	min uint64
+}
+
+func (p *thresholdPrecompile) RequiredGas(input []byte) uint64 { return 100 }
+
+func (p *thresholdPrecompile) Run(input []byte) ([]byte, error) { return input, nil }
+
+func (p *thresholdPrecompile) RunWithGas(input []byte, gas uint64) ([]byte, uint64, error) {
+	if gas < p.min {
+		return nil, 0, errThresholdGas
+	}
+	return input, 10, nil
+}
+
+var errThresholdGas = errors.New("gas below threshold")
+
+func TestPrecompileWithGas(t *testing.T) {
+	var (
+		addr  = common.BytesToAddress([]byte{0x01, 0x00})
+		vmctx = BlockContext{
+			BlockNumber: new(big.Int),
+			Transfer:    func(StateDB, common.Address, common.Address, *uint256.Int) {},
+		}
+	)
+	tests := []struct {
+		config  *params.ChainConfig
+		gas     uint64
+		wantErr error
+		wantUse uint64
+	}{
+		{params.AllEthashProtocolChanges, 1000, nil, 10},
+		{params.AllEthashProtocolChanges, 400, errThresholdGas, 400},
+		// On canonical networks, the precompile runs with its RequiredGas.
+		{params.MainnetChainConfig, 400, nil, 100},
+	}
+	for _, test := range tests {
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+		evm := NewEVM(vmctx, statedb, test.config, Config{})
+		evm.SetPrecompiles(PrecompiledContracts{addr: &thresholdPrecompile{min: 500}})
+		ret, left, err := evm.Call(AccountRef(common.Address{}), addr, []byte{1, 2, 3}, test.gas, new(uint256.Int))
+		if !errors.Is(err, test.wantErr) {
+			t.Fatalf("chain %v, gas %d: wrong error %v, want %v", test.config.ChainID, test.gas, err, test.wantErr)
+		}
+		if err == nil && !bytes.Equal(ret, []byte{1, 2, 3}) {
+			t.Errorf("chain %v, gas %d: wrong output %x", test.config.ChainID, test.gas, ret)
+		}
+		if used := test.gas - left; used != test.wantUse {
+			t.Errorf("chain %v, gas %d: wrong gas used %d, want %d", test.config.ChainID, test.gas, used, test.wantUse)
+		}
+	}
+}
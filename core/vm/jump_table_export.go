@@ -56,6 +56,31 @@ func LookupInstructionSet(rules params.Rules) (JumpTable, error) {
 	return newFrontierInstructionSet(), nil
 }
 
+// GasCostTable returns the constant gas cost of the opcodes defined in the instruction
+// set of the given rules, i.e. the cost charged by the interpreter before executing
+// an opcode.
+//
+// Dynamic costs are not included. They are charged on top of the constant cost and
+// depend on operands and state, e.g. memory expansion, copy sizes, the EXP exponent,
+// storage changes and value transfers. Since EIP-2929 (Berlin), opcodes accessing an
+// account have the warm access cost as constant cost and the cold access surcharge
+// is dynamic, while SLOAD is charged entirely as dynamic cost.
+//
+// Like LookupInstructionSet, it returns an error for forks which are not defined yet.
+func GasCostTable(rules params.Rules) (map[OpCode]uint64, error) {
+	table, err := LookupInstructionSet(rules)
+	if err != nil {
+		return nil, err
+	}
+	costs := make(map[OpCode]uint64)
+	for i, op := range table {
+		if !op.undefined {
+			costs[OpCode(i)] = op.constantGas
+		}
+	}
+	return costs, nil
+}
+
 // Stack returns the minimum and maximum stack requirements.
 func (op *operation) Stack() (int, int) {
 	return op.minStack, op.maxStack
@@ -19,6 +19,7 @@ package vm
 import (
 	"testing"
 
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/stretchr/testify/require"
 )
 
@@ -33,3 +34,44 @@ func TestJumpTableCopy(t *testing.T) {
 	require.Equal(t, uint64(100), deepCopy[SLOAD].constantGas)
 	require.Equal(t, uint64(0), tbl[SLOAD].constantGas)
 }
+
+func TestGasCostTable(t *testing.T) {
+	costs := func(rules params.Rules) map[OpCode]uint64 {
+		table, err := GasCostTable(rules)
+		require.NoError(t, err)
+		return table
+	}
+	var (
+		istanbul = costs(params.Rules{IsIstanbul: true})
+		berlin   = costs(params.Rules{IsIstanbul: true, IsBerlin: true})
+		london   = costs(params.Rules{IsIstanbul: true, IsBerlin: true, IsLondon: true})
+	)
+	tests := []struct {
+		op                       OpCode
+		istanbul, berlin, london uint64
+	}{
+		{ADD, 3, 3, 3},
+		{SLOAD, 800, 0, 0},
+		{BALANCE, 700, 100, 100},
+		{EXTCODEHASH, 700, 100, 100},
+		{CALL, 700, 100, 100},
+		{SELFDESTRUCT, 0, 5000, 5000}, // dynamic before EIP-2929
+		{SELFBALANCE, 5, 5, 5},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.istanbul, istanbul[test.op], "istanbul %v", test.op)
+		require.Equal(t, test.berlin, berlin[test.op], "berlin %v", test.op)
+		require.Equal(t, test.london, london[test.op], "london %v", test.op)
+	}
+	// Opcodes are only present in the forks defining them.
+	_, ok := berlin[BASEFEE]
+	require.False(t, ok, "BASEFEE defined in berlin")
+	require.Equal(t, uint64(2), london[BASEFEE])
+	_, ok = london[PUSH0]
+	require.False(t, ok, "PUSH0 defined in london")
+	require.Equal(t, uint64(0), london[STOP])
+
+	// Undefined forks are rejected.
+	_, err := GasCostTable(params.Rules{IsCancun: true, IsPrague: true})
+	require.Error(t, err)
+}
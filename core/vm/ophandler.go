@@ -0,0 +1,140 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+)
+
+// OpHandler receives the operands of selected opcodes, decoded from the stack. The
+// hooks are invoked after gas has been charged and memory has been expanded, right
+// before the opcode is executed. Only opcodes with a non-nil hook are decoded.
+//
+// Slices passed to the hooks refer to EVM memory and are only valid during the call.
+type OpHandler struct {
+	// OnSload is called for SLOAD.
+	OnSload func(scope *ScopeContext, slot common.Hash)
+
+	// OnSstore is called for SSTORE.
+	OnSstore func(scope *ScopeContext, slot, value common.Hash)
+
+	// OnCall is called for CALL, CALLCODE, DELEGATECALL and STATICCALL.
+	OnCall func(scope *ScopeContext, op OpCode, args CallArgs)
+
+	// OnCreate is called for CREATE and CREATE2.
+	OnCreate func(scope *ScopeContext, op OpCode, args CreateArgs)
+
+	// OnLog is called for LOG0 to LOG4.
+	OnLog func(scope *ScopeContext, topics []common.Hash, data []byte)
+
+	// OnSelfdestruct is called for SELFDESTRUCT.
+	OnSelfdestruct func(scope *ScopeContext, beneficiary common.Address)
+}
+
+// CallArgs contains the operands of a call opcode.
+type CallArgs struct {
+	Gas   uint256.Int    // requested gas
+	To    common.Address // called account
+	Value uint256.Int    // transferred value, zero for DELEGATECALL and STATICCALL
+
+	// Location of the call input and output in memory. An offset is only
+	// meaningful if the corresponding size is non-zero.
+	InOffset, InSize   uint64
+	RetOffset, RetSize uint64
+}
+
+// CreateArgs contains the operands of a contract creation opcode.
+type CreateArgs struct {
+	Value  uint256.Int // endowment of the new contract
+	Offset uint64      // location of the init code in memory, if Size is non-zero
+	Size   uint64
+	Salt   common.Hash // CREATE2 only
+}
+
+// opDecoder decodes the operands of an opcode and passes them to an OpHandler hook.
+type opDecoder func(op OpCode, scope *ScopeContext)
+
+// newOpDecoders creates the decoders for all opcodes with a hook in h.
+func newOpDecoders(h *OpHandler) *[256]opDecoder {
+	if h == nil {
+		return nil
+	}
+	var d [256]opDecoder
+	if h.OnSload != nil {
+		d[SLOAD] = func(op OpCode, scope *ScopeContext) {
+			h.OnSload(scope, scope.Stack.Back(0).Bytes32())
+		}
+	}
+	if h.OnSstore != nil {
+		d[SSTORE] = func(op OpCode, scope *ScopeContext) {
+			h.OnSstore(scope, scope.Stack.Back(0).Bytes32(), scope.Stack.Back(1).Bytes32())
+		}
+	}
+	if h.OnCall != nil {
+		decodeCall := func(op OpCode, scope *ScopeContext) {
+			var (
+				stack = scope.Stack
+				args  = CallArgs{Gas: *stack.Back(0), To: stack.Back(1).Bytes20()}
+				pos   = 2
+			)
+			if op == CALL || op == CALLCODE {
+				args.Value = *stack.Back(2)
+				pos++
+			}
+			args.InOffset, args.InSize = stack.Back(pos).Uint64(), stack.Back(pos+1).Uint64()
+			args.RetOffset, args.RetSize = stack.Back(pos+2).Uint64(), stack.Back(pos+3).Uint64()
+			h.OnCall(scope, op, args)
+		}
+		d[CALL], d[CALLCODE], d[DELEGATECALL], d[STATICCALL] = decodeCall, decodeCall, decodeCall, decodeCall
+	}
+	if h.OnCreate != nil {
+		decodeCreate := func(op OpCode, scope *ScopeContext) {
+			stack := scope.Stack
+			args := CreateArgs{Value: *stack.Back(0), Offset: stack.Back(1).Uint64(), Size: stack.Back(2).Uint64()}
+			if op == CREATE2 {
+				args.Salt = stack.Back(3).Bytes32()
+			}
+			h.OnCreate(scope, op, args)
+		}
+		d[CREATE], d[CREATE2] = decodeCreate, decodeCreate
+	}
+	if h.OnLog != nil {
+		for op := LOG0; op <= LOG4; op++ {
+			n := int(op - LOG0)
+			d[op] = func(op OpCode, scope *ScopeContext) {
+				stack := scope.Stack
+				topics := make([]common.Hash, n)
+				for i := range topics {
+					topics[i] = stack.Back(2 + i).Bytes32()
+				}
+				offset, size := stack.Back(0), stack.Back(1)
+				var data []byte
+				if !size.IsZero() {
+					data = scope.Memory.GetPtr(offset.Uint64(), size.Uint64())
+				}
+				h.OnLog(scope, topics, data)
+			}
+		}
+	}
+	if h.OnSelfdestruct != nil {
+		d[SELFDESTRUCT] = func(op OpCode, scope *ScopeContext) {
+			h.OnSelfdestruct(scope, scope.Stack.Back(0).Bytes20())
+		}
+	}
+	return &d
+}
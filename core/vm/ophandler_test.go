@@ -0,0 +1,145 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// opHandlerTestCode exercises all opcodes supported by OpHandler.
+var opHandlerTestCode = common.FromHex("602a" + "6001" + "55" + // sstore(1, 42)
+	"6001" + "54" + "50" + // pop(sload(1))
+	"60ff" + "6000" + "52" + // mstore(0, 0xff)
+	"60bb" + "60aa" + "6020" + "6000" + "a2" + // log2(0, 32, 0xaa, 0xbb)
+	"6020" + "6040" + "6020" + "6000" + "6000" + "6004" + "61ffff" + "f1" + "50" + // call identity
+	"6000" + "6000" + "6020" + "6000" + "6004" + "5a" + "fa" + "50" + // staticcall identity
+	"6055" + "6000" + "6000" + "6000" + "f5" + "50" + // create2(0, 0, 0, 0x55)
+	"6077" + "ff") // selfdestruct(0x77)
+
+func TestOpHandler(t *testing.T) {
+	var (
+		address    = common.BytesToAddress([]byte("contract"))
+		statedb, _ = state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+		manual     []string // operands read from the raw stack by the tracer
+		decoded    []string // operands passed to OpHandler
+	)
+	statedb.CreateAccount(address)
+	statedb.SetCode(address, opHandlerTestCode)
+	statedb.Finalise(true)
+
+	tracer := &tracing.Hooks{
+		OnOpcode: func(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+			stack := scope.StackData()
+			back := func(i int) *uint256.Int { return &stack[len(stack)-1-i] }
+			switch OpCode(op) {
+			case SLOAD:
+				manual = append(manual, fmt.Sprintf("SLOAD %x", back(0).Bytes32()))
+			case SSTORE:
+				manual = append(manual, fmt.Sprintf("SSTORE %x %x", back(0).Bytes32(), back(1).Bytes32()))
+			case CALL:
+				manual = append(manual, fmt.Sprintf("CALL %v %x %v %d %d %d %d", back(0), back(1).Bytes20(), back(2), back(3).Uint64(), back(4).Uint64(), back(5).Uint64(), back(6).Uint64()))
+			case STATICCALL:
+				manual = append(manual, fmt.Sprintf("STATICCALL %v %x %v %d %d %d %d", back(0), back(1).Bytes20(), new(uint256.Int), back(2).Uint64(), back(3).Uint64(), back(4).Uint64(), back(5).Uint64()))
+			case CREATE2:
+				manual = append(manual, fmt.Sprintf("CREATE2 %v %d %d %x", back(0), back(1).Uint64(), back(2).Uint64(), back(3).Bytes32()))
+			case LOG2:
+				data := scope.MemoryData()[back(0).Uint64() : back(0).Uint64()+back(1).Uint64()]
+				manual = append(manual, fmt.Sprintf("LOG [%x %x] %x", back(2).Bytes32(), back(3).Bytes32(), data))
+			case SELFDESTRUCT:
+				manual = append(manual, fmt.Sprintf("SELFDESTRUCT %x", back(0).Bytes20()))
+			}
+		},
+	}
+	handler := &OpHandler{
+		OnSload: func(scope *ScopeContext, slot common.Hash) {
+			decoded = append(decoded, fmt.Sprintf("SLOAD %x", slot))
+		},
+		OnSstore: func(scope *ScopeContext, slot, value common.Hash) {
+			decoded = append(decoded, fmt.Sprintf("SSTORE %x %x", slot, value))
+		},
+		OnCall: func(scope *ScopeContext, op OpCode, args CallArgs) {
+			decoded = append(decoded, fmt.Sprintf("%v %v %x %v %d %d %d %d", op, &args.Gas, args.To, &args.Value, args.InOffset, args.InSize, args.RetOffset, args.RetSize))
+		},
+		OnCreate: func(scope *ScopeContext, op OpCode, args CreateArgs) {
+			decoded = append(decoded, fmt.Sprintf("%v %v %d %d %x", op, &args.Value, args.Offset, args.Size, args.Salt))
+		},
+		OnLog: func(scope *ScopeContext, topics []common.Hash, data []byte) {
+			decoded = append(decoded, fmt.Sprintf("LOG %x %x", topics, data))
+		},
+		OnSelfdestruct: func(scope *ScopeContext, beneficiary common.Address) {
+			decoded = append(decoded, fmt.Sprintf("SELFDESTRUCT %x", beneficiary))
+		},
+	}
+	vmctx := BlockContext{
+		BlockNumber: new(big.Int),
+		CanTransfer: func(StateDB, common.Address, *uint256.Int) bool { return true },
+		Transfer:    func(StateDB, common.Address, common.Address, *uint256.Int) {},
+	}
+	evm := NewEVM(vmctx, statedb, params.AllEthashProtocolChanges, Config{Tracer: tracer, OpHandler: handler})
+	if _, _, err := evm.Call(AccountRef(common.Address{}), address, nil, 1000000, new(uint256.Int)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(manual) != 7 {
+		t.Fatalf("wrong number of traced opcodes %d:\n%q", len(manual), manual)
+	}
+	if !slices.Equal(decoded, manual) {
+		t.Fatalf("decoded operands don't match stack:\nhave %q\nwant %q", decoded, manual)
+	}
+	if want := fmt.Sprintf("SSTORE %x %x", common.BigToHash(big.NewInt(1)), common.BigToHash(big.NewInt(42))); decoded[0] != want {
+		t.Errorf("wrong SSTORE operands %q, want %q", decoded[0], want)
+	}
+}
+
+func TestOpHandlerSubset(t *testing.T) {
+	var (
+		address    = common.BytesToAddress([]byte("contract"))
+		statedb, _ = state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+		slots      []common.Hash
+	)
+	statedb.CreateAccount(address)
+	statedb.SetCode(address, opHandlerTestCode)
+	statedb.Finalise(true)
+
+	handler := &OpHandler{
+		OnSstore: func(scope *ScopeContext, slot, value common.Hash) { slots = append(slots, slot) },
+	}
+	evm := NewEVM(BlockContext{
+		BlockNumber: new(big.Int),
+		CanTransfer: func(StateDB, common.Address, *uint256.Int) bool { return true },
+		Transfer:    func(StateDB, common.Address, common.Address, *uint256.Int) {},
+	}, statedb, params.AllEthashProtocolChanges, Config{OpHandler: handler})
+	if evm.interpreter.decoders[SLOAD] != nil || evm.interpreter.decoders[CALL] != nil {
+		t.Fatal("decoders installed for opcodes without hook")
+	}
+	if _, _, err := evm.Call(AccountRef(common.Address{}), address, nil, 1000000, new(uint256.Int)); err != nil {
+		t.Fatal(err)
+	}
+	if len(slots) != 1 || slots[0] != common.BigToHash(big.NewInt(1)) {
+		t.Fatalf("wrong SSTORE slots %x", slots)
+	}
+}
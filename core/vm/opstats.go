@@ -0,0 +1,64 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+// OpStats contains the number of executions and the gas used per opcode, indexed
+// by opcode. Opcodes are counted once they have been charged for, i.e. opcodes
+// failing due to insufficient gas or stack are not included.
+//
+// The gas of call and create opcodes excludes the gas used by the opcodes of the
+// callee, which are counted separately. Thus the total gas of all opcodes equals the
+// gas used by the executed code, unless a call frame failed with an error consuming
+// all of its remaining gas. This gas is attributed to the call opcode of the parent
+// frame, or not at all for the outermost frame.
+type OpStats struct {
+	Counts [256]uint64
+	Gas    [256]uint64
+
+	total uint64 // sum of Gas
+}
+
+// OpStat is the number of executions and the gas used by an opcode.
+type OpStat struct {
+	Count uint64
+	Gas   uint64
+}
+
+// Map returns the statistics of all executed opcodes.
+func (s *OpStats) Map() map[OpCode]OpStat {
+	m := make(map[OpCode]OpStat)
+	for op, count := range s.Counts {
+		if count > 0 {
+			m[OpCode(op)] = OpStat{Count: count, Gas: s.Gas[op]}
+		}
+	}
+	return m
+}
+
+// TotalGas returns the gas used by all opcodes.
+func (s *OpStats) TotalGas() uint64 {
+	return s.total
+}
+
+// record adds an execution of op, which used the given amount of gas including the
+// gas used by callee frames.
+func (s *OpStats) record(op OpCode, gas uint64, totalBefore uint64) {
+	gas -= s.total - totalBefore
+	s.Counts[op]++
+	s.Gas[op] += gas
+	s.total += gas
+}
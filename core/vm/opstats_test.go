@@ -0,0 +1,90 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// opStatsLoopCode counts from 0 to 100, with a JUMP and a JUMPI per iteration.
+var opStatsLoopCode = common.FromHex("6000" + "5b" + "6001" + "01" + // loop: i++
+	"6009" + "56" + "5b" + // jump to next instruction
+	"80" + "6064" + "11" + "6002" + "57" + // jump to loop if i < 100
+	"00")
+
+func TestOpStats(t *testing.T) {
+	var (
+		loop       = common.BytesToAddress([]byte("loop"))
+		caller     = common.BytesToAddress([]byte("caller"))
+		callerCode = append(append(common.FromHex("6000"+"6000"+"6000"+"6000"+"6000"+"73"), loop.Bytes()...), common.FromHex("5a"+"f1"+"50"+"00")...)
+		statedb, _ = state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	)
+	statedb.CreateAccount(loop)
+	statedb.SetCode(loop, opStatsLoopCode)
+	statedb.CreateAccount(caller)
+	statedb.SetCode(caller, callerCode)
+	statedb.Finalise(true)
+	vmctx := BlockContext{
+		BlockNumber: new(big.Int),
+		CanTransfer: func(StateDB, common.Address, *uint256.Int) bool { return true },
+		Transfer:    func(StateDB, common.Address, common.Address, *uint256.Int) {},
+	}
+
+	for _, test := range []struct {
+		name       string
+		to         common.Address
+		wantCounts map[OpCode]uint64
+	}{
+		{"direct", loop, map[OpCode]uint64{JUMP: 100, JUMPI: 100, ADD: 100, STOP: 1}},
+		{"nested", caller, map[OpCode]uint64{JUMP: 100, JUMPI: 100, CALL: 1, STOP: 2}},
+	} {
+		evm := NewEVM(vmctx, statedb.Copy(), params.AllEthashProtocolChanges, Config{EnableOpStats: true})
+		const gas = 1000000
+		_, left, err := evm.Call(AccountRef(common.Address{}), test.to, nil, gas, new(uint256.Int))
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		stats := evm.OpStats()
+		for op, want := range test.wantCounts {
+			if stats.Counts[op] != want {
+				t.Errorf("%s: wrong %v count %d, want %d", test.name, op, stats.Counts[op], want)
+			}
+		}
+		var sum uint64
+		for op, stat := range stats.Map() {
+			if stat.Count == 0 || stat.Gas != stats.Gas[op] {
+				t.Errorf("%s: wrong map entry for %v: %+v", test.name, op, stat)
+			}
+			sum += stat.Gas
+		}
+		if sum != gas-left || stats.TotalGas() != sum {
+			t.Errorf("%s: opcode gas sum %d (total %d) != gas used %d", test.name, sum, stats.TotalGas(), gas-left)
+		}
+	}
+
+	// Stats are not collected by default.
+	if evm := NewEVM(vmctx, statedb, params.AllEthashProtocolChanges, Config{}); evm.OpStats() != nil {
+		t.Fatal("stats collected without EnableOpStats")
+	}
+}
@@ -17,11 +17,13 @@
 package runtime
 
 import (
+	"errors"
 	"math"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -120,6 +122,12 @@ func Execute(code, input []byte, cfg *Config) ([]byte, *state.StateDB, error) {
 	if cfg == nil {
 		cfg = new(Config)
 	}
+	ret, _, err := execute(code, input, cfg)
+	return ret, cfg.State, err
+}
+
+// execute runs the code like Execute, but returns the leftover gas instead of the state.
+func execute(code, input []byte, cfg *Config) ([]byte, uint64, error) {
 	setDefaults(cfg)
 
 	if cfg.State == nil {
@@ -152,7 +160,65 @@ func Execute(code, input []byte, cfg *Config) ([]byte, *state.StateDB, error) {
 	if cfg.EVMConfig.Tracer != nil && cfg.EVMConfig.Tracer.OnTxEnd != nil {
 		cfg.EVMConfig.Tracer.OnTxEnd(&types.Receipt{GasUsed: cfg.GasLimit - leftOverGas}, err)
 	}
-	return ret, cfg.State, err
+	return ret, leftOverGas, err
+}
+
+// RunConfig configures the execution of a bytecode snippet by RunSnippet.
+type RunConfig struct {
+	Config
+
+	// OpcodeGas enables collection of the gas spent per opcode.
+	OpcodeGas bool
+}
+
+// RunResult is the outcome of running a bytecode snippet.
+type RunResult struct {
+	ReturnData []byte               // Data returned by the code, or the revert reason
+	GasUsed    uint64               // Gas used by the execution, excluding intrinsic gas
+	Reverted   bool                 // Whether execution ended with REVERT
+	OpcodeGas  map[vm.OpCode]uint64 // Gas spent per opcode, nil unless requested
+}
+
+// RunSnippet executes the given code with input as call data in an isolated,
+// in-memory environment and reports the gas used by it. No account needs to be
+// deployed: the code is installed at a temporary address before execution.
+//
+// If cfg.OpcodeGas is set, the gas spent by each opcode of the snippet is collected
+// into RunResult.OpcodeGas. Only opcodes executed by the snippet itself are counted,
+// and the cost of call opcodes includes the gas forwarded to the callee.
+//
+// A REVERT is reported through RunResult.Reverted. Other execution failures, such as
+// running out of gas, are returned as an error alongside the result.
+func RunSnippet(code, input []byte, cfg RunConfig) (RunResult, error) {
+	var (
+		config = cfg.Config
+		result RunResult
+	)
+	if cfg.OpcodeGas {
+		result.OpcodeGas = make(map[vm.OpCode]uint64)
+		var hooks tracing.Hooks
+		if config.EVMConfig.Tracer != nil {
+			hooks = *config.EVMConfig.Tracer
+		}
+		inner := hooks.OnOpcode
+		hooks.OnOpcode = func(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+			if depth == 1 {
+				result.OpcodeGas[vm.OpCode(op)] += cost
+			}
+			if inner != nil {
+				inner(pc, op, gas, cost, scope, rData, depth, err)
+			}
+		}
+		config.EVMConfig.Tracer = &hooks
+	}
+	ret, leftOverGas, err := execute(code, input, &config)
+	result.ReturnData = ret
+	result.GasUsed = config.GasLimit - leftOverGas
+	if errors.Is(err, vm.ErrExecutionReverted) {
+		result.Reverted = true
+		err = nil
+	}
+	return result, err
 }
 
 // Create executes the code using the EVM create method
@@ -18,9 +18,11 @@ package runtime
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math/big"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
@@ -35,9 +37,11 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/core/vm/program"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/eth/tracers/logger"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
 
 	// force-load js tracers to trigger registration
 	_ "github.com/ethereum/go-ethereum/eth/tracers/js"
@@ -105,6 +109,218 @@ func TestExecute(t *testing.T) {
 	}
 }
 
+func TestRunSnippet(t *testing.T) {
+	code := []byte{
+		byte(vm.PUSH1), 10,
+		byte(vm.PUSH1), 0,
+		byte(vm.MSTORE),
+		byte(vm.PUSH1), 32,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	}
+	res, err := RunSnippet(code, nil, RunConfig{OpcodeGas: true})
+	if err != nil {
+		t.Fatal("didn't expect error", err)
+	}
+	if num := new(big.Int).SetBytes(res.ReturnData); num.Cmp(big.NewInt(10)) != 0 {
+		t.Error("Expected 10, got", num)
+	}
+	if res.Reverted {
+		t.Error("Expected no revert")
+	}
+	// 4 * PUSH1 + MSTORE (including one word of memory expansion) + RETURN
+	if res.GasUsed != 18 {
+		t.Errorf("wrong gas used: have %d, want %d", res.GasUsed, 18)
+	}
+	want := map[vm.OpCode]uint64{vm.PUSH1: 12, vm.MSTORE: 6, vm.RETURN: 0}
+	if !reflect.DeepEqual(res.OpcodeGas, want) {
+		t.Errorf("wrong opcode gas: have %v, want %v", res.OpcodeGas, want)
+	}
+
+	// Reverts are reported in the result.
+	res, err = RunSnippet([]byte{byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.REVERT)}, nil, RunConfig{})
+	if err != nil {
+		t.Fatal("didn't expect error", err)
+	}
+	if !res.Reverted || res.GasUsed != 6 || res.OpcodeGas != nil {
+		t.Errorf("wrong result for reverting snippet: %+v", res)
+	}
+
+	// Other failures are returned as errors.
+	res, err = RunSnippet(code, nil, RunConfig{Config: Config{GasLimit: 10}})
+	if !errors.Is(err, vm.ErrOutOfGas) {
+		t.Fatalf("wrong error: have %v, want %v", err, vm.ErrOutOfGas)
+	}
+	if res.GasUsed != 10 {
+		t.Errorf("wrong gas used: have %d, want %d", res.GasUsed, 10)
+	}
+}
+
+// Tests that the OnSelfDestruct hook reports the transferred balance and whether
+// the account was destroyed, both before and after EIP-6780.
+func TestSelfDestructHook(t *testing.T) {
+	var (
+		origin      = common.HexToAddress("0xaa")
+		beneficiary = common.HexToAddress("0xbb")
+		code        = []byte{byte(vm.PUSH1), 0xbb, byte(vm.SELFDESTRUCT)}
+	)
+	for _, cancun := range []bool{false, true} {
+		cfg := &Config{Origin: origin, Value: big.NewInt(1000)}
+		setDefaults(cfg)
+		if !cancun {
+			chainConfig := *cfg.ChainConfig
+			chainConfig.CancunTime = nil
+			cfg.ChainConfig = &chainConfig
+		}
+		cfg.State, _ = state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+		cfg.State.SetBalance(origin, uint256.NewInt(1000), tracing.BalanceChangeUnspecified)
+
+		var calls int
+		cfg.EVMConfig.Tracer = &tracing.Hooks{
+			OnSelfDestruct: func(contract, to common.Address, balance *big.Int, destroyed bool) {
+				calls++
+				if contract != common.BytesToAddress([]byte("contract")) || to != beneficiary {
+					t.Errorf("cancun=%v: wrong addresses: contract %x, beneficiary %x", cancun, contract, to)
+				}
+				if balance.Cmp(big.NewInt(1000)) != 0 {
+					t.Errorf("cancun=%v: wrong balance: have %v, want 1000", cancun, balance)
+				}
+				// The contract exists before the call, so it is only destroyed pre-Cancun.
+				if destroyed == cancun {
+					t.Errorf("cancun=%v: wrong destroyed flag: %v", cancun, destroyed)
+				}
+			},
+		}
+		if _, _, err := Execute(code, nil, cfg); err != nil {
+			t.Fatalf("cancun=%v: execution failed: %v", cancun, err)
+		}
+		if calls != 1 {
+			t.Errorf("cancun=%v: hook called %d times, want 1", cancun, calls)
+		}
+	}
+}
+
+// Tests that TSTORE and TLOAD are reported via the transient storage hooks.
+func TestTransientStorageHooks(t *testing.T) {
+	var (
+		slot = common.Hash{31: 0x01}
+		code = []byte{
+			byte(vm.PUSH1), 0x2a, byte(vm.PUSH1), 0x01, byte(vm.TSTORE), // tstore(1, 42)
+			byte(vm.PUSH1), 0x2b, byte(vm.PUSH1), 0x01, byte(vm.TSTORE), // tstore(1, 43)
+			byte(vm.PUSH1), 0x01, byte(vm.TLOAD), // tload(1)
+		}
+		writes [][2]common.Hash
+		reads  []common.Hash
+	)
+	cfg := &Config{
+		EVMConfig: vm.Config{
+			Tracer: &tracing.Hooks{
+				OnTransientStorageRead: func(addr common.Address, s common.Hash, value common.Hash) {
+					if s != slot {
+						t.Errorf("wrong slot read: %x", s)
+					}
+					reads = append(reads, value)
+				},
+				OnTransientStorageWrite: func(addr common.Address, s common.Hash, prev, new common.Hash) {
+					if s != slot {
+						t.Errorf("wrong slot written: %x", s)
+					}
+					writes = append(writes, [2]common.Hash{prev, new})
+				},
+			},
+		},
+	}
+	if _, _, err := Execute(code, nil, cfg); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	wantWrites := [][2]common.Hash{
+		{{}, common.Hash{31: 0x2a}},
+		{common.Hash{31: 0x2a}, common.Hash{31: 0x2b}},
+	}
+	if !reflect.DeepEqual(writes, wantWrites) {
+		t.Errorf("wrong writes: have %x, want %x", writes, wantWrites)
+	}
+	if len(reads) != 1 || reads[0] != (common.Hash{31: 0x2b}) {
+		t.Errorf("wrong reads: have %x", reads)
+	}
+}
+
+// Tests that CREATE and CREATE2 are reported via OnContractCreation.
+func TestContractCreationHook(t *testing.T) {
+	type creation struct {
+		creator, addr common.Address
+		hash          common.Hash
+		op            vm.OpCode
+	}
+	var (
+		contract = common.BytesToAddress([]byte("contract"))
+		initcode = program.New().Return(0, 0).Bytes()
+		salt     = common.Hash{31: 0x42}
+		code     = program.New().
+				Mstore(initcode, 0).
+				Push(len(initcode)).Push(0).Push(0).Op(vm.CREATE, vm.POP).
+				Push(salt.Big()).Push(len(initcode)).Push(0).Push(0).Op(vm.CREATE2, vm.POP).
+				Bytes()
+		have []creation
+	)
+	cfg := &Config{
+		EVMConfig: vm.Config{
+			Tracer: &tracing.Hooks{
+				OnContractCreation: func(creator, addr common.Address, hash common.Hash, op byte) {
+					have = append(have, creation{creator, addr, hash, vm.OpCode(op)})
+				},
+			},
+		},
+	}
+	if _, _, err := Execute(code, nil, cfg); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	hash := crypto.Keccak256Hash(initcode)
+	want := []creation{
+		{contract, crypto.CreateAddress(contract, 0), hash, vm.CREATE},
+		{contract, crypto.CreateAddress2(contract, salt, hash.Bytes()), hash, vm.CREATE2},
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("wrong creations:\nhave %+v\nwant %+v", have, want)
+	}
+}
+
+func TestCodeSizeLimitOverrides(t *testing.T) {
+	var (
+		deploy = program.New().Return(0, params.MaxCodeSize+1).Bytes()
+		create = program.New().Push(params.MaxInitCodeSize + 1).Push(0).Push(0).Op(vm.CREATE).Bytes()
+	)
+	// The default limits apply without overrides.
+	if _, _, _, err := Create(deploy, new(Config)); !errors.Is(err, vm.ErrMaxCodeSizeExceeded) {
+		t.Fatalf("wrong deployment error: %v", err)
+	}
+	if _, _, err := Execute(create, nil, new(Config)); err == nil || !strings.Contains(err.Error(), vm.ErrMaxInitCodeSizeExceeded.Error()) {
+		t.Fatalf("wrong create error: %v", err)
+	}
+
+	// Raised limits allow larger contracts.
+	newConfig := func() *Config {
+		cfg := new(Config)
+		setDefaults(cfg)
+		cfg.ChainConfig.ChainID = big.NewInt(1337)
+		cfg.ChainConfig.MaxCodeSize = new(uint64)
+		*cfg.ChainConfig.MaxCodeSize = 2 * params.MaxCodeSize
+		cfg.ChainConfig.MaxInitCodeSize = new(uint64)
+		*cfg.ChainConfig.MaxInitCodeSize = 2 * params.MaxInitCodeSize
+		return cfg
+	}
+	code, _, _, err := Create(deploy, newConfig())
+	if err != nil {
+		t.Fatalf("deployment failed: %v", err)
+	}
+	if len(code) != params.MaxCodeSize+1 {
+		t.Fatalf("wrong code size %d", len(code))
+	}
+	if _, _, err := Execute(create, nil, newConfig()); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+}
+
 func TestCall(t *testing.T) {
 	state, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
 	address := common.HexToAddress("0xaa")
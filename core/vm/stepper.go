@@ -0,0 +1,250 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+var errStepperRunning = errors.New("stepper used during execution")
+
+// ExecutionState is a checkpoint of the execution of a Stepper. It can be encoded
+// to JSON and restored into a Stepper executing the same contract.
+//
+// The checkpoint only covers the state of the executing call frame. Restoring it
+// yields the same subsequent execution only if the StateDB of the EVM is in the same
+// state as when the checkpoint was taken.
+type ExecutionState struct {
+	PC         uint64         `json:"pc"`
+	Gas        uint64         `json:"gas"`
+	Stack      []hexutil.U256 `json:"stack"`
+	Memory     hexutil.Bytes  `json:"memory"`
+	ReturnData hexutil.Bytes  `json:"returnData"`
+	Steps      uint64         `json:"steps"`
+	TotalSteps uint64         `json:"totalSteps"`
+}
+
+// Stepper executes the code of a contract instruction by instruction, for use in
+// debugging tools. Calls and contract creations made by the code are executed as a
+// single step. Unlike EVM.Call, the Stepper does not transfer value, take a state
+// snapshot or invoke the tracer, and gas is not refunded to the caller. Otherwise,
+// instructions are executed like in EVMInterpreter.Run, honoring the MaxSteps,
+// MaxMemorySize, OpHandler and EnableOpStats options of the EVM config.
+//
+// The Stepper is not used by regular execution and must not be used for consensus
+// purposes.
+type Stepper struct {
+	evm        *EVM
+	contract   *Contract
+	scope      *ScopeContext
+	pc         uint64
+	returnData []byte
+	steps      uint64 // instructions executed by the contract itself
+	totalSteps uint64 // instructions counted against Config.MaxSteps, including calls
+
+	done bool
+	ret  []byte
+	err  error
+}
+
+// NewStepper creates a Stepper executing the code of the given contract with the
+// given input. The contract's gas is used as the gas limit of the execution.
+func NewStepper(evm *EVM, contract *Contract, input []byte) *Stepper {
+	contract.Input = input
+	return &Stepper{
+		evm:      evm,
+		contract: contract,
+		scope:    &ScopeContext{Memory: NewMemory(), Stack: newstack(), Contract: contract},
+		done:     len(contract.Code) == 0,
+	}
+}
+
+// Step executes up to n instructions. It returns true when the execution has finished,
+// along with the error of the execution.
+func (s *Stepper) Step(n int) (bool, error) {
+	if s.done {
+		return true, s.err
+	}
+	if s.evm.depth != 0 {
+		return false, errStepperRunning
+	}
+	in := s.evm.interpreter
+	s.evm.depth++
+	in.returnData, in.steps = s.returnData, s.totalSteps
+	defer func() {
+		s.returnData, s.totalSteps = in.returnData, in.steps
+		s.evm.depth--
+	}()
+
+	for i := 0; i < n && !s.done; i++ {
+		res, err := s.step(in)
+		if err == nil {
+			continue
+		}
+		s.done = true
+		if err == errStopToken {
+			s.ret = res
+		} else {
+			s.ret, s.err = res, err
+		}
+	}
+	return s.done, s.err
+}
+
+// step executes a single instruction. This mirrors the loop of EVMInterpreter.Run,
+// without tracing.
+func (s *Stepper) step(in *EVMInterpreter) ([]byte, error) {
+	var (
+		contract = s.contract
+		stack    = s.scope.Stack
+		mem      = s.scope.Memory
+
+		stats      = in.evm.opStats // nil unless Config.EnableOpStats is set
+		statsGas   uint64           // gas before the current opcode
+		statsTotal uint64           // total of stats before the current opcode
+	)
+	if stats != nil {
+		statsGas, statsTotal = contract.Gas, stats.total
+	}
+	if in.evm.chainRules.IsEIP4762 && !contract.IsDeployment {
+		contractAddr := contract.Address()
+		contract.Gas -= in.evm.TxContext.AccessEvents.CodeChunksRangeGas(contractAddr, s.pc, 1, uint64(len(contract.Code)), false)
+	}
+	if limit := in.evm.Config.MaxSteps; limit != 0 {
+		if in.steps++; in.steps > limit {
+			return nil, &ErrStepLimitExceeded{steps: in.steps, limit: limit}
+		}
+	}
+	s.steps++
+
+	op := contract.GetOp(s.pc)
+	operation := in.table[op]
+	if sLen := stack.len(); sLen < operation.minStack {
+		return nil, &ErrStackUnderflow{stackLen: sLen, required: operation.minStack}
+	} else if sLen > operation.maxStack {
+		return nil, &ErrStackOverflow{stackLen: sLen, limit: operation.maxStack}
+	}
+	if !contract.UseGas(operation.constantGas, nil, 0) {
+		return nil, ErrOutOfGas
+	}
+	if operation.dynamicGas != nil {
+		var memorySize uint64
+		if operation.memorySize != nil {
+			memSize, overflow := operation.memorySize(stack)
+			if overflow {
+				return nil, ErrGasUintOverflow
+			}
+			if memorySize, overflow = math.SafeMul(toWordSize(memSize), 32); overflow {
+				return nil, ErrGasUintOverflow
+			}
+			if limit := in.evm.Config.MaxMemorySize; limit != 0 && memorySize > limit {
+				return nil, ErrMemoryLimit
+			}
+		}
+		dynamicCost, err := operation.dynamicGas(in.evm, contract, stack, mem, memorySize)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrOutOfGas, err)
+		}
+		if !contract.UseGas(dynamicCost, nil, 0) {
+			return nil, ErrOutOfGas
+		}
+		if memorySize > 0 {
+			mem.Resize(memorySize)
+		}
+	}
+	if in.decoders != nil {
+		if decode := in.decoders[op]; decode != nil {
+			decode(op, s.scope)
+		}
+	}
+	res, err := operation.execute(&s.pc, in, s.scope)
+	if stats != nil {
+		stats.record(op, statsGas-contract.Gas, statsTotal)
+	}
+	if err != nil {
+		return res, err
+	}
+	s.pc++
+	return nil, nil
+}
+
+// Done reports whether the execution has finished.
+func (s *Stepper) Done() bool {
+	return s.done
+}
+
+// Result returns the output and error of a finished execution.
+func (s *Stepper) Result() ([]byte, error) {
+	return s.ret, s.err
+}
+
+// Checkpoint returns the current execution state.
+func (s *Stepper) Checkpoint() *ExecutionState {
+	data := s.scope.Stack.Data()
+	state := &ExecutionState{
+		PC:         s.pc,
+		Gas:        s.contract.Gas,
+		Stack:      make([]hexutil.U256, len(data)),
+		Memory:     append(hexutil.Bytes{}, s.scope.Memory.Data()...),
+		ReturnData: append(hexutil.Bytes{}, s.returnData...),
+		Steps:      s.steps,
+		TotalSteps: s.totalSteps,
+	}
+	for i := range data {
+		state.Stack[i] = hexutil.U256(data[i])
+	}
+	return state
+}
+
+// Restore resets the execution to the given checkpoint. The checkpoint must have been
+// taken during the execution of the same code.
+func (s *Stepper) Restore(state *ExecutionState) error {
+	if len(state.Stack) > int(params.StackLimit) {
+		return fmt.Errorf("stack size %d exceeds limit", len(state.Stack))
+	}
+	if len(state.Memory)%32 != 0 {
+		return fmt.Errorf("memory size %d not a multiple of 32", len(state.Memory))
+	}
+	if state.PC > uint64(len(s.contract.Code)) {
+		return fmt.Errorf("pc %d out of code range", state.PC)
+	}
+	stack := newstack()
+	for i := range state.Stack {
+		v := uint256.Int(state.Stack[i])
+		stack.push(&v)
+	}
+	mem := NewMemory()
+	if size := uint64(len(state.Memory)); size > 0 {
+		mem.Resize(size)
+		mem.Set(0, size, state.Memory)
+		words := size / 32
+		mem.lastGasCost = words*params.MemoryGas + words*words/params.QuadCoeffDiv
+	}
+	s.scope.Stack, s.scope.Memory = stack, mem
+	s.pc, s.contract.Gas = state.PC, state.Gas
+	s.steps, s.totalSteps = state.Steps, state.TotalSteps
+	s.returnData = common.CopyBytes(state.ReturnData)
+	s.done, s.ret, s.err = false, nil, nil
+	return nil
+}
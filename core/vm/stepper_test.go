@@ -0,0 +1,238 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"maps"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// stepperTestCode stores the squares of 0..9 in memory, passes two of them through
+// the identity precompile and returns the memory including the return data.
+var stepperTestCode = common.FromHex("6000" + "5b" + "8080" + "02" + "81" + "6020" + "02" + "52" + // loop: mstore(32i, i*i)
+	"6001" + "01" + "80" + "600a" + "11" + "6002" + "57" + "50" + // i++, jump to loop if i < 10
+	"6000" + "6000" + "6040" + "6020" + "6004" + "5a" + "fa" + "50" + // staticcall identity with mem[32:96]
+	"3d" + "6000" + "610200" + "3e" + // returndatacopy(0x200, 0, returndatasize)
+	"610240" + "6000" + "f3") // return mem[0:0x240]
+
+func newStepperTestEVM(t *testing.T) (*EVM, common.Address) {
+	return newStepperTestEVMWithConfig(t, Config{})
+}
+
+func newStepperTestEVMWithConfig(t *testing.T, config Config) (*EVM, common.Address) {
+	address := common.BytesToAddress([]byte("contract"))
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	statedb.CreateAccount(address)
+	statedb.SetCode(address, stepperTestCode)
+	statedb.Finalise(true)
+	vmctx := BlockContext{
+		BlockNumber: new(big.Int),
+		Transfer:    func(StateDB, common.Address, common.Address, *uint256.Int) {},
+	}
+	return NewEVM(vmctx, statedb, params.AllEthashProtocolChanges, config), address
+}
+
+func newTestStepper(evm *EVM, address common.Address, gas uint64) *Stepper {
+	contract := NewContract(AccountRef(common.Address{}), AccountRef(address), new(uint256.Int), gas)
+	contract.SetCallCode(&address, crypto.Keccak256Hash(stepperTestCode), stepperTestCode)
+	return NewStepper(evm, contract, nil)
+}
+
+func TestStepper(t *testing.T) {
+	const gas = 100000
+
+	// Run the code in one shot.
+	evm, address := newStepperTestEVM(t)
+	want, wantGas, err := evm.Call(AccountRef(common.Address{}), address, nil, gas, new(uint256.Int))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(want) != 0x240 || want[0x200+31] != 1 || want[0x220+31] != 4 {
+		t.Fatalf("unexpected output %x", want)
+	}
+	// Run it step by step.
+	evm, address = newStepperTestEVM(t)
+	stepper := newTestStepper(evm, address, gas)
+	var steps int
+	for done := false; !done; steps++ {
+		if done, err = stepper.Step(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ret, err := stepper.Result(); err != nil || !bytes.Equal(ret, want) {
+		t.Fatalf("wrong stepwise result %x, err %v", ret, err)
+	}
+	if stepper.contract.Gas != wantGas {
+		t.Fatalf("wrong gas left %d, want %d", stepper.contract.Gas, wantGas)
+	}
+	// Run it from checkpoints taken at various steps, restored into a new stepper.
+	for _, n := range []int{0, 1, 7, 100, steps - 5, steps - 1} {
+		evm, address := newStepperTestEVM(t)
+		stepper := newTestStepper(evm, address, gas)
+		if done, err := stepper.Step(n); done || err != nil {
+			t.Fatalf("step %d: unexpected end of execution, err %v", n, err)
+		}
+		enc, err := json.Marshal(stepper.Checkpoint())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var checkpoint ExecutionState
+		if err := json.Unmarshal(enc, &checkpoint); err != nil {
+			t.Fatal(err)
+		}
+		resumed := newTestStepper(evm, address, gas)
+		if err := resumed.Restore(&checkpoint); err != nil {
+			t.Fatalf("step %d: restore failed: %v", n, err)
+		}
+		if done, err := resumed.Step(steps); !done || err != nil {
+			t.Fatalf("step %d: execution not finished, err %v", n, err)
+		}
+		if ret, _ := resumed.Result(); !bytes.Equal(ret, want) {
+			t.Errorf("step %d: wrong result %x", n, ret)
+		}
+		if resumed.contract.Gas != wantGas {
+			t.Errorf("step %d: wrong gas left %d, want %d", n, resumed.contract.Gas, wantGas)
+		}
+		if cp := resumed.Checkpoint(); cp.Steps != uint64(steps) {
+			t.Errorf("step %d: wrong step count %d, want %d", n, cp.Steps, steps)
+		}
+	}
+}
+
+// TestStepperConfig checks that the Stepper honors the execution options of the
+// EVM config like EVMInterpreter.Run does.
+func TestStepperConfig(t *testing.T) {
+	const gas = 100000
+
+	// Step limit.
+	evm, address := newStepperTestEVMWithConfig(t, Config{MaxSteps: 10})
+	stepper := newTestStepper(evm, address, gas)
+	for i := 0; i < 5; i++ {
+		if done, err := stepper.Step(2); done || err != nil {
+			t.Fatalf("step %d: unexpected end of execution, err %v", i, err)
+		}
+	}
+	var stepErr *ErrStepLimitExceeded
+	if done, err := stepper.Step(1); !done || !errors.As(err, &stepErr) {
+		t.Fatalf("wrong result with step limit: done %v, err %v", done, err)
+	}
+
+	// Memory limit.
+	evm, address = newStepperTestEVMWithConfig(t, Config{MaxMemorySize: 0x100})
+	stepper = newTestStepper(evm, address, gas)
+	if done, err := stepper.Step(1000); !done || !errors.Is(err, ErrMemoryLimit) {
+		t.Fatalf("wrong result with memory limit: done %v, err %v", done, err)
+	}
+
+	// Opcode statistics, these must match a regular execution.
+	evm, address = newStepperTestEVMWithConfig(t, Config{EnableOpStats: true})
+	if _, _, err := evm.Call(AccountRef(common.Address{}), address, nil, gas, new(uint256.Int)); err != nil {
+		t.Fatal(err)
+	}
+	want := evm.OpStats().Map()
+	evm, address = newStepperTestEVMWithConfig(t, Config{EnableOpStats: true})
+	stepper = newTestStepper(evm, address, gas)
+	if done, err := stepper.Step(1000); !done || err != nil {
+		t.Fatalf("execution not finished, err %v", err)
+	}
+	if have := evm.OpStats().Map(); !maps.Equal(have, want) {
+		t.Fatalf("wrong opcode statistics:\nhave %v\nwant %v", have, want)
+	}
+}
+
+// TestStepperRestoreStepLimit checks that a checkpoint taken after a nested call
+// keeps the instructions of the call counted against the step limit.
+func TestStepperRestoreStepLimit(t *testing.T) {
+	const gas = 100000
+	var (
+		caller = common.BytesToAddress([]byte("caller"))
+		callee = common.BytesToAddress([]byte("callee"))
+		// call(gas, callee, 0, 0, 0, 0, 0), then add two numbers
+		callerCode = common.FromHex("6000" + "6000" + "6000" + "6000" + "6000" + "73" + callee.Hex()[2:] + "5a" + "f1" + "50" +
+			"6001" + "6002" + "01" + "50" + "00")
+		// count to 10 in a loop
+		calleeCode = common.FromHex("6000" + "5b" + "6001" + "01" + "80" + "600a" + "11" + "6002" + "57" + "00")
+	)
+	newEVM := func(maxSteps uint64) *EVM {
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+		statedb.SetCode(caller, callerCode)
+		statedb.SetCode(callee, calleeCode)
+		statedb.Finalise(true)
+		vmctx := BlockContext{
+			BlockNumber: new(big.Int),
+			Transfer:    func(StateDB, common.Address, common.Address, *uint256.Int) {},
+		}
+		return NewEVM(vmctx, statedb, params.AllEthashProtocolChanges, Config{MaxSteps: maxSteps})
+	}
+	newStepper := func(evm *EVM) *Stepper {
+		contract := NewContract(AccountRef(common.Address{}), AccountRef(caller), new(uint256.Int), gas)
+		contract.SetCallCode(&caller, crypto.Keccak256Hash(callerCode), callerCode)
+		return NewStepper(evm, contract, nil)
+	}
+	// Count the instructions of a regular execution, including the call.
+	evm := newEVM(1 << 20)
+	if _, _, err := evm.Call(AccountRef(common.Address{}), caller, nil, gas, new(uint256.Int)); err != nil {
+		t.Fatal(err)
+	}
+	total := evm.interpreter.steps
+
+	for _, limit := range []uint64{total - 1, total} {
+		// Execute the instructions up to and including the call, then resume from a
+		// checkpoint in a new stepper.
+		stepper := newStepper(newEVM(limit))
+		if done, err := stepper.Step(9); done || err != nil {
+			t.Fatalf("limit %d: unexpected end of execution, err %v", limit, err)
+		}
+		enc, err := json.Marshal(stepper.Checkpoint())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var checkpoint ExecutionState
+		if err := json.Unmarshal(enc, &checkpoint); err != nil {
+			t.Fatal(err)
+		}
+		if checkpoint.Steps != 9 || checkpoint.TotalSteps <= checkpoint.Steps {
+			t.Fatalf("limit %d: wrong step counts %d, %d in checkpoint", limit, checkpoint.Steps, checkpoint.TotalSteps)
+		}
+		resumed := newStepper(newEVM(limit))
+		if err := resumed.Restore(&checkpoint); err != nil {
+			t.Fatal(err)
+		}
+		done, err := resumed.Step(100)
+		if !done {
+			t.Fatalf("limit %d: execution not finished", limit)
+		}
+		var stepErr *ErrStepLimitExceeded
+		if limit < total && !errors.As(err, &stepErr) {
+			t.Errorf("limit %d: wrong error %v, want step limit exceeded", limit, err)
+		}
+		if limit == total && err != nil {
+			t.Errorf("limit %d: unexpected error %v", limit, err)
+		}
+	}
+}
@@ -1274,9 +1274,8 @@ func getBody(block *types.Block) *engine.ExecutionPayloadBody {
 	}
 
 	// Post-shanghai withdrawals MUST be set to empty slice instead of nil
-	result.Withdrawals = block.Withdrawals()
-	if block.Withdrawals() == nil && block.Header().WithdrawalsHash != nil {
-		result.Withdrawals = []*types.Withdrawal{}
+	if block.WithdrawalsRoot() != nil {
+		result.Withdrawals = block.Withdrawals()
 	}
 
 	return &result
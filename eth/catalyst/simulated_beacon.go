@@ -93,6 +93,10 @@ type SimulatedBeacon struct {
 	engineAPI          *ConsensusAPI
 	curForkchoiceState engine.ForkchoiceStateV1
 	lastBlockTime      uint64
+
+	timeLock      sync.Mutex // guards blockTime and nextBlockTime
+	blockTime     uint64     // fixed timestamp increment of on-demand blocks, 0 for wall clock
+	nextBlockTime uint64     // timestamp of the next on-demand block, overrides blockTime
 }
 
 // NewSimulatedBeacon constructs a new simulated beacon chain.
@@ -277,7 +281,15 @@ func (c *SimulatedBeacon) setCurrentState(headHash, finalizedHash common.Hash) {
 // Commit seals a block on demand.
 func (c *SimulatedBeacon) Commit() common.Hash {
 	withdrawals := c.withdrawals.pop(10)
-	if err := c.sealBlock(withdrawals, uint64(time.Now().Unix())); err != nil {
+	timestamp := uint64(time.Now().Unix())
+	c.timeLock.Lock()
+	if c.nextBlockTime != 0 {
+		timestamp, c.nextBlockTime = c.nextBlockTime, 0
+	} else if c.blockTime != 0 {
+		timestamp = c.lastBlockTime + c.blockTime
+	}
+	c.timeLock.Unlock()
+	if err := c.sealBlock(withdrawals, timestamp); err != nil {
 		log.Warn("Error performing sealing work", "err", err)
 	}
 	return c.eth.BlockChain().CurrentBlock().Hash()
@@ -317,6 +329,29 @@ func (c *SimulatedBeacon) AdjustTime(adjustment time.Duration) error {
 	return c.sealBlock(withdrawals, parent.Time+uint64(adjustment/time.Second))
 }
 
+// SetBlockTime makes the timestamp of each block sealed via Commit advance by the
+// given number of seconds from its parent, instead of following the wall clock.
+// Zero restores the default behavior.
+func (c *SimulatedBeacon) SetBlockTime(seconds uint64) {
+	c.timeLock.Lock()
+	defer c.timeLock.Unlock()
+
+	c.blockTime = seconds
+}
+
+// SetNextBlockTime sets the timestamp of the next block sealed via Commit. The
+// timestamp must be later than the one of the current head block.
+func (c *SimulatedBeacon) SetNextBlockTime(timestamp uint64) error {
+	if head := c.eth.BlockChain().CurrentBlock(); timestamp <= head.Time {
+		return fmt.Errorf("timestamp %d not after current block time %d", timestamp, head.Time)
+	}
+	c.timeLock.Lock()
+	defer c.timeLock.Unlock()
+
+	c.nextBlockTime = timestamp
+	return nil
+}
+
 // RegisterSimulatedBeaconAPIs registers the simulated beacon's API with the
 // stack.
 func RegisterSimulatedBeaconAPIs(stack *node.Node, sim *SimulatedBeacon) {
@@ -202,3 +202,33 @@ func TestOnDemandSpam(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the block time can be configured while blocks are being sealed. Run
+// with -race to detect unsynchronized access.
+func TestSimulatedBeaconConcurrentBlockTime(t *testing.T) {
+	var (
+		genesis         = core.DeveloperGenesisBlock(10_000_000, nil)
+		node, eth, mock = startSimulatedBeaconEthService(t, genesis, 0)
+		done            = make(chan struct{})
+	)
+	defer node.Close()
+
+	go func() {
+		defer close(done)
+		for i := uint64(0); i < 50; i++ {
+			mock.SetBlockTime(i % 3)
+			mock.SetNextBlockTime(eth.BlockChain().CurrentBlock().Time + 100)
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		mock.Commit()
+	}
+	<-done
+
+	mock.SetBlockTime(5)
+	parent := eth.BlockChain().CurrentBlock()
+	mock.Commit()
+	if head := eth.BlockChain().CurrentBlock(); head.Time < parent.Time+5 {
+		t.Errorf("wrong block time: parent %d, head %d", parent.Time, head.Time)
+	}
+}
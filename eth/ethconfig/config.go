@@ -153,6 +153,10 @@ type Config struct {
 
 	// OverrideVerkle (TODO: remove after the fork)
 	OverrideVerkle *uint64 `toml:",omitempty"`
+
+	// WrapEngine, if set, wraps the consensus engine created for the chain. This is
+	// meant for simulated chains which need to customize block production.
+	WrapEngine func(consensus.Engine) consensus.Engine `toml:"-"`
 }
 
 // CreateConsensusEngine creates a consensus engine for the given chain config.
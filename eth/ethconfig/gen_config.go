@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
@@ -48,8 +49,9 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		RPCGasCap               uint64
 		RPCEVMTimeout           time.Duration
 		RPCTxFeeCap             float64
-		OverrideCancun          *uint64 `toml:",omitempty"`
-		OverrideVerkle          *uint64 `toml:",omitempty"`
+		OverrideCancun          *uint64                                 `toml:",omitempty"`
+		OverrideVerkle          *uint64                                 `toml:",omitempty"`
+		WrapEngine              func(consensus.Engine) consensus.Engine `toml:"-"`
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
@@ -86,6 +88,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.RPCTxFeeCap = c.RPCTxFeeCap
 	enc.OverrideCancun = c.OverrideCancun
 	enc.OverrideVerkle = c.OverrideVerkle
+	enc.WrapEngine = c.WrapEngine
 	return &enc, nil
 }
 
@@ -124,8 +127,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		RPCGasCap               *uint64
 		RPCEVMTimeout           *time.Duration
 		RPCTxFeeCap             *float64
-		OverrideCancun          *uint64 `toml:",omitempty"`
-		OverrideVerkle          *uint64 `toml:",omitempty"`
+		OverrideCancun          *uint64                                 `toml:",omitempty"`
+		OverrideVerkle          *uint64                                 `toml:",omitempty"`
+		WrapEngine              func(consensus.Engine) consensus.Engine `toml:"-"`
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -233,5 +237,8 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.OverrideVerkle != nil {
 		c.OverrideVerkle = dec.OverrideVerkle
 	}
+	if dec.WrapEngine != nil {
+		c.WrapEngine = dec.WrapEngine
+	}
 	return nil
 }
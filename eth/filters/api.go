@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"slices"
 	"sync"
 	"time"
 
@@ -149,38 +150,67 @@ func (api *FilterAPI) NewPendingTransactionFilter(fullTx *bool) rpc.ID {
 	return pendingTxSub.ID
 }
 
+// SubscriptionOptions are the optional parameters of subscriptions created via
+// eth_subscribe.
+type SubscriptionOptions struct {
+	// WithSnapshot requests notifications for the current value of the subscribed
+	// data before live updates are sent. Snapshot notifications are always delivered
+	// strictly before the first live notification. Note that an event happening
+	// while the snapshot is taken may be included in the snapshot and also be
+	// delivered as a live notification.
+	//
+	// For newHeads, the snapshot is the current head header. For logs, it is the set
+	// of logs in the current head block matching the criteria. For
+	// newPendingTransactions, it is the set of pending transactions in the pool.
+	WithSnapshot bool `json:"withSnapshot"`
+}
+
+func (opts *SubscriptionOptions) withSnapshot() bool {
+	return opts != nil && opts.WithSnapshot
+}
+
 // NewPendingTransactions creates a subscription that is triggered each time a
 // transaction enters the transaction pool. If fullTx is true the full tx is
 // sent to the client, otherwise the hash is sent.
-func (api *FilterAPI) NewPendingTransactions(ctx context.Context, fullTx *bool) (*rpc.Subscription, error) {
+func (api *FilterAPI) NewPendingTransactions(ctx context.Context, fullTx *bool, opts *SubscriptionOptions) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
 		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
 	}
 
-	rpcSub := notifier.CreateSubscription()
+	var (
+		rpcSub       = notifier.CreateSubscription()
+		txs          = make(chan []*types.Transaction, 128)
+		pendingTxSub = api.events.SubscribePendingTxs(txs)
+		chainConfig  = api.sys.backend.ChainConfig()
+	)
+	notify := func(txs []*types.Transaction) {
+		// To keep the original behaviour, send a single tx hash in one notification.
+		// TODO(rjl493456442) Send a batch of tx hashes in one notification
+		latest := api.sys.backend.CurrentHeader()
+		for _, tx := range txs {
+			if fullTx != nil && *fullTx {
+				rpcTx := ethapi.NewRPCPendingTransaction(tx, latest, chainConfig)
+				notifier.Notify(rpcSub.ID, rpcTx)
+			} else {
+				notifier.Notify(rpcSub.ID, tx.Hash())
+			}
+		}
+	}
+	// Notifications sent before returning are delivered to the client right after
+	// the subscription ID, ahead of any live event.
+	if opts.withSnapshot() {
+		pending, _ := api.sys.backend.TxPoolContent()
+		notify(flattenPending(pending))
+	}
 
 	go func() {
-		txs := make(chan []*types.Transaction, 128)
-		pendingTxSub := api.events.SubscribePendingTxs(txs)
 		defer pendingTxSub.Unsubscribe()
 
-		chainConfig := api.sys.backend.ChainConfig()
-
 		for {
 			select {
 			case txs := <-txs:
-				// To keep the original behaviour, send a single tx hash in one notification.
-				// TODO(rjl493456442) Send a batch of tx hashes in one notification
-				latest := api.sys.backend.CurrentHeader()
-				for _, tx := range txs {
-					if fullTx != nil && *fullTx {
-						rpcTx := ethapi.NewRPCPendingTransaction(tx, latest, chainConfig)
-						notifier.Notify(rpcSub.ID, rpcTx)
-					} else {
-						notifier.Notify(rpcSub.ID, tx.Hash())
-					}
-				}
+				notify(txs)
 			case <-rpcSub.Err():
 				return
 			}
@@ -190,6 +220,21 @@ func (api *FilterAPI) NewPendingTransactions(ctx context.Context, fullTx *bool)
 	return rpcSub, nil
 }
 
+// flattenPending returns the transactions of the given per-account lists, ordered
+// by sender address and nonce.
+func flattenPending(pending map[common.Address][]*types.Transaction) []*types.Transaction {
+	senders := make([]common.Address, 0, len(pending))
+	for sender := range pending {
+		senders = append(senders, sender)
+	}
+	slices.SortFunc(senders, common.Address.Cmp)
+	var txs []*types.Transaction
+	for _, sender := range senders {
+		txs = append(txs, pending[sender]...)
+	}
+	return txs
+}
+
 // NewBlockFilter creates a filter that fetches blocks that are imported into the chain.
 // It is part of the filter package since polling goes with eth_getFilterChanges.
 func (api *FilterAPI) NewBlockFilter() rpc.ID {
@@ -224,22 +269,34 @@ func (api *FilterAPI) NewBlockFilter() rpc.ID {
 }
 
 // NewHeads send a notification each time a new (header) block is appended to the chain.
-func (api *FilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+func (api *FilterAPI) NewHeads(ctx context.Context, opts *SubscriptionOptions) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
 		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
 	}
 
-	rpcSub := notifier.CreateSubscription()
+	var (
+		rpcSub     = notifier.CreateSubscription()
+		headers    = make(chan *types.Header)
+		headersSub = api.events.SubscribeNewHeads(headers)
+		snapshot   common.Hash
+	)
+	if opts.withSnapshot() {
+		if head := api.sys.backend.CurrentHeader(); head != nil {
+			snapshot = head.Hash()
+			notifier.Notify(rpcSub.ID, head)
+		}
+	}
 
 	go func() {
-		headers := make(chan *types.Header)
-		headersSub := api.events.SubscribeNewHeads(headers)
 		defer headersSub.Unsubscribe()
 
 		for {
 			select {
 			case h := <-headers:
+				if snapshot != (common.Hash{}) && h.Hash() == snapshot {
+					continue // already delivered as snapshot
+				}
 				notifier.Notify(rpcSub.ID, h)
 			case <-rpcSub.Err():
 				return
@@ -251,7 +308,7 @@ func (api *FilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
 }
 
 // Logs creates a subscription that fires for all new log that match the given filter criteria.
-func (api *FilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subscription, error) {
+func (api *FilterAPI) Logs(ctx context.Context, crit FilterCriteria, opts *SubscriptionOptions) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
 		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
@@ -266,6 +323,18 @@ func (api *FilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subsc
 	if err != nil {
 		return nil, err
 	}
+	if opts.withSnapshot() {
+		if head := api.sys.backend.CurrentHeader(); head != nil {
+			logs, err := api.sys.NewBlockFilter(head.Hash(), crit.Addresses, crit.Topics).Logs(ctx)
+			if err != nil {
+				logsSub.Unsubscribe()
+				return nil, err
+			}
+			for _, log := range logs {
+				notifier.Notify(rpcSub.ID, log)
+			}
+		}
+	}
 
 	go func() {
 		defer logsSub.Unsubscribe()
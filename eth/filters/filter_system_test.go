@@ -48,6 +48,7 @@ type testBackend struct {
 	chainFeed       event.Feed
 	pendingBlock    *types.Block
 	pendingReceipts types.Receipts
+	pendingTxs      map[common.Address][]*types.Transaction
 }
 
 func (b *testBackend) ChainConfig() *params.ChainConfig {
@@ -121,6 +122,10 @@ func (b *testBackend) GetLogs(ctx context.Context, hash common.Hash, number uint
 	return logs, nil
 }
 
+func (b *testBackend) TxPoolContent() (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction) {
+	return b.pendingTxs, nil
+}
+
 func (b *testBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
 	return b.txFeed.Subscribe(ch)
 }
@@ -351,6 +356,74 @@ func TestPendingTxFilterFullTx(t *testing.T) {
 	}
 }
 
+// TestSubscriptionSnapshot tests that subscriptions created with the withSnapshot
+// option deliver the current value before any live event.
+func TestSubscriptionSnapshot(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(t, db, Config{})
+		api          = NewFilterAPI(sys)
+		head         = &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1)}
+		next         = &types.Header{Number: big.NewInt(2), ParentHash: head.Hash(), Difficulty: big.NewInt(1)}
+		tx1          = types.NewTransaction(0, common.Address{}, common.Big0, 0, common.Big0, nil)
+		tx2          = types.NewTransaction(1, common.Address{}, common.Big0, 0, common.Big0, nil)
+	)
+	rawdb.WriteHeader(db, head)
+	rawdb.WriteCanonicalHash(db, head.Hash(), 1)
+	rawdb.WriteHeadBlockHash(db, head.Hash())
+	backend.pendingTxs = map[common.Address][]*types.Transaction{{1}: {tx1}}
+
+	srv := rpc.NewServer()
+	defer srv.Stop()
+	if err := srv.RegisterName("eth", api); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(srv)
+	defer client.Close()
+
+	headers := make(chan *types.Header, 4)
+	headSub, err := client.EthSubscribe(context.Background(), headers, "newHeads", SubscriptionOptions{WithSnapshot: true})
+	if err != nil {
+		t.Fatal("newHeads subscription failed:", err)
+	}
+	defer headSub.Unsubscribe()
+
+	hashes := make(chan common.Hash, 4)
+	txSub, err := client.EthSubscribe(context.Background(), hashes, "newPendingTransactions", false, SubscriptionOptions{WithSnapshot: true})
+	if err != nil {
+		t.Fatal("newPendingTransactions subscription failed:", err)
+	}
+	defer txSub.Unsubscribe()
+
+	// Post live events. The subscriptions are installed in the event system
+	// before eth_subscribe returns.
+	backend.chainFeed.Send(core.ChainEvent{Header: next})
+	backend.txFeed.Send(core.NewTxsEvent{Txs: []*types.Transaction{tx2}})
+
+	for i, want := range []common.Hash{head.Hash(), next.Hash()} {
+		select {
+		case h := <-headers:
+			if h.Hash() != want {
+				t.Errorf("header %d: wrong hash %x, want %x", i, h.Hash(), want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("header %d: timeout", i)
+		}
+	}
+	for i, want := range []common.Hash{tx1.Hash(), tx2.Hash()} {
+		select {
+		case h := <-hashes:
+			if h != want {
+				t.Errorf("tx %d: wrong hash %x, want %x", i, h, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("tx %d: timeout", i)
+		}
+	}
+}
+
 // TestLogFilterCreation test whether a given filter criteria makes sense.
 // If not it must return an error.
 func TestLogFilterCreation(t *testing.T) {
@@ -0,0 +1,52 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/forkid"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+)
+
+func TestNodeFilter(t *testing.T) {
+	backend := newTestBackend(3)
+	defer backend.close()
+
+	node := func(entry enr.Entry) *enode.Node {
+		r := new(enr.Record)
+		if entry != nil {
+			r.Set(entry)
+		}
+		return enode.SignNull(r, enode.ID{})
+	}
+	var (
+		filter = NewNodeFilter(backend.chain)
+		local  = currentENREntry(backend.chain)
+		remote = &enrEntry{ForkID: forkid.ID{Hash: [4]byte{0xde, 0xad, 0xbe, 0xef}}}
+	)
+	if !filter(node(local)) {
+		t.Error("node with compatible fork ID rejected")
+	}
+	if filter(node(remote)) {
+		t.Error("node with incompatible fork ID accepted")
+	}
+	if filter(node(nil)) {
+		t.Error("node without eth entry accepted")
+	}
+}
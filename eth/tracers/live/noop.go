@@ -40,23 +40,29 @@ type noop struct{}
 func newNoopTracer(_ json.RawMessage) (*tracing.Hooks, error) {
 	t := &noop{}
 	return &tracing.Hooks{
-		OnTxStart:        t.OnTxStart,
-		OnTxEnd:          t.OnTxEnd,
-		OnEnter:          t.OnEnter,
-		OnExit:           t.OnExit,
-		OnOpcode:         t.OnOpcode,
-		OnFault:          t.OnFault,
-		OnGasChange:      t.OnGasChange,
-		OnBlockchainInit: t.OnBlockchainInit,
-		OnBlockStart:     t.OnBlockStart,
-		OnBlockEnd:       t.OnBlockEnd,
-		OnSkippedBlock:   t.OnSkippedBlock,
-		OnGenesisBlock:   t.OnGenesisBlock,
-		OnBalanceChange:  t.OnBalanceChange,
-		OnNonceChange:    t.OnNonceChange,
-		OnCodeChange:     t.OnCodeChange,
-		OnStorageChange:  t.OnStorageChange,
-		OnLog:            t.OnLog,
+		OnTxStart:               t.OnTxStart,
+		OnTxEnd:                 t.OnTxEnd,
+		OnEnter:                 t.OnEnter,
+		OnExit:                  t.OnExit,
+		OnOpcode:                t.OnOpcode,
+		OnFault:                 t.OnFault,
+		OnGasChange:             t.OnGasChange,
+		OnGasRefundChange:       t.OnGasRefundChange,
+		OnSelfDestruct:          t.OnSelfDestruct,
+		OnTransientStorageRead:  t.OnTransientStorageRead,
+		OnTransientStorageWrite: t.OnTransientStorageWrite,
+		OnBlobHashRead:          t.OnBlobHashRead,
+		OnContractCreation:      t.OnContractCreation,
+		OnBlockchainInit:        t.OnBlockchainInit,
+		OnBlockStart:            t.OnBlockStart,
+		OnBlockEnd:              t.OnBlockEnd,
+		OnSkippedBlock:          t.OnSkippedBlock,
+		OnGenesisBlock:          t.OnGenesisBlock,
+		OnBalanceChange:         t.OnBalanceChange,
+		OnNonceChange:           t.OnNonceChange,
+		OnCodeChange:            t.OnCodeChange,
+		OnStorageChange:         t.OnStorageChange,
+		OnLog:                   t.OnLog,
 	}, nil
 }
 
@@ -110,3 +116,21 @@ func (t *noop) OnLog(l *types.Log) {
 
 func (t *noop) OnGasChange(old, new uint64, reason tracing.GasChangeReason) {
 }
+
+func (t *noop) OnGasRefundChange(delta int64, total uint64) {
+}
+
+func (t *noop) OnSelfDestruct(contract, beneficiary common.Address, balance *big.Int, destroyed bool) {
+}
+
+func (t *noop) OnTransientStorageRead(addr common.Address, slot common.Hash, value common.Hash) {
+}
+
+func (t *noop) OnTransientStorageWrite(addr common.Address, slot common.Hash, prev, new common.Hash) {
+}
+
+func (t *noop) OnBlobHashRead(index uint64, hash common.Hash) {
+}
+
+func (t *noop) OnContractCreation(creator, newAddr common.Address, initCodeHash common.Hash, opcode byte) {
+}
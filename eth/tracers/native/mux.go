@@ -58,18 +58,24 @@ func newMuxTracer(ctx *tracers.Context, cfg json.RawMessage, chainConfig *params
 	t := &muxTracer{names: names, tracers: objects}
 	return &tracers.Tracer{
 		Hooks: &tracing.Hooks{
-			OnTxStart:       t.OnTxStart,
-			OnTxEnd:         t.OnTxEnd,
-			OnEnter:         t.OnEnter,
-			OnExit:          t.OnExit,
-			OnOpcode:        t.OnOpcode,
-			OnFault:         t.OnFault,
-			OnGasChange:     t.OnGasChange,
-			OnBalanceChange: t.OnBalanceChange,
-			OnNonceChange:   t.OnNonceChange,
-			OnCodeChange:    t.OnCodeChange,
-			OnStorageChange: t.OnStorageChange,
-			OnLog:           t.OnLog,
+			OnTxStart:               t.OnTxStart,
+			OnTxEnd:                 t.OnTxEnd,
+			OnEnter:                 t.OnEnter,
+			OnExit:                  t.OnExit,
+			OnOpcode:                t.OnOpcode,
+			OnFault:                 t.OnFault,
+			OnGasChange:             t.OnGasChange,
+			OnGasRefundChange:       t.OnGasRefundChange,
+			OnSelfDestruct:          t.OnSelfDestruct,
+			OnTransientStorageRead:  t.OnTransientStorageRead,
+			OnTransientStorageWrite: t.OnTransientStorageWrite,
+			OnBlobHashRead:          t.OnBlobHashRead,
+			OnContractCreation:      t.OnContractCreation,
+			OnBalanceChange:         t.OnBalanceChange,
+			OnNonceChange:           t.OnNonceChange,
+			OnCodeChange:            t.OnCodeChange,
+			OnStorageChange:         t.OnStorageChange,
+			OnLog:                   t.OnLog,
 		},
 		GetResult: t.GetResult,
 		Stop:      t.Stop,
@@ -100,6 +106,54 @@ func (t *muxTracer) OnGasChange(old, new uint64, reason tracing.GasChangeReason)
 	}
 }
 
+func (t *muxTracer) OnGasRefundChange(delta int64, total uint64) {
+	for _, t := range t.tracers {
+		if t.OnGasRefundChange != nil {
+			t.OnGasRefundChange(delta, total)
+		}
+	}
+}
+
+func (t *muxTracer) OnSelfDestruct(contract, beneficiary common.Address, balance *big.Int, destroyed bool) {
+	for _, t := range t.tracers {
+		if t.OnSelfDestruct != nil {
+			t.OnSelfDestruct(contract, beneficiary, balance, destroyed)
+		}
+	}
+}
+
+func (t *muxTracer) OnTransientStorageRead(addr common.Address, slot common.Hash, value common.Hash) {
+	for _, t := range t.tracers {
+		if t.OnTransientStorageRead != nil {
+			t.OnTransientStorageRead(addr, slot, value)
+		}
+	}
+}
+
+func (t *muxTracer) OnTransientStorageWrite(addr common.Address, slot common.Hash, prev, new common.Hash) {
+	for _, t := range t.tracers {
+		if t.OnTransientStorageWrite != nil {
+			t.OnTransientStorageWrite(addr, slot, prev, new)
+		}
+	}
+}
+
+func (t *muxTracer) OnBlobHashRead(index uint64, hash common.Hash) {
+	for _, t := range t.tracers {
+		if t.OnBlobHashRead != nil {
+			t.OnBlobHashRead(index, hash)
+		}
+	}
+}
+
+func (t *muxTracer) OnContractCreation(creator, newAddr common.Address, initCodeHash common.Hash, opcode byte) {
+	for _, t := range t.tracers {
+		if t.OnContractCreation != nil {
+			t.OnContractCreation(creator, newAddr, initCodeHash, opcode)
+		}
+	}
+}
+
 func (t *muxTracer) OnEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
 	for _, t := range t.tracers {
 		if t.OnEnter != nil {
@@ -40,18 +40,24 @@ func newNoopTracer(ctx *tracers.Context, cfg json.RawMessage, chainConfig *param
 	t := &noopTracer{}
 	return &tracers.Tracer{
 		Hooks: &tracing.Hooks{
-			OnTxStart:       t.OnTxStart,
-			OnTxEnd:         t.OnTxEnd,
-			OnEnter:         t.OnEnter,
-			OnExit:          t.OnExit,
-			OnOpcode:        t.OnOpcode,
-			OnFault:         t.OnFault,
-			OnGasChange:     t.OnGasChange,
-			OnBalanceChange: t.OnBalanceChange,
-			OnNonceChange:   t.OnNonceChange,
-			OnCodeChange:    t.OnCodeChange,
-			OnStorageChange: t.OnStorageChange,
-			OnLog:           t.OnLog,
+			OnTxStart:               t.OnTxStart,
+			OnTxEnd:                 t.OnTxEnd,
+			OnEnter:                 t.OnEnter,
+			OnExit:                  t.OnExit,
+			OnOpcode:                t.OnOpcode,
+			OnFault:                 t.OnFault,
+			OnGasChange:             t.OnGasChange,
+			OnGasRefundChange:       t.OnGasRefundChange,
+			OnSelfDestruct:          t.OnSelfDestruct,
+			OnTransientStorageRead:  t.OnTransientStorageRead,
+			OnTransientStorageWrite: t.OnTransientStorageWrite,
+			OnBlobHashRead:          t.OnBlobHashRead,
+			OnContractCreation:      t.OnContractCreation,
+			OnBalanceChange:         t.OnBalanceChange,
+			OnNonceChange:           t.OnNonceChange,
+			OnCodeChange:            t.OnCodeChange,
+			OnStorageChange:         t.OnStorageChange,
+			OnLog:                   t.OnLog,
 		},
 		GetResult: t.GetResult,
 		Stop:      t.Stop,
@@ -66,6 +72,23 @@ func (t *noopTracer) OnFault(pc uint64, op byte, gas, cost uint64, _ tracing.OpC
 
 func (t *noopTracer) OnGasChange(old, new uint64, reason tracing.GasChangeReason) {}
 
+func (t *noopTracer) OnGasRefundChange(delta int64, total uint64) {}
+
+func (t *noopTracer) OnSelfDestruct(contract, beneficiary common.Address, balance *big.Int, destroyed bool) {
+}
+
+func (t *noopTracer) OnTransientStorageRead(addr common.Address, slot common.Hash, value common.Hash) {
+}
+
+func (t *noopTracer) OnTransientStorageWrite(addr common.Address, slot common.Hash, prev, new common.Hash) {
+}
+
+func (t *noopTracer) OnBlobHashRead(index uint64, hash common.Hash) {
+}
+
+func (t *noopTracer) OnContractCreation(creator, newAddr common.Address, initCodeHash common.Hash, opcode byte) {
+}
+
 func (t *noopTracer) OnEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
 }
 
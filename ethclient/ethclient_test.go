@@ -190,6 +190,38 @@ func TestEthClient(t *testing.T) {
 	}
 }
 
+// BenchmarkGetBlockByNumber compares in-process calls through JSON encoding with
+// direct calls.
+func BenchmarkGetBlockByNumber(b *testing.B) {
+	backend, _, err := newTestBackend(nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer backend.Close()
+
+	for _, bc := range []struct {
+		name   string
+		client *rpc.Client
+	}{
+		{"json", backend.Attach()},
+		{"direct", backend.AttachInProc()},
+	} {
+		defer bc.client.Close()
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var block map[string]interface{}
+				if err := bc.client.Call(&block, "eth_getBlockByNumber", rpc.BlockNumber(2), true); err != nil {
+					b.Fatal(err)
+				}
+				if block["number"] == nil {
+					b.Fatal("missing block number")
+				}
+			}
+		})
+	}
+}
+
 func testHeader(t *testing.T, chain []*types.Block, client *rpc.Client) {
 	tests := map[string]struct {
 		block   *big.Int
@@ -18,6 +18,7 @@ package simulated
 
 import (
 	"errors"
+	"math/big"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
@@ -62,6 +63,8 @@ type simClient struct {
 // Backend is a simulated blockchain. You can use it to test your contracts or
 // other code that interacts with the Ethereum chain.
 type Backend struct {
+	eth    *eth.Ethereum
+	engine *baseFeeEngine
 	node   *node.Node
 	beacon *catalyst.SimulatedBeacon
 	client simClient
@@ -105,6 +108,7 @@ func NewBackend(alloc types.GenesisAlloc, options ...func(nodeConf *node.Config,
 // newWithNode sets up a simulated backend on an existing node. The provided node
 // must not be started and will be started by this method.
 func newWithNode(stack *node.Node, conf *eth.Config, blockPeriod uint64) (*Backend, error) {
+	conf.WrapEngine = newBaseFeeEngine
 	backend, err := eth.New(stack, conf)
 	if err != nil {
 		return nil, err
@@ -129,6 +133,8 @@ func newWithNode(stack *node.Node, conf *eth.Config, blockPeriod uint64) (*Backe
 		return nil, err
 	}
 	return &Backend{
+		eth:    backend,
+		engine: backend.Engine().(*baseFeeEngine),
 		node:   stack,
 		beacon: beacon,
 		client: simClient{ethclient.NewClient(stack.Attach())},
@@ -186,6 +192,44 @@ func (n *Backend) AdjustTime(adjustment time.Duration) error {
 	return n.beacon.AdjustTime(adjustment)
 }
 
+// SetBlockTime makes the timestamp of each committed block advance by the given
+// interval from its parent, making block times deterministic. The interval is
+// truncated to whole seconds. Zero restores the default of using the wall clock.
+func (n *Backend) SetBlockTime(interval time.Duration) {
+	n.beacon.SetBlockTime(uint64(interval / time.Second))
+}
+
+// SetNextBlockTime sets the timestamp of the next committed block. It must be
+// later than the timestamp of the current head block.
+func (n *Backend) SetNextBlockTime(t time.Time) error {
+	if t.Unix() < 0 {
+		return errors.New("negative block time")
+	}
+	return n.beacon.SetNextBlockTime(uint64(t.Unix()))
+}
+
+// SetNextBaseFee sets the base fee of the next committed block, overriding the base
+// fee defined by EIP-1559. The base fee of the blocks after it is derived from it as
+// usual.
+func (n *Backend) SetNextBaseFee(baseFee *big.Int) error {
+	if baseFee == nil || baseFee.Sign() < 0 {
+		return errors.New("invalid base fee")
+	}
+	head := n.eth.BlockChain().CurrentBlock()
+	if !n.eth.BlockChain().Config().IsLondon(new(big.Int).Add(head.Number, common.Big1)) {
+		return errors.New("base fee not supported before london")
+	}
+	n.engine.setBaseFee(head.Hash(), baseFee)
+	return nil
+}
+
+// SetBlockGasLimit changes the gas limit targeted when producing blocks. Note the
+// gas limit of each block may only deviate from its parent by 1/1024, so the target
+// is approached over multiple blocks.
+func (n *Backend) SetBlockGasLimit(gaslimit uint64) {
+	n.eth.Miner().SetGasCeil(gaslimit)
+}
+
 // Client returns a client that accesses the simulated chain.
 func (n *Backend) Client() Client {
 	return n.client
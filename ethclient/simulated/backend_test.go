@@ -30,6 +30,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
@@ -158,6 +159,55 @@ func TestAdjustTime(t *testing.T) {
 	}
 }
 
+func TestSetBlockTime(t *testing.T) {
+	sim := NewBackend(types.GenesisAlloc{})
+	defer sim.Close()
+
+	client := sim.Client()
+	sim.SetBlockTime(12 * time.Second)
+	sim.Commit()
+	block1, _ := client.BlockByNumber(context.Background(), nil)
+	sim.Commit()
+	block2, _ := client.BlockByNumber(context.Background(), nil)
+	if diff := block2.Time() - block1.Time(); diff != 12 {
+		t.Errorf("block time mismatch: have %d, want 12", diff)
+	}
+
+	// An explicit timestamp takes precedence, but only for the next block.
+	next := time.Unix(int64(block2.Time())+1000, 0)
+	if err := sim.SetNextBlockTime(next); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	block3, _ := client.BlockByNumber(context.Background(), nil)
+	if block3.Time() != uint64(next.Unix()) {
+		t.Errorf("block timestamp mismatch: have %d, want %d", block3.Time(), next.Unix())
+	}
+	sim.Commit()
+	block4, _ := client.BlockByNumber(context.Background(), nil)
+	if diff := block4.Time() - block3.Time(); diff != 12 {
+		t.Errorf("block time mismatch after explicit timestamp: have %d, want 12", diff)
+	}
+	if err := sim.SetNextBlockTime(time.Unix(int64(block4.Time()), 0)); err == nil {
+		t.Error("expected error for timestamp not after head")
+	}
+}
+
+func TestSetBlockGasLimit(t *testing.T) {
+	sim := NewBackend(types.GenesisAlloc{}, WithBlockGasLimit(30_000_000))
+	defer sim.Close()
+
+	client := sim.Client()
+	sim.SetBlockGasLimit(20_000_000)
+	sim.Commit()
+	head, _ := client.BlockByNumber(context.Background(), nil)
+
+	// The gas limit may only move by 1/1024 of the parent limit per block.
+	if want := uint64(30_000_000 - 30_000_000/params.GasLimitBoundDivisor + 1); head.GasLimit() != want {
+		t.Errorf("gas limit mismatch: have %d, want %d", head.GasLimit(), want)
+	}
+}
+
 func TestSendTransaction(t *testing.T) {
 	sim := simTestBackend(testAddr)
 	defer sim.Close()
@@ -350,3 +400,43 @@ func TestAdjustTimeAfterFork(t *testing.T) {
 		t.Errorf("failed to build block on fork")
 	}
 }
+
+func TestSetNextBaseFee(t *testing.T) {
+	sim := simTestBackend(testAddr)
+	defer sim.Close()
+
+	client := sim.Client()
+	baseFee := big.NewInt(50 * params.GWei)
+	if err := sim.SetNextBaseFee(baseFee); err != nil {
+		t.Fatal(err)
+	}
+	// Transactions paying the overridden base fee are included.
+	chainid, _ := client.ChainID(context.Background())
+	tx, err := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainid,
+		GasTipCap: big.NewInt(params.GWei),
+		GasFeeCap: big.NewInt(100 * params.GWei),
+		Gas:       21000,
+		To:        &testAddr2,
+	}), types.LatestSignerForChainID(chainid), testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	head, _ := client.BlockByNumber(context.Background(), nil)
+	if head.BaseFee().Cmp(baseFee) != 0 {
+		t.Fatalf("base fee mismatch: have %v, want %v", head.BaseFee(), baseFee)
+	}
+	if len(head.Transactions()) != 1 {
+		t.Fatalf("wrong number of transactions in block: %d", len(head.Transactions()))
+	}
+	// Subsequent blocks follow EIP-1559 again.
+	sim.Commit()
+	next, _ := client.BlockByNumber(context.Background(), nil)
+	if want := eip1559.CalcBaseFee(params.AllDevChainProtocolChanges, head.Header()); next.BaseFee().Cmp(want) != 0 {
+		t.Errorf("next base fee mismatch: have %v, want %v", next.BaseFee(), want)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulated
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// baseFeeEngine wraps the consensus engine of the simulated chain, allowing the base
+// fee of a block to deviate from the one defined by EIP-1559.
+type baseFeeEngine struct {
+	consensus.Engine
+
+	lock     sync.RWMutex
+	baseFees map[common.Hash]*big.Int // Overridden base fees of child blocks, by parent hash
+}
+
+func newBaseFeeEngine(engine consensus.Engine) consensus.Engine {
+	return &baseFeeEngine{Engine: engine, baseFees: make(map[common.Hash]*big.Int)}
+}
+
+// setBaseFee makes the child blocks of the given parent use the given base fee.
+func (e *baseFeeEngine) setBaseFee(parent common.Hash, baseFee *big.Int) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.baseFees[parent] = new(big.Int).Set(baseFee)
+}
+
+// baseFee returns the overridden base fee of the child blocks of parent, or nil if it
+// is not overridden.
+func (e *baseFeeEngine) baseFee(parent common.Hash) *big.Int {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	if fee := e.baseFees[parent]; fee != nil {
+		return new(big.Int).Set(fee)
+	}
+	return nil
+}
+
+// Prepare implements consensus.Engine, applying the overridden base fee to the header
+// of a block being produced.
+func (e *baseFeeEngine) Prepare(chain consensus.ChainHeaderReader, header *types.Header) error {
+	if err := e.Engine.Prepare(chain, header); err != nil {
+		return err
+	}
+	if fee := e.baseFee(header.ParentHash); fee != nil {
+		header.BaseFee = fee
+	}
+	return nil
+}
+
+// VerifyHeader implements consensus.Engine. If the base fee of the header is
+// overridden, it must match the override. All other fields are verified by the
+// wrapped engine.
+func (e *baseFeeEngine) VerifyHeader(chain consensus.ChainHeaderReader, header *types.Header) error {
+	fee := e.baseFee(header.ParentHash)
+	if fee == nil {
+		return e.Engine.VerifyHeader(chain, header)
+	}
+	if header.BaseFee == nil || header.BaseFee.Cmp(fee) != 0 {
+		return fmt.Errorf("invalid baseFee: have %v, want overridden %v", header.BaseFee, fee)
+	}
+	parent := chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
+	cpy := types.CopyHeader(header)
+	cpy.BaseFee = eip1559.CalcBaseFee(chain.Config(), parent)
+	return e.Engine.VerifyHeader(chain, cpy)
+}
+
+// VerifyHeaders implements consensus.Engine. Batches without overridden base fees are
+// verified by the wrapped engine. Otherwise the headers are verified one by one, which
+// requires their parents to be known already. This holds for the simulated chain, as
+// it imports the blocks it produces one at a time.
+func (e *baseFeeEngine) VerifyHeaders(chain consensus.ChainHeaderReader, headers []*types.Header) (chan<- struct{}, <-chan error) {
+	overridden := false
+	for _, header := range headers {
+		if e.baseFee(header.ParentHash) != nil {
+			overridden = true
+			break
+		}
+	}
+	if !overridden {
+		return e.Engine.VerifyHeaders(chain, headers)
+	}
+	var (
+		abort   = make(chan struct{})
+		results = make(chan error, len(headers))
+	)
+	go func() {
+		for _, header := range headers {
+			select {
+			case <-abort:
+				return
+			case results <- e.VerifyHeader(chain, header):
+			}
+		}
+	}()
+	return abort, results
+}
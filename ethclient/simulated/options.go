@@ -32,6 +32,14 @@ func WithBlockGasLimit(gaslimit uint64) func(nodeConf *node.Config, ethConf *eth
 	}
 }
 
+// WithInitialBaseFee configures the base fee of the genesis block. The base fee of
+// subsequent blocks is derived from it according to EIP-1559.
+func WithInitialBaseFee(basefee *big.Int) func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+	return func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+		ethConf.Genesis.BaseFee = new(big.Int).Set(basefee)
+	}
+}
+
 // WithCallGasLimit configures the simulated backend to cap eth_calls to a specific
 // gas limit when running client operations.
 func WithCallGasLimit(gaslimit uint64) func(nodeConf *node.Config, ethConf *ethconfig.Config) {
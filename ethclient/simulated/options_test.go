@@ -54,6 +54,31 @@ func TestWithBlockGasLimitOption(t *testing.T) {
 	}
 }
 
+// Tests that the simulator starts with the configured base fee and derives the
+// base fee of subsequent blocks from it.
+func TestWithInitialBaseFeeOption(t *testing.T) {
+	sim := NewBackend(types.GenesisAlloc{}, WithInitialBaseFee(big.NewInt(7_000_000_000)))
+	defer sim.Close()
+
+	client := sim.Client()
+	genesis, err := client.BlockByNumber(context.Background(), big.NewInt(0))
+	if err != nil {
+		t.Fatalf("failed to retrieve genesis block: %v", err)
+	}
+	if genesis.BaseFee().Cmp(big.NewInt(7_000_000_000)) != 0 {
+		t.Errorf("genesis base fee mismatch: have %v, want %v", genesis.BaseFee(), 7_000_000_000)
+	}
+	// An empty block lowers the base fee by 1/8th.
+	sim.Commit()
+	head, err := client.BlockByNumber(context.Background(), big.NewInt(1))
+	if err != nil {
+		t.Fatalf("failed to retrieve head block: %v", err)
+	}
+	if head.BaseFee().Cmp(big.NewInt(6_125_000_000)) != 0 {
+		t.Errorf("head base fee mismatch: have %v, want %v", head.BaseFee(), 6_125_000_000)
+	}
+}
+
 // Tests that the simulator honors the RPC call caps set by the options.
 func TestWithCallGasLimitOption(t *testing.T) {
 	// Construct a simulator, targeting a different gas limit
@@ -2315,6 +2315,61 @@ func TestSimulateV1(t *testing.T) {
 	}
 }
 
+// Tests that PREVRANDAO returns the value of the prevRandao block override in
+// simulated post-merge blocks.
+func TestSimulateV1PrevRandao(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts = newAccounts(1)
+		// mstore(0, prevrandao()) return(0, 32)
+		randao  = common.HexToAddress("0x0000000000000000000000000000000000000aaa")
+		genesis = &core.Genesis{
+			Config: params.MergedTestChainConfig,
+			Alloc: types.GenesisAlloc{
+				accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+				randao:           {Code: common.FromHex("0x445f5260205ff3")},
+			},
+		}
+	)
+	api := NewBlockChainAPI(newTestBackend(t, 1, genesis, beacon.New(ethash.NewFaker()), func(i int, b *core.BlockGen) {
+		b.SetPoS()
+	}))
+	var (
+		latest = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		call   = TransactionArgs{From: &accounts[0].addr, To: &randao}
+		r1     = common.HexToHash("0x01")
+		r2     = common.HexToHash("0xdeadbeef")
+		opts   = simOpts{BlockStateCalls: []simBlock{
+			{BlockOverrides: &override.BlockOverrides{PrevRandao: &r1}, Calls: []TransactionArgs{call}},
+			{BlockOverrides: &override.BlockOverrides{PrevRandao: &r2}, Calls: []TransactionArgs{call}},
+			{Calls: []TransactionArgs{call}},
+		}}
+	)
+	result, err := api.SimulateV1(context.Background(), opts, &latest)
+	if err != nil {
+		t.Fatalf("simulation failed: %v", err)
+	}
+	var have []struct {
+		MixHash common.Hash `json:"mixHash"`
+		Calls   []struct {
+			ReturnValue hexutil.Bytes `json:"returnData"`
+		} `json:"calls"`
+	}
+	enc, _ := json.Marshal(result)
+	if err := json.Unmarshal(enc, &have); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+	for i, want := range []common.Hash{r1, r2, {}} {
+		if have[i].MixHash != want {
+			t.Errorf("block %d: wrong mix hash %v, want %v", i, have[i].MixHash, want)
+		}
+		if got := common.BytesToHash(have[i].Calls[0].ReturnValue); got != want {
+			t.Errorf("block %d: PREVRANDAO returned %v, want %v", i, got, want)
+		}
+	}
+}
+
 func TestSignTransaction(t *testing.T) {
 	t.Parallel()
 	// Initialize test accounts
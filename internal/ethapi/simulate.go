@@ -380,6 +380,11 @@ func (sim *simulator) makeHeaders(blocks []simBlock) ([]*types.Header, error) {
 			WithdrawalsHash:  withdrawalsHash,
 			ParentBeaconRoot: parentBeaconRoot,
 		})
+		// PREVRANDAO reads the mix digest only after the merge, before that the
+		// opcode returns the difficulty. Reject overrides which would be ignored.
+		if overrides.PrevRandao != nil && header.Difficulty.Sign() != 0 {
+			return nil, &invalidParamsError{fmt.Sprintf("prevRandao override requires a post-merge block, block %d has difficulty %v", header.Number, header.Difficulty)}
+		}
 		res[bi] = header
 	}
 	return res, nil
@@ -20,9 +20,11 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/ethapi/override"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 func TestSimulateSanitizeBlockOrder(t *testing.T) {
@@ -116,6 +118,29 @@ func TestSimulateSanitizeBlockOrder(t *testing.T) {
 	}
 }
 
+func TestSimulateMakeHeadersPrevRandao(t *testing.T) {
+	randao := common.HexToHash("0x01")
+	blocks := []simBlock{{BlockOverrides: &override.BlockOverrides{Number: newInt(11), Time: newUint64(62), PrevRandao: &randao}}}
+
+	// Before the merge, the override would be ignored by PREVRANDAO.
+	sim := &simulator{
+		base:        &types.Header{Number: big.NewInt(10), Time: 50, Difficulty: big.NewInt(1)},
+		chainConfig: params.TestChainConfig,
+	}
+	if _, err := sim.makeHeaders(blocks); err == nil {
+		t.Fatal("expected error for prevRandao override before the merge")
+	}
+	// After the merge, it becomes the mix digest.
+	sim.base.Difficulty = new(big.Int)
+	headers, err := sim.makeHeaders(blocks)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if headers[0].MixDigest != randao {
+		t.Fatalf("wrong mix digest %v, want %v", headers[0].MixDigest, randao)
+	}
+}
+
 func newInt(n int64) *hexutil.Big {
 	return (*hexutil.Big)(big.NewInt(n))
 }
@@ -181,6 +181,7 @@ func (api *adminAPI) StartHTTP(host *string, port *int, cors *string, apis *stri
 		rpcEndpointConfig: rpcEndpointConfig{
 			batchItemLimit:         api.node.config.BatchRequestLimit,
 			batchResponseSizeLimit: api.node.config.BatchResponseMaxSize,
+			largeResponseLog:       api.node.config.LargeResponseLogThreshold,
 		},
 	}
 	if cors != nil {
@@ -255,10 +256,12 @@ func (api *adminAPI) StartWS(host *string, port *int, allowedOrigins *string, ap
 	config := wsConfig{
 		Modules: api.node.config.WSModules,
 		Origins: api.node.config.WSOrigins,
+		conn:    api.node.config.WSConfig.rpcConfig(),
 		// ExposeAll: api.node.config.WSExposeAll,
 		rpcEndpointConfig: rpcEndpointConfig{
 			batchItemLimit:         api.node.config.BatchRequestLimit,
 			batchResponseSizeLimit: api.node.config.BatchResponseMaxSize,
+			largeResponseLog:       api.node.config.LargeResponseLogThreshold,
 		},
 	}
 	if apis != nil {
@@ -24,6 +24,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -134,9 +135,24 @@ type Config struct {
 	// interface.
 	HTTPTimeouts rpc.HTTPTimeouts
 
+	// HTTPShutdownGracePeriod is the time the HTTP and WebSocket RPC servers drain
+	// in-flight requests when the node stops. During the grace period, no new
+	// connections are accepted and new requests are answered with status 503. If
+	// zero, the servers are stopped immediately.
+	HTTPShutdownGracePeriod time.Duration `toml:",omitempty"`
+
 	// HTTPPathPrefix specifies a path prefix on which http-rpc is to be served.
 	HTTPPathPrefix string `toml:",omitempty"`
 
+	// HTTPPprofToken enables serving the net/http/pprof handlers on the HTTP-RPC
+	// server. Requests must carry the token as a bearer token in the Authorization
+	// header. Profiling is not exposed if the token is empty.
+	HTTPPprofToken string `toml:",omitempty"`
+
+	// HTTPPprofPath is the path below which the pprof handlers are served. It
+	// defaults to DefaultPprofPath.
+	HTTPPprofPath string `toml:",omitempty"`
+
 	// AuthAddr is the listening address on which authenticated APIs are provided.
 	AuthAddr string `toml:",omitempty"`
 
@@ -176,6 +192,10 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// WSConfig contains the message size limit and keepalive settings of websocket
+	// RPC connections.
+	WSConfig WSConfig `toml:",omitempty"`
+
 	// GraphQLCors is the Cross-Origin Resource Sharing header to send to requesting
 	// clients. Please be aware that CORS is a browser enforced security, it's fully
 	// useless for custom HTTP clients.
@@ -198,12 +218,22 @@ type Config struct {
 	// AllowUnprotectedTxs allows non EIP-155 protected transactions to be send over RPC.
 	AllowUnprotectedTxs bool `toml:",omitempty"`
 
-	// BatchRequestLimit is the maximum number of requests in a batch.
+	// BatchRequestLimit is the maximum number of requests in a batch. Batches exceeding
+	// the limit are rejected with a 'batch too large' error. The limit applies to the
+	// HTTP, WebSocket, IPC and in-process endpoints. Zero means no limit.
 	BatchRequestLimit int `toml:",omitempty"`
 
 	// BatchResponseMaxSize is the maximum number of bytes returned from a batched rpc call.
+	// Once the limit is reached, the remaining calls in the batch are not processed and
+	// answered with a 'response too large' error instead. The limit applies to the same
+	// endpoints as BatchRequestLimit. Zero means no limit.
 	BatchResponseMaxSize int `toml:",omitempty"`
 
+	// LargeResponseLogThreshold enables debug logging of RPC responses larger than
+	// the given number of bytes. It applies to the same endpoints as
+	// BatchRequestLimit. Zero disables logging.
+	LargeResponseLogThreshold int `toml:",omitempty"`
+
 	// JWTSecret is the path to the hex-encoded jwt secret.
 	JWTSecret string `toml:",omitempty"`
 
@@ -211,6 +241,46 @@ type Config struct {
 	EnablePersonal bool `toml:"-"`
 
 	DBEngine string `toml:",omitempty"`
+
+	// ManifestFile is the path of a JSON file describing the running node, see
+	// NodeManifest. Relative paths are resolved against the instance directory.
+	// The file is written once the node has started and removed when it stops.
+	// An empty path disables the manifest file.
+	ManifestFile string `toml:",omitempty"`
+}
+
+// WSConfig contains settings of websocket RPC connections. Zero values select the
+// defaults of package rpc.
+type WSConfig struct {
+	// MaxMessageSize is the maximum size of a message sent by the client. Clients
+	// sending larger messages are disconnected with close status 1009 (message too
+	// big).
+	MaxMessageSize int64 `toml:",omitempty"`
+
+	// PingInterval is the time after which idle connections are pinged to keep
+	// them alive.
+	PingInterval time.Duration `toml:",omitempty"`
+
+	// WriteTimeout is the maximum time spent sending a single message, e.g. a
+	// large subscription notification, to the client.
+	WriteTimeout time.Duration `toml:",omitempty"`
+
+	// Compression enables permessage-deflate compression for clients supporting it.
+	Compression bool `toml:",omitempty"`
+
+	// CompressionLevel is the flate level used for compressed messages.
+	CompressionLevel int `toml:",omitempty"`
+}
+
+// rpcConfig converts the settings to their package rpc representation.
+func (c WSConfig) rpcConfig() rpc.WebsocketConfig {
+	return rpc.WebsocketConfig{
+		MaxMessageSize:   c.MaxMessageSize,
+		PingInterval:     c.PingInterval,
+		WriteTimeout:     c.WriteTimeout,
+		Compression:      c.Compression,
+		CompressionLevel: c.CompressionLevel,
+	}
 }
 
 // IPCEndpoint resolves an IPC endpoint based on a configured value, taking into
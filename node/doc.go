@@ -45,13 +45,16 @@ database are permitted while initializing.
 
 Once everything is registered, the node can be started, which moves it into the RUNNING
 state. Starting the node starts all registered Lifecycle objects and enables RPC and
-peer-to-peer networking. Note that no additional Lifecycles, APIs or p2p protocols can be
-registered while the node is running.
+peer-to-peer networking. Lifecycles are started in registration order, except that
+lifecycles implementing DependentLifecycle or registered using RegisterLifecycleWithDeps
+are started after their dependencies. Note that no additional Lifecycles or p2p protocols
+can be registered while the node is running. RPC APIs registered while the node is
+running are added to the running RPC endpoints.
 
 Closing the node releases all held resources. The actions performed by Close depend on the
 state it was in. When closing a node in INITIALIZING state, resources related to the data
 directory are released. If the node was RUNNING, closing it also stops all Lifecycle
-objects and shuts down RPC and peer-to-peer networking.
+objects in reverse start order and shuts down RPC and peer-to-peer networking.
 
 You must always call Close on Node, even if the node was not started.
 
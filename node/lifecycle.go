@@ -16,6 +16,13 @@
 
 package node
 
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
 // Lifecycle encompasses the behavior of services that can be started and stopped
 // on the node. Lifecycle management is delegated to the node, but it is the
 // responsibility of the service-specific package to configure and register the
@@ -29,3 +36,118 @@ type Lifecycle interface {
 	// are all terminated.
 	Stop() error
 }
+
+// DependentLifecycle can be implemented by lifecycles which need other lifecycles
+// to be started first. The node starts a dependent lifecycle after its dependencies
+// and stops it before them.
+type DependentLifecycle interface {
+	Lifecycle
+
+	// Dependencies returns the types of the lifecycles this lifecycle depends on.
+	// An interface type matches all registered lifecycles implementing it.
+	Dependencies() []reflect.Type
+}
+
+// ReadinessReporter can be implemented by lifecycles which need to delay the
+// node's readiness, e.g. until they have finished initial synchronization.
+// Lifecycles not implementing it are always considered ready.
+type ReadinessReporter interface {
+	// Ready reports whether the service is ready to serve requests. If it isn't,
+	// the returned reason describes what the service is waiting for.
+	Ready() (bool, string)
+}
+
+// sortLifecycles orders lifecycles such that all dependencies of a lifecycle come
+// before it. Otherwise, the registration order is kept. Besides the dependencies
+// declared by DependentLifecycle, explicit contains the dependencies given to
+// RegisterLifecycleWithDeps. An error is returned if a dependency isn't registered
+// or the dependencies contain a cycle.
+func sortLifecycles(lifecycles []Lifecycle, explicit map[Lifecycle][]Lifecycle) ([]Lifecycle, error) {
+	// Resolve the dependencies to lifecycle indexes.
+	deps := make([][]int, len(lifecycles))
+	for i, lifecycle := range lifecycles {
+		for _, dep := range explicit[lifecycle] {
+			j := slices.Index(lifecycles, dep)
+			if j < 0 || j == i {
+				return nil, fmt.Errorf("lifecycle %T depends on unregistered %T", lifecycle, dep)
+			}
+			deps[i] = append(deps[i], j)
+		}
+		dl, ok := lifecycle.(DependentLifecycle)
+		if !ok {
+			continue
+		}
+		for _, typ := range dl.Dependencies() {
+			var found bool
+			for j, other := range lifecycles {
+				otyp := reflect.TypeOf(other)
+				if j != i && (otyp == typ || typ.Kind() == reflect.Interface && otyp.Implements(typ)) {
+					deps[i] = append(deps[i], j)
+					found = true
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("lifecycle %T depends on unregistered %v", lifecycle, typ)
+			}
+		}
+	}
+	// Repeatedly pick the first lifecycle whose dependencies are all started.
+	var (
+		sorted = make([]Lifecycle, 0, len(lifecycles))
+		placed = make([]bool, len(lifecycles))
+	)
+	ready := func(i int) bool {
+		for _, j := range deps[i] {
+			if !placed[j] {
+				return false
+			}
+		}
+		return true
+	}
+	for len(sorted) < len(lifecycles) {
+		next := -1
+		for i := range lifecycles {
+			if !placed[i] && ready(i) {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			cycle := findCycle(deps, placed)
+			names := make([]string, len(cycle))
+			for k, i := range cycle {
+				names[k] = fmt.Sprintf("%T", lifecycles[i])
+			}
+			return nil, fmt.Errorf("dependency cycle between lifecycles %s", strings.Join(names, " -> "))
+		}
+		placed[next] = true
+		sorted = append(sorted, lifecycles[next])
+	}
+	return sorted, nil
+}
+
+// findCycle returns a dependency cycle among the lifecycles which are not placed yet.
+// It must only be called when none of them has all dependencies placed. The first
+// element of the cycle is repeated at the end.
+func findCycle(deps [][]int, placed []bool) []int {
+	// Every unplaced lifecycle has an unplaced dependency, so following them
+	// from any unplaced lifecycle eventually revisits one.
+	var (
+		path  []int
+		index = make(map[int]int)
+		i     = slices.Index(placed, false)
+	)
+	for {
+		if k, ok := index[i]; ok {
+			return append(path[k:], i)
+		}
+		index[i] = len(path)
+		path = append(path, i)
+		for _, j := range deps[i] {
+			if !placed[j] {
+				i = j
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+)
+
+// NodeManifest describes a running node in machine-readable form. It is meant for
+// tooling which needs to find the endpoints of a node without parsing its logs.
+// Endpoints which are disabled are empty.
+type NodeManifest struct {
+	Name          string   `json:"name"`          // instance name
+	ClientVersion string   `json:"clientVersion"` // devp2p client identifier
+	DataDir       string   `json:"datadir"`
+	ChainID       *big.Int `json:"chainId,omitempty"`
+	Enode         string   `json:"enode,omitempty"`
+	P2PListenAddr string   `json:"p2pListenAddr,omitempty"`
+	IPC           string   `json:"ipc,omitempty"`
+	HTTP          string   `json:"http,omitempty"`
+	WS            string   `json:"ws,omitempty"`
+	AuthRPC       string   `json:"authrpc,omitempty"`
+}
+
+// SetChainID sets the chain ID of the network served by the node, which is reported
+// in the manifest. It is meant to be called by the protocol backend.
+func (n *Node) SetChainID(id *big.Int) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.chainID = nil
+	if id != nil {
+		n.chainID = new(big.Int).Set(id)
+	}
+}
+
+// Manifest returns the manifest of the node. The enode URL and the endpoints are only
+// set while the node is running, ports contain the actual values chosen by the
+// operating system.
+func (n *Node) Manifest() NodeManifest {
+	n.lock.Lock()
+	m := NodeManifest{
+		Name:          n.config.name(),
+		ClientVersion: n.config.NodeName(),
+		DataDir:       n.config.DataDir,
+	}
+	if n.chainID != nil {
+		m.ChainID = new(big.Int).Set(n.chainID)
+	}
+	running := n.state == runningState
+	n.lock.Unlock()
+
+	if !running {
+		return m
+	}
+	m.Enode = n.server.Self().URLv4()
+	m.P2PListenAddr = n.P2PListenAddr()
+	n.ipc.mu.Lock()
+	if n.ipc.listener != nil {
+		m.IPC = n.ipc.endpoint
+	}
+	n.ipc.mu.Unlock()
+	m.HTTP = n.HTTPEndpoint()
+	m.WS = n.WSEndpoint()
+	if addr := n.httpAuth.listenAddr(); addr != "" {
+		m.AuthRPC = "http://" + addr
+	}
+	return m
+}
+
+// manifestPath returns the location of the manifest file, or the empty string if
+// the manifest file is disabled.
+func (n *Node) manifestPath() (string, error) {
+	if n.config.ManifestFile == "" {
+		return "", nil
+	}
+	path := n.ResolvePath(n.config.ManifestFile)
+	if path == "" {
+		return "", errors.New("relative manifest file path requires a data directory")
+	}
+	return path, nil
+}
+
+// writeManifest writes the manifest file. The file is replaced atomically, so
+// readers never observe partial content.
+func (n *Node) writeManifest() error {
+	path, err := n.manifestPath()
+	if path == "" || err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(n.Manifest(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("can't write manifest: %w", err)
+	}
+	n.log.Info("Wrote node manifest", "path", path)
+	return nil
+}
+
+// removeManifest deletes the manifest file.
+func (n *Node) removeManifest() {
+	path, _ := n.manifestPath()
+	if path == "" {
+		return
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		n.log.Warn("Failed to remove node manifest", "path", path, "err", err)
+	}
+}
@@ -17,10 +17,12 @@
 package node
 
 import (
+	"bytes"
 	crand "crypto/rand"
 	"errors"
 	"fmt"
 	"hash/crc32"
+	"math/big"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -28,6 +30,7 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
@@ -38,6 +41,7 @@ import (
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/gofrs/flock"
 )
@@ -57,16 +61,18 @@ type Node struct {
 	state         int           // Tracks state of node lifecycle
 
 	lock          sync.Mutex
-	lifecycles    []Lifecycle // All registered backends, services, and auxiliary services that have a lifecycle
-	rpcAPIs       []rpc.API   // List of APIs currently provided by the node
-	http          *httpServer //
-	ws            *httpServer //
-	httpAuth      *httpServer //
-	wsAuth        *httpServer //
-	ipc           *ipcServer  // Stores information about the ipc http server
-	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests
+	lifecycles    []Lifecycle            // All registered backends, services, and auxiliary services that have a lifecycle
+	lifecycleDeps map[Lifecycle][]Lifecycle // Dependencies given to RegisterLifecycleWithDeps
+	rpcAPIs       []rpc.API              // List of APIs currently provided by the node
+	http          *httpServer            //
+	ws            *httpServer            //
+	httpAuth      *httpServer            //
+	wsAuth        *httpServer            //
+	ipc           *ipcServer             // Stores information about the ipc http server
+	inprocHandler *rpc.Server            // In-process RPC request handler to process the API requests
 
 	databases map[*closeTrackingDB]struct{} // All open databases
+	chainID   *big.Int                      // Chain ID reported in the manifest
 }
 
 const (
@@ -105,6 +111,7 @@ func New(conf *Config) (*Node, error) {
 	}
 	server := rpc.NewServer()
 	server.SetBatchLimits(conf.BatchRequestLimit, conf.BatchResponseMaxSize)
+	server.SetLargeResponseLogThreshold(conf.LargeResponseLogThreshold)
 	node := &Node{
 		config:        conf,
 		inprocHandler: server,
@@ -153,9 +160,30 @@ func New(conf *Config) (*Node, error) {
 	node.http = newHTTPServer(node.log, conf.HTTPTimeouts)
 	node.httpAuth = newHTTPServer(node.log, conf.HTTPTimeouts)
 	node.ws = newHTTPServer(node.log, rpc.DefaultHTTPTimeouts)
+	node.http.gracePeriod = conf.HTTPShutdownGracePeriod
+	node.ws.gracePeriod = conf.HTTPShutdownGracePeriod
 	node.wsAuth = newHTTPServer(node.log, rpc.DefaultHTTPTimeouts)
 	node.ipc = newIPCServer(node.log, conf.IPCEndpoint())
 
+	// Serve the readiness of the node and its services.
+	node.http.mux.Handle(ReadinessPath, &readinessHandler{node})
+	node.http.handlerNames[ReadinessPath] = "readiness"
+
+	// Serve the profiling handlers if requested.
+	if conf.HTTPPprofToken != "" {
+		path := conf.HTTPPprofPath
+		if path == "" {
+			path = DefaultPprofPath
+		}
+		if err := validatePrefix("pprof", path); err != nil {
+			return nil, err
+		}
+		if !strings.HasSuffix(path, "/") {
+			path += "/"
+		}
+		node.http.mux.Handle(path, newPprofHandler(conf.HTTPPprofToken, path))
+		node.http.handlerNames[path] = "pprof"
+	}
 	return node, nil
 }
 
@@ -174,11 +202,17 @@ func (n *Node) Start() error {
 		n.lock.Unlock()
 		return ErrNodeStopped
 	}
+	// Order lifecycles by their dependencies. The order is kept
+	// so they are stopped in reverse order when closing the node.
+	lifecycles, err := sortLifecycles(n.lifecycles, n.lifecycleDeps)
+	if err != nil {
+		n.lock.Unlock()
+		return err
+	}
+	n.lifecycles = lifecycles
 	n.state = runningState
 	// open networking and RPC endpoints
-	err := n.openEndpoints()
-	lifecycles := make([]Lifecycle, len(n.lifecycles))
-	copy(lifecycles, n.lifecycles)
+	err = n.openEndpoints()
 	n.lock.Unlock()
 
 	// Check if endpoint startup failed.
@@ -194,6 +228,10 @@ func (n *Node) Start() error {
 		}
 		started = append(started, lifecycle)
 	}
+	// Publish the manifest now that all endpoints are bound.
+	if err == nil {
+		err = n.writeManifest()
+	}
 	// Check if any lifecycle failed to start.
 	if err != nil {
 		n.stopServices(started)
@@ -217,6 +255,7 @@ func (n *Node) Close() error {
 		return n.doClose(nil)
 	case runningState:
 		// The node was started, release resources acquired by Start().
+		n.removeManifest()
 		var errs []error
 		if err := n.stopServices(n.lifecycles); err != nil {
 			errs = append(errs, err)
@@ -379,9 +418,17 @@ func (n *Node) startRPC() error {
 		return err
 	}
 
+	// The batch limits apply to all transports except the authenticated
+	// engine API, which uses its own limits.
+	rpcConfig := rpcEndpointConfig{
+		batchItemLimit:         n.config.BatchRequestLimit,
+		batchResponseSizeLimit: n.config.BatchResponseMaxSize,
+		largeResponseLog:       n.config.LargeResponseLogThreshold,
+	}
+
 	// Configure IPC.
 	if n.ipc.endpoint != "" {
-		if err := n.ipc.start(n.rpcAPIs); err != nil {
+		if err := n.ipc.start(n.rpcAPIs, rpcConfig); err != nil {
 			return err
 		}
 	}
@@ -390,11 +437,6 @@ func (n *Node) startRPC() error {
 		openAPIs, allAPIs = n.getAPIs()
 	)
 
-	rpcConfig := rpcEndpointConfig{
-		batchItemLimit:         n.config.BatchRequestLimit,
-		batchResponseSizeLimit: n.config.BatchResponseMaxSize,
-	}
-
 	initHttp := func(server *httpServer, port int) error {
 		if err := server.setListenAddr(n.config.HTTPHost, port); err != nil {
 			return err
@@ -421,6 +463,7 @@ func (n *Node) startRPC() error {
 			Modules:           n.config.WSModules,
 			Origins:           n.config.WSOrigins,
 			prefix:            n.config.WSPathPrefix,
+			conn:              n.config.WSConfig.rpcConfig(),
 			rpcEndpointConfig: rpcConfig,
 		}); err != nil {
 			return err
@@ -515,8 +558,17 @@ func (n *Node) wsServerForPort(port int, authenticated bool) *httpServer {
 }
 
 func (n *Node) stopRPC() {
-	n.http.stop()
-	n.ws.stop()
+	// The public endpoints drain concurrently, so stopping takes at most
+	// one grace period.
+	var wg sync.WaitGroup
+	for _, srv := range []*httpServer{n.http, n.ws} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			srv.stop()
+		}()
+	}
+	wg.Wait()
 	n.httpAuth.stop()
 	n.wsAuth.stop()
 	n.ipc.stop()
@@ -545,6 +597,15 @@ func (n *Node) Wait() {
 
 // RegisterLifecycle registers the given Lifecycle on the node.
 func (n *Node) RegisterLifecycle(lifecycle Lifecycle) {
+	n.RegisterLifecycleWithDeps(lifecycle)
+}
+
+// RegisterLifecycleWithDeps registers the given Lifecycle on the node. The node starts
+// it after the given lifecycles it depends on, and stops it before them.
+//
+// Starting the node fails if a dependency isn't registered or the dependencies
+// contain a cycle.
+func (n *Node) RegisterLifecycleWithDeps(lifecycle Lifecycle, dependsOn ...Lifecycle) {
 	n.lock.Lock()
 	defer n.lock.Unlock()
 
@@ -555,6 +616,12 @@ func (n *Node) RegisterLifecycle(lifecycle Lifecycle) {
 		panic(fmt.Sprintf("attempt to register lifecycle %T more than once", lifecycle))
 	}
 	n.lifecycles = append(n.lifecycles, lifecycle)
+	if len(dependsOn) > 0 {
+		if n.lifecycleDeps == nil {
+			n.lifecycleDeps = make(map[Lifecycle][]Lifecycle)
+		}
+		n.lifecycleDeps[lifecycle] = dependsOn
+	}
 }
 
 // RegisterProtocols adds backend's protocols to the node's p2p server.
@@ -569,14 +636,68 @@ func (n *Node) RegisterProtocols(protocols []p2p.Protocol) {
 }
 
 // RegisterAPIs registers the APIs a service provides on the node.
-func (n *Node) RegisterAPIs(apis []rpc.API) {
+//
+// APIs registered after the node has started are added to the running RPC endpoints,
+// subject to the module list of each endpoint. In that case, an error is returned if
+// any method of the APIs is already provided by the node, and none of the APIs are
+// registered.
+func (n *Node) RegisterAPIs(apis []rpc.API) error {
 	n.lock.Lock()
 	defer n.lock.Unlock()
 
-	if n.state != initializingState {
-		panic("can't register APIs on running/stopped node")
+	switch n.state {
+	case initializingState:
+		n.rpcAPIs = append(n.rpcAPIs, apis...)
+		return nil
+	case closedState:
+		return ErrNodeStopped
+	}
+	// Check for conflicts with the registered APIs before touching the endpoints,
+	// so a failed registration doesn't leave them in an inconsistent state.
+	check := rpc.NewServer()
+	defer check.Stop()
+	for _, api := range n.rpcAPIs {
+		if err := check.RegisterName(api.Namespace, api.Service); err != nil {
+			return err
+		}
+	}
+	for _, api := range apis {
+		if err := check.RegisterNameUnique(api.Namespace, api.Service); err != nil {
+			return err
+		}
+	}
+	// Add the APIs to the running endpoints. They are only recorded once all
+	// endpoints have accepted them.
+	if err := n.startInProc(apis); err != nil {
+		return err
+	}
+	if err := n.ipc.registerAPIs(apis); err != nil {
+		return err
+	}
+	var openAPIs []rpc.API
+	for _, api := range apis {
+		if !api.Authenticated {
+			openAPIs = append(openAPIs, api)
+		}
+	}
+	if err := n.http.registerAPIs(openAPIs); err != nil {
+		return err
+	}
+	if n.ws != n.http {
+		if err := n.ws.registerAPIs(openAPIs); err != nil {
+			return err
+		}
+	}
+	if err := n.httpAuth.registerAPIs(apis); err != nil {
+		return err
+	}
+	if n.wsAuth != n.httpAuth {
+		if err := n.wsAuth.registerAPIs(apis); err != nil {
+			return err
+		}
 	}
 	n.rpcAPIs = append(n.rpcAPIs, apis...)
+	return nil
 }
 
 // getAPIs return two sets of APIs, both the ones that do not require
@@ -611,6 +732,13 @@ func (n *Node) Attach() *rpc.Client {
 	return rpc.DialInProc(n.inprocHandler)
 }
 
+// AttachInProc creates an RPC client attached to the in-process API handler, which
+// passes arguments and results of method calls without JSON encoding where possible.
+// See rpc.DialInProcDirect for details.
+func (n *Node) AttachInProc() *rpc.Client {
+	return rpc.DialInProcDirect(n.inprocHandler)
+}
+
 // RPCHandler returns the in-process RPC request handler.
 func (n *Node) RPCHandler() (*rpc.Server, error) {
 	n.lock.Lock()
@@ -622,6 +750,19 @@ func (n *Node) RPCHandler() (*rpc.Server, error) {
 	return n.inprocHandler, nil
 }
 
+// SetHTTPVirtualHosts replaces the virtual hostnames accepted by the HTTP-RPC server.
+// The change applies to new requests immediately, the server is not restarted. It has
+// no effect if JSON-RPC over HTTP is not enabled.
+func (n *Node) SetHTTPVirtualHosts(vhosts []string) {
+	n.http.setVirtualHosts(vhosts)
+}
+
+// SetHTTPCORS replaces the CORS origins allowed by the HTTP-RPC server. Like
+// SetHTTPVirtualHosts, it applies to new requests immediately.
+func (n *Node) SetHTTPCORS(cors []string) {
+	n.http.setCORS(cors)
+}
+
 // Config returns the configuration of node.
 func (n *Node) Config() *Config {
 	return n.config
@@ -665,16 +806,86 @@ func (n *Node) IPCEndpoint() string {
 
 // HTTPEndpoint returns the URL of the HTTP server. Note that this URL does not
 // contain the JSON-RPC path prefix set by HTTPPathPrefix.
+//
+// When the server was configured with port zero, the returned URL contains the port
+// chosen by the operating system. This is only valid after the node has started.
+// An empty string is returned when the HTTP server is disabled.
 func (n *Node) HTTPEndpoint() string {
-	return "http://" + n.http.listenAddr()
+	addr := n.http.listenAddr()
+	if addr == "" {
+		return ""
+	}
+	return "http://" + addr
 }
 
-// WSEndpoint returns the current JSON-RPC over WebSocket endpoint.
+// WSEndpoint returns the current JSON-RPC over WebSocket endpoint. Like HTTPEndpoint,
+// it contains the actual listening port and is empty when WebSocket is disabled.
 func (n *Node) WSEndpoint() string {
 	if n.http.wsAllowed() {
 		return "ws://" + n.http.listenAddr() + n.http.wsConfig.prefix
 	}
-	return "ws://" + n.ws.listenAddr() + n.ws.wsConfig.prefix
+	addr := n.ws.listenAddr()
+	if addr == "" || !n.ws.wsAllowed() {
+		return ""
+	}
+	return "ws://" + addr + n.ws.wsConfig.prefix
+}
+
+// P2PListenAddr returns the address the P2P server is listening on for incoming
+// RLPx connections. When the server was configured with port zero, the returned
+// address contains the port chosen by the operating system. An empty string is
+// returned if the node is not running or the P2P server doesn't listen.
+func (n *Node) P2PListenAddr() string {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.state != runningState {
+		return ""
+	}
+	return n.server.ListenAddr
+}
+
+// PeerDetail contains information about a connected p2p peer.
+type PeerDetail struct {
+	Node       *enode.Node
+	Name       string        // client name advertised by the peer
+	Inbound    bool          // whether the peer connected to us
+	Protocols  []p2p.Cap     // negotiated protocols and versions
+	RemoteAddr string        // remote endpoint of the TCP connection
+	Connected  time.Duration // time since the connection was established
+	BytesIn    uint64        // bytes received from the peer
+	BytesOut   uint64        // bytes sent to the peer
+}
+
+// PeerInfo returns information about all connected p2p peers, sorted by node ID. Unlike
+// the admin_peers RPC method, it doesn't query the protocols for peer metadata and is
+// therefore cheap to call. Nil is returned if the node is not running.
+func (n *Node) PeerInfo() []PeerDetail {
+	n.lock.Lock()
+	running, server := n.state == runningState, n.server
+	n.lock.Unlock()
+	if !running {
+		return nil
+	}
+	peers := server.Peers()
+	details := make([]PeerDetail, 0, len(peers))
+	for _, p := range peers {
+		in, out := p.Traffic()
+		details = append(details, PeerDetail{
+			Node:       p.Node(),
+			Name:       p.Fullname(),
+			Inbound:    p.Inbound(),
+			Protocols:  p.RunningProtocols(),
+			RemoteAddr: p.RemoteAddr().String(),
+			Connected:  p.Lifetime(),
+			BytesIn:    in,
+			BytesOut:   out,
+		})
+	}
+	slices.SortFunc(details, func(a, b PeerDetail) int {
+		return bytes.Compare(a.Node.ID().Bytes(), b.Node.ID().Bytes())
+	})
+	return details
 }
 
 // HTTPAuthEndpoint returns the URL of the authenticated HTTP server.
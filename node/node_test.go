@@ -17,15 +17,21 @@
 package node
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"net"
 	"net/http"
+	"os"
 	"reflect"
+	"runtime"
 	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -259,6 +265,115 @@ func TestLifecycleLifeCycle(t *testing.T) {
 	}
 }
 
+// Tests that lifecycles are started after their dependencies and stopped before them.
+func TestLifecycleDependencies(t *testing.T) {
+	var (
+		log      []string
+		instType = reflect.TypeOf(new(InstrumentedService))
+		depType  = reflect.TypeOf(new(DependentService))
+	)
+	instrumented := func(name string) InstrumentedService {
+		return InstrumentedService{
+			startHook: func() { log = append(log, "start "+name) },
+			stopHook:  func() { log = append(log, "stop "+name) },
+		}
+	}
+	stack, _ := New(testNodeConfig())
+	stack.RegisterLifecycle(&DependentService{InstrumentedService: instrumented("dep"), deps: []reflect.Type{instType}})
+	inst1, inst2 := instrumented("inst1"), instrumented("inst2")
+	stack.RegisterLifecycle(&inst1)
+	stack.RegisterLifecycle(&inst2)
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start protocol stack: %v", err)
+	}
+	if err := stack.Close(); err != nil {
+		t.Fatalf("failed to stop protocol stack: %v", err)
+	}
+	want := []string{"start inst1", "start inst2", "start dep", "stop dep", "stop inst2", "stop inst1"}
+	if !slices.Equal(log, want) {
+		t.Fatalf("wrong lifecycle order:\nhave %q\nwant %q", log, want)
+	}
+
+	// Missing dependencies and cycles are rejected.
+	stack, _ = New(testNodeConfig())
+	defer stack.Close()
+	stack.RegisterLifecycle(&DependentService{deps: []reflect.Type{reflect.TypeOf(new(FullService))}})
+	if err := stack.Start(); err == nil || !strings.Contains(err.Error(), "unregistered *node.FullService") {
+		t.Fatalf("wrong error for missing dependency: %v", err)
+	}
+	stack, _ = New(testNodeConfig())
+	defer stack.Close()
+	stack.RegisterLifecycle(&DependentService{deps: []reflect.Type{depType}})
+	stack.RegisterLifecycle(&DependentService{deps: []reflect.Type{depType}})
+	if err := stack.Start(); err == nil || !strings.Contains(err.Error(), "dependency cycle") {
+		t.Fatalf("wrong error for dependency cycle: %v", err)
+	}
+}
+
+// Lifecycle types used for testing dependencies given explicitly.
+type (
+	baseService  struct{ InstrumentedService }
+	leftService  struct{ InstrumentedService }
+	rightService struct{ InstrumentedService }
+	topService   struct{ InstrumentedService }
+)
+
+func TestLifecycleExplicitDependencies(t *testing.T) {
+	var log []string
+	instrumented := func(name string) InstrumentedService {
+		return InstrumentedService{
+			startHook: func() { log = append(log, "start "+name) },
+			stopHook:  func() { log = append(log, "stop "+name) },
+		}
+	}
+	// Diamond: top depends on left and right, which both depend on base.
+	var (
+		base  = &baseService{instrumented("base")}
+		left  = &leftService{instrumented("left")}
+		right = &rightService{instrumented("right")}
+		top   = &topService{instrumented("top")}
+	)
+	stack, _ := New(testNodeConfig())
+	stack.RegisterLifecycleWithDeps(top, left, right)
+	stack.RegisterLifecycleWithDeps(right, base)
+	stack.RegisterLifecycleWithDeps(left, base)
+	stack.RegisterLifecycle(base)
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start protocol stack: %v", err)
+	}
+	if err := stack.Close(); err != nil {
+		t.Fatalf("failed to stop protocol stack: %v", err)
+	}
+	want := []string{
+		"start base", "start right", "start left", "start top",
+		"stop top", "stop left", "stop right", "stop base",
+	}
+	if !slices.Equal(log, want) {
+		t.Fatalf("wrong lifecycle order:\nhave %q\nwant %q", log, want)
+	}
+
+	// Cycles are reported with the lifecycles involved, in dependency order.
+	base, left, right, top = new(baseService), new(leftService), new(rightService), new(topService)
+	stack, _ = New(testNodeConfig())
+	defer stack.Close()
+	stack.RegisterLifecycle(base)
+	stack.RegisterLifecycleWithDeps(top, left)
+	stack.RegisterLifecycleWithDeps(left, right, base)
+	stack.RegisterLifecycleWithDeps(right, top)
+	wantErr := "dependency cycle between lifecycles *node.topService -> *node.leftService -> *node.rightService -> *node.topService"
+	if err := stack.Start(); err == nil || err.Error() != wantErr {
+		t.Fatalf("wrong error for dependency cycle:\nhave %v\nwant %s", err, wantErr)
+	}
+
+	// Dependencies must be registered.
+	stack, _ = New(testNodeConfig())
+	defer stack.Close()
+	stack.RegisterLifecycleWithDeps(new(topService), new(baseService))
+	if err := stack.Start(); err == nil || !strings.Contains(err.Error(), "unregistered *node.baseService") {
+		t.Fatalf("wrong error for missing dependency: %v", err)
+	}
+}
+
 // Tests that if a Lifecycle fails to start, all others started before it will be
 // shut down.
 func TestLifecycleStartupError(t *testing.T) {
@@ -416,6 +531,126 @@ func TestRegisterHandler_Successful(t *testing.T) {
 	assert.Equal(t, "success", string(buf))
 }
 
+// Tests that the pprof handlers are only served with the configured token.
+func TestPprofHandler(t *testing.T) {
+	node, err := New(&Config{
+		HTTPHost:       "127.0.0.1",
+		HTTPTimeouts:   rpc.DefaultHTTPTimeouts,
+		HTTPPprofToken: "secret",
+		HTTPPprofPath:  "/pprof",
+	})
+	if err != nil {
+		t.Fatalf("could not create node: %v", err)
+	}
+	defer node.Close()
+	if err := node.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	url := node.HTTPEndpoint() + "/pprof/cmdline"
+	for _, tt := range []struct {
+		auth string
+		want int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer wrong", http.StatusUnauthorized},
+		{"secret", http.StatusUnauthorized},
+		{"Bearer secret", http.StatusOK},
+	} {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.auth != "" {
+			req.Header.Set("Authorization", tt.auth)
+		}
+		if resp := doHTTPRequest(t, req); resp.StatusCode != tt.want {
+			t.Errorf("auth %q: wrong status code: have %d, want %d", tt.auth, resp.StatusCode, tt.want)
+		}
+	}
+}
+
+// Tests that the readiness endpoint aggregates the readiness of all services.
+func TestReadinessHandler(t *testing.T) {
+	node := createNode(t, 0, 0)
+	defer node.Close()
+
+	syncing := &ReadyService{reason: "syncing"}
+	indexing := &ReadyService{reason: "indexing"}
+	node.RegisterLifecycle(syncing)
+	node.RegisterLifecycle(indexing)
+	node.RegisterLifecycle(NewNoop())
+
+	if ready, reasons := node.Readiness(); ready || len(reasons) != 1 {
+		t.Fatalf("node ready before start: %v", reasons)
+	}
+	if err := node.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	check := func(wantStatus int, wantBody string) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, node.HTTPEndpoint()+ReadinessPath, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp := doHTTPRequest(t, req)
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != wantStatus {
+			t.Errorf("wrong status code: have %d, want %d", resp.StatusCode, wantStatus)
+		}
+		if string(body) != wantBody {
+			t.Errorf("wrong response body: have %q, want %q", body, wantBody)
+		}
+	}
+	check(http.StatusServiceUnavailable, "NOT READY\n*node.ReadyService: syncing\n*node.ReadyService: indexing\n")
+	syncing.ready.Store(true)
+	check(http.StatusServiceUnavailable, "NOT READY\n*node.ReadyService: indexing\n")
+	indexing.ready.Store(true)
+	check(http.StatusOK, "READY\n")
+}
+
+// Tests that the batch limits are applied to the IPC endpoint.
+func TestIPCBatchLimits(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping unix socket test on windows")
+	}
+	conf := testNodeConfig()
+	conf.DataDir = t.TempDir()
+	conf.IPCPath = "geth.ipc"
+	conf.BatchRequestLimit = 2
+	node, err := New(conf)
+	if err != nil {
+		t.Fatalf("could not create node: %v", err)
+	}
+	defer node.Close()
+	if err := node.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	client, err := rpc.DialIPC(context.Background(), node.IPCEndpoint())
+	if err != nil {
+		t.Fatalf("could not dial IPC endpoint: %v", err)
+	}
+	defer client.Close()
+
+	batch := make([]rpc.BatchElem, 3)
+	for i := range batch {
+		batch[i] = rpc.BatchElem{Method: "rpc_modules", Result: new(map[string]string)}
+	}
+	if err := client.BatchCall(batch); err != nil {
+		t.Fatalf("batch call failed: %v", err)
+	}
+	if batch[0].Error == nil || !strings.Contains(batch[0].Error.Error(), "batch too large") {
+		t.Fatalf("expected 'batch too large' error, got %v", batch[0].Error)
+	}
+	if err := client.BatchCall(batch[:2]); err != nil {
+		t.Fatalf("batch call failed: %v", err)
+	}
+	for i, elem := range batch[:2] {
+		if elem.Error != nil {
+			t.Errorf("batch element %d failed: %v", i, elem.Error)
+		}
+	}
+}
+
 // Tests whether websocket requests can be handled on the same port as a regular http server.
 func TestWebsocketHTTPOnSamePort_WebsocketRequest(t *testing.T) {
 	node := startHTTP(t, 0, 0)
@@ -465,6 +700,156 @@ func TestWebsocketHTTPOnSeparatePort_WSRequest(t *testing.T) {
 	}
 }
 
+// Tests that the node reports the actual listening addresses when started with
+// OS-assigned ports, and empty addresses for disabled endpoints.
+func TestNodeEndpoints(t *testing.T) {
+	conf := testNodeConfig()
+	conf.HTTPHost = "127.0.0.1"
+	conf.P2P.ListenAddr = "127.0.0.1:0"
+	conf.P2P.NoDiscovery = true
+	node, err := New(conf)
+	if err != nil {
+		t.Fatalf("could not create node: %v", err)
+	}
+	defer node.Close()
+	if addr := node.P2PListenAddr(); addr != "" {
+		t.Fatalf("P2P listen address reported before start: %q", addr)
+	}
+	if err := node.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+
+	hasPort := func(addr string) bool {
+		_, port, err := net.SplitHostPort(addr)
+		return err == nil && port != "0"
+	}
+	if ep := node.HTTPEndpoint(); !strings.HasPrefix(ep, "http://") || !hasPort(strings.TrimPrefix(ep, "http://")) {
+		t.Errorf("wrong HTTP endpoint %q", ep)
+	}
+	if !checkRPC(node.HTTPEndpoint()) {
+		t.Errorf("http request failed")
+	}
+	if ep := node.WSEndpoint(); ep != "" {
+		t.Errorf("WebSocket endpoint %q reported while disabled", ep)
+	}
+	if addr := node.P2PListenAddr(); !hasPort(addr) {
+		t.Errorf("wrong P2P listen address %q", addr)
+	}
+}
+
+// Tests that the manifest file is written on start and removed on stop.
+func TestNodeManifest(t *testing.T) {
+	conf := testNodeConfig()
+	conf.DataDir = t.TempDir()
+	conf.HTTPHost = "127.0.0.1"
+	conf.ManifestFile = "manifest.json"
+	conf.P2P.ListenAddr = "127.0.0.1:0"
+	conf.P2P.NoDiscovery = true
+	node, err := New(conf)
+	if err != nil {
+		t.Fatalf("could not create node: %v", err)
+	}
+	defer node.Close()
+	node.SetChainID(big.NewInt(5))
+	path := node.ResolvePath("manifest.json")
+	if err := node.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("can't read manifest: %v", err)
+	}
+	var m NodeManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("invalid manifest: %v", err)
+	}
+	if !reflect.DeepEqual(m, node.Manifest()) {
+		t.Errorf("manifest file doesn't match\nfile: %+v\nnode: %+v", m, node.Manifest())
+	}
+	if m.HTTP != node.HTTPEndpoint() || m.HTTP == "" {
+		t.Errorf("wrong HTTP endpoint %q", m.HTTP)
+	}
+	if m.P2PListenAddr != node.P2PListenAddr() || m.Enode != node.Server().Self().URLv4() {
+		t.Errorf("wrong p2p info: %q %q", m.P2PListenAddr, m.Enode)
+	}
+	if m.ChainID.Cmp(big.NewInt(5)) != 0 || m.DataDir != conf.DataDir || m.WS != "" {
+		t.Errorf("wrong manifest %+v", m)
+	}
+
+	node.Close()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("manifest not removed on close: %v", err)
+	}
+}
+
+type registerAPITest struct{ val string }
+
+func (api *registerAPITest) Value() string { return api.val }
+
+// Tests that APIs can be registered on a running node.
+func TestRegisterAPIsAfterStart(t *testing.T) {
+	conf := testNodeConfig()
+	conf.HTTPHost = "127.0.0.1"
+	conf.HTTPModules = []string{"test"}
+	node, err := New(conf)
+	if err != nil {
+		t.Fatalf("could not create node: %v", err)
+	}
+	defer node.Close()
+	if err := node.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	err = node.RegisterAPIs([]rpc.API{
+		{Namespace: "test", Service: &registerAPITest{"test"}},
+		{Namespace: "other", Service: &registerAPITest{"other"}},
+	})
+	if err != nil {
+		t.Fatalf("could not register APIs: %v", err)
+	}
+
+	// The APIs should be available in-process and over HTTP, subject to the modules.
+	client, err := rpc.DialHTTP(node.HTTPEndpoint())
+	if err != nil {
+		t.Fatalf("could not dial HTTP endpoint: %v", err)
+	}
+	defer client.Close()
+	var result string
+	if err := client.Call(&result, "test_value"); err != nil || result != "test" {
+		t.Errorf("wrong result from HTTP: %q %v", result, err)
+	}
+	if err := client.Call(&result, "other_value"); err == nil {
+		t.Error("module not in HTTP module list is available over HTTP")
+	}
+	if err := node.Attach().Call(&result, "other_value"); err != nil || result != "other" {
+		t.Errorf("wrong result from inproc: %q %v", result, err)
+	}
+
+	// Registering a method again is rejected.
+	registered := len(node.rpcAPIs)
+	err = node.RegisterAPIs([]rpc.API{
+		{Namespace: "new", Service: &registerAPITest{"new"}},
+		{Namespace: "test", Service: &registerAPITest{"dup"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "test_value is already registered") {
+		t.Fatalf("wrong error for duplicate registration: %v", err)
+	}
+	if err := client.Call(&result, "test_value"); err != nil || result != "test" {
+		t.Errorf("wrong result after duplicate registration: %q %v", result, err)
+	}
+	if err := node.Attach().Call(&result, "new_value"); err == nil {
+		t.Error("API of failed registration is available")
+	}
+	if len(node.rpcAPIs) != registered {
+		t.Errorf("APIs of failed registration recorded: have %d, want %d", len(node.rpcAPIs), registered)
+	}
+
+	node.Close()
+	if err := node.RegisterAPIs(nil); err != ErrNodeStopped {
+		t.Errorf("wrong error after close: %v", err)
+	}
+}
+
 type rpcPrefixTest struct {
 	httpPrefix, wsPrefix string
 	// These lists paths on which JSON-RPC should be served / not served.
@@ -621,3 +1006,128 @@ func containsAPI(stackAPIs []rpc.API, api rpc.API) bool {
 	}
 	return false
 }
+
+func TestNodePeerInfo(t *testing.T) {
+	proto := p2p.Protocol{
+		Name:    "test",
+		Version: 1,
+		Length:  1,
+		Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+			for {
+				msg, err := rw.ReadMsg()
+				if err != nil {
+					return err
+				}
+				msg.Discard()
+			}
+		},
+	}
+	newNode := func() *Node {
+		key, _ := crypto.GenerateKey()
+		conf := &Config{P2P: p2p.Config{PrivateKey: key, ListenAddr: "127.0.0.1:0", NoDiscovery: true, MaxPeers: 10}}
+		node, err := New(conf)
+		if err != nil {
+			t.Fatalf("could not create node: %v", err)
+		}
+		node.RegisterProtocols([]p2p.Protocol{proto})
+		return node
+	}
+	dialer, listener := newNode(), newNode()
+	defer dialer.Close()
+	defer listener.Close()
+	if peers := dialer.PeerInfo(); peers != nil {
+		t.Fatalf("peers reported before start: %v", peers)
+	}
+	if err := dialer.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := listener.Start(); err != nil {
+		t.Fatal(err)
+	}
+	dialer.Server().AddPeer(listener.Server().Self())
+
+	check := func(node *Node, remote *Node, inbound bool) {
+		t.Helper()
+		var peers []PeerDetail
+		for i := 0; i < 100; i++ {
+			if peers = node.PeerInfo(); len(peers) > 0 {
+				break
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		if len(peers) != 1 {
+			t.Fatalf("wrong number of peers: %d", len(peers))
+		}
+		p := peers[0]
+		if p.Node.ID() != remote.Server().Self().ID() || p.Inbound != inbound {
+			t.Errorf("wrong peer identity: %v, inbound %v", p.Node.ID(), p.Inbound)
+		}
+		if !slices.Equal(p.Protocols, []p2p.Cap{{Name: "test", Version: 1}}) {
+			t.Errorf("wrong protocols: %v", p.Protocols)
+		}
+		if p.Connected <= 0 || p.BytesIn == 0 || p.BytesOut == 0 {
+			t.Errorf("missing connection stats: %+v", p)
+		}
+	}
+	check(dialer, listener, false)
+	check(listener, dialer, true)
+}
+
+// This test checks that virtual hosts and CORS origins of the HTTP-RPC server can be
+// changed while it is running.
+func TestNodeSetHTTPVirtualHostsAndCORS(t *testing.T) {
+	node, err := New(&Config{
+		HTTPHost:         "127.0.0.1",
+		HTTPVirtualHosts: []string{"test"},
+		HTTPTimeouts:     rpc.DefaultHTTPTimeouts,
+	})
+	if err != nil {
+		t.Fatal("can't create node:", err)
+	}
+	defer node.Close()
+	if err := node.Start(); err != nil {
+		t.Fatal("can't start node:", err)
+	}
+	url := node.HTTPEndpoint()
+
+	if resp := rpcRequest(t, url, testMethod, "host", "other"); resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("wrong status for unknown host: %d", resp.StatusCode)
+	}
+
+	// Send requests while the settings change.
+	var (
+		stop = make(chan struct{})
+		done = make(chan struct{})
+	)
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				rpcRequest(t, url, testMethod, "host", "test")
+			}
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		node.SetHTTPVirtualHosts([]string{"test", fmt.Sprint(i)})
+	}
+	node.SetHTTPVirtualHosts([]string{"test", "other"})
+	close(stop)
+	<-done
+
+	if resp := rpcRequest(t, url, testMethod, "host", "other"); resp.StatusCode != http.StatusOK {
+		t.Errorf("wrong status for allowed host: %d", resp.StatusCode)
+	}
+	if resp := rpcRequest(t, url, testMethod, "origin", "https://example.com"); resp.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Error("CORS header set before enabling origin")
+	}
+	node.SetHTTPCORS([]string{"https://example.com"})
+	if resp := rpcRequest(t, url, testMethod, "origin", "https://example.com"); resp.Header.Get("Access-Control-Allow-Origin") != "https://example.com" {
+		t.Error("CORS header missing after enabling origin")
+	}
+	if resp := rpcRequest(t, url, testMethod, "host", "other"); resp.StatusCode != http.StatusOK {
+		t.Errorf("virtual hosts changed by SetHTTPCORS: status %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+)
+
+// DefaultPprofPath is the default path on which the pprof handlers are served.
+const DefaultPprofPath = "/debug/pprof/"
+
+type pprofHandler struct {
+	token  []byte
+	prefix string
+	mux    *http.ServeMux
+}
+
+// newPprofHandler creates a http.Handler serving the net/http/pprof handlers below
+// the given path prefix, accessible only with the given bearer token.
+func newPprofHandler(token, prefix string) http.Handler {
+	// The pprof index page expects to be served under /debug/pprof/, so requests
+	// are rewritten to that path before being dispatched.
+	mux := http.NewServeMux()
+	mux.HandleFunc(DefaultPprofPath, pprof.Index)
+	mux.HandleFunc(DefaultPprofPath+"cmdline", pprof.Cmdline)
+	mux.HandleFunc(DefaultPprofPath+"profile", pprof.Profile)
+	mux.HandleFunc(DefaultPprofPath+"symbol", pprof.Symbol)
+	mux.HandleFunc(DefaultPprofPath+"trace", pprof.Trace)
+
+	return &pprofHandler{token: []byte(token), prefix: prefix, mux: mux}
+}
+
+// ServeHTTP implements http.Handler
+func (handler *pprofHandler) ServeHTTP(out http.ResponseWriter, r *http.Request) {
+	auth, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || len(auth) == 0 {
+		http.Error(out, "missing token", http.StatusUnauthorized)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(auth), handler.token) != 1 {
+		http.Error(out, "invalid token", http.StatusUnauthorized)
+		return
+	}
+	r2 := r.Clone(r.Context())
+	r2.URL.Path = DefaultPprofPath + strings.TrimPrefix(r.URL.Path, handler.prefix)
+	r2.URL.RawPath = ""
+	handler.mux.ServeHTTP(out, r2)
+}
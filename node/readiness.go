@@ -0,0 +1,75 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ReadinessPath is the path on which the node's readiness is served.
+const ReadinessPath = "/readyz"
+
+// Readiness reports whether the node is running and all registered lifecycles
+// implementing ReadinessReporter are ready. If not, the reasons of all lifecycles
+// that aren't ready are returned.
+func (n *Node) Readiness() (bool, []string) {
+	n.lock.Lock()
+	state, lifecycles := n.state, n.lifecycles
+	n.lock.Unlock()
+
+	switch state {
+	case initializingState:
+		return false, []string{"node not started"}
+	case closedState:
+		return false, []string{"node stopped"}
+	}
+	var reasons []string
+	for _, lifecycle := range lifecycles {
+		reporter, ok := lifecycle.(ReadinessReporter)
+		if !ok {
+			continue
+		}
+		if ready, reason := reporter.Ready(); !ready {
+			if reason == "" {
+				reason = "not ready"
+			}
+			reasons = append(reasons, fmt.Sprintf("%T: %s", lifecycle, reason))
+		}
+	}
+	return len(reasons) == 0, reasons
+}
+
+// readinessHandler serves the node's readiness. It responds with status 200 if
+// the node is ready, and with 503 and the reasons otherwise.
+type readinessHandler struct {
+	node *Node
+}
+
+// ServeHTTP implements http.Handler
+func (handler *readinessHandler) ServeHTTP(out http.ResponseWriter, r *http.Request) {
+	ready, reasons := handler.node.Readiness()
+	out.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	out.Header().Set("Cache-Control", "no-store")
+	if !ready {
+		out.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(out, "NOT READY\n%s\n", strings.Join(reasons, "\n"))
+		return
+	}
+	fmt.Fprintln(out, "READY")
+}
@@ -50,6 +50,7 @@ type wsConfig struct {
 	Origins []string
 	Modules []string
 	prefix  string // path prefix on which to mount ws handler
+	conn    rpc.WebsocketConfig
 	rpcEndpointConfig
 }
 
@@ -58,6 +59,7 @@ type rpcEndpointConfig struct {
 	batchItemLimit         int
 	batchResponseSizeLimit int
 	httpBodyLimit          int
+	largeResponseLog       int
 }
 
 type rpcHandler struct {
@@ -89,6 +91,10 @@ type httpServer struct {
 	port     int
 
 	handlerNames map[string]string
+
+	// Requests are rejected while the server drains during shutdown.
+	gracePeriod time.Duration
+	draining    atomic.Bool
 }
 
 const (
@@ -197,6 +203,13 @@ func (h *httpServer) start() error {
 }
 
 func (h *httpServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.draining.Load() {
+		retry := int((h.gracePeriod + time.Second - 1) / time.Second)
+		w.Header().Set("Retry-After", strconv.Itoa(retry))
+		w.Header().Set("Connection", "close")
+		http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
 	// check if ws request and serve if ws enabled
 	ws := h.wsHandler.Load().(*rpcHandler)
 	if ws != nil && isWebsocket(r) {
@@ -266,6 +279,20 @@ func (h *httpServer) doStop() {
 		return // not running
 	}
 
+	// Let in-flight requests finish before stopping the RPC handlers.
+	if h.gracePeriod > 0 {
+		h.draining.Store(true)
+		defer h.draining.Store(false)
+		h.log.Info("HTTP server draining", "endpoint", h.listener.Addr(), "period", h.gracePeriod)
+
+		ctx, cancel := context.WithTimeout(context.Background(), h.gracePeriod)
+		err := h.server.Shutdown(ctx)
+		cancel()
+		if err != nil && err == ctx.Err() {
+			h.log.Warn("HTTP server grace period expired", "endpoint", h.listener.Addr())
+		}
+	}
+
 	// Shut down the server.
 	httpHandler := h.httpHandler.Load().(*rpcHandler)
 	wsHandler := h.wsHandler.Load().(*rpcHandler)
@@ -306,6 +333,7 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig) error {
 	// Create RPC server and handler.
 	srv := rpc.NewServer()
 	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
+	srv.SetLargeResponseLogThreshold(config.largeResponseLog)
 	if config.httpBodyLimit > 0 {
 		srv.SetHTTPBodyLimit(config.httpBodyLimit)
 	}
@@ -330,6 +358,38 @@ func (h *httpServer) disableRPC() bool {
 	return handler != nil
 }
 
+// setVirtualHosts replaces the virtual hosts accepted by the HTTP RPC handler.
+func (h *httpServer) setVirtualHosts(vhosts []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.httpConfig.Vhosts = vhosts
+	h.rebuildHTTPHandler()
+}
+
+// setCORS replaces the CORS origins allowed by the HTTP RPC handler.
+func (h *httpServer) setCORS(cors []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.httpConfig.CorsAllowedOrigins = cors
+	h.rebuildHTTPHandler()
+}
+
+// rebuildHTTPHandler swaps the HTTP RPC handler for one using the current config.
+// Requests which are already being processed are not affected. This is internal,
+// the caller must hold h.mu.
+func (h *httpServer) rebuildHTTPHandler() {
+	handler := h.httpHandler.Load().(*rpcHandler)
+	if handler == nil {
+		return
+	}
+	h.httpHandler.Store(&rpcHandler{
+		Handler: NewHTTPHandlerStack(handler.server, h.httpConfig.CorsAllowedOrigins, h.httpConfig.Vhosts, h.httpConfig.jwtSecret),
+		server:  handler.server,
+	})
+}
+
 // enableWS turns on JSON-RPC over WebSocket on the server.
 func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 	h.mu.Lock()
@@ -341,9 +401,11 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 	// Create RPC server and handler.
 	srv := rpc.NewServer()
 	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
+	srv.SetLargeResponseLogThreshold(config.largeResponseLog)
 	if config.httpBodyLimit > 0 {
 		srv.SetHTTPBodyLimit(config.httpBodyLimit)
 	}
+	srv.SetWebsocketConfig(config.conn)
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
 	}
@@ -355,6 +417,25 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 	return nil
 }
 
+// registerAPIs adds the given APIs to the enabled RPC handlers of the server,
+// applying their module lists.
+func (h *httpServer) registerAPIs(apis []rpc.API) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if handler := h.httpHandler.Load().(*rpcHandler); handler != nil {
+		if err := registerAllowedApis(apis, h.httpConfig.Modules, handler.server); err != nil {
+			return err
+		}
+	}
+	if handler := h.wsHandler.Load().(*rpcHandler); handler != nil {
+		if err := registerAllowedApis(apis, h.wsConfig.Modules, handler.server); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // stopWS disables JSON-RPC over WebSocket and also stops the server if it only serves WebSocket.
 func (h *httpServer) stopWS() {
 	h.mu.Lock()
@@ -597,15 +678,18 @@ func newIPCServer(log log.Logger, endpoint string) *ipcServer {
 	return &ipcServer{log: log, endpoint: endpoint}
 }
 
-// start starts the httpServer's http.Server
-func (is *ipcServer) start(apis []rpc.API) error {
+// start starts the IPC endpoint, applying the batch limits of the given config.
+func (is *ipcServer) start(apis []rpc.API, config rpcEndpointConfig) error {
 	is.mu.Lock()
 	defer is.mu.Unlock()
 
 	if is.listener != nil {
 		return nil // already running
 	}
-	listener, srv, err := rpc.StartIPCEndpoint(is.endpoint, apis)
+	srv := rpc.NewServer()
+	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
+	srv.SetLargeResponseLogThreshold(config.largeResponseLog)
+	listener, err := rpc.ServeIPCEndpoint(is.endpoint, apis, srv)
 	if err != nil {
 		is.log.Warn("IPC opening failed", "url", is.endpoint, "error", err)
 		return err
@@ -615,6 +699,22 @@ func (is *ipcServer) start(apis []rpc.API) error {
 	return nil
 }
 
+// registerAPIs adds the given APIs to the running IPC endpoint.
+func (is *ipcServer) registerAPIs(apis []rpc.API) error {
+	is.mu.Lock()
+	defer is.mu.Unlock()
+
+	if is.srv == nil {
+		return nil // not running
+	}
+	for _, api := range apis {
+		if err := is.srv.RegisterName(api.Namespace, api.Service); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (is *ipcServer) stop() error {
 	is.mu.Lock()
 	defer is.mu.Unlock()
@@ -635,6 +735,12 @@ func RegisterApis(apis []rpc.API, modules []string, srv *rpc.Server) error {
 	if bad, available := checkModuleAvailability(modules, apis); len(bad) > 0 {
 		log.Error("Unavailable modules in HTTP API list", "unavailable", bad, "available", available)
 	}
+	return registerAllowedApis(apis, modules, srv)
+}
+
+// registerAllowedApis registers the APIs whose namespace is contained in modules.
+// All APIs are registered if modules is empty.
+func registerAllowedApis(apis []rpc.API, modules []string, srv *rpc.Server) error {
 	// Generate the allow list based on the allowed modules
 	allowList := make(map[string]bool)
 	for _, module := range modules {
@@ -166,6 +166,59 @@ func TestWebsocketOrigins(t *testing.T) {
 }
 
 // TestIsWebsocket tests if an incoming websocket upgrade request is handled properly.
+// TestWebsocketConfig checks that the message size limit and ping interval are
+// applied to websocket connections.
+func TestWebsocketConfig(t *testing.T) {
+	wsConf := &wsConfig{
+		Origins: []string{"*"},
+		conn:    rpc.WebsocketConfig{MaxMessageSize: 512, PingInterval: 50 * time.Millisecond},
+	}
+	srv := createAndStartServer(t, &httpConfig{}, true, wsConf, nil)
+	defer srv.stop()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+srv.listenAddr(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	pings := make(chan struct{}, 1)
+	conn.SetPingHandler(func(string) error {
+		select {
+		case pings <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+
+	// Small requests are answered, idle connections are pinged.
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","id":1,"method":"rpc_modules"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := conn.ReadMessage(); err != nil {
+		t.Fatalf("failed to read response: %v", err)
+	}
+	go conn.ReadMessage()
+	select {
+	case <-pings:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no ping received")
+	}
+	conn.Close()
+
+	// Oversized messages are rejected with a close message.
+	conn, _, err = websocket.DefaultDialer.Dial("ws://"+srv.listenAddr(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := conn.WriteMessage(websocket.TextMessage, bytes.Repeat([]byte{' '}, 1024)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseMessageTooBig) {
+		t.Fatalf("wrong error for oversized message: %v", err)
+	}
+}
+
 func TestIsWebsocket(t *testing.T) {
 	r, _ := http.NewRequest(http.MethodGet, "/", nil)
 
@@ -595,6 +648,44 @@ func TestHTTPWriteTimeout(t *testing.T) {
 	})
 }
 
+func TestHTTPShutdownGracePeriod(t *testing.T) {
+	srv := createAndStartServer(t, &httpConfig{Modules: []string{"test"}}, false, &wsConfig{}, nil)
+	srv.gracePeriod = 2500 * time.Millisecond
+	url := fmt.Sprintf("http://%v", srv.listenAddr())
+
+	// Start a slow request and stop the server while it's in flight.
+	result := make(chan string, 1)
+	go func() {
+		resp := rpcRequest(t, url, "test_sleep")
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		result <- strings.TrimSpace(string(body))
+	}()
+	time.Sleep(300 * time.Millisecond)
+	stopped := make(chan struct{})
+	go func() {
+		srv.stop()
+		close(stopped)
+	}()
+	for !srv.draining.Load() {
+		time.Sleep(10 * time.Millisecond)
+	}
+	// New requests are rejected while draining.
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"test_greet"}`)))
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") != "3" {
+		t.Errorf("wrong response while draining: status %d, Retry-After %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	// The in-flight request completes.
+	if body := <-result; body != `{"jsonrpc":"2.0","id":1,"result":null}` {
+		t.Errorf("wrong response of in-flight request: %s", body)
+	}
+	<-stopped
+	if srv.draining.Load() {
+		t.Error("server still draining after stop")
+	}
+}
+
 func apis() []rpc.API {
 	return []rpc.API{
 		{
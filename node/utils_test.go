@@ -20,6 +20,9 @@
 package node
 
 import (
+	"reflect"
+	"sync/atomic"
+
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -63,6 +66,23 @@ func (s *InstrumentedService) Stop() error {
 	return s.stop
 }
 
+// DependentService is an InstrumentedService which depends on other lifecycles.
+type DependentService struct {
+	InstrumentedService
+	deps []reflect.Type
+}
+
+func (s *DependentService) Dependencies() []reflect.Type { return s.deps }
+
+// ReadyService is a NoopLifecycle reporting a settable readiness.
+type ReadyService struct {
+	NoopLifecycle
+	ready  atomic.Bool
+	reason string
+}
+
+func (s *ReadyService) Ready() (bool, string) { return s.ready.Load(), s.reason }
+
 type FullService struct{}
 
 func NewFullService(stack *Node) (*FullService, error) {
@@ -0,0 +1,133 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import "github.com/ethereum/go-ethereum/rlp"
+
+// CompressionHint controls the snappy compression of a protocol's messages.
+//
+// The hints are exchanged in the devp2p handshake. If both ends send hints, the
+// compression of a protocol is disabled if either end disables it, and enabled if
+// either end requests it. Otherwise, messages are compressed if both ends support
+// devp2p version 5.
+type CompressionHint uint
+
+const (
+	// CompressionDefault compresses messages if both ends support it.
+	CompressionDefault CompressionHint = iota
+
+	// CompressionAlways compresses messages unless the remote end disables
+	// compression of the protocol.
+	CompressionAlways
+
+	// CompressionNever disables compression of the protocol's messages.
+	CompressionNever
+)
+
+// capCompression is the compression hint of a protocol in the devp2p handshake.
+type capCompression struct {
+	Name string
+	Hint CompressionHint
+
+	// Ignore additional fields (for forward compatibility).
+	Rest []rlp.RawValue `rlp:"tail"`
+}
+
+// compressionHints returns the handshake hints for the given protocols. A hint is
+// sent for every protocol name, so the remote end can tell that hints are supported.
+func compressionHints(protocols []Protocol) []capCompression {
+	var hints []capCompression
+	seen := make(map[string]bool)
+	for _, p := range protocols {
+		if !seen[p.Name] {
+			seen[p.Name] = true
+			hints = append(hints, capCompression{Name: p.Name, Hint: p.Compression})
+		}
+	}
+	return hints
+}
+
+// setCompression stores the compression hints as the first additional field of the
+// handshake.
+func (h *protoHandshake) setCompression(hints []capCompression) {
+	enc, err := rlp.EncodeToBytes(hints)
+	if err != nil {
+		panic("can't encode compression hints: " + err.Error())
+	}
+	h.Rest = []rlp.RawValue{enc}
+}
+
+// compression returns the compression hints sent by the remote end. The hints are
+// decoded leniently: if the first additional field is missing or isn't a list of
+// hints, it is ignored and nil is returned.
+func (h *protoHandshake) compression() []capCompression {
+	if len(h.Rest) == 0 {
+		return nil
+	}
+	var hints []capCompression
+	if err := rlp.DecodeBytes(h.Rest[0], &hints); err != nil {
+		return nil
+	}
+	return hints
+}
+
+// compressionSetter is implemented by transports supporting per-protocol compression.
+type compressionSetter interface {
+	setCompression(overrides []compressionRange)
+}
+
+// compressionRange is a range of message codes whose compression differs from the
+// default of the connection.
+type compressionRange struct {
+	offset, length uint64
+	compress       bool
+}
+
+// compressionOverrides negotiates the compression of the running protocols with the
+// hints sent by the remote end.
+func compressionOverrides(running map[string]*protoRW, theirs []capCompression) []compressionRange {
+	if len(theirs) == 0 {
+		return nil // remote end doesn't support hints
+	}
+	remote := make(map[string]CompressionHint, len(theirs))
+	for _, h := range theirs {
+		remote[h.Name] = h.Hint
+	}
+	var overrides []compressionRange
+	for name, rw := range running {
+		ours, hint := rw.Compression, remote[name]
+		switch {
+		case ours == CompressionNever || hint == CompressionNever:
+			overrides = append(overrides, compressionRange{rw.offset, rw.Length, false})
+		case ours == CompressionAlways || hint == CompressionAlways:
+			overrides = append(overrides, compressionRange{rw.offset, rw.Length, true})
+		}
+	}
+	return overrides
+}
+
+// compressionFilter returns the function selecting the compressed messages.
+func compressionFilter(snappy bool, overrides []compressionRange) func(code uint64) bool {
+	return func(code uint64) bool {
+		for _, r := range overrides {
+			if code >= r.offset && code < r.offset+r.length {
+				return r.compress
+			}
+		}
+		return snappy
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/pipes"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestCompressionOverrides(t *testing.T) {
+	protocols := []Protocol{
+		{Name: "a", Version: 1, Length: 2},
+		{Name: "b", Version: 1, Length: 3, Compression: CompressionNever},
+		{Name: "c", Version: 1, Length: 4, Compression: CompressionAlways},
+		{Name: "d", Version: 1, Length: 5},
+	}
+	caps := []Cap{{"a", 1}, {"b", 1}, {"c", 1}, {"d", 1}}
+	running := matchProtocols(protocols, caps, nil)
+
+	tests := []struct {
+		snappy bool
+		theirs []capCompression
+		want   map[uint64]bool // compression of first message code per protocol
+	}{
+		// Remote end without hints: default for all protocols.
+		{true, nil, map[uint64]bool{16: true, 18: true, 21: true, 25: true}},
+		{false, nil, map[uint64]bool{16: false, 18: false, 21: false, 25: false}},
+		// Remote end with default hints: local hints apply.
+		{
+			true,
+			[]capCompression{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}},
+			map[uint64]bool{0: true, 16: true, 18: false, 21: true, 25: true},
+		},
+		{
+			false,
+			[]capCompression{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}},
+			map[uint64]bool{0: false, 16: false, 18: false, 21: true, 24: true, 25: false},
+		},
+		// Never wins over always, unknown hints are ignored.
+		{
+			true,
+			[]capCompression{{Name: "a", Hint: CompressionNever}, {Name: "b", Hint: CompressionAlways}, {Name: "c", Hint: CompressionNever}, {Name: "d", Hint: 7}},
+			map[uint64]bool{16: false, 18: false, 21: false, 25: true},
+		},
+	}
+	for i, test := range tests {
+		filter := compressionFilter(test.snappy, compressionOverrides(running, test.theirs))
+		for code, want := range test.want {
+			if got := filter(code); got != want {
+				t.Errorf("test %d: wrong compression of code %d: %t", i, code, got)
+			}
+		}
+	}
+}
+
+func TestCompressionTransport(t *testing.T) {
+	var (
+		prv0, _ = crypto.GenerateKey()
+		prv1, _ = crypto.GenerateKey()
+		protos0 = []Protocol{{Name: "bulk", Version: 1, Length: 1}, {Name: "tiny", Version: 1, Length: 1, Compression: CompressionNever}}
+		protos1 = []Protocol{{Name: "bulk", Version: 1, Length: 1}, {Name: "tiny", Version: 1, Length: 1}}
+		caps    = []Cap{{"bulk", 1}, {"tiny", 1}}
+		payload = bytes.Repeat([]byte{0x42}, 1000)
+	)
+	fd0, fd1, err := pipes.TCPPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fd0.Close()
+	defer fd1.Close()
+
+	setup := func(tr transport, prv *ecdsa.PrivateKey, protos []Protocol) error {
+		if _, err := tr.doEncHandshake(prv); err != nil {
+			return err
+		}
+		pub := crypto.FromECDSAPub(&prv.PublicKey)[1:]
+		ours := &protoHandshake{Version: baseProtocolVersion, ID: pub, Caps: caps}
+		ours.setCompression(compressionHints(protos))
+		their, err := tr.doProtoHandshake(ours)
+		if err != nil {
+			return err
+		}
+		tr.(compressionSetter).setCompression(compressionOverrides(matchProtocols(protos, their.Caps, nil), their.compression()))
+		return nil
+	}
+	var (
+		t0, t1 = newRLPX(fd0, &prv1.PublicKey), newRLPX(fd1, nil)
+		wg     sync.WaitGroup
+		errs   = make(chan error, 2)
+	)
+	wg.Add(2)
+	go func() { defer wg.Done(); errs <- setup(t0, prv0, protos0) }()
+	go func() { defer wg.Done(); errs <- setup(t1, prv1, protos1) }()
+	wg.Wait()
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+	// "bulk" is compressed by default, "tiny" is disabled by one end.
+	for _, test := range []struct {
+		code       uint64
+		compressed bool
+	}{{16, true}, {17, false}} {
+		go t0.WriteMsg(Msg{Code: test.code, Size: uint32(len(payload)), Payload: bytes.NewReader(payload)})
+		msg, err := t1.ReadMsg()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if compressed := msg.meterSize < msg.Size; compressed != test.compressed {
+			t.Errorf("message %d: wrong wire size %d, compression %t", test.code, msg.meterSize, test.compressed)
+		}
+		msg.Discard()
+	}
+}
+
+func TestCompressionHandshakeCompat(t *testing.T) {
+	hints := []capCompression{{Name: "a", Hint: CompressionNever, Rest: []rlp.RawValue{}}, {Name: "b", Rest: []rlp.RawValue{}}}
+	tests := []struct {
+		extra []interface{} // additional handshake fields
+		want  []capCompression
+	}{
+		{nil, nil},
+		{[]interface{}{hints}, hints},
+		{[]interface{}{hints, uint(1), "future"}, hints},
+		// Unknown fields in place of the hints are ignored.
+		{[]interface{}{uint(7)}, nil},
+		{[]interface{}{"future", hints}, nil},
+		{[]interface{}{[]interface{}{[]byte{1}, []interface{}{}}}, nil},
+	}
+	for i, test := range tests {
+		fields := []interface{}{uint(baseProtocolVersion), "name", []Cap{{"a", 1}}, uint(0), []byte{1, 2, 3}}
+		enc, err := rlp.EncodeToBytes(append(fields, test.extra...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var hs protoHandshake
+		if err := rlp.DecodeBytes(enc, &hs); err != nil {
+			t.Errorf("test %d: decode error: %v", i, err)
+			continue
+		}
+		if got := hs.compression(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("test %d: wrong hints %v, want %v", i, got, test.want)
+		}
+	}
+}
@@ -104,6 +104,8 @@ type dialScheduler struct {
 	remStaticCh   chan *enode.Node
 	addPeerCh     chan *conn
 	remPeerCh     chan *conn
+	backoffCh     chan backoffQuery
+	clearHistCh   chan enode.ID
 
 	// Everything below here belongs to loop and
 	// should only be accessed by code on the loop goroutine.
@@ -129,6 +131,11 @@ type dialScheduler struct {
 
 type dialSetupFunc func(net.Conn, connFlag, *enode.Node) error
 
+type backoffQuery struct {
+	id     enode.ID
+	result chan time.Duration
+}
+
 type dialConfig struct {
 	self           enode.ID         // our own ID
 	maxDialPeers   int              // maximum number of dialed peers
@@ -139,6 +146,7 @@ type dialConfig struct {
 	log            log.Logger
 	clock          mclock.Clock
 	rand           *mrand.Rand
+	errorFeed      *dialErrorFeed // receives dial errors, disabled if nil
 }
 
 func (cfg dialConfig) withDefaults() dialConfig {
@@ -176,6 +184,8 @@ func newDialScheduler(config dialConfig, it enode.Iterator, setupFunc dialSetupF
 		remStaticCh:   make(chan *enode.Node),
 		addPeerCh:     make(chan *conn),
 		remPeerCh:     make(chan *conn),
+		backoffCh:     make(chan backoffQuery),
+		clearHistCh:   make(chan enode.ID),
 	}
 	d.lastStatsLog = d.clock.Now()
 	d.ctx, d.cancel = context.WithCancel(context.Background())
@@ -223,6 +233,25 @@ func (d *dialScheduler) peerRemoved(c *conn) {
 	}
 }
 
+// redialBackoff returns the time until the given node can be dialed again.
+func (d *dialScheduler) redialBackoff(id enode.ID) time.Duration {
+	q := backoffQuery{id: id, result: make(chan time.Duration, 1)}
+	select {
+	case d.backoffCh <- q:
+		return <-q.result
+	case <-d.ctx.Done():
+		return 0
+	}
+}
+
+// clearHistory removes the given node from the dial history.
+func (d *dialScheduler) clearHistory(id enode.ID) {
+	select {
+	case d.clearHistCh <- id:
+	case <-d.ctx.Done():
+	}
+}
+
 // loop is the main loop of the dialer.
 func (d *dialScheduler) loop(it enode.Iterator) {
 	var (
@@ -241,6 +270,7 @@ loop:
 		}
 		d.rearmHistoryTimer()
 		d.logStats()
+		dialCooldownGauge.Update(int64(len(d.history)))
 
 		select {
 		case node := <-nodesCh:
@@ -285,7 +315,9 @@ loop:
 			}
 			task := newDialTask(node, staticDialedConn)
 			d.static[id] = task
-			if d.checkDial(node) == nil {
+			if err := d.checkDial(node); err != nil {
+				d.reportError(node, err)
+			} else {
 				d.addToStaticPool(task)
 			}
 
@@ -300,6 +332,20 @@ loop:
 				}
 			}
 
+		case q := <-d.backoffCh:
+			var backoff time.Duration
+			if exp, ok := d.history.expiry(string(q.id.Bytes())); ok {
+				backoff = max(exp.Sub(d.clock.Now()), 0)
+			}
+			q.result <- backoff
+
+		case id := <-d.clearHistCh:
+			if d.history.remove(string(id.Bytes())) {
+				d.log.Debug("Cleared dial history", "id", id)
+				// Static nodes are redialed immediately.
+				d.updateStaticPool(id)
+			}
+
 		case <-d.historyTimer.C():
 			d.expireHistory()
 
@@ -399,6 +445,18 @@ func (d *dialScheduler) checkDial(n *enode.Node) error {
 	return nil
 }
 
+// reportError sends a dial error event for node n.
+func (d *dialScheduler) reportError(n *enode.Node, err error) {
+	if d.errorFeed == nil {
+		return
+	}
+	ev := &DialErrorEvent{Kind: ClassifyDialError(err), Node: n, Err: err}
+	if addr, ok := n.TCPEndpoint(); ok {
+		ev.Addr = net.TCPAddrFromAddrPort(addr)
+	}
+	d.errorFeed.send(ev)
+}
+
 // startStaticDials starts n static dial tasks.
 func (d *dialScheduler) startStaticDials(n int) (started int) {
 	for started = 0; started < n && len(d.staticPool) > 0; started++ {
@@ -531,6 +589,10 @@ type dialError struct {
 	error
 }
 
+func (e *dialError) Unwrap() error {
+	return e.error
+}
+
 func (t *dialTask) dest() *enode.Node {
 	return t.destPtr.Load()
 }
@@ -614,7 +676,9 @@ func (t *dialTask) dial(d *dialScheduler, dest *enode.Node) error {
 		addr, _ := dest.TCPEndpoint()
 		d.log.Trace("Dial error", "id", dest.ID(), "addr", addr, "conn", t.flags, "err", cleanupDialErr(err))
 		dialConnectionError.Mark(1)
-		return &dialError{err}
+		err = &dialError{err}
+		d.reportError(dest, err)
+		return err
 	}
 	return d.setupFunc(newMeteredConn(fd), t.flags, dest)
 }
@@ -0,0 +1,178 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+// DialErrorKind classifies the reason why a connection attempt failed.
+type DialErrorKind uint8
+
+const (
+	DialErrUnknown            DialErrorKind = iota // unclassified error
+	DialErrTimeout                                 // dial or handshake timed out
+	DialErrConnection                              // TCP connection failed, e.g. refused or unreachable
+	DialErrHandshake                               // RLPx encryption or protocol handshake failed
+	DialErrUnexpectedIdentity                      // remote node has a different ID than expected
+	DialErrTooManyPeers                            // local or remote node has no free peer slots
+	DialErrAlreadyConnected                        // node is already connected or being dialed
+	DialErrSelf                                    // node is the local node
+	DialErrUselessPeer                             // node has no matching protocols
+	DialErrRecentlyDialed                          // node was dialed recently
+	DialErrNetRestrict                             // node IP is not in the netrestrict list
+	DialErrNoEndpoint                              // node has no usable TCP endpoint
+	DialErrThrottled                               // inbound connection attempts from IP are too frequent
+	DialErrServerStopped                           // server is not running
+)
+
+var dialErrorKindToString = [...]string{
+	DialErrUnknown:            "unknown",
+	DialErrTimeout:            "timeout",
+	DialErrConnection:         "connection failed",
+	DialErrHandshake:          "handshake failed",
+	DialErrUnexpectedIdentity: "unexpected identity",
+	DialErrTooManyPeers:       "too many peers",
+	DialErrAlreadyConnected:   "already connected",
+	DialErrSelf:               "is self",
+	DialErrUselessPeer:        "useless peer",
+	DialErrRecentlyDialed:     "recently dialed",
+	DialErrNetRestrict:        "not in netrestrict list",
+	DialErrNoEndpoint:         "no endpoint",
+	DialErrThrottled:          "throttled",
+	DialErrServerStopped:      "server stopped",
+}
+
+func (k DialErrorKind) String() string {
+	if int(k) >= len(dialErrorKindToString) {
+		return fmt.Sprintf("unknown dial error kind %d", k)
+	}
+	return dialErrorKindToString[k]
+}
+
+// DialErrorEvent is sent to subscribers of Server.SubscribeDialErrors when an outbound
+// dial or an inbound connection fails before the peer is added.
+type DialErrorEvent struct {
+	Kind    DialErrorKind
+	Node    *enode.Node // remote node, nil for inbound connections rejected before the handshake
+	Addr    net.Addr    // remote address, may be nil
+	Inbound bool
+	Err     error
+}
+
+// dialErrorFeed delivers DialErrorEvents to subscribers. Unlike event.Feed, sending
+// never blocks: an event is dropped for every subscriber whose channel is full, so a
+// slow subscriber can't stall dialing or accepting connections.
+type dialErrorFeed struct {
+	mu   sync.Mutex
+	subs map[chan<- *DialErrorEvent]struct{}
+}
+
+// subscribe adds ch to the subscribers until the subscription is canceled.
+func (f *dialErrorFeed) subscribe(ch chan<- *DialErrorEvent) event.Subscription {
+	f.mu.Lock()
+	if f.subs == nil {
+		f.subs = make(map[chan<- *DialErrorEvent]struct{})
+	}
+	f.subs[ch] = struct{}{}
+	f.mu.Unlock()
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		f.mu.Lock()
+		delete(f.subs, ch)
+		f.mu.Unlock()
+		return nil
+	})
+}
+
+// send delivers ev to all subscribers which have room for it in their channel.
+// It returns the number of subscribers the event was delivered to.
+func (f *dialErrorFeed) send(ev *DialErrorEvent) (nsent int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for ch := range f.subs {
+		select {
+		case ch <- ev:
+			nsent++
+		default:
+		}
+	}
+	return nsent
+}
+
+// ClassifyDialError returns the kind of the given connection error. It can be applied
+// to errors returned by Server.SetupConn as well as to DialErrorEvent.Err.
+func ClassifyDialError(err error) DialErrorKind {
+	switch {
+	case err == nil:
+		return DialErrUnknown
+	case errors.Is(err, errServerStopped):
+		return DialErrServerStopped
+	case errors.Is(err, errSelf):
+		return DialErrSelf
+	case errors.Is(err, errAlreadyDialing), errors.Is(err, errAlreadyConnected):
+		return DialErrAlreadyConnected
+	case errors.Is(err, errRecentlyDialed):
+		return DialErrRecentlyDialed
+	case errors.Is(err, errNetRestrict):
+		return DialErrNetRestrict
+	case errors.Is(err, errNoPort), errors.Is(err, errNoResolvedIP):
+		return DialErrNoEndpoint
+	case errors.Is(err, errTooManyAttempts):
+		return DialErrThrottled
+	}
+	// Disconnect reasons are either sent by the remote end during the
+	// handshake or produced by the local post-handshake checks.
+	var reason DiscReason
+	if errors.As(err, &reason) {
+		switch reason {
+		case DiscTooManyPeers:
+			return DialErrTooManyPeers
+		case DiscAlreadyConnected:
+			return DialErrAlreadyConnected
+		case DiscSelf:
+			return DialErrSelf
+		case DiscUselessPeer:
+			return DialErrUselessPeer
+		case DiscUnexpectedIdentity:
+			return DialErrUnexpectedIdentity
+		case DiscReadTimeout:
+			return DialErrTimeout
+		}
+	}
+	var nerr net.Error
+	if errors.As(err, &nerr) && nerr.Timeout() {
+		return DialErrTimeout
+	}
+	var dialErr *dialError
+	switch {
+	case errors.As(err, &dialErr):
+		return DialErrConnection
+	case errors.Is(err, errEncHandshakeError), errors.Is(err, errProtoHandshakeError):
+		return DialErrHandshake
+	default:
+		return DialErrUnknown
+	}
+}
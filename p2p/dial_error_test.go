@@ -0,0 +1,147 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/internal/testlog"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+func TestClassifyDialError(t *testing.T) {
+	var (
+		timeout = &net.OpError{Op: "dial", Net: "tcp", Err: os.ErrDeadlineExceeded}
+		refused = &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	)
+	tests := []struct {
+		err  error
+		want DialErrorKind
+	}{
+		{nil, DialErrUnknown},
+		{errors.New("foo"), DialErrUnknown},
+		{errServerStopped, DialErrServerStopped},
+		{errSelf, DialErrSelf},
+		{errAlreadyDialing, DialErrAlreadyConnected},
+		{errAlreadyConnected, DialErrAlreadyConnected},
+		{errRecentlyDialed, DialErrRecentlyDialed},
+		{errNetRestrict, DialErrNetRestrict},
+		{errNoPort, DialErrNoEndpoint},
+		{errNoResolvedIP, DialErrNoEndpoint},
+		{errTooManyAttempts, DialErrThrottled},
+		{&dialError{timeout}, DialErrTimeout},
+		{&dialError{refused}, DialErrConnection},
+		{DiscTooManyPeers, DialErrTooManyPeers},
+		{DiscAlreadyConnected, DialErrAlreadyConnected},
+		{DiscSelf, DialErrSelf},
+		{DiscUselessPeer, DialErrUselessPeer},
+		{DiscUnexpectedIdentity, DialErrUnexpectedIdentity},
+		{fmt.Errorf("%w: %w", errEncHandshakeError, timeout), DialErrTimeout},
+		{fmt.Errorf("%w: %w", errEncHandshakeError, errors.New("bad auth")), DialErrHandshake},
+		{fmt.Errorf("%w: %w", errProtoHandshakeError, DiscTooManyPeers), DialErrTooManyPeers},
+		{fmt.Errorf("%w: %w", errProtoHandshakeError, DiscIncompatibleVersion), DialErrHandshake},
+	}
+	for _, test := range tests {
+		if have := ClassifyDialError(test.err); have != test.want {
+			t.Errorf("wrong kind for %q: have %v, want %v", test.err, have, test.want)
+		}
+	}
+}
+
+func TestServerDialErrorEvents(t *testing.T) {
+	var (
+		srvkey    = newkey()
+		clientpub = &newkey().PublicKey
+		tt        = &setupTransport{pubkey: clientpub, protoHandshakeErr: DiscTooManyPeers}
+	)
+	srv := &Server{
+		Config: Config{
+			PrivateKey:  srvkey,
+			MaxPeers:    10,
+			NoDial:      true,
+			NoDiscovery: true,
+			Protocols:   []Protocol{discard},
+			Logger:      testlog.Logger(t, log.LvlTrace),
+		},
+		newTransport: func(fd net.Conn, dialDest *ecdsa.PublicKey) transport { return tt },
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("couldn't start server: %v", err)
+	}
+	defer srv.Stop()
+
+	ch := make(chan *DialErrorEvent, 1)
+	sub := srv.SubscribeDialErrors(ch)
+	defer sub.Unsubscribe()
+
+	// The remote end is full and disconnects during the protocol handshake.
+	dest := enode.NewV4(clientpub, nil, 0, 0)
+	p1, _ := net.Pipe()
+	err := srv.SetupConn(p1, dynDialedConn, dest)
+	if !errors.Is(err, errProtoHandshakeError) {
+		t.Fatalf("wrong error from SetupConn: %v", err)
+	}
+	select {
+	case ev := <-ch:
+		if ev.Kind != DialErrTooManyPeers {
+			t.Errorf("wrong kind: have %v, want %v", ev.Kind, DialErrTooManyPeers)
+		}
+		if ev.Node != dest || ev.Inbound || ev.Err != err {
+			t.Errorf("wrong event: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no dial error event")
+	}
+}
+
+func TestDialErrorFeedDrop(t *testing.T) {
+	var (
+		feed dialErrorFeed
+		full = make(chan *DialErrorEvent) // never ready
+		ch   = make(chan *DialErrorEvent, 1)
+		ev   = &DialErrorEvent{Kind: DialErrTimeout}
+	)
+	sub1 := feed.subscribe(full)
+	defer sub1.Unsubscribe()
+	sub2 := feed.subscribe(ch)
+
+	// Sending doesn't block on the subscriber which isn't ready.
+	if n := feed.send(ev); n != 1 {
+		t.Fatalf("wrong number of receivers %d, want 1", n)
+	}
+	if got := <-ch; got != ev {
+		t.Fatalf("wrong event %+v", got)
+	}
+	// The event is dropped for subscribers whose channel is full.
+	feed.send(ev)
+	if n := feed.send(ev); n != 0 {
+		t.Fatalf("wrong number of receivers %d with full channels, want 0", n)
+	}
+	<-ch
+	sub2.Unsubscribe()
+	if n := feed.send(ev); n != 0 {
+		t.Fatalf("event sent to unsubscribed channel")
+	}
+}
@@ -359,6 +359,54 @@ func TestDialSchedHistory(t *testing.T) {
 	})
 }
 
+// This test checks that clearing the dial history of a static node redials it.
+func TestDialSchedClearHistory(t *testing.T) {
+	t.Parallel()
+
+	config := dialConfig{
+		maxActiveDials: 3,
+		maxDialPeers:   3,
+	}
+	runDialTest(t, config, []dialTestRound{
+		{
+			update: func(d *dialScheduler) {
+				d.addStatic(newNode(uintID(0x01), "127.0.0.1:30303"))
+				d.addStatic(newNode(uintID(0x02), "127.0.0.2:30303"))
+			},
+			wantNewDials: []*enode.Node{
+				newNode(uintID(0x01), "127.0.0.1:30303"),
+				newNode(uintID(0x02), "127.0.0.2:30303"),
+			},
+		},
+		{
+			succeeded: []enode.ID{
+				uintID(0x01),
+			},
+			failed: []enode.ID{
+				uintID(0x02),
+			},
+			wantResolves: map[enode.ID]*enode.Node{
+				uintID(0x02): nil,
+			},
+		},
+		// Node 0x02 is still in the dial history, clearing it redials the node.
+		{
+			update: func(d *dialScheduler) {
+				if backoff := d.redialBackoff(uintID(0x02)); backoff <= 0 || backoff > dialHistoryExpiration {
+					t.Errorf("wrong backoff for 0x02: %v", backoff)
+				}
+				if backoff := d.redialBackoff(uintID(0x03)); backoff != 0 {
+					t.Errorf("wrong backoff for unknown node: %v", backoff)
+				}
+				d.clearHistory(uintID(0x02))
+			},
+			wantNewDials: []*enode.Node{
+				newNode(uintID(0x02), "127.0.0.2:30303"),
+			},
+		},
+	})
+}
+
 func TestDialSchedResolve(t *testing.T) {
 	t.Parallel()
 
@@ -19,6 +19,9 @@ package enode
 import (
 	"sync"
 	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 // Iterator represents a sequence of nodes. The Next method moves to the next node in the
@@ -123,6 +126,29 @@ func (f *filterIter) Next() bool {
 	return false
 }
 
+// HasEntry returns a filter function which accepts nodes whose record contains the
+// given key, regardless of its value.
+func HasEntry(key string) func(*Node) bool {
+	return func(n *Node) bool {
+		var value rlp.RawValue
+		return n.Load(enr.WithEntry(key, &value)) == nil
+	}
+}
+
+// All returns a filter function which accepts nodes accepted by all of the given
+// filter functions. The functions are called in order, and evaluation stops at the
+// first function rejecting the node.
+func All(checks ...func(*Node) bool) func(*Node) bool {
+	return func(n *Node) bool {
+		for _, check := range checks {
+			if !check(n) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
 // FairMix aggregates multiple node iterators. The mixer itself is an iterator which ends
 // only when Close is called. Source iterators added via AddSource are removed from the
 // mix when they end.
@@ -289,3 +289,50 @@ func (it *callCountIter) Next() bool {
 	it.count++
 	return it.Iterator.Next()
 }
+
+func TestFilterPredicates(t *testing.T) {
+	nodes := make([]*Node, 10)
+	for i := range nodes {
+		r := new(enr.Record)
+		r.SetSeq(uint64(i))
+		if i%2 == 0 {
+			r.Set(enr.WithEntry("foo", uint(i)))
+		}
+		if i%3 == 0 {
+			r.Set(enr.WithEntry("bar", true))
+		}
+		var id ID
+		binary.BigEndian.PutUint64(id[:], uint64(i))
+		nodes[i] = SignNull(r, id)
+	}
+	var calls []uint64
+	counted := func(n *Node) bool {
+		calls = append(calls, n.Seq())
+		return true
+	}
+	inner := &callCountIter{Iterator: IterNodes(nodes)}
+	it := Filter(inner, All(HasEntry("foo"), HasEntry("bar"), counted))
+
+	// Nodes are only checked when Next is called, and the later predicates are
+	// not called for nodes rejected by earlier ones.
+	if inner.count != 0 || len(calls) != 0 {
+		t.Fatal("filter evaluated before Next")
+	}
+	for _, want := range []uint64{0, 6} {
+		if !it.Next() {
+			t.Fatal("Next returned false")
+		}
+		if seq := it.Node().Seq(); seq != want {
+			t.Fatalf("wrong node %d, want %d", seq, want)
+		}
+		if inner.count != int(want)+1 {
+			t.Fatalf("%d calls to inner Next, want %d", inner.count, want+1)
+		}
+	}
+	if it.Next() {
+		t.Fatal("Next returned true after underlying iterator has ended")
+	}
+	if len(calls) != 2 || calls[0] != 0 || calls[1] != 6 {
+		t.Fatalf("wrong predicate calls %v", calls)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package enode
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// nodeJSON is a single entry of the node set file format. The record is stored
+// as a base64 "enr:..." string. Seq is redundant with the sequence number of the
+// record, but it is included to make files easier to inspect.
+//
+// The file format is compatible with the nodes.json files written by the devp2p tool.
+type nodeJSON struct {
+	Seq    uint64 `json:"seq"`
+	Record string `json:"record"`
+}
+
+// WriteNodesJSON writes the given nodes to a JSON file. The file contains a JSON
+// object keyed by hex node ID:
+//
+//	{
+//	    "<node id>": {
+//	        "seq": <sequence number of the record>,
+//	        "record": "enr:..."
+//	    },
+//	    ...
+//	}
+//
+// If the list contains multiple records of the same node, the one with the highest
+// sequence number is written.
+func WriteNodesJSON(file string, nodes []*Node) error {
+	set := make(map[ID]nodeJSON, len(nodes))
+	for _, n := range nodes {
+		if prev, ok := set[n.ID()]; ok && prev.Seq > n.Seq() {
+			continue
+		}
+		set[n.ID()] = nodeJSON{Seq: n.Seq(), Record: n.String()}
+	}
+	enc, err := json.MarshalIndent(set, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, enc, 0644)
+}
+
+// ReadNodesJSON reads a node set file written by WriteNodesJSON. The records are
+// verified using ValidSchemes. Invalid entries are skipped, and a warning is logged
+// for each of them. The returned nodes are sorted by ID.
+func ReadNodesJSON(file string) ([]*Node, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var set map[string]json.RawMessage
+	if err := json.Unmarshal(content, &set); err != nil {
+		return nil, err
+	}
+	nodes := make([]*Node, 0, len(set))
+	for key, raw := range set {
+		var entry nodeJSON
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			log.Warn("Skipping malformed node set entry", "file", file, "id", key, "err", err)
+			continue
+		}
+		n, err := Parse(ValidSchemes, entry.Record)
+		if err != nil {
+			log.Warn("Skipping invalid node record", "file", file, "id", key, "err", err)
+			continue
+		}
+		if id, err := ParseID(key); err != nil || id != n.ID() {
+			log.Warn("Skipping node record with mismatching ID", "file", file, "id", key, "recordID", n.ID())
+			continue
+		}
+		if entry.Seq != n.Seq() {
+			log.Warn("Node record sequence number mismatch", "file", file, "id", key, "seq", entry.Seq, "recordSeq", n.Seq())
+		}
+		nodes = append(nodes, n)
+	}
+	slices.SortFunc(nodes, func(a, b *Node) int {
+		return bytes.Compare(a.ID().Bytes(), b.ID().Bytes())
+	})
+	return nodes, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package enode
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+)
+
+func signedTestNode(t *testing.T, key *ecdsa.PrivateKey, seq uint64) *Node {
+	t.Helper()
+	var r enr.Record
+	r.SetSeq(seq)
+	if err := SignV4(&r, key); err != nil {
+		t.Fatal(err)
+	}
+	n, err := New(ValidSchemes, &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestNodesJSON(t *testing.T) {
+	var (
+		file    = filepath.Join(t.TempDir(), "nodes.json")
+		key1, _ = crypto.GenerateKey()
+		key2, _ = crypto.GenerateKey()
+		n1      = signedTestNode(t, key1, 5)
+		n1old   = signedTestNode(t, key1, 3)
+		n2      = signedTestNode(t, key2, 1)
+	)
+	if err := WriteNodesJSON(file, []*Node{n1, n2, n1old}); err != nil {
+		t.Fatal("write error:", err)
+	}
+	nodes, err := ReadNodesJSON(file)
+	if err != nil {
+		t.Fatal("read error:", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("wrong number of nodes: %d", len(nodes))
+	}
+	for _, n := range nodes {
+		switch n.ID() {
+		case n1.ID():
+			if n.Seq() != 5 {
+				t.Errorf("wrong seq for node 1: %d", n.Seq())
+			}
+		case n2.ID():
+			if n.Seq() != 1 {
+				t.Errorf("wrong seq for node 2: %d", n.Seq())
+			}
+		default:
+			t.Errorf("unexpected node %v", n.ID())
+		}
+	}
+
+	// Add some invalid entries and check they are skipped.
+	var set map[string]json.RawMessage
+	content, _ := os.ReadFile(file)
+	if err := json.Unmarshal(content, &set); err != nil {
+		t.Fatal(err)
+	}
+	set["not-an-object"] = json.RawMessage(`42`)
+	set[ID{1}.String()] = json.RawMessage(`{"seq": 1, "record": "enr:invalid"}`)
+	set[ID{2}.String()] = json.RawMessage(`{"seq": 1, "record": "` + n2.String() + `"}`)
+	content, _ = json.Marshal(set)
+	if err := os.WriteFile(file, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	nodes, err = ReadNodesJSON(file)
+	if err != nil {
+		t.Fatal("read error:", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("wrong number of nodes after adding invalid entries: %d", len(nodes))
+	}
+}
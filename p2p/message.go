@@ -86,6 +86,37 @@ type MsgWriter interface {
 	WriteMsg(Msg) error
 }
 
+// MsgPriority is the priority of an outgoing message. When multiple messages wait to be
+// written to a peer connection, the message with the highest priority is sent first.
+// Messages written with WriteMsg have normal priority.
+type MsgPriority uint8
+
+const (
+	PriorityLow    MsgPriority = iota // bulk data transfers
+	PriorityNormal                    // default priority
+	PriorityHigh                      // small, latency sensitive control messages
+
+	numPriorities = int(PriorityHigh) + 1
+)
+
+// MsgPriorityWriter is implemented by message writers which support priorities.
+type MsgPriorityWriter interface {
+	MsgWriter
+
+	// WriteMsgPriority sends a message with the given priority. Like WriteMsg, it
+	// blocks until the message's Payload has been consumed by the other end.
+	WriteMsgPriority(msg Msg, prio MsgPriority) error
+}
+
+// WriteMsgPriority sends a message with the given priority. If w doesn't support
+// priorities, the message is written with WriteMsg.
+func WriteMsgPriority(w MsgWriter, msg Msg, prio MsgPriority) error {
+	if pw, ok := w.(MsgPriorityWriter); ok {
+		return pw.WriteMsgPriority(msg, prio)
+	}
+	return w.WriteMsg(msg)
+}
+
 // MsgReadWriter provides reading and writing of encoded messages.
 // Implementations should ensure that ReadMsg and WriteMsg can be
 // called simultaneously from multiple goroutines.
@@ -299,7 +330,13 @@ func (ev *msgEventer) ReadMsg() (Msg, error) {
 // WriteMsg writes a message to the underlying MsgReadWriter and emits a
 // "message sent" event
 func (ev *msgEventer) WriteMsg(msg Msg) error {
-	err := ev.MsgReadWriter.WriteMsg(msg)
+	return ev.WriteMsgPriority(msg, PriorityNormal)
+}
+
+// WriteMsgPriority writes a message with the given priority to the underlying
+// MsgReadWriter and emits a "message sent" event
+func (ev *msgEventer) WriteMsgPriority(msg Msg, prio MsgPriority) error {
+	err := WriteMsgPriority(ev.MsgReadWriter, msg, prio)
 	if err != nil {
 		return err
 	}
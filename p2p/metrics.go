@@ -21,6 +21,7 @@ package p2p
 import (
 	"errors"
 	"net"
+	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/metrics"
 )
@@ -34,6 +35,10 @@ const (
 
 	// egressMeterName is the prefix of the per-packet outbound metrics.
 	egressMeterName = "p2p/egress"
+
+	// compressionHistName is the prefix of the per-protocol histograms of the wire
+	// size of outbound messages, in percent of their uncompressed size.
+	compressionHistName = "p2p/compression"
 )
 
 var (
@@ -50,6 +55,11 @@ var (
 	dialMeter           = metrics.NewRegisteredMeter("p2p/dials", nil)
 	dialSuccessMeter    = metrics.NewRegisteredMeter("p2p/dials/success", nil)
 	dialConnectionError = metrics.NewRegisteredMeter("p2p/dials/error/connection", nil)
+	dialCooldownGauge   = metrics.NewRegisteredGauge("p2p/dials/cooldown", nil)
+
+	// handshake limit meters
+	serveHandshakeRejected = metrics.NewRegisteredMeter("p2p/serves/error/pending", nil)
+	handshakeTimeoutMeter  = metrics.NewRegisteredMeter("p2p/handshakes/timeout", nil)
 
 	// handshake error meters
 	dialTooManyPeers        = metrics.NewRegisteredMeter("p2p/dials/error/saturated", nil)
@@ -61,33 +71,69 @@ var (
 	dialProtoHandshakeError = metrics.NewRegisteredMeter("p2p/dials/error/rlpx/proto", nil)
 )
 
+// compressionSample creates the sample of the compression ratio histograms.
+func compressionSample() metrics.Sample {
+	return metrics.ResettingSample(metrics.NewExpDecaySample(1028, 0.015))
+}
+
 // markDialError matches errors that occur while setting up a dial connection
 // to the corresponding meter.
 func markDialError(err error) {
 	if !metrics.Enabled() {
 		return
 	}
-	if err2 := errors.Unwrap(err); err2 != nil {
-		err = err2
-	}
-	switch err {
-	case DiscTooManyPeers:
+	// Handshake errors may wrap a disconnect reason sent by the remote end,
+	// they are checked first so they are counted as handshake errors.
+	switch {
+	case errors.Is(err, errEncHandshakeError):
+		dialEncHandshakeError.Mark(1)
+	case errors.Is(err, errProtoHandshakeError):
+		dialProtoHandshakeError.Mark(1)
+	case errors.Is(err, DiscTooManyPeers):
 		dialTooManyPeers.Mark(1)
-	case DiscAlreadyConnected:
+	case errors.Is(err, DiscAlreadyConnected):
 		dialAlreadyConnected.Mark(1)
-	case DiscSelf:
+	case errors.Is(err, DiscSelf):
 		dialSelf.Mark(1)
-	case DiscUselessPeer:
+	case errors.Is(err, DiscUselessPeer):
 		dialUselessPeer.Mark(1)
-	case DiscUnexpectedIdentity:
+	case errors.Is(err, DiscUnexpectedIdentity):
 		dialUnexpectedIdentity.Mark(1)
-	case errEncHandshakeError:
-		dialEncHandshakeError.Mark(1)
-	case errProtoHandshakeError:
-		dialProtoHandshakeError.Mark(1)
 	}
 }
 
+// markHandshakeTimeout bumps the handshake timeout meter if the given handshake
+// error was caused by a connection deadline.
+func markHandshakeTimeout(err error) {
+	if !metrics.Enabled() {
+		return
+	}
+	var nerr net.Error
+	if errors.As(err, &nerr) && nerr.Timeout() {
+		handshakeTimeoutMeter.Mark(1)
+	}
+}
+
+// countingConn is a wrapper around a net.Conn that counts the bytes read from and
+// written to the connection.
+type countingConn struct {
+	net.Conn
+	ingress atomic.Uint64
+	egress  atomic.Uint64
+}
+
+func (c *countingConn) Read(b []byte) (n int, err error) {
+	n, err = c.Conn.Read(b)
+	c.ingress.Add(uint64(n))
+	return n, err
+}
+
+func (c *countingConn) Write(b []byte) (n int, err error) {
+	n, err = c.Conn.Write(b)
+	c.egress.Add(uint64(n))
+	return n, err
+}
+
 // meteredConn is a wrapper around a net.Conn that meters both the
 // inbound and outbound network traffic.
 type meteredConn struct {
@@ -64,7 +64,8 @@ type protoHandshake struct {
 	ListenPort uint64
 	ID         []byte // secp256k1 public key
 
-	// Ignore additional fields (for forward compatibility).
+	// Ignore additional fields (for forward compatibility). The first additional
+	// field may carry compression hints, see compression.
 	Rest []rlp.RawValue `rlp:"tail"`
 }
 
@@ -191,11 +192,44 @@ func (p *Peer) RunningCap(protocol string, versions []uint) bool {
 	return false
 }
 
+// NegotiatedVersion returns the version of the given protocol that is running
+// with the peer. The boolean result is false if the protocol is not running.
+func (p *Peer) NegotiatedVersion(protocol string) (uint, bool) {
+	if proto, ok := p.running[protocol]; ok {
+		return proto.Version, true
+	}
+	return 0, false
+}
+
 // RemoteAddr returns the remote address of the network connection.
 func (p *Peer) RemoteAddr() net.Addr {
 	return p.rw.fd.RemoteAddr()
 }
 
+// RunningProtocols returns the protocols negotiated with the peer, sorted by name.
+func (p *Peer) RunningProtocols() []Cap {
+	caps := make([]Cap, 0, len(p.running))
+	for _, proto := range p.running {
+		caps = append(caps, proto.cap())
+	}
+	slices.SortFunc(caps, Cap.Cmp)
+	return caps
+}
+
+// Lifetime returns the time since the peer connection was established.
+func (p *Peer) Lifetime() time.Duration {
+	return time.Duration(mclock.Now() - p.created)
+}
+
+// Traffic returns the number of bytes received from and sent to the peer,
+// including the RLPx framing overhead.
+func (p *Peer) Traffic() (ingress, egress uint64) {
+	if p.rw.traffic == nil {
+		return 0, 0
+	}
+	return p.rw.traffic.ingress.Load(), p.rw.traffic.egress.Load()
+}
+
 // LocalAddr returns the local address of the network connection.
 func (p *Peer) LocalAddr() net.Addr {
 	return p.rw.fd.LocalAddr()
@@ -227,6 +261,9 @@ func (p *Peer) Inbound() bool {
 
 func newPeer(log log.Logger, conn *conn, protocols []Protocol) *Peer {
 	protomap := matchProtocols(protocols, conn.caps, conn)
+	if t, ok := conn.transport.(compressionSetter); ok {
+		t.setCompression(compressionOverrides(protomap, conn.compression))
+	}
 	p := &Peer{
 		rw:       conn,
 		running:  protomap,
@@ -251,13 +288,18 @@ func (p *Peer) run() (remoteRequested bool, err error) {
 		readErr    = make(chan error, 1)
 		reason     DiscReason // sent to the peer
 	)
-	p.wg.Add(2)
+	lanes := make([]chan struct{}, numPriorities)
+	for i := range lanes {
+		lanes[i] = make(chan struct{})
+	}
+	p.wg.Add(3)
 	go p.readLoop(readErr)
 	go p.pingLoop()
+	go p.scheduleWrites(writeStart, lanes)
 
 	// Start all protocol handlers.
 	writeStart <- struct{}{}
-	p.startProtocols(writeStart, writeErr)
+	p.startProtocols(lanes, writeErr)
 
 	// Wait for an error or disconnect.
 loop:
@@ -294,6 +336,40 @@ loop:
 	return remoteRequested, err
 }
 
+// scheduleWrites passes the write token to waiting protocol writers. Writers
+// of higher priority are preferred.
+func (p *Peer) scheduleWrites(writeStart <-chan struct{}, lanes []chan struct{}) {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-writeStart:
+		case <-p.closed:
+			return
+		}
+		// Hand the token to the highest priority writer which is already waiting.
+		granted := false
+		for prio := len(lanes) - 1; prio >= 0 && !granted; prio-- {
+			select {
+			case lanes[prio] <- struct{}{}:
+				granted = true
+			default:
+			}
+		}
+		if granted {
+			continue
+		}
+		// Nobody is waiting, give it to the first writer that shows up.
+		select {
+		case lanes[PriorityHigh] <- struct{}{}:
+		case lanes[PriorityNormal] <- struct{}{}:
+		case lanes[PriorityLow] <- struct{}{}:
+		case <-p.closed:
+			return
+		}
+	}
+}
+
 func (p *Peer) pingLoop() {
 	defer p.wg.Done()
 
@@ -412,12 +488,14 @@ func matchProtocols(protocols []Protocol, caps []Cap, rw MsgReadWriter) map[stri
 outer:
 	for _, cap := range caps {
 		for _, proto := range protocols {
-			if proto.Name == cap.Name && proto.Version == cap.Version {
+			if proto.supports(cap) {
 				// If an old protocol version matched, revert it
 				if old := result[cap.Name]; old != nil {
 					offset -= old.Length
 				}
-				// Assign the new match
+				// Assign the new match. For protocols negotiating a version range,
+				// the running instance carries the negotiated version.
+				proto.Version = cap.Version
 				result[cap.Name] = &protoRW{Protocol: proto, offset: offset, in: make(chan Msg), w: rw}
 				offset += proto.Length
 
@@ -428,7 +506,7 @@ outer:
 	return result
 }
 
-func (p *Peer) startProtocols(writeStart <-chan struct{}, writeErr chan<- error) {
+func (p *Peer) startProtocols(writeStart []chan struct{}, writeErr chan<- error) {
 	p.wg.Add(len(p.running))
 	for _, proto := range p.running {
 		proto.closed = p.closed
@@ -468,13 +546,17 @@ type protoRW struct {
 	Protocol
 	in     chan Msg        // receives read messages
 	closed <-chan struct{} // receives when peer is shutting down
-	wstart <-chan struct{} // receives when write may start
+	wstart []chan struct{} // receives when write may start, indexed by priority
 	werr   chan<- error    // for write results
 	offset uint64
 	w      MsgWriter
 }
 
-func (rw *protoRW) WriteMsg(msg Msg) (err error) {
+func (rw *protoRW) WriteMsg(msg Msg) error {
+	return rw.WriteMsgPriority(msg, PriorityNormal)
+}
+
+func (rw *protoRW) WriteMsgPriority(msg Msg, prio MsgPriority) (err error) {
 	if msg.Code >= rw.Length {
 		return newPeerError(errInvalidMsgCode, "not handled")
 	}
@@ -484,7 +566,7 @@ func (rw *protoRW) WriteMsg(msg Msg) (err error) {
 	msg.Code += rw.offset
 
 	select {
-	case <-rw.wstart:
+	case <-rw.wstart[min(prio, PriorityHigh)]:
 		err = rw.w.WriteMsg(msg)
 		// Report write status back to Peer.run. It will initiate
 		// shutdown if the error is non-nil and unblock the next write
@@ -17,6 +17,7 @@
 package p2p
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -25,6 +26,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -168,6 +170,51 @@ func TestPeerProtoEncodeMsg(t *testing.T) {
 	}
 }
 
+func TestPeerWritePriority(t *testing.T) {
+	queued := make(chan struct{})
+	proto := Protocol{
+		Name:   "a",
+		Length: 3,
+		Run: func(peer *Peer, rw MsgReadWriter) error {
+			var wg sync.WaitGroup
+			write := func(code uint64, prio MsgPriority) {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if err := WriteMsgPriority(rw, Msg{Code: code, Payload: bytes.NewReader(nil)}, prio); err != nil {
+						t.Errorf("write error: %v", err)
+					}
+				}()
+			}
+			// The first write holds the write token until the remote end reads it.
+			write(0, PriorityNormal)
+			time.Sleep(50 * time.Millisecond)
+			for i := 0; i < 3; i++ {
+				write(1, PriorityLow)
+			}
+			write(2, PriorityHigh)
+			time.Sleep(50 * time.Millisecond)
+			close(queued)
+			wg.Wait()
+			return nil
+		},
+	}
+	closer, rw, _, _ := testPeer([]Protocol{proto})
+	defer closer()
+
+	<-queued
+	for i, want := range []uint64{16, 18, 17, 17, 17} {
+		msg, err := rw.ReadMsg()
+		if err != nil {
+			t.Fatalf("read error: %v", err)
+		}
+		if msg.Code != want {
+			t.Fatalf("message %d: wrong code %d, want %d", i, msg.Code, want)
+		}
+		msg.Discard()
+	}
+}
+
 func TestPeerPing(t *testing.T) {
 	closer, rw, _, _ := testPeer(nil)
 	defer closer()
@@ -266,6 +313,30 @@ func TestNewPeer(t *testing.T) {
 	p.Disconnect(DiscAlreadyConnected) // Should not hang
 }
 
+func TestPeerNegotiatedVersion(t *testing.T) {
+	protos := []Protocol{{Name: "a", Version: 5, MinVersion: 2}, {Name: "b", Version: 1}}
+	if caps := protos[0].caps(); len(caps) != 4 || caps[0] != (Cap{"a", 2}) || caps[3] != (Cap{"a", 5}) {
+		t.Fatalf("wrong advertised caps: %v", caps)
+	}
+	conn := &conn{
+		node: newNode(uintID(1), ""),
+		caps: []Cap{{"a", 1}, {"a", 2}, {"a", 3}, {"b", 1}},
+	}
+	peer := newPeer(log.Root(), conn, protos)
+	if v, ok := peer.NegotiatedVersion("a"); !ok || v != 3 {
+		t.Errorf("wrong negotiated version for a: %d (running %t), want 3", v, ok)
+	}
+	if v, ok := peer.NegotiatedVersion("b"); !ok || v != 1 {
+		t.Errorf("wrong negotiated version for b: %d (running %t), want 1", v, ok)
+	}
+	if _, ok := peer.NegotiatedVersion("c"); ok {
+		t.Error("protocol c reported as running")
+	}
+	if !peer.RunningCap("a", []uint{3}) {
+		t.Error("RunningCap does not report negotiated version")
+	}
+}
+
 func TestMatchProtocols(t *testing.T) {
 	tests := []struct {
 		Remote []Cap
@@ -326,6 +397,23 @@ func TestMatchProtocols(t *testing.T) {
 			Local:  []Protocol{{Version: 1, Length: 1}, {Version: 2, Length: 2}, {Version: 3, Length: 3}, {Name: "a"}},
 			Match:  map[string]protoRW{"": {Protocol: Protocol{Version: 3}}, "a": {Protocol: Protocol{Name: "a"}, offset: 3}},
 		},
+		{
+			// Version range, highest mutual version
+			Remote: []Cap{{Version: 1}, {Version: 2}, {Version: 3}, {Version: 5}},
+			Local:  []Protocol{{Version: 4, MinVersion: 2}},
+			Match:  map[string]protoRW{"": {Protocol: Protocol{Version: 3}}},
+		},
+		{
+			// Version range, remote supports newer versions
+			Remote: []Cap{{Version: 2}, {Version: 3}, {Version: 4}, {Version: 5}},
+			Local:  []Protocol{{Version: 4, MinVersion: 1}},
+			Match:  map[string]protoRW{"": {Protocol: Protocol{Version: 4}}},
+		},
+		{
+			// Version range, no mutual versions
+			Remote: []Cap{{Version: 1}, {Version: 5}},
+			Local:  []Protocol{{Version: 4, MinVersion: 2}},
+		},
 	}
 
 	for i, tt := range tests {
@@ -34,10 +34,23 @@ type Protocol struct {
 	// Version should contain the version number of the protocol.
 	Version uint
 
+	// MinVersion, if non-zero, enables version range negotiation. The protocol is
+	// advertised with all versions from MinVersion up to Version, and the highest
+	// version supported by both sides is selected. The negotiated version can be
+	// retrieved using Peer.NegotiatedVersion.
+	//
+	// If MinVersion is zero, the protocol only matches peers advertising exactly
+	// Version.
+	MinVersion uint
+
 	// Length should contain the number of message codes used
 	// by the protocol.
 	Length uint64
 
+	// Compression controls the snappy compression of the protocol's messages. By
+	// default, messages are compressed if both ends support it.
+	Compression CompressionHint
+
 	// Run is called in a new goroutine when the protocol has been
 	// negotiated with a peer. It should read and write messages from
 	// rw. The Payload for each message must be fully consumed.
@@ -69,6 +82,29 @@ func (p Protocol) cap() Cap {
 	return Cap{p.Name, p.Version}
 }
 
+// caps returns all capabilities advertised for the protocol.
+func (p Protocol) caps() []Cap {
+	if p.MinVersion == 0 || p.MinVersion >= p.Version {
+		return []Cap{p.cap()}
+	}
+	caps := make([]Cap, 0, p.Version-p.MinVersion+1)
+	for v := p.MinVersion; v <= p.Version; v++ {
+		caps = append(caps, Cap{p.Name, v})
+	}
+	return caps
+}
+
+// supports reports whether the protocol can run with the given capability.
+func (p Protocol) supports(cap Cap) bool {
+	if p.Name != cap.Name {
+		return false
+	}
+	if p.MinVersion == 0 {
+		return p.Version == cap.Version
+	}
+	return cap.Version >= p.MinVersion && cap.Version <= p.Version
+}
+
 // Cap is the structure of a peer capability.
 type Cap struct {
 	Name    string
@@ -55,6 +55,9 @@ type Conn struct {
 	// Compression is enabled if they are non-nil.
 	snappyReadBuffer  []byte
 	snappyWriteBuffer []byte
+	// snappyFilter selects the compressed messages if compression is enabled.
+	// All messages are compressed if it is nil.
+	snappyFilter func(code uint64) bool
 }
 
 // sessionState contains the session keys.
@@ -110,6 +113,21 @@ func (c *Conn) SetSnappy(snappy bool) {
 	}
 }
 
+// SetSnappyFilter restricts snappy compression to the messages for which compress
+// returns true. Both ends of the connection must apply the same filter. The filter has
+// no effect unless compression is enabled using SetSnappy, and a nil filter selects
+// all messages.
+//
+// The filter must be set before reading or writing messages that depend on it.
+func (c *Conn) SetSnappyFilter(compress func(code uint64) bool) {
+	c.snappyFilter = compress
+}
+
+// compressed reports whether the message with the given code is compressed.
+func (c *Conn) compressed(code uint64) bool {
+	return c.snappyFilter == nil || c.snappyFilter(code)
+}
+
 // SetReadDeadline sets the deadline for all future read operations.
 func (c *Conn) SetReadDeadline(time time.Time) error {
 	return c.conn.SetReadDeadline(time)
@@ -143,7 +161,7 @@ func (c *Conn) Read() (code uint64, data []byte, wireSize int, err error) {
 	wireSize = len(data)
 
 	// If snappy is enabled, verify and decompress message.
-	if c.snappyReadBuffer != nil {
+	if c.snappyReadBuffer != nil && c.compressed(code) {
 		var actualSize int
 		actualSize, err = snappy.DecodedLen(data)
 		if err != nil {
@@ -214,7 +232,7 @@ func (c *Conn) Write(code uint64, data []byte) (uint32, error) {
 	if len(data) > maxUint24 {
 		return 0, errPlainMessageTooLarge
 	}
-	if c.snappyWriteBuffer != nil {
+	if c.snappyWriteBuffer != nil && c.compressed(code) {
 		// Ensure the buffer has sufficient size.
 		// Package snappy will allocate its own buffer if the provided
 		// one is smaller than MaxEncodedLen.
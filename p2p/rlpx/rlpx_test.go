@@ -64,6 +64,38 @@ func TestReadWriteMsg(t *testing.T) {
 	checkMsgReadWrite(t, peer1, peer2, testCode, testData)
 }
 
+func TestReadWriteMsgSnappyFilter(t *testing.T) {
+	peer1, peer2 := createPeers(t)
+	defer peer1.Close()
+	defer peer2.Close()
+
+	filter := func(code uint64) bool { return code != 24 }
+	for _, p := range []*Conn{peer1, peer2} {
+		p.SetSnappy(true)
+		p.SetSnappyFilter(filter)
+	}
+	testData := bytes.Repeat([]byte("test"), 100)
+	checkMsgReadWrite(t, peer1, peer2, 23, testData)
+	checkMsgReadWrite(t, peer1, peer2, 24, testData)
+
+	// Check the sizes on the wire.
+	for _, code := range []uint64{23, 24} {
+		done := make(chan struct{})
+		go func() {
+			peer1.Read()
+			close(done)
+		}()
+		size, err := peer2.Write(code, testData)
+		<-done
+		if err != nil {
+			t.Fatal(err)
+		}
+		if compressed := size < uint32(len(testData)); compressed != filter(code) {
+			t.Errorf("message %d: wrong wire size %d", code, size)
+		}
+	}
+}
+
 func checkMsgReadWrite(t *testing.T, p1, p2 *Conn, msgCode uint64, msgData []byte) {
 	// Set up the reader.
 	ch := make(chan message, 1)
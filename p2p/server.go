@@ -70,6 +70,7 @@ var (
 	errServerStopped       = errors.New("server stopped")
 	errEncHandshakeError   = errors.New("rlpx enc error")
 	errProtoHandshakeError = errors.New("rlpx proto error")
+	errTooManyAttempts     = errors.New("too many attempts")
 )
 
 // Config holds Server options.
@@ -86,6 +87,15 @@ type Config struct {
 	// Zero defaults to preset values.
 	MaxPendingPeers int `toml:",omitempty"`
 
+	// MaxPendingHandshakes is the maximum number of inbound connections that can be
+	// in the handshake phase at the same time. Inbound connections exceeding the limit
+	// are closed right after being accepted. Zero means no limit beyond MaxPendingPeers.
+	MaxPendingHandshakes int `toml:",omitempty"`
+
+	// HandshakeTimeout is the maximum time allowed for the encryption and protocol
+	// handshakes of a connection. Zero defaults to 5 seconds.
+	HandshakeTimeout time.Duration `toml:",omitempty"`
+
 	// DialRatio controls the ratio of inbound to dialed connections.
 	// Example: a DialRatio of 2 allows 1/2 of connections to be dialed.
 	// Setting DialRatio to zero defaults it to 3.
@@ -145,9 +155,25 @@ type Config struct {
 	ListenAddr string
 
 	// If DiscAddr is set to a non-nil value, the server will use ListenAddr
-	// for TCP and DiscAddr for the UDP discovery protocol.
+	// for TCP and DiscAddr for the UDP discovery protocol. This allows binding
+	// discovery and RLPx to different interfaces on multi-homed hosts. When
+	// DiscAddr is empty, discovery listens on ListenAddr.
+	//
+	// If DiscAddr is set, it will be updated with the actual address of the
+	// UDP listener when the server is started.
 	DiscAddr string
 
+	// If ListenFunc is set, it is called to create the listener for inbound
+	// connections instead of listening on ListenAddr via TCP. This allows serving
+	// RLPx over other stream transports, e.g. TLS-wrapped TCP or a QUIC adapter.
+	// The RLPx handshake runs on every connection returned by Accept, so any
+	// security provided by the transport is in addition to RLPx encryption.
+	//
+	// ListenAddr is updated with the address of the listener. Note that discovery
+	// listens on the same address unless DiscAddr is set, which conflicts with
+	// listeners using UDP.
+	ListenFunc func() (net.Listener, error) `toml:"-" json:"-"`
+
 	// If set to a non-nil value, the given NAT port mapper
 	// is used to make the listening port available to the
 	// Internet.
@@ -188,6 +214,7 @@ type Server struct {
 	ourHandshake *protoHandshake
 	loopWG       sync.WaitGroup // loop, listenLoop
 	peerFeed     event.Feed
+	dialErrFeed  dialErrorFeed
 	log          log.Logger
 
 	nodedb    *enode.DB
@@ -234,13 +261,16 @@ const (
 // conn wraps a network connection with information gathered
 // during the two handshakes.
 type conn struct {
-	fd net.Conn
+	fd      net.Conn
+	traffic *countingConn // byte counters of fd, nil if not set up via SetupConn
 	transport
 	node  *enode.Node
 	flags connFlag
 	cont  chan error // The run loop uses cont to signal errors to SetupConn.
 	caps  []Cap      // valid after the protocol handshake
 	name  string     // valid after the protocol handshake
+
+	compression []capCompression // valid after the protocol handshake
 }
 
 type transport interface {
@@ -368,6 +398,19 @@ func (srv *Server) RemovePeer(node *enode.Node) {
 	}
 }
 
+// RedialBackoff returns the remaining time during which the given node will not be
+// dialed because it was dialed recently. It returns zero if the node can be dialed.
+func (srv *Server) RedialBackoff(node *enode.Node) time.Duration {
+	return srv.dialsched.redialBackoff(node.ID())
+}
+
+// ClearDialHistory removes the given node from the dial history, ending its redial
+// backoff. If the node is a static node and not connected, it is dialed again
+// immediately.
+func (srv *Server) ClearDialHistory(node *enode.Node) {
+	srv.dialsched.clearHistory(node.ID())
+}
+
 // AddTrustedPeer adds the given node to a reserved trusted list which allows the
 // node to always connect, even if the slot are full.
 func (srv *Server) AddTrustedPeer(node *enode.Node) {
@@ -390,6 +433,15 @@ func (srv *Server) SubscribeEvents(ch chan *PeerEvent) event.Subscription {
 	return srv.peerFeed.Subscribe(ch)
 }
 
+// SubscribeDialErrors subscribes the given channel to connection errors. An event is
+// sent when dialing a node fails, when a static node can't be dialed, and when an
+// inbound connection is rejected or fails before the peer is added. The server never
+// waits for subscribers: if the channel is full, the event is dropped. Use a buffered
+// channel to avoid losing events.
+func (srv *Server) SubscribeDialErrors(ch chan *DialErrorEvent) event.Subscription {
+	return srv.dialErrFeed.subscribe(ch)
+}
+
 // Self returns the local node's endpoint information.
 func (srv *Server) Self() *enode.Node {
 	srv.lock.Lock()
@@ -472,7 +524,7 @@ func (srv *Server) Start() (err error) {
 	if srv.clock == nil {
 		srv.clock = mclock.System{}
 	}
-	if srv.NoDial && srv.ListenAddr == "" {
+	if srv.NoDial && srv.ListenAddr == "" && srv.ListenFunc == nil {
 		srv.log.Warn("P2P server will be useless, neither dialing nor listening")
 	}
 
@@ -481,7 +533,7 @@ func (srv *Server) Start() (err error) {
 		return errors.New("Server.PrivateKey must be set to a non-nil key")
 	}
 	if srv.newTransport == nil {
-		srv.newTransport = newRLPX
+		srv.newTransport = srv.newRLPX
 	}
 	if srv.listenFunc == nil {
 		srv.listenFunc = net.Listen
@@ -500,7 +552,7 @@ func (srv *Server) Start() (err error) {
 	}
 	srv.setupPortMapping()
 
-	if srv.ListenAddr != "" {
+	if srv.ListenAddr != "" || srv.ListenFunc != nil {
 		if err := srv.setupListening(); err != nil {
 			return err
 		}
@@ -520,9 +572,10 @@ func (srv *Server) setupLocalNode() error {
 	pubkey := crypto.FromECDSAPub(&srv.PrivateKey.PublicKey)
 	srv.ourHandshake = &protoHandshake{Version: baseProtocolVersion, Name: srv.Name, ID: pubkey[1:]}
 	for _, p := range srv.Protocols {
-		srv.ourHandshake.Caps = append(srv.ourHandshake.Caps, p.cap())
+		srv.ourHandshake.Caps = append(srv.ourHandshake.Caps, p.caps()...)
 	}
 	slices.SortFunc(srv.ourHandshake.Caps, Cap.Cmp)
+	srv.ourHandshake.setCompression(compressionHints(srv.Protocols))
 
 	// Create the local node.
 	db, err := enode.OpenDB(srv.NodeDatabase)
@@ -613,6 +666,7 @@ func (srv *Server) setupDialScheduler() {
 		netRestrict:    srv.NetRestrict,
 		dialer:         srv.Dialer,
 		clock:          srv.clock,
+		errorFeed:      &srv.dialErrFeed,
 	}
 	if srv.discv4 != nil {
 		config.resolver = srv.discv4
@@ -647,7 +701,15 @@ func (srv *Server) maxDialedConns() (limit int) {
 
 func (srv *Server) setupListening() error {
 	// Launch the listener.
-	listener, err := srv.listenFunc("tcp", srv.ListenAddr)
+	var (
+		listener net.Listener
+		err      error
+	)
+	if srv.ListenFunc != nil {
+		listener, err = srv.ListenFunc()
+	} else {
+		listener, err = srv.listenFunc("tcp", srv.ListenAddr)
+	}
 	if err != nil {
 		return err
 	}
@@ -689,6 +751,9 @@ func (srv *Server) setupUDPListening() (*net.UDPConn, error) {
 		return nil, err
 	}
 	laddr := conn.LocalAddr().(*net.UDPAddr)
+	if srv.DiscAddr != "" {
+		srv.DiscAddr = laddr.String()
+	}
 	srv.localnode.SetFallbackUDP(laddr.Port)
 	srv.log.Debug("UDP listener up", "addr", laddr)
 	if !laddr.IP.IsLoopback() && !laddr.IP.IsPrivate() {
@@ -859,7 +924,7 @@ func (srv *Server) addPeerChecks(peers map[enode.ID]*Peer, inboundCount int, c *
 // listenLoop runs in its own goroutine and accepts
 // inbound connections.
 func (srv *Server) listenLoop() {
-	srv.log.Debug("TCP listener up", "addr", srv.listener.Addr())
+	srv.log.Debug("RLPx listener up", "addr", srv.listener.Addr())
 
 	// The slots channel limits accepts of new connections.
 	tokens := defaultMaxPendingPeers
@@ -871,6 +936,12 @@ func (srv *Server) listenLoop() {
 		slots <- struct{}{}
 	}
 
+	// The handshakes channel limits the number of concurrent inbound handshakes.
+	var handshakes chan struct{}
+	if srv.MaxPendingHandshakes > 0 {
+		handshakes = make(chan struct{}, srv.MaxPendingHandshakes)
+	}
+
 	// Wait for slots to be returned on exit. This ensures all connection goroutines
 	// are down before listenLoop returns.
 	defer srv.loopWG.Done()
@@ -909,10 +980,22 @@ func (srv *Server) listenLoop() {
 		remoteIP := netutil.AddrAddr(fd.RemoteAddr())
 		if err := srv.checkInboundConn(remoteIP); err != nil {
 			srv.log.Debug("Rejected inbound connection", "addr", fd.RemoteAddr(), "err", err)
+			srv.dialErrFeed.send(&DialErrorEvent{Kind: ClassifyDialError(err), Addr: fd.RemoteAddr(), Inbound: true, Err: err})
 			fd.Close()
 			slots <- struct{}{}
 			continue
 		}
+		if handshakes != nil {
+			select {
+			case handshakes <- struct{}{}:
+			default:
+				srv.log.Debug("Rejected inbound connection", "addr", fd.RemoteAddr(), "err", "too many pending handshakes")
+				serveHandshakeRejected.Mark(1)
+				fd.Close()
+				slots <- struct{}{}
+				continue
+			}
+		}
 		if remoteIP.IsValid() {
 			fd = newMeteredConn(fd)
 			serveMeter.Mark(1)
@@ -920,11 +1003,23 @@ func (srv *Server) listenLoop() {
 		}
 		go func() {
 			srv.SetupConn(fd, inboundConn, nil)
+			if handshakes != nil {
+				<-handshakes
+			}
 			slots <- struct{}{}
 		}()
 	}
 }
 
+// newRLPX creates an RLPx transport using the configured handshake timeout.
+func (srv *Server) newRLPX(fd net.Conn, dialDest *ecdsa.PublicKey) transport {
+	t := newRLPX(fd, dialDest).(*rlpxTransport)
+	if srv.HandshakeTimeout > 0 {
+		t.handshakeTimeout = srv.HandshakeTimeout
+	}
+	return t
+}
+
 func (srv *Server) checkInboundConn(remoteIP netip.Addr) error {
 	if !remoteIP.IsValid() {
 		// This case happens for internal test connections without remote address.
@@ -932,13 +1027,13 @@ func (srv *Server) checkInboundConn(remoteIP netip.Addr) error {
 	}
 	// Reject connections that do not match NetRestrict.
 	if srv.NetRestrict != nil && !srv.NetRestrict.ContainsAddr(remoteIP) {
-		return errors.New("not in netrestrict list")
+		return errNetRestrict
 	}
 	// Reject Internet peers that try too often.
 	now := srv.clock.Now()
 	srv.inboundHistory.expire(now, nil)
 	if !netutil.AddrIsLAN(remoteIP) && srv.inboundHistory.contains(remoteIP.String()) {
-		return errors.New("too many attempts")
+		return errTooManyAttempts
 	}
 	srv.inboundHistory.add(remoteIP.String(), now.Add(inboundThrottleTime))
 	return nil
@@ -948,7 +1043,9 @@ func (srv *Server) checkInboundConn(remoteIP netip.Addr) error {
 // as a peer. It returns when the connection has been added as a peer
 // or the handshakes have failed.
 func (srv *Server) SetupConn(fd net.Conn, flags connFlag, dialDest *enode.Node) error {
-	c := &conn{fd: fd, flags: flags, cont: make(chan error)}
+	traffic := &countingConn{Conn: fd}
+	fd = traffic
+	c := &conn{fd: fd, traffic: traffic, flags: flags, cont: make(chan error)}
 	if dialDest == nil {
 		c.transport = srv.newTransport(fd, nil)
 	} else {
@@ -960,6 +1057,17 @@ func (srv *Server) SetupConn(fd net.Conn, flags connFlag, dialDest *enode.Node)
 		if !c.is(inboundConn) {
 			markDialError(err)
 		}
+		node := c.node
+		if node == nil {
+			node = dialDest
+		}
+		srv.dialErrFeed.send(&DialErrorEvent{
+			Kind:    ClassifyDialError(err),
+			Node:    node,
+			Addr:    fd.RemoteAddr(),
+			Inbound: c.is(inboundConn),
+			Err:     err,
+		})
 		c.close(err)
 	}
 	return err
@@ -988,7 +1096,8 @@ func (srv *Server) setupConn(c *conn, dialDest *enode.Node) error {
 	remotePubkey, err := c.doEncHandshake(srv.PrivateKey)
 	if err != nil {
 		srv.log.Trace("Failed RLPx handshake", "addr", c.fd.RemoteAddr(), "conn", c.flags, "err", err)
-		return fmt.Errorf("%w: %v", errEncHandshakeError, err)
+		markHandshakeTimeout(err)
+		return fmt.Errorf("%w: %w", errEncHandshakeError, err)
 	}
 	if dialDest != nil {
 		c.node = dialDest
@@ -1006,13 +1115,14 @@ func (srv *Server) setupConn(c *conn, dialDest *enode.Node) error {
 	phs, err := c.doProtoHandshake(srv.ourHandshake)
 	if err != nil {
 		clog.Trace("Failed p2p handshake", "err", err)
-		return fmt.Errorf("%w: %v", errProtoHandshakeError, err)
+		markHandshakeTimeout(err)
+		return fmt.Errorf("%w: %w", errProtoHandshakeError, err)
 	}
 	if id := c.node.ID(); !bytes.Equal(crypto.Keccak256(phs.ID), id[:]) {
 		clog.Trace("Wrong devp2p handshake identity", "phsid", hex.EncodeToString(phs.ID))
 		return DiscUnexpectedIdentity
 	}
-	c.caps, c.name = phs.Caps, phs.Name
+	c.caps, c.name, c.compression = phs.Caps, phs.Name, phs.compression()
 	err = srv.checkpoint(c, srv.checkpointAddPeer)
 	if err != nil {
 		clog.Trace("Rejected peer", "err", err)
@@ -1099,6 +1209,7 @@ type NodeInfo struct {
 		Listener  int `json:"listener"`  // TCP listening port for RLPx
 	} `json:"ports"`
 	ListenAddr string                 `json:"listenAddr"`
+	DiscAddr   string                 `json:"discAddr,omitempty"`
 	Protocols  map[string]interface{} `json:"protocols"`
 }
 
@@ -1112,6 +1223,7 @@ func (srv *Server) NodeInfo() *NodeInfo {
 		ID:         node.ID().String(),
 		IP:         node.IPAddr().String(),
 		ListenAddr: srv.ListenAddr,
+		DiscAddr:   srv.DiscAddr,
 		Protocols:  make(map[string]interface{}),
 	}
 	info.Ports.Discovery = node.UDP()
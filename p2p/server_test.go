@@ -125,6 +125,111 @@ func TestServerListen(t *testing.T) {
 	}
 }
 
+// countingListener counts accepted connections.
+type countingListener struct {
+	net.Listener
+	accepted chan net.Conn
+}
+
+func (l *countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		l.accepted <- conn
+	}
+	return conn, err
+}
+
+// This test checks that inbound connections are served from a custom listener.
+func TestServerListenFunc(t *testing.T) {
+	var (
+		connected = make(chan *Peer, 1)
+		remid     = &newkey().PublicKey
+		listener  *countingListener
+	)
+	srv := &Server{
+		Config: Config{
+			Name:        "test",
+			MaxPeers:    10,
+			NoDiscovery: true,
+			PrivateKey:  newkey(),
+			Logger:      testlog.Logger(t, log.LvlTrace),
+			ListenFunc: func() (net.Listener, error) {
+				l, err := net.Listen("tcp", "127.0.0.1:0")
+				if err != nil {
+					return nil, err
+				}
+				listener = &countingListener{l, make(chan net.Conn, 1)}
+				return listener, nil
+			},
+		},
+		newPeerHook: func(p *Peer) { connected <- p },
+		newTransport: func(fd net.Conn, dialDest *ecdsa.PublicKey) transport {
+			return newTestTransport(remid, fd, dialDest)
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start: %v", err)
+	}
+	defer srv.Stop()
+
+	if srv.ListenAddr != listener.Addr().String() {
+		t.Fatalf("wrong listen address %q, want %q", srv.ListenAddr, listener.Addr())
+	}
+	conn, err := net.DialTimeout("tcp", srv.ListenAddr, 5*time.Second)
+	if err != nil {
+		t.Fatalf("could not dial: %v", err)
+	}
+	defer conn.Close()
+
+	select {
+	case <-listener.accepted:
+	case <-time.After(1 * time.Second):
+		t.Fatal("custom listener did not accept")
+	}
+	select {
+	case peer := <-connected:
+		if peer.ID() != enode.PubkeyToIDV4(remid) {
+			t.Error("peer connected with wrong node id")
+		}
+	case <-time.After(1 * time.Second):
+		t.Error("server did not add peer within one second")
+	}
+}
+
+// This test checks that discovery can be bound to a different address than RLPx.
+func TestServerDiscAddr(t *testing.T) {
+	srv := &Server{
+		Config: Config{
+			PrivateKey:  newkey(),
+			NoDial:      true,
+			ListenAddr:  "127.0.0.1:0",
+			DiscAddr:    "127.0.0.1:0",
+			DiscoveryV4: true,
+			Logger:      testlog.Logger(t, log.LvlTrace),
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start: %v", err)
+	}
+	defer srv.Stop()
+
+	_, tcpPort, _ := net.SplitHostPort(srv.ListenAddr)
+	_, udpPort, _ := net.SplitHostPort(srv.DiscAddr)
+	if tcpPort == "0" || udpPort == "0" {
+		t.Fatalf("listening addresses not resolved: tcp %q, udp %q", srv.ListenAddr, srv.DiscAddr)
+	}
+	info := srv.NodeInfo()
+	if strconv.Itoa(info.Ports.Listener) != tcpPort {
+		t.Errorf("wrong listener port %d, want %s", info.Ports.Listener, tcpPort)
+	}
+	if strconv.Itoa(info.Ports.Discovery) != udpPort {
+		t.Errorf("wrong discovery port %d, want %s", info.Ports.Discovery, udpPort)
+	}
+	if info.DiscAddr != srv.DiscAddr {
+		t.Errorf("wrong discovery address %q, want %q", info.DiscAddr, srv.DiscAddr)
+	}
+}
+
 func TestServerDial(t *testing.T) {
 	// run a one-shot TCP server to handle the connection.
 	listener, err := net.Listen("tcp", "127.0.0.1:0")
@@ -579,6 +684,104 @@ func TestServerInboundThrottle(t *testing.T) {
 	}
 }
 
+func TestServerMaxPendingHandshakes(t *testing.T) {
+	const timeout = 5 * time.Second
+	newTransportCalled := make(chan struct{})
+	srv := &Server{
+		Config: Config{
+			PrivateKey:           newkey(),
+			ListenAddr:           "127.0.0.1:0",
+			MaxPeers:             10,
+			MaxPendingHandshakes: 1,
+			NoDial:               true,
+			NoDiscovery:          true,
+			Protocols:            []Protocol{discard},
+			Logger:               testlog.Logger(t, log.LvlTrace),
+		},
+		newTransport: func(fd net.Conn, dialDest *ecdsa.PublicKey) transport {
+			newTransportCalled <- struct{}{}
+			return newRLPX(fd, dialDest)
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatal("can't start: ", err)
+	}
+	defer srv.Stop()
+
+	// Dial the test server, but don't perform the handshake.
+	conn, err := net.DialTimeout("tcp", srv.ListenAddr, timeout)
+	if err != nil {
+		t.Fatalf("could not dial: %v", err)
+	}
+	defer conn.Close()
+	select {
+	case <-newTransportCalled:
+		// OK
+	case <-time.After(timeout):
+		t.Fatal("newTransport not called")
+	}
+
+	// Dial again. The handshake limit is reached, the connection should be closed.
+	conn2, err := net.DialTimeout("tcp", srv.ListenAddr, timeout)
+	if err != nil {
+		t.Fatalf("could not dial: %v", err)
+	}
+	defer conn2.Close()
+	connClosed := make(chan struct{}, 1)
+	go func() {
+		conn2.SetDeadline(time.Now().Add(timeout))
+		buf := make([]byte, 10)
+		if n, err := conn2.Read(buf); err != io.EOF || n != 0 {
+			t.Errorf("expected io.EOF and n == 0, got error %q and n == %d", err, n)
+		}
+		connClosed <- struct{}{}
+	}()
+	select {
+	case <-connClosed:
+		// OK
+	case <-newTransportCalled:
+		t.Error("newTransport called for connection over the limit")
+	case <-time.After(timeout):
+		t.Error("connection not closed within timeout")
+	}
+}
+
+func TestServerHandshakeTimeout(t *testing.T) {
+	srv := &Server{
+		Config: Config{
+			PrivateKey:       newkey(),
+			ListenAddr:       "127.0.0.1:0",
+			MaxPeers:         10,
+			HandshakeTimeout: 100 * time.Millisecond,
+			NoDial:           true,
+			NoDiscovery:      true,
+			Protocols:        []Protocol{discard},
+			Logger:           testlog.Logger(t, log.LvlTrace),
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatal("can't start: ", err)
+	}
+	defer srv.Stop()
+
+	// Dial the server and stay silent. The server should drop the connection
+	// once the handshake timeout expires, well before the default timeout.
+	conn, err := net.DialTimeout("tcp", srv.ListenAddr, time.Second)
+	if err != nil {
+		t.Fatalf("could not dial: %v", err)
+	}
+	defer conn.Close()
+
+	start := time.Now()
+	conn.SetDeadline(start.Add(defaultHandshakeTimeout))
+	if _, err := io.ReadAll(conn); err != nil {
+		t.Fatalf("connection not closed by server: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= defaultHandshakeTimeout/2 {
+		t.Fatalf("connection closed too late: %v", elapsed)
+	}
+}
+
 func listenFakeAddr(network, laddr string, remoteAddr net.Addr) (net.Listener, error) {
 	l, err := net.Listen(network, laddr)
 	if err == nil {
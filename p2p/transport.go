@@ -34,9 +34,9 @@ import (
 )
 
 const (
-	// total timeout for encryption handshake and protocol
+	// default total timeout for encryption handshake and protocol
 	// handshake in both directions.
-	handshakeTimeout = 5 * time.Second
+	defaultHandshakeTimeout = 5 * time.Second
 
 	// This is the timeout for sending the disconnect reason.
 	// This is shorter than the usual timeout because we don't want
@@ -50,10 +50,13 @@ type rlpxTransport struct {
 	rmu, wmu sync.Mutex
 	wbuf     bytes.Buffer
 	conn     *rlpx.Conn
+	snappy   bool // whether compression is enabled by default
+
+	handshakeTimeout time.Duration
 }
 
 func newRLPX(conn net.Conn, dialDest *ecdsa.PublicKey) transport {
-	return &rlpxTransport{conn: rlpx.NewConn(conn, dialDest)}
+	return &rlpxTransport{conn: rlpx.NewConn(conn, dialDest), handshakeTimeout: defaultHandshakeTimeout}
 }
 
 func (t *rlpxTransport) ReadMsg() (Msg, error) {
@@ -102,6 +105,11 @@ func (t *rlpxTransport) WriteMsg(msg Msg) error {
 		m := fmt.Sprintf("%s/%s/%d/%#02x", egressMeterName, msg.meterCap.Name, msg.meterCap.Version, msg.meterCode)
 		metrics.GetOrRegisterMeter(m, nil).Mark(int64(msg.meterSize))
 		metrics.GetOrRegisterMeter(m+"/packets", nil).Mark(1)
+
+		if msg.Size > 0 {
+			h := fmt.Sprintf("%s/%s/%d", compressionHistName, msg.meterCap.Name, msg.meterCap.Version)
+			metrics.GetOrRegisterHistogramLazy(h, nil, compressionSample).Update(int64(msg.meterSize) * 100 / int64(msg.Size))
+		}
 	}
 	return nil
 }
@@ -127,7 +135,7 @@ func (t *rlpxTransport) close(err error) {
 }
 
 func (t *rlpxTransport) doEncHandshake(prv *ecdsa.PrivateKey) (*ecdsa.PublicKey, error) {
-	t.conn.SetDeadline(time.Now().Add(handshakeTimeout))
+	t.conn.SetDeadline(time.Now().Add(t.handshakeTimeout))
 	return t.conn.Handshake(prv)
 }
 
@@ -146,11 +154,27 @@ func (t *rlpxTransport) doProtoHandshake(our *protoHandshake) (their *protoHands
 		return nil, fmt.Errorf("write error: %v", err)
 	}
 	// If the protocol version supports Snappy encoding, upgrade immediately
-	t.conn.SetSnappy(their.Version >= snappyProtocolVersion)
+	t.snappy = their.Version >= snappyProtocolVersion
+	t.conn.SetSnappy(t.snappy)
 
 	return their, nil
 }
 
+// setCompression applies the negotiated compression of the subprotocols. It must be
+// called before any subprotocol messages are exchanged.
+func (t *rlpxTransport) setCompression(overrides []compressionRange) {
+	if len(overrides) == 0 {
+		return
+	}
+	t.rmu.Lock()
+	t.wmu.Lock()
+	defer t.rmu.Unlock()
+	defer t.wmu.Unlock()
+
+	t.conn.SetSnappy(true)
+	t.conn.SetSnappyFilter(compressionFilter(t.snappy, overrides))
+}
+
 func readProtocolHandshake(rw MsgReader) (*protoHandshake, error) {
 	msg, err := rw.ReadMsg()
 	if err != nil {
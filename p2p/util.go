@@ -51,6 +51,27 @@ func (h expHeap) contains(item string) bool {
 	return false
 }
 
+// expiry returns the expiry time of an item.
+func (h expHeap) expiry(item string) (mclock.AbsTime, bool) {
+	for _, v := range h {
+		if v.item == item {
+			return v.exp, true
+		}
+	}
+	return 0, false
+}
+
+// remove removes an item. It returns false if the item is not present.
+func (h *expHeap) remove(item string) bool {
+	for i, v := range *h {
+		if v.item == item {
+			heap.Remove(h, i)
+			return true
+		}
+	}
+	return false
+}
+
 // expire removes items with expiry time before 'now'.
 func (h *expHeap) expire(now mclock.AbsTime, onExp func(string)) {
 	for h.Len() > 0 && h.nextExpiry() < now {
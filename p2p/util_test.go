@@ -54,3 +54,22 @@ func TestExpHeap(t *testing.T) {
 		t.Fatal("heap doesn't contain all live items")
 	}
 }
+
+func TestExpHeapRemove(t *testing.T) {
+	var h expHeap
+	for i, item := range []string{"a", "b", "c", "d"} {
+		h.add(item, mclock.AbsTime(i))
+	}
+	if exp, ok := h.expiry("c"); !ok || exp != 2 {
+		t.Fatalf("wrong expiry of c: %v %v", exp, ok)
+	}
+	if !h.remove("a") || h.remove("a") {
+		t.Fatal("wrong result removing a")
+	}
+	if _, ok := h.expiry("a"); ok || h.contains("a") {
+		t.Fatal("heap contains a after removing it")
+	}
+	if h.nextExpiry() != 1 {
+		t.Fatal("wrong nextExpiry")
+	}
+}
@@ -339,6 +339,12 @@ type ChainConfig struct {
 	// those cases.
 	EnableVerkleAtGenesis bool `json:"enableVerkleAtGenesis,omitempty"`
 
+	// MaxCodeSize and MaxInitCodeSize override the contract code size limit of EIP-170
+	// and the initcode size limit of EIP-3860. They are meant for L2 and experimental
+	// networks and can't be set on the public Ethereum networks.
+	MaxCodeSize     *uint64 `json:"maxCodeSize,omitempty"`     // nil = params.MaxCodeSize
+	MaxInitCodeSize *uint64 `json:"maxInitCodeSize,omitempty"` // nil = params.MaxInitCodeSize
+
 	// Various consensus engines
 	Ethash *EthashConfig `json:"ethash,omitempty"`
 	Clique *CliqueConfig `json:"clique,omitempty"`
@@ -650,6 +656,31 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 	return nil
 }
 
+// CheckSizeLimits verifies the code size limit overrides. They must not be zero, must
+// not exceed 4GB and may only be set on custom networks.
+func (c *ChainConfig) CheckSizeLimits() error {
+	if c.MaxCodeSize == nil && c.MaxInitCodeSize == nil {
+		return nil
+	}
+	if c.ChainID != nil {
+		if network, ok := NetworkNames[c.ChainID.String()]; ok {
+			return fmt.Errorf("code size limits can't be overridden on %s", network)
+		}
+	}
+	for _, limit := range []struct {
+		name string
+		val  *uint64
+	}{
+		{"maxCodeSize", c.MaxCodeSize},
+		{"maxInitCodeSize", c.MaxInitCodeSize},
+	} {
+		if limit.val != nil && (*limit.val == 0 || *limit.val > math.MaxUint32) {
+			return fmt.Errorf("invalid %s %d", limit.name, *limit.val)
+		}
+	}
+	return nil
+}
+
 func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int, headTimestamp uint64) *ConfigCompatError {
 	if isForkBlockIncompatible(c.HomesteadBlock, newcfg.HomesteadBlock, headNumber) {
 		return newBlockCompatError("Homestead fork block", c.HomesteadBlock, newcfg.HomesteadBlock)
@@ -718,6 +749,14 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkTimestampIncompatible(c.VerkleTime, newcfg.VerkleTime, headTimestamp) {
 		return newTimestampCompatError("Verkle fork timestamp", c.VerkleTime, newcfg.VerkleTime)
 	}
+	// The code size limits apply from genesis, so they can't change once blocks
+	// have been processed.
+	if headNumber.Sign() > 0 && !configTimestampEqual(c.MaxCodeSize, newcfg.MaxCodeSize) {
+		return newBlockCompatError("max code size", common.Big0, common.Big0)
+	}
+	if headNumber.Sign() > 0 && !configTimestampEqual(c.MaxInitCodeSize, newcfg.MaxInitCodeSize) {
+		return newBlockCompatError("max initcode size", common.Big0, common.Big0)
+	}
 	return nil
 }
 
@@ -731,6 +770,22 @@ func (c *ChainConfig) ElasticityMultiplier() uint64 {
 	return DefaultElasticityMultiplier
 }
 
+// CodeSizeLimit returns the maximum size of deployed contract code.
+func (c *ChainConfig) CodeSizeLimit() int {
+	if c.MaxCodeSize != nil {
+		return int(*c.MaxCodeSize)
+	}
+	return MaxCodeSize
+}
+
+// InitCodeSizeLimit returns the maximum size of contract creation code.
+func (c *ChainConfig) InitCodeSizeLimit() int {
+	if c.MaxInitCodeSize != nil {
+		return int(*c.MaxInitCodeSize)
+	}
+	return MaxInitCodeSize
+}
+
 // LatestFork returns the latest time-based fork that would be active for the given time.
 func (c *ChainConfig) LatestFork(time uint64) forks.Fork {
 	// Assume last non-time-based fork has passed.
@@ -110,6 +110,23 @@ func TestCheckCompatible(t *testing.T) {
 				RewindToTime: 9,
 			},
 		},
+		{
+			stored:    &ChainConfig{MaxCodeSize: newUint64(1 << 16)},
+			new:       &ChainConfig{},
+			headBlock: 0,
+			wantErr:   nil,
+		},
+		{
+			stored:    &ChainConfig{MaxInitCodeSize: newUint64(1 << 17)},
+			new:       &ChainConfig{MaxInitCodeSize: newUint64(1 << 18)},
+			headBlock: 10,
+			wantErr: &ConfigCompatError{
+				What:          "max initcode size",
+				StoredBlock:   big.NewInt(0),
+				NewBlock:      big.NewInt(0),
+				RewindToBlock: 0,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -139,6 +156,43 @@ func TestConfigRules(t *testing.T) {
 	}
 }
 
+func TestCheckSizeLimits(t *testing.T) {
+	custom := func(chainID int64, code, initcode *uint64) *ChainConfig {
+		cfg := *AllDevChainProtocolChanges
+		cfg.ChainID = big.NewInt(chainID)
+		cfg.MaxCodeSize, cfg.MaxInitCodeSize = code, initcode
+		return &cfg
+	}
+	tests := []struct {
+		config *ChainConfig
+		valid  bool
+	}{
+		{MainnetChainConfig, true},
+		{custom(1337, nil, nil), true},
+		{custom(1337, newUint64(1<<16), newUint64(1<<17)), true},
+		{custom(1337, nil, newUint64(1<<17)), true},
+		{custom(1, newUint64(1<<16), nil), false},
+		{custom(11155111, nil, newUint64(1<<17)), false},
+		{custom(17000, newUint64(1<<16), newUint64(1<<17)), false},
+		{custom(1337, newUint64(0), nil), false},
+		{custom(1337, nil, newUint64(math.MaxUint32+1)), false},
+	}
+	for i, test := range tests {
+		if err := test.config.CheckSizeLimits(); (err == nil) != test.valid {
+			t.Errorf("test %d: wrong result %v", i, err)
+		}
+	}
+
+	// The limits default to the protocol parameters.
+	if MainnetChainConfig.CodeSizeLimit() != MaxCodeSize || MainnetChainConfig.InitCodeSizeLimit() != MaxInitCodeSize {
+		t.Error("wrong default limits")
+	}
+	cfg := custom(1337, newUint64(1<<16), newUint64(1<<17))
+	if cfg.CodeSizeLimit() != 1<<16 || cfg.InitCodeSizeLimit() != 1<<17 {
+		t.Error("overrides not applied")
+	}
+}
+
 func TestTimestampCompatError(t *testing.T) {
 	require.Equal(t, new(ConfigCompatError).Error(), "")
 
@@ -23,10 +23,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"math/big"
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/rlp/internal/rlpstruct"
 	"github.com/holiman/uint256"
@@ -46,6 +48,7 @@ var (
 	ErrElemTooLarge     = errors.New("rlp: element is larger than containing list")
 	ErrValueTooLarge    = errors.New("rlp: value size exceeds available input length")
 	ErrMoreThanOneValue = errors.New("rlp: input contains more than one value")
+	ErrDepthLimit       = errors.New("rlp: list nesting exceeds depth limit")
 
 	// internal errors
 	errNotInList     = errors.New("rlp: call of ListEnd outside of any list")
@@ -151,6 +154,7 @@ var (
 	decoderInterface = reflect.TypeOf(new(Decoder)).Elem()
 	bigInt           = reflect.TypeOf(big.Int{})
 	u256Int          = reflect.TypeOf(uint256.Int{})
+	timeType         = reflect.TypeOf(time.Time{})
 )
 
 func makeDecoder(typ reflect.Type, tags rlpstruct.Tags) (dec decoder, err error) {
@@ -158,6 +162,8 @@ func makeDecoder(typ reflect.Type, tags rlpstruct.Tags) (dec decoder, err error)
 	switch {
 	case typ == rawValueType:
 		return decodeRawValue, nil
+	case tags.Unix && typ == timeType:
+		return decodeUnixTime, nil
 	case typ.AssignableTo(reflect.PointerTo(bigInt)):
 		return decodeBigInt, nil
 	case typ.AssignableTo(bigInt):
@@ -178,6 +184,8 @@ func makeDecoder(typ reflect.Type, tags rlpstruct.Tags) (dec decoder, err error)
 		return decodeString, nil
 	case kind == reflect.Slice || kind == reflect.Array:
 		return makeListDecoder(typ, tags)
+	case kind == reflect.Map:
+		return makeMapDecoder(typ)
 	case kind == reflect.Struct:
 		return makeStructDecoder(typ)
 	case kind == reflect.Interface:
@@ -196,6 +204,18 @@ func decodeRawValue(s *Stream, val reflect.Value) error {
 	return nil
 }
 
+func decodeUnixTime(s *Stream, val reflect.Value) error {
+	num, err := s.uint(64)
+	if err != nil {
+		return wrapStreamError(err, val.Type())
+	}
+	if num > math.MaxInt64 {
+		return &decodeError{msg: "negative unix time", typ: val.Type()}
+	}
+	val.Set(reflect.ValueOf(time.Unix(int64(num), 0).UTC()))
+	return nil
+}
+
 func decodeUint(s *Stream, val reflect.Value) error {
 	typ := val.Type()
 	num, err := s.uint(typ.Bits())
@@ -401,6 +421,75 @@ func decodeByteArray(s *Stream, val reflect.Value) error {
 	return nil
 }
 
+func makeMapDecoder(typ reflect.Type) (decoder, error) {
+	if typ.Key().Kind() == reflect.Interface {
+		// Decoded interface values are slices, which can't be map keys.
+		return nil, fmt.Errorf("rlp: map key type %v is not supported", typ.Key())
+	}
+	keyinfo := theTC.infoWhileGenerating(typ.Key(), rlpstruct.Tags{})
+	if keyinfo.decoderErr != nil {
+		return nil, keyinfo.decoderErr
+	}
+	valinfo := theTC.infoWhileGenerating(typ.Elem(), rlpstruct.Tags{})
+	if valinfo.decoderErr != nil {
+		return nil, valinfo.decoderErr
+	}
+
+	dec := func(s *Stream, val reflect.Value) error {
+		if _, err := s.List(); err != nil {
+			return wrapStreamError(err, typ)
+		}
+		var (
+			m       = reflect.MakeMap(typ)
+			keys    = new(Stream)
+			prevKey []byte
+		)
+		for i := 0; ; i++ {
+			ctx := fmt.Sprint("[", i, "]")
+			if _, err := s.List(); err == EOL {
+				break
+			} else if err != nil {
+				return addErrorContext(wrapStreamError(err, typ), ctx)
+			}
+			// Read the raw key first, its encoding determines the entry order.
+			rawKey, err := s.Raw()
+			if err == EOL {
+				return &decodeError{msg: "map entry has too few elements", typ: typ, ctx: []string{ctx}}
+			} else if err != nil {
+				return addErrorContext(wrapStreamError(err, typ), ctx)
+			}
+			if prevKey != nil {
+				switch bytes.Compare(prevKey, rawKey) {
+				case 0:
+					return &decodeError{msg: "duplicate map key", typ: typ, ctx: []string{ctx}}
+				case 1:
+					return &decodeError{msg: "map keys not in canonical order", typ: typ, ctx: []string{ctx}}
+				}
+			}
+			prevKey = rawKey
+
+			key := reflect.New(typ.Key()).Elem()
+			keys.Reset(bytes.NewReader(rawKey), uint64(len(rawKey)))
+			if err := keyinfo.decoder(keys, key); err != nil {
+				return addErrorContext(err, ctx+".key")
+			}
+			elem := reflect.New(typ.Elem()).Elem()
+			if err := valinfo.decoder(s, elem); err == EOL {
+				return &decodeError{msg: "map entry has too few elements", typ: typ, ctx: []string{ctx}}
+			} else if err != nil {
+				return addErrorContext(err, ctx+".value")
+			}
+			if err := s.ListEnd(); err != nil {
+				return addErrorContext(wrapStreamError(err, typ), ctx)
+			}
+			m.SetMapIndex(key, elem)
+		}
+		val.Set(m)
+		return s.ListEnd()
+	}
+	return dec, nil
+}
+
 func makeStructDecoder(typ reflect.Type) (decoder, error) {
 	fields, err := structFields(typ)
 	if err != nil {
@@ -594,8 +683,12 @@ type Stream struct {
 	kind      Kind     // kind of value ahead
 	byteval   byte     // value of single byte in type tag
 	limited   bool     // true if input limit is in effect
+	maxDepth  int      // maximum list nesting depth, zero means DefaultMaxDepth
 }
 
+// DefaultMaxDepth is the default maximum nesting depth of lists accepted by Stream.
+const DefaultMaxDepth = 1024
+
 // NewStream creates a new decoding stream reading from r.
 //
 // If r implements the ByteReader interface, Stream will
@@ -619,6 +712,13 @@ func NewStream(r io.Reader, inputLimit uint64) *Stream {
 	return s
 }
 
+// SetMaxDepth sets the maximum nesting depth of lists. Opening a list nested
+// deeper than n returns ErrDepthLimit. Passing zero or a negative value restores
+// the default of DefaultMaxDepth. The depth limit is kept when the stream is reset.
+func (s *Stream) SetMaxDepth(n int) {
+	s.maxDepth = max(n, 0)
+}
+
 // NewListStream creates a new stream that pretends to be positioned
 // at an encoded list of the given length.
 func NewListStream(r io.Reader, len uint64) *Stream {
@@ -801,6 +901,13 @@ func (s *Stream) List() (size uint64, err error) {
 	if kind != List {
 		return 0, ErrExpectedList
 	}
+	maxDepth := s.maxDepth
+	if maxDepth == 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	if len(s.stack) >= maxDepth {
+		return 0, ErrDepthLimit
+	}
 
 	// Remove size of inner list from outer list before pushing the new size
 	// onto the stack. This ensures that the remaining outer list size will
@@ -27,6 +27,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/holiman/uint256"
@@ -455,6 +456,14 @@ type optionalPtrFieldNil struct {
 	B *[3]byte `rlp:"optional,nil"`
 }
 
+type unixTimeField struct {
+	T time.Time `rlp:"unix"`
+}
+
+type invalidUnixTag struct {
+	T uint64 `rlp:"unix"`
+}
+
 type ignoredField struct {
 	A uint
 	B uint `rlp:"-"`
@@ -665,6 +674,33 @@ var decodeTests = []decodeTest{
 		error: `rlp: invalid struct tag "tail" for rlp.invalidTail2.B (field type is not slice)`,
 	},
 
+	// struct tag "unix"
+	{
+		input: "C1C0",
+		ptr:   new(unixTimeField),
+		error: "rlp: expected input string or byte for time.Time, decoding into (rlp.unixTimeField).T",
+	},
+	{
+		input: "C180",
+		ptr:   new(unixTimeField),
+		value: unixTimeField{T: time.Unix(0, 0).UTC()},
+	},
+	{
+		input: "C5845F5E1000",
+		ptr:   new(unixTimeField),
+		value: unixTimeField{T: time.Unix(1600000000, 0).UTC()},
+	},
+	{
+		input: "C9888000000000000000",
+		ptr:   new(unixTimeField),
+		error: "rlp: negative unix time for time.Time, decoding into (rlp.unixTimeField).T",
+	},
+	{
+		input: "C180",
+		ptr:   new(invalidUnixTag),
+		error: `rlp: invalid struct tag "unix" for rlp.invalidUnixTag.T (field type is not time.Time)`,
+	},
+
 	// struct tag "-"
 	{
 		input: "C20102",
@@ -868,6 +904,42 @@ var decodeTests = []decodeTest{
 		error: "rlp: type io.Reader is not RLP-serializable",
 	},
 
+	// maps
+	{input: "C0", ptr: new(map[string]uint64), value: map[string]uint64{}},
+	{input: "C6C26101C26202", ptr: new(map[string]uint64), value: map[string]uint64{"a": 1, "b": 2}},
+	{input: "C8C26202C482616101", ptr: new(map[string]uint), value: map[string]uint{"aa": 1, "b": 2}},
+	{input: "C9C578C3C27901C27AC0", ptr: new(map[string]map[string]uint), value: map[string]map[string]uint{"x": {"y": 1}, "z": {}}},
+	{
+		input: "C6C26202C26101",
+		ptr:   new(map[string]uint64),
+		error: "rlp: map keys not in canonical order for map[string]uint64, decoding into (map[string]uint64)[1]",
+	},
+	{
+		input: "C6C26101C26102",
+		ptr:   new(map[string]uint64),
+		error: "rlp: duplicate map key for map[string]uint64, decoding into (map[string]uint64)[1]",
+	},
+	{
+		input: "C2C161",
+		ptr:   new(map[string]uint64),
+		error: "rlp: map entry has too few elements for map[string]uint64, decoding into (map[string]uint64)[0]",
+	},
+	{
+		input: "C5C461010203",
+		ptr:   new(map[string]uint64),
+		error: "rlp: input list has too many elements for map[string]uint64, decoding into (map[string]uint64)[0]",
+	},
+	{
+		input: "C4C3810101",
+		ptr:   new(map[uint]uint),
+		error: "rlp: non-canonical size information for uint, decoding into (map[uint]uint)[0].key",
+	},
+	{
+		input: "C0",
+		ptr:   new(map[interface{}]uint),
+		error: "rlp: map key type interface {} is not supported",
+	},
+
 	// fuzzer crashes
 	{
 		input: "c330f9c030f93030ce3030303030303030bd303030303030",
@@ -904,12 +976,99 @@ func runTests(t *testing.T, decode func([]byte, interface{}) error) {
 	}
 }
 
+// nestedLists returns the encoding of depth nested empty lists.
+func nestedLists(depth int) []byte {
+	// Compute the content size of each list from the inside out.
+	sizes := make([]uint64, depth)
+	for i := 1; i < depth; i++ {
+		sizes[i] = uint64(headsize(sizes[i-1])) + sizes[i-1]
+	}
+	var out []byte
+	for i := depth - 1; i >= 0; i-- {
+		var head [9]byte
+		n := puthead(head[:], 0xC0, 0xF7, sizes[i])
+		out = append(out, head[:n]...)
+	}
+	return out
+}
+
+func TestStreamDepthLimit(t *testing.T) {
+	// Decoding deeply nested lists must fail instead of exhausting the stack.
+	input := nestedLists(100000)
+	var v interface{}
+	if err := DecodeBytes(input, &v); err != ErrDepthLimit {
+		t.Fatalf("wrong error decoding 100000 nested lists: %v", err)
+	}
+	if err := DecodeBytes(nestedLists(DefaultMaxDepth), &v); err != nil {
+		t.Fatalf("can't decode %d nested lists: %v", DefaultMaxDepth, err)
+	}
+
+	// The limit is checked when opening a list.
+	s := NewStream(bytes.NewReader(input), 0)
+	s.SetMaxDepth(10)
+	for i := 0; i < 10; i++ {
+		if _, err := s.List(); err != nil {
+			t.Fatalf("List %d: unexpected error: %v", i, err)
+		}
+	}
+	if _, err := s.List(); err != ErrDepthLimit {
+		t.Fatalf("wrong error for list exceeding limit: %v", err)
+	}
+
+	// The limit is kept across resets, and zero restores the default.
+	s.Reset(bytes.NewReader(nestedLists(11)), 0)
+	if err := s.Decode(&v); err != ErrDepthLimit {
+		t.Fatalf("wrong error after reset: %v", err)
+	}
+	s.SetMaxDepth(0)
+	s.Reset(bytes.NewReader(nestedLists(11)), 0)
+	if err := s.Decode(&v); err != nil {
+		t.Fatalf("unexpected error with default limit: %v", err)
+	}
+}
+
 func TestDecodeWithByteReader(t *testing.T) {
 	runTests(t, func(input []byte, into interface{}) error {
 		return Decode(bytes.NewReader(input), into)
 	})
 }
 
+func TestMapRoundTrip(t *testing.T) {
+	type config struct {
+		Limits  map[string]uint64
+		Nested  map[uint64]map[string][]byte
+		Empty   map[string]uint64
+		Version uint
+	}
+	in := config{
+		Limits: map[string]uint64{"gas": 30000000, "blobs": 6, "": 1},
+		Nested: map[uint64]map[string][]byte{
+			0:    {},
+			1:    {"a": {1}, "b": nil},
+			1000: {"c": {2, 3}},
+		},
+		Empty:   map[string]uint64{},
+		Version: 2,
+	}
+	enc, err := EncodeToBytes(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out config
+	if err := DecodeBytes(enc, &out); err != nil {
+		t.Fatal(err)
+	}
+	// Nil byte slices decode as empty ones.
+	in.Nested[1]["b"] = []byte{}
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("round-trip mismatch:\nhave %+v\nwant %+v", out, in)
+	}
+	reenc, _ := EncodeToBytes(&out)
+	if !bytes.Equal(enc, reenc) {
+		t.Fatalf("re-encoding mismatch:\nhave %x\nwant %x", reenc, enc)
+	}
+}
+
 func testDecodeWithEncReader(t *testing.T, n int) {
 	s := strings.Repeat("0", n)
 	_, r, _ := EncodeToReader(s)
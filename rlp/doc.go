@@ -55,7 +55,11 @@ Boolean values are encoded as the unsigned integers zero (false) and one (true).
 
 An interface value encodes as the value contained in the interface.
 
-Floating point numbers, maps, channels and functions are not supported.
+Maps are encoded as an RLP list of two-element lists, each containing an encoded key and
+its value. To keep the encoding canonical, the entries are sorted by the bytes of their
+encoded keys. A nil map encodes as an empty RLP list.
+
+Floating point numbers, channels and functions are not supported.
 
 # Decoding Rules
 
@@ -86,6 +90,11 @@ are interpreted as a big endian representation of the integer. If the RLP string
 than the bit size of the type, decoding will return an error. Decode also supports
 *big.Int. There is no size limit for big integers.
 
+To decode into a map, the input must be a list of two-element lists as produced by the
+encoder. The entries must be sorted by the bytes of their encoded keys, and keys must not
+repeat. Decoding always allocates a new map. Map types with interface keys cannot be
+decoded into.
+
 To decode into a boolean, the input must contain an unsigned integer of value zero (false)
 or one (true).
 
@@ -95,7 +104,7 @@ To decode into an interface value, one of these types is stored in the value:
 	[]byte, for RLP strings
 
 Non-empty interface types are not supported when decoding.
-Signed integers, floating point numbers, maps, channels and functions cannot be decoded into.
+Signed integers, floating point numbers, channels and functions cannot be decoded into.
 
 # Struct Tags
 
@@ -154,5 +163,14 @@ list.
 The choice of null value can be made explicit with the "nilList" and "nilString" struct
 tags. Using these tags encodes/decodes a Go nil pointer value as the empty RLP value kind
 defined by the tag.
+
+The "unix" tag applies to fields of type time.Time only. The field is encoded as an
+unsigned integer holding the unix time in seconds, discarding any sub-second precision.
+Times before the unix epoch cannot be encoded. Decoded values are in UTC. Without this tag,
+time.Time values are not supported.
+
+	type StructWithTimestamp struct {
+	    Time time.Time `rlp:"unix"`
+	}
 */
 package rlp
@@ -17,11 +17,14 @@
 package rlp
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"math/big"
 	"reflect"
+	"slices"
+	"time"
 
 	"github.com/ethereum/go-ethereum/rlp/internal/rlpstruct"
 	"github.com/holiman/uint256"
@@ -39,6 +42,10 @@ var (
 
 var ErrNegativeBigInt = errors.New("rlp: cannot encode negative big.Int")
 
+// ErrNegativeUnixTime is returned when encoding a time.Time before the unix epoch
+// with the "unix" struct tag.
+var ErrNegativeUnixTime = errors.New("rlp: cannot encode negative unix time")
+
 // Encoder is implemented by types that require custom
 // encoding rules or want to encode private fields.
 type Encoder interface {
@@ -141,6 +148,8 @@ func makeWriter(typ reflect.Type, ts rlpstruct.Tags) (writer, error) {
 	switch {
 	case typ == rawValueType:
 		return writeRawValue, nil
+	case ts.Unix && typ == timeType:
+		return writeUnixTime, nil
 	case typ.AssignableTo(reflect.PointerTo(bigInt)):
 		return writeBigIntPtr, nil
 	case typ.AssignableTo(bigInt):
@@ -165,6 +174,8 @@ func makeWriter(typ reflect.Type, ts rlpstruct.Tags) (writer, error) {
 		return makeByteArrayWriter(typ), nil
 	case kind == reflect.Slice || kind == reflect.Array:
 		return makeSliceWriter(typ, ts)
+	case kind == reflect.Map:
+		return makeMapWriter(typ)
 	case kind == reflect.Struct:
 		return makeStructWriter(typ)
 	case kind == reflect.Interface:
@@ -179,6 +190,15 @@ func writeRawValue(val reflect.Value, w *encBuffer) error {
 	return nil
 }
 
+func writeUnixTime(val reflect.Value, w *encBuffer) error {
+	t := val.Interface().(time.Time)
+	if t.Unix() < 0 {
+		return ErrNegativeUnixTime
+	}
+	w.writeUint64(uint64(t.Unix()))
+	return nil
+}
+
 func writeUint(val reflect.Value, w *encBuffer) error {
 	w.writeUint64(val.Uint())
 	return nil
@@ -339,6 +359,60 @@ func makeSliceWriter(typ reflect.Type, ts rlpstruct.Tags) (writer, error) {
 	return wfn, nil
 }
 
+func makeMapWriter(typ reflect.Type) (writer, error) {
+	keyinfo := theTC.infoWhileGenerating(typ.Key(), rlpstruct.Tags{})
+	if keyinfo.writerErr != nil {
+		return nil, keyinfo.writerErr
+	}
+	valinfo := theTC.infoWhileGenerating(typ.Elem(), rlpstruct.Tags{})
+	if valinfo.writerErr != nil {
+		return nil, valinfo.writerErr
+	}
+
+	type entry struct {
+		key []byte // encoded key
+		val reflect.Value
+	}
+	writer := func(val reflect.Value, w *encBuffer) error {
+		if val.Len() == 0 {
+			w.str = append(w.str, 0xC0)
+			return nil
+		}
+		// Encode the keys to determine the order of entries.
+		keybuf := getEncBuffer()
+		defer encBufferPool.Put(keybuf)
+
+		entries := make([]entry, 0, val.Len())
+		iter := val.MapRange()
+		for iter.Next() {
+			keybuf.reset()
+			if err := keyinfo.writer(iter.Key(), keybuf); err != nil {
+				return err
+			}
+			entries = append(entries, entry{keybuf.makeBytes(), iter.Value()})
+		}
+		slices.SortFunc(entries, func(a, b entry) int {
+			return bytes.Compare(a.key, b.key)
+		})
+
+		listOffset := w.list()
+		for i, e := range entries {
+			if i > 0 && bytes.Equal(e.key, entries[i-1].key) {
+				return fmt.Errorf("rlp: duplicate key encoding %x in %v", e.key, typ)
+			}
+			entryOffset := w.list()
+			w.str = append(w.str, e.key...)
+			if err := valinfo.writer(e.val, w); err != nil {
+				return err
+			}
+			w.listEnd(entryOffset)
+		}
+		w.listEnd(listOffset)
+		return nil
+	}
+	return writer, nil
+}
+
 func makeStructWriter(typ reflect.Type) (writer, error) {
 	fields, err := structFields(typ)
 	if err != nil {
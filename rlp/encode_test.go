@@ -25,6 +25,7 @@ import (
 	"runtime"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/holiman/uint256"
@@ -299,9 +300,25 @@ var encTests = []encTest{
 	{val: &recstruct{5, &recstruct{4, &recstruct{3, nil}}}, output: "C605C404C203C0"},
 	{val: &intField{X: 3}, error: "rlp: type int is not RLP-serializable (struct field rlp.intField.X)"},
 
+	// maps
+	{val: map[string]uint64(nil), output: "C0"},
+	{val: map[string]uint64{}, output: "C0"},
+	{val: map[string]uint64{"b": 2, "a": 1}, output: "C6C26101C26202"},
+	{val: map[string]uint{"aa": 1, "b": 2}, output: "C8C26202C482616101"},
+	{val: map[uint]string{256: "x", 1: "y", 127: "z"}, output: "CBC20179C27F7AC482010078"},
+	{val: map[string]map[string]uint{"x": {"y": 1}, "z": nil}, output: "C9C578C3C27901C27AC0"},
+	{val: map[interface{}]uint{uint(1): 1, uint8(1): 2}, error: "rlp: duplicate key encoding 01 in map[interface {}]uint"},
+	{val: map[string]int{"a": 1}, error: "rlp: type int is not RLP-serializable"},
+
 	// struct tag "-"
 	{val: &ignoredField{A: 1, B: 2, C: 3}, output: "C20103"},
 
+	// struct tag "unix"
+	{val: &unixTimeField{T: time.Unix(0, 0)}, output: "C180"},
+	{val: &unixTimeField{T: time.Unix(1600000000, 999)}, output: "C5845F5E1000"},
+	{val: &unixTimeField{T: time.Unix(-1, 0)}, error: "rlp: cannot encode negative unix time"},
+	{val: &invalidUnixTag{}, error: `rlp: invalid struct tag "unix" for rlp.invalidUnixTag.T (field type is not time.Time)`},
+
 	// struct tag "tail"
 	{val: &tailRaw{A: 1, Tail: []RawValue{unhex("02"), unhex("03")}}, output: "C3010203"},
 	{val: &tailRaw{A: 1, Tail: []RawValue{unhex("02")}}, output: "C20102"},
@@ -449,6 +466,94 @@ func TestEncodeAppendToBytes(t *testing.T) {
 	})
 }
 
+func TestStreamEncoder(t *testing.T) {
+	runEncTests(t, func(val interface{}) ([]byte, error) {
+		b := new(bytes.Buffer)
+		err := NewEncoder(b).Encode(val)
+		return b.Bytes(), err
+	})
+}
+
+func TestStreamEncoderList(t *testing.T) {
+	b := new(bytes.Buffer)
+	e := NewEncoder(b)
+	e.ListStart()
+	e.Encode(uint(1))
+	e.ListStart()
+	e.Encode([]uint{2, 3})
+	if err := e.ListEnd(); err != nil {
+		t.Fatal(err)
+	}
+	if b.Len() != 0 {
+		t.Fatalf("output written before list end: %x", b.Bytes())
+	}
+	e.Encode("foo")
+	if err := e.ListEnd(); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Encode([]string{"bar"}); err != nil {
+		t.Fatal(err)
+	}
+	want := unhex("C901C3C2020383666F6FC483626172")
+	if !bytes.Equal(b.Bytes(), want) {
+		t.Fatalf("wrong output %X, want %X", b.Bytes(), want)
+	}
+	if err := e.ListEnd(); err != errNoOpenList {
+		t.Fatalf("wrong error for unbalanced ListEnd: %v", err)
+	}
+}
+
+// countingWriter discards its input, keeping track of the number of bytes written.
+type countingWriter struct {
+	n int
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	w.n += len(b)
+	return len(b), nil
+}
+
+func TestStreamEncoderLargeList(t *testing.T) {
+	type item struct {
+		A uint64
+		B []byte
+		C []uint
+	}
+	list := make([]item, 100000)
+	for i := range list {
+		list[i] = item{A: uint64(i), B: make([]byte, i%100), C: make([]uint, i%5)}
+	}
+	want, err := EncodeToBytes(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := new(bytes.Buffer)
+	if err := NewEncoder(b).Encode(&list); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b.Bytes(), want) {
+		t.Fatal("output mismatch")
+	}
+
+	// Encoding must not allocate memory proportional to the list size.
+	var (
+		w             = new(countingWriter)
+		e             = NewEncoder(w)
+		before, after runtime.MemStats
+	)
+	runtime.ReadMemStats(&before)
+	if err := e.Encode(list); err != nil {
+		t.Fatal(err)
+	}
+	runtime.ReadMemStats(&after)
+	if w.n != len(want) {
+		t.Fatalf("wrong output size %d, want %d", w.n, len(want))
+	}
+	if alloc := after.TotalAlloc - before.TotalAlloc; alloc > uint64(len(want)/100) {
+		t.Fatalf("encoder allocated %d bytes for %d bytes of output", alloc, len(want))
+	}
+}
+
 func TestEncodeToReader(t *testing.T) {
 	runEncTests(t, func(val interface{}) ([]byte, error) {
 		_, r, err := EncodeToReader(val)
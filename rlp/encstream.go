@@ -0,0 +1,135 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rlp
+
+import (
+	"errors"
+	"io"
+	"reflect"
+)
+
+var errNoOpenList = errors.New("rlp: ListEnd called without open list")
+
+// StreamEncoder writes RLP-encoded values to an io.Writer. Unlike Encode, which
+// buffers the whole encoding before writing it, StreamEncoder writes top-level
+// lists one element at a time. The list header is computed in a first pass over
+// the elements, so every element is encoded twice, and memory usage is bounded by
+// the encoded size of the largest element rather than the whole list.
+//
+// Lists opened explicitly using ListStart are not streamed. Their content, including
+// any nested lists, is buffered in memory until the outermost open list is closed
+// by ListEnd, because the list header can only be written once the size of the
+// content is known. Large lists should be passed to Encode as a slice instead.
+//
+// The output is identical to the output of Encode for the same values.
+type StreamEncoder struct {
+	w     io.Writer
+	buf   *encBuffer // content of explicitly opened lists
+	lists []int      // list header indexes in buf
+	elem  *encBuffer // scratch buffer for list elements
+}
+
+// NewEncoder creates an encoder writing to w.
+func NewEncoder(w io.Writer) *StreamEncoder {
+	return &StreamEncoder{w: w, buf: new(encBuffer), elem: new(encBuffer)}
+}
+
+// Encode writes the RLP encoding of val. If val is a slice or array, or a pointer to
+// one, and no list is open, the elements are encoded and written one at a time.
+// Inside an open list, val is added to the list buffer.
+func (e *StreamEncoder) Encode(val interface{}) error {
+	if len(e.lists) > 0 {
+		return e.buf.encode(val)
+	}
+	rval := reflect.ValueOf(val)
+	if rval.Kind() == reflect.Ptr && !rval.IsNil() && isStreamable(rval.Type().Elem()) {
+		rval = rval.Elem()
+	}
+	if !rval.IsValid() || !isStreamable(rval.Type()) || rval.Len() == 0 {
+		e.buf.reset()
+		if err := e.buf.encode(val); err != nil {
+			return err
+		}
+		return e.buf.writeTo(e.w)
+	}
+	return e.encodeList(rval)
+}
+
+// encodeList writes the list val, encoding each element twice: once to compute the
+// size of the list content, and once more to write it.
+func (e *StreamEncoder) encodeList(val reflect.Value) error {
+	writer, err := cachedWriter(val.Type().Elem())
+	if err != nil {
+		return err
+	}
+	var size uint64
+	for i := 0; i < val.Len(); i++ {
+		e.elem.reset()
+		if err := writer(val.Index(i), e.elem); err != nil {
+			return err
+		}
+		size += uint64(e.elem.size())
+	}
+	n := puthead(e.elem.sizebuf[:], 0xC0, 0xF7, size)
+	if _, err := e.w.Write(e.elem.sizebuf[:n]); err != nil {
+		return err
+	}
+	for i := 0; i < val.Len(); i++ {
+		e.elem.reset()
+		if err := writer(val.Index(i), e.elem); err != nil {
+			return err
+		}
+		if err := e.elem.writeTo(e.w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListStart opens a list. All values encoded until the matching call to ListEnd
+// become elements of the list. The list is buffered until the outermost open list
+// is closed.
+func (e *StreamEncoder) ListStart() {
+	if len(e.lists) == 0 {
+		e.buf.reset()
+	}
+	e.lists = append(e.lists, e.buf.list())
+}
+
+// ListEnd closes the innermost open list. When the outermost list is closed, its
+// encoding is written.
+func (e *StreamEncoder) ListEnd() error {
+	if len(e.lists) == 0 {
+		return errNoOpenList
+	}
+	e.buf.listEnd(e.lists[len(e.lists)-1])
+	e.lists = e.lists[:len(e.lists)-1]
+	if len(e.lists) > 0 {
+		return nil
+	}
+	return e.buf.writeTo(e.w)
+}
+
+// isStreamable reports whether values of typ are encoded as a plain RLP list of
+// their elements.
+func isStreamable(typ reflect.Type) bool {
+	kind := typ.Kind()
+	if kind != reflect.Slice && kind != reflect.Array {
+		return false
+	}
+	return !isByte(typ.Elem()) && typ != u256Int && !reflect.PointerTo(typ).Implements(encoderInterface)
+}
@@ -79,6 +79,10 @@ type Tags struct {
 
 	// rlp:"-" ignores fields.
 	Ignored bool
+
+	// rlp:"unix" encodes a time.Time field as an integer holding its unix time
+	// in seconds.
+	Unix bool
 }
 
 // TagError is raised for invalid struct tags.
@@ -183,6 +187,11 @@ func parseTag(field Field, lastPublic int) (Tags, error) {
 			if field.Type.Kind != reflect.Slice {
 				return ts, TagError{Field: name, Tag: t, Err: "field type is not slice"}
 			}
+		case "unix":
+			ts.Unix = true
+			if field.Type.Name != "time.Time" {
+				return ts, TagError{Field: name, Tag: t, Err: "field type is not time.Time"}
+			}
 		default:
 			return ts, TagError{Field: name, Tag: t, Err: "unknown tag"}
 		}
@@ -540,6 +540,9 @@ func checkUnsupportedTags(field string, tag rlpstruct.Tags) error {
 	if tag.Tail {
 		return fmt.Errorf(`field %s has unsupported struct tag "tail"`, field)
 	}
+	if tag.Unix {
+		return fmt.Errorf(`field %s has unsupported struct tag "unix"`, field)
+	}
 	return nil
 }
 
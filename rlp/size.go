@@ -0,0 +1,252 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rlp
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rlp/internal/rlpstruct"
+	"github.com/holiman/uint256"
+)
+
+// Size returns the length of the RLP encoding of val, without creating the encoding.
+// The result is equal to the length of the output of EncodeToBytes, and Size returns
+// an error if and only if EncodeToBytes does.
+//
+// Values implementing the Encoder interface, as well as maps, are encoded into a
+// temporary buffer to determine their size.
+func Size(val interface{}) (uint64, error) {
+	rval := reflect.ValueOf(val)
+	info := theTC.info(rval.Type())
+	if info.writerErr != nil {
+		return 0, info.writerErr
+	}
+	return info.sizer(rval)
+}
+
+// makeSizer creates a sizer function for the given type. It must be kept in sync
+// with makeWriter, and is only called for types that have a writer.
+func makeSizer(typ reflect.Type, ts rlpstruct.Tags) sizer {
+	kind := typ.Kind()
+	switch {
+	case typ == rawValueType:
+		return sizeRawValue
+	case ts.Unix && typ == timeType:
+		return sizeUnixTime
+	case typ.AssignableTo(reflect.PointerTo(bigInt)):
+		return sizeBigIntPtr
+	case typ.AssignableTo(bigInt):
+		return sizeBigIntNoPtr
+	case typ == reflect.PointerTo(u256Int):
+		return sizeU256IntPtr
+	case typ == u256Int:
+		return sizeU256IntNoPtr
+	case kind == reflect.Ptr:
+		return makePtrSizer(typ)
+	case reflect.PointerTo(typ).Implements(encoderInterface):
+		return makeBufferedSizer(typ)
+	case isUint(kind):
+		return sizeUint
+	case kind == reflect.Bool:
+		return sizeBool
+	case kind == reflect.String:
+		return sizeString
+	case kind == reflect.Slice && isByte(typ.Elem()):
+		return sizeBytes
+	case kind == reflect.Array && isByte(typ.Elem()):
+		return makeByteArraySizer(typ)
+	case kind == reflect.Slice || kind == reflect.Array:
+		return makeSliceSizer(typ, ts)
+	case kind == reflect.Map:
+		return makeBufferedSizer(typ)
+	case kind == reflect.Struct:
+		return makeStructSizer(typ)
+	case kind == reflect.Interface:
+		return sizeInterface
+	default:
+		panic(fmt.Sprintf("rlp: no sizer for type %v", typ))
+	}
+}
+
+func sizeRawValue(val reflect.Value) (uint64, error) {
+	return uint64(val.Len()), nil
+}
+
+func sizeUnixTime(val reflect.Value) (uint64, error) {
+	t := val.Interface().(time.Time)
+	if t.Unix() < 0 {
+		return 0, ErrNegativeUnixTime
+	}
+	return uint64(IntSize(uint64(t.Unix()))), nil
+}
+
+func sizeBigIntPtr(val reflect.Value) (uint64, error) {
+	ptr := val.Interface().(*big.Int)
+	if ptr == nil {
+		return 1, nil
+	}
+	if ptr.Sign() == -1 {
+		return 0, ErrNegativeBigInt
+	}
+	return bigIntSize(ptr.BitLen(), ptr.Uint64()), nil
+}
+
+func sizeBigIntNoPtr(val reflect.Value) (uint64, error) {
+	i := val.Interface().(big.Int)
+	if i.Sign() == -1 {
+		return 0, ErrNegativeBigInt
+	}
+	return bigIntSize(i.BitLen(), i.Uint64()), nil
+}
+
+func sizeU256IntPtr(val reflect.Value) (uint64, error) {
+	ptr := val.Interface().(*uint256.Int)
+	if ptr == nil {
+		return 1, nil
+	}
+	return bigIntSize(ptr.BitLen(), ptr.Uint64()), nil
+}
+
+func sizeU256IntNoPtr(val reflect.Value) (uint64, error) {
+	i := val.Interface().(uint256.Int)
+	return bigIntSize(i.BitLen(), i.Uint64()), nil
+}
+
+// bigIntSize returns the encoded size of an integer with the given bit length.
+// The low 64 bits of the integer are only used if it fits into a uint64.
+func bigIntSize(bitlen int, low uint64) uint64 {
+	if bitlen <= 64 {
+		return uint64(IntSize(low))
+	}
+	length := uint64((bitlen + 7) / 8)
+	return uint64(headsize(length)) + length
+}
+
+func sizeUint(val reflect.Value) (uint64, error) {
+	return uint64(IntSize(val.Uint())), nil
+}
+
+func sizeBool(val reflect.Value) (uint64, error) {
+	return 1, nil
+}
+
+func sizeString(val reflect.Value) (uint64, error) {
+	return StringSize(val.String()), nil
+}
+
+func sizeBytes(val reflect.Value) (uint64, error) {
+	return BytesSize(val.Bytes()), nil
+}
+
+func makeByteArraySizer(typ reflect.Type) sizer {
+	switch length := uint64(typ.Len()); length {
+	case 0:
+		return func(val reflect.Value) (uint64, error) { return 1, nil }
+	case 1:
+		return func(val reflect.Value) (uint64, error) {
+			if val.Index(0).Uint() <= 0x7f {
+				return 1, nil
+			}
+			return 2, nil
+		}
+	default:
+		return func(val reflect.Value) (uint64, error) {
+			return uint64(headsize(length)) + length, nil
+		}
+	}
+}
+
+func makeSliceSizer(typ reflect.Type, ts rlpstruct.Tags) sizer {
+	etypeinfo := theTC.infoWhileGenerating(typ.Elem(), rlpstruct.Tags{})
+	return func(val reflect.Value) (uint64, error) {
+		var size uint64
+		for i := 0; i < val.Len(); i++ {
+			esize, err := etypeinfo.sizer(val.Index(i))
+			if err != nil {
+				return 0, err
+			}
+			size += esize
+		}
+		if ts.Tail {
+			// Struct tail slices have no list header.
+			return size, nil
+		}
+		return ListSize(size), nil
+	}
+}
+
+// makeBufferedSizer creates a sizer that encodes the value into a temporary buffer.
+// This is used for maps, where entries with equal key encodings are an error, and
+// for values implementing Encoder.
+func makeBufferedSizer(typ reflect.Type) sizer {
+	info := theTC.infoWhileGenerating(typ, rlpstruct.Tags{})
+	return func(val reflect.Value) (uint64, error) {
+		buf := getEncBuffer()
+		defer encBufferPool.Put(buf)
+		if err := info.writer(val, buf); err != nil {
+			return 0, err
+		}
+		return uint64(buf.size()), nil
+	}
+}
+
+func makeStructSizer(typ reflect.Type) sizer {
+	fields, _ := structFields(typ)
+	firstOptionalField := firstOptionalField(fields)
+	return func(val reflect.Value) (uint64, error) {
+		lastField := len(fields) - 1
+		for ; lastField >= firstOptionalField; lastField-- {
+			if !val.Field(fields[lastField].index).IsZero() {
+				break
+			}
+		}
+		var size uint64
+		for i := 0; i <= lastField; i++ {
+			fsize, err := fields[i].info.sizer(val.Field(fields[i].index))
+			if err != nil {
+				return 0, err
+			}
+			size += fsize
+		}
+		return ListSize(size), nil
+	}
+}
+
+func makePtrSizer(typ reflect.Type) sizer {
+	etypeinfo := theTC.infoWhileGenerating(typ.Elem(), rlpstruct.Tags{})
+	return func(val reflect.Value) (uint64, error) {
+		if ev := val.Elem(); ev.IsValid() {
+			return etypeinfo.sizer(ev)
+		}
+		return 1, nil
+	}
+}
+
+func sizeInterface(val reflect.Value) (uint64, error) {
+	if val.IsNil() {
+		return 1, nil
+	}
+	eval := val.Elem()
+	info := theTC.info(eval.Type())
+	if info.writerErr != nil {
+		return 0, info.writerErr
+	}
+	return info.sizer(eval)
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rlp
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	fuzz "github.com/google/gofuzz"
+	"github.com/holiman/uint256"
+)
+
+func TestSize(t *testing.T) {
+	for i, test := range encTests {
+		size, err := Size(test.val)
+		if test.error != "" {
+			if fmt.Sprint(err) != test.error {
+				t.Errorf("test %d: error mismatch\ngot   %v\nwant  %v\nvalue %#v", i, err, test.error, test.val)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v\nvalue %#v", i, err, test.val)
+			continue
+		}
+		if want := uint64(len(unhex(test.output))); size != want {
+			t.Errorf("test %d: wrong size %d, want %d\nvalue %#v", i, size, want, test.val)
+		}
+	}
+}
+
+type sizeFuzzStruct struct {
+	A uint64
+	B string
+	C []byte
+	D [3]byte
+	E [1]byte
+	F *big.Int
+	G big.Int
+	H *uint256.Int
+	I []sizeFuzzTail
+	J map[string]uint32
+	K bool
+	L *[]uint16
+	M *sizeFuzzStruct
+	N []interface{}
+	O *uint  `rlp:"nil"`
+	P uint   `rlp:"optional"`
+	Q []byte `rlp:"optional"`
+}
+
+type sizeFuzzTail struct {
+	A uint8
+	B *[2]byte
+	C []string `rlp:"tail"`
+}
+
+func FuzzSize(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte("some input to generate a struct from"))
+	f.Fuzz(func(t *testing.T, input []byte) {
+		var (
+			fuzzer = fuzz.NewFromGoFuzz(input).MaxDepth(4).Funcs(
+				func(i *big.Int, c fuzz.Continue) {
+					var b []byte
+					c.Fuzz(&b)
+					i.SetBytes(b)
+				},
+				func(v *[]interface{}, c fuzz.Continue) {
+					var (
+						n uint64
+						s string
+						l []uint
+					)
+					c.Fuzz(&n)
+					c.Fuzz(&s)
+					c.Fuzz(&l)
+					*v = []interface{}{n, s, l, nil}
+				},
+			)
+			val sizeFuzzStruct
+		)
+		fuzzer.Fuzz(&val)
+		enc, err := EncodeToBytes(&val)
+		if err != nil {
+			t.Fatal(err)
+		}
+		size, err := Size(&val)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size != uint64(len(enc)) {
+			t.Fatalf("wrong size %d, want %d", size, len(enc))
+		}
+	})
+}
@@ -32,6 +32,7 @@ type typeinfo struct {
 	decoderErr error // error from makeDecoder
 	writer     writer
 	writerErr  error // error from makeWriter
+	sizer      sizer // set if there is a writer
 }
 
 // typekey is the key of a type in typeCache. It includes the struct tags because
@@ -45,6 +46,8 @@ type decoder func(*Stream, reflect.Value) error
 
 type writer func(reflect.Value, *encBuffer) error
 
+type sizer func(reflect.Value) (uint64, error)
+
 var theTC = newTypeCache()
 
 type typeCache struct {
@@ -180,6 +183,9 @@ func (e structFieldError) Error() string {
 func (i *typeinfo) generate(typ reflect.Type, tags rlpstruct.Tags) {
 	i.decoder, i.decoderErr = makeDecoder(typ, tags)
 	i.writer, i.writerErr = makeWriter(typ, tags)
+	if i.writerErr == nil {
+		i.sizer = makeSizer(typ, tags)
+	}
 }
 
 // rtypeToStructType converts typ to rlpstruct.Type.
@@ -0,0 +1,82 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const redactedArgs = "<redacted>"
+
+// callLogger writes a log entry for every method call handled by the server.
+type callLogger struct {
+	log    log.Logger
+	level  slog.Level
+	redact map[string]struct{} // namespaces whose arguments are not logged
+	reg    *serviceRegistry
+}
+
+func newCallLogger(logger log.Logger, level slog.Level, reg *serviceRegistry, redactNamespaces []string) *callLogger {
+	l := &callLogger{
+		log:    logger,
+		level:  level,
+		redact: make(map[string]struct{}, len(redactNamespaces)),
+		reg:    reg,
+	}
+	for _, ns := range redactNamespaces {
+		l.redact[ns] = struct{}{}
+	}
+	reg.setRedacted(redactNamespaces)
+	return l
+}
+
+// logCall logs the call of the given method. The arguments are replaced if the call
+// targets a redacted namespace.
+func (l *callLogger) logCall(ctx context.Context, msg *jsonrpcMessage, callb *callback) {
+	args := string(msg.Params)
+	if l.isRedacted(msg.namespace(), callb) {
+		args = redactedArgs
+	}
+	info := PeerInfoFromContext(ctx)
+	logctx := []any{"method", msg.Method, "reqid", idForLog{msg.ID}, "transport", info.Transport, "remote", info.RemoteAddr}
+	if info.HTTP.Origin != "" {
+		logctx = append(logctx, "origin", info.HTTP.Origin)
+	}
+	if info.HTTP.UserAgent != "" {
+		logctx = append(logctx, "agent", info.HTTP.UserAgent)
+	}
+	logctx = append(logctx, "args", args)
+	l.log.Log(l.level, "RPC call", logctx...)
+}
+
+// isRedacted reports whether arguments of the callback must not be logged. Matching the
+// namespace alone isn't enough: the same method can also be reachable through a service
+// registered under a different name, so the callback is also checked against all
+// methods of the redacted namespaces, which the registry keeps precomputed.
+func (l *callLogger) isRedacted(namespace string, callb *callback) bool {
+	if _, ok := l.redact[namespace]; ok {
+		return true
+	}
+	if callb == nil || callb.fn.Kind() != reflect.Func {
+		return false
+	}
+	return l.reg.isRedacted(callb.fn.Pointer())
+}
@@ -80,6 +80,7 @@ type Client struct {
 	idgen    func() ID // for subscriptions
 	isHTTP   bool      // connection type: http, ws or ipc
 	services *serviceRegistry
+	direct   *Server // in-process server for direct calls, see DialInProcDirect
 
 	idCounter atomic.Uint32
 
@@ -89,6 +90,16 @@ type Client struct {
 	// config fields
 	batchItemLimit       int
 	batchResponseMaxSize int
+	largeResponseLog     int
+	streamResultLimit    int
+	concurrencyLimit     int
+	connInit             func(PeerInfo) context.Context
+	callLog              *callLogger
+	batchDisabled        bool
+	middleware           []func(Handler) Handler
+	transformResult      func(method string, result json.RawMessage) json.RawMessage
+	callMetrics          func(CallInfo)
+	subTransfers         *subscriptionTransfers
 
 	// writeConn is used for writing to the connection on the caller's goroutine. It should
 	// only be accessed outside of dispatch, with the write lock held. The write lock is
@@ -120,7 +131,15 @@ func (c *Client) newClientConn(conn ServerCodec) *clientConn {
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, clientContextKey{}, c)
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, conn.peerInfo())
-	handler := newHandler(ctx, conn, c.idgen, c.services, c.batchItemLimit, c.batchResponseMaxSize)
+	ctx = withConnContext(ctx, c.connInit)
+	handler := newHandler(ctx, conn, c.idgen, c.services, c.batchItemLimit, c.batchResponseMaxSize, c.largeResponseLog, c.streamResultLimit)
+	handler.callLog = c.callLog
+	handler.batchDisabled = c.batchDisabled
+	handler.middleware = c.middleware
+	handler.transformResult = c.transformResult
+	handler.callMetrics = c.callMetrics
+	handler.transfers = c.subTransfers
+	handler.setConcurrencyLimit(c.concurrencyLimit)
 	return &clientConn{conn, handler}
 }
 
@@ -248,6 +267,16 @@ func initClient(conn ServerCodec, services *serviceRegistry, cfg *clientConfig)
 		idgen:                cfg.idgen,
 		batchItemLimit:       cfg.batchItemLimit,
 		batchResponseMaxSize: cfg.batchResponseLimit,
+		largeResponseLog:     cfg.largeResponseLog,
+		streamResultLimit:    cfg.streamResultLimit,
+		concurrencyLimit:     cfg.concurrencyLimit,
+		connInit:             cfg.connInit,
+		callLog:              cfg.callLog,
+		batchDisabled:        cfg.batchDisabled,
+		middleware:           cfg.middleware,
+		transformResult:      cfg.transformResult,
+		callMetrics:          cfg.callMetrics,
+		subTransfers:         cfg.subTransfers,
 		writeConn:            conn,
 		close:                make(chan struct{}),
 		closing:              make(chan struct{}),
@@ -339,41 +368,53 @@ func (c *Client) CallContext(ctx context.Context, result interface{}, method str
 	if result != nil && reflect.TypeOf(result).Kind() != reflect.Ptr {
 		return fmt.Errorf("call result parameter must be pointer or nil interface: %v", result)
 	}
+	if c.direct != nil {
+		if ok, err := c.direct.callDirect(ctx, result, method, args); ok {
+			return err
+		}
+	}
 	msg, err := c.newMessage(method, args...)
 	if err != nil {
 		return err
 	}
+	resp, err := c.sendCall(ctx, msg)
+	if err != nil {
+		return err
+	}
+	switch {
+	case resp.Error != nil:
+		return resp.Error
+	case len(resp.Result) == 0:
+		return ErrNoResult
+	default:
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(resp.Result, result)
+	}
+}
+
+// sendCall sends a single call and waits for its response.
+func (c *Client) sendCall(ctx context.Context, msg *jsonrpcMessage) (*jsonrpcMessage, error) {
 	op := &requestOp{
 		ids:  []json.RawMessage{msg.ID},
 		resp: make(chan []*jsonrpcMessage, 1),
 	}
-
+	var err error
 	if c.isHTTP {
 		err = c.sendHTTP(ctx, op, msg)
 	} else {
 		err = c.send(ctx, op, msg)
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
-
 	// dispatch has accepted the request and will close the channel when it quits.
 	batchresp, err := op.wait(ctx, c)
 	if err != nil {
-		return err
-	}
-	resp := batchresp[0]
-	switch {
-	case resp.Error != nil:
-		return resp.Error
-	case len(resp.Result) == 0:
-		return ErrNoResult
-	default:
-		if result == nil {
-			return nil
-		}
-		return json.Unmarshal(resp.Result, result)
+		return nil, err
 	}
+	return batchresp[0], nil
 }
 
 // BatchCall sends all given requests as a single batch and waits for the server
@@ -506,6 +547,15 @@ func (c *Client) ShhSubscribe(ctx context.Context, channel interface{}, args ...
 // ErrSubscriptionQueueOverflow. Use a sufficiently large buffer on the channel or ensure
 // that the channel usually has at least one reader to prevent this issue.
 func (c *Client) Subscribe(ctx context.Context, namespace string, channel interface{}, args ...interface{}) (*ClientSubscription, error) {
+	msg, err := c.newMessage(namespace+subscribeMethodSuffix, args...)
+	if err != nil {
+		return nil, err
+	}
+	return c.subscribe(ctx, namespace, channel, msg)
+}
+
+// subscribe sends a call creating a subscription, which returns the subscription ID.
+func (c *Client) subscribe(ctx context.Context, namespace string, channel interface{}, msg *jsonrpcMessage) (*ClientSubscription, error) {
 	// Check type of channel first.
 	chanVal := reflect.ValueOf(channel)
 	if chanVal.Kind() != reflect.Chan || chanVal.Type().ChanDir()&reflect.SendDir == 0 {
@@ -517,11 +567,6 @@ func (c *Client) Subscribe(ctx context.Context, namespace string, channel interf
 	if c.isHTTP {
 		return nil, ErrNotificationsUnsupported
 	}
-
-	msg, err := c.newMessage(namespace+subscribeMethodSuffix, args...)
-	if err != nil {
-		return nil, err
-	}
 	op := &requestOp{
 		ids:  []json.RawMessage{msg.ID},
 		resp: make(chan []*jsonrpcMessage, 1),
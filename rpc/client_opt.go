@@ -17,6 +17,8 @@
 package rpc
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 
 	"github.com/gorilla/websocket"
@@ -41,6 +43,16 @@ type clientConfig struct {
 	idgen              func() ID
 	batchItemLimit     int
 	batchResponseLimit int
+	largeResponseLog   int
+	streamResultLimit  int
+	concurrencyLimit   int
+	connInit           func(PeerInfo) context.Context
+	callLog            *callLogger
+	middleware         []func(Handler) Handler
+	transformResult    func(method string, result json.RawMessage) json.RawMessage
+	callMetrics        func(CallInfo)
+	subTransfers       *subscriptionTransfers
+	batchDisabled      bool
 }
 
 func (cfg *clientConfig) initHeaders() {
@@ -102,6 +114,15 @@ func WithHTTPClient(c *http.Client) ClientOption {
 	})
 }
 
+// WithHTTPClientConfig configures the RPC client to use a http.Client created by
+// NewHTTPClient with the given connection pooling settings. This option replaces any
+// client configured using WithHTTPClient.
+func WithHTTPClientConfig(config HTTPClientConfig) ClientOption {
+	return optionFunc(func(cfg *clientConfig) {
+		cfg.httpClient = NewHTTPClient(config)
+	})
+}
+
 // WithHTTPAuth configures HTTP request authentication. The given provider will be called
 // whenever a request is made. Note that only one authentication provider can be active at
 // any time.
@@ -74,6 +74,33 @@ An example server which uses the JSON codec:
 	 l, _ := net.ListenUnix("unix", &net.UnixAddr{Net: "unix", Name: "/tmp/calculator.sock"})
 	 server.ServeListener(l)
 
+# Streamed Results
+
+Methods returning large byte payloads can avoid building the whole result in memory by
+declaring a result of type io.Reader or RawBytes. The server copies the reader into the
+response as it is written, encoded as a 0x-prefixed hex string, or as base64 if requested
+through RawBytes. Readers implementing io.Closer are closed afterwards.
+
+	func (s *ExportService) Chain(ctx context.Context) (io.Reader, error)
+
+Large array results can be streamed by declaring a result of type ResultStream. The
+elements returned by the stream are encoded one at a time and written as a JSON array,
+which clients decode like any other array result.
+
+	func (s *LogService) Logs(ctx context.Context, from, to uint64) (rpc.ResultStream, error)
+
+Streamed results are limited in size, see Server.SetStreamResultLimit. On HTTP, the
+response is sent using chunked transfer encoding. On WebSocket connections, the response
+is a single message split across multiple frames. On IPC, the response is written to the
+socket incrementally. Results of calls in a batch are buffered in memory and count
+towards the batch response size limit.
+
+If reading the result fails, or the result exceeds the size limit, after part of the
+response has been written to the connection, the response can't be turned into an error
+anymore. The server drops the connection in this case, and HTTP clients receive a
+truncated response body. A ResultStream which fails before any data reached the
+connection still produces a regular error response.
+
 # Subscriptions
 
 The package also supports the publish subscribe pattern through the use of subscriptions.
@@ -71,6 +71,7 @@ const (
 	errMsgTimeout          = "request timed out"
 	errMsgResponseTooLarge = "response too large"
 	errMsgBatchTooLarge    = "batch too large"
+	errMsgBatchDisabled    = "batch requests are not supported"
 )
 
 type methodNotFoundError struct{ method string }
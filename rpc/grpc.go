@@ -0,0 +1,329 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The gRPC transport serves the JSON-RPC service methods using the schema in grpc.proto.
+// Method parameters and results are carried as JSON, only the envelope is protobuf.
+const (
+	grpcCallPath      = "/geth.rpc.JSONRPC/Call"
+	grpcSubscribePath = "/geth.rpc.JSONRPC/Subscribe"
+)
+
+// gRPC status codes, see https://grpc.github.io/grpc/core/md_doc_statuscodes.html
+const (
+	grpcStatusInvalidArgument = 3
+	grpcStatusUnimplemented   = 12
+	grpcStatusInternal        = 13
+	grpcStatusUnavailable     = 14
+)
+
+// ServeGRPC serves the services registered on server over gRPC on the given listener.
+// Calls are served by the unary Call method and subscriptions by the server-streaming
+// Subscribe method of the geth.rpc.JSONRPC service. See grpc.proto for the schema.
+//
+// The transport uses HTTP/2 without TLS. ServeGRPC blocks until the listener is closed.
+func ServeGRPC(lis net.Listener, server *Server) error {
+	srv := &http.Server{Handler: h2c.NewHandler(&grpcHandler{server}, new(http2.Server))}
+	return srv.Serve(lis)
+}
+
+type grpcHandler struct {
+	s *Server
+}
+
+func (h *grpcHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ProtoMajor != 2 {
+		http.Error(w, "gRPC requires HTTP/2 POST requests", http.StatusMethodNotAllowed)
+		return
+	}
+	if !strings.HasPrefix(r.Header.Get("content-type"), "application/grpc") {
+		http.Error(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+	w.Header().Set("content-type", "application/grpc")
+	w.Header().Set("trailer", "grpc-status, grpc-message")
+	stream := &grpcStream{w: w}
+	defer stream.finish()
+
+	if r.URL.Path != grpcCallPath && r.URL.Path != grpcSubscribePath {
+		stream.status, stream.message = grpcStatusUnimplemented, "unknown method "+r.URL.Path
+		return
+	}
+	req, err := readGRPCRequest(r.Body, h.s.httpBodyLimit)
+	if err != nil {
+		stream.status, stream.message = grpcStatusInvalidArgument, err.Error()
+		return
+	}
+	connInfo := PeerInfo{Transport: "grpc", RemoteAddr: r.RemoteAddr}
+	connInfo.HTTP.Version = r.Proto
+	connInfo.HTTP.Host = r.Host
+	connInfo.HTTP.UserAgent = r.Header.Get("user-agent")
+	ctx := context.WithValue(r.Context(), peerInfoContextKey{}, connInfo)
+
+	codec := newGRPCCodec(ctx, connInfo, req, stream)
+	defer codec.close()
+	if r.URL.Path == grpcCallPath {
+		h.s.serveSingleRequest(ctx, codec)
+	} else {
+		if !req.isSubscribe() {
+			stream.send(errorMessage(&invalidRequestError{"method " + req.Method + " is not a subscription"}))
+			return
+		}
+		codec.subscribe = true
+		context.AfterFunc(ctx, codec.close)
+		h.s.ServeCodec(codec, 0)
+	}
+	if !stream.sent() {
+		stream.status, stream.message = grpcStatusUnavailable, "server is shutting down"
+	}
+}
+
+// grpcStream writes response messages to a gRPC response stream.
+type grpcStream struct {
+	w http.ResponseWriter
+
+	mu      sync.Mutex
+	done    bool
+	count   int
+	status  int
+	message string
+}
+
+// send writes msg as a Response message. It returns an error if the stream has ended.
+func (s *grpcStream) send(msg *jsonrpcMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.done {
+		return errors.New("gRPC stream closed")
+	}
+	resp := encodeGRPCResponse(msg)
+	frame := make([]byte, 5, 5+len(resp))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(resp)))
+	if _, err := s.w.Write(append(frame, resp...)); err != nil {
+		s.status, s.message = grpcStatusInternal, err.Error()
+		return err
+	}
+	s.w.(http.Flusher).Flush()
+	s.count++
+	return nil
+}
+
+// sent reports whether any message was sent.
+func (s *grpcStream) sent() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count > 0
+}
+
+// finish writes the status trailers and ends the stream.
+func (s *grpcStream) finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.done = true
+	s.w.Header().Set("grpc-status", strconv.Itoa(s.status))
+	if s.message != "" {
+		s.w.Header().Set("grpc-message", s.message)
+	}
+}
+
+// grpcCodec is the ServerCodec of a gRPC request. It delivers the request to the
+// server and forwards the responses to the gRPC stream.
+type grpcCodec struct {
+	ctx       context.Context
+	info      PeerInfo
+	req       *jsonrpcMessage
+	stream    *grpcStream
+	subscribe bool // forward subscription notifications instead of responses
+
+	mu        sync.Mutex
+	reqRead   bool
+	closeCh   chan interface{}
+	closeOnce sync.Once
+}
+
+func newGRPCCodec(ctx context.Context, info PeerInfo, req *jsonrpcMessage, stream *grpcStream) *grpcCodec {
+	return &grpcCodec{ctx: ctx, info: info, req: req, stream: stream, closeCh: make(chan interface{})}
+}
+
+func (c *grpcCodec) peerInfo() PeerInfo {
+	return c.info
+}
+
+func (c *grpcCodec) remoteAddr() string {
+	return c.info.RemoteAddr
+}
+
+// readBatch returns the request on the first call. Later calls block until the
+// gRPC stream ends.
+func (c *grpcCodec) readBatch() ([]*jsonrpcMessage, bool, error) {
+	c.mu.Lock()
+	read := c.reqRead
+	c.reqRead = true
+	c.mu.Unlock()
+
+	if !read {
+		return []*jsonrpcMessage{c.req}, false, nil
+	}
+	select {
+	case <-c.ctx.Done():
+	case <-c.closeCh:
+	}
+	return nil, false, io.EOF
+}
+
+func (c *grpcCodec) writeJSON(ctx context.Context, v interface{}, isError bool) error {
+	// Messages are re-encoded through JSON, which also resolves streamed results.
+	enc, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var msg jsonrpcMessage
+	if err := json.Unmarshal(enc, &msg); err != nil {
+		return err
+	}
+	if !c.subscribe {
+		return c.stream.send(&msg)
+	}
+	switch {
+	case msg.isNotification():
+		var params struct {
+			Result json.RawMessage `json:"result"`
+		}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return err
+		}
+		return c.stream.send(&jsonrpcMessage{Result: params.Result})
+	case msg.Error != nil:
+		// The subscription failed, end the stream after reporting the error.
+		defer c.close()
+		return c.stream.send(&msg)
+	default:
+		// The subscription ID isn't forwarded, the stream ends the subscription.
+		return nil
+	}
+}
+
+func (c *grpcCodec) closed() <-chan interface{} {
+	return c.closeCh
+}
+
+func (c *grpcCodec) close() {
+	c.closeOnce.Do(func() { close(c.closeCh) })
+}
+
+// readGRPCRequest reads a single length-prefixed Request message.
+func readGRPCRequest(r io.Reader, limit int) (*jsonrpcMessage, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, fmt.Errorf("can't read request: %v", err)
+	}
+	if header[0] != 0 {
+		return nil, errors.New("compressed requests are not supported")
+	}
+	size := binary.BigEndian.Uint32(header[1:])
+	if uint64(size) > uint64(limit) {
+		return nil, fmt.Errorf("request too large (%d bytes)", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, fmt.Errorf("can't read request: %v", err)
+	}
+	return decodeGRPCRequest(data)
+}
+
+// decodeGRPCRequest decodes a Request message into a JSON-RPC call.
+func decodeGRPCRequest(data []byte) (*jsonrpcMessage, error) {
+	msg := &jsonrpcMessage{Version: vsn, ID: json.RawMessage("1")}
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		data = data[n:]
+		switch {
+		case num == 1 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			msg.Method = string(v)
+			data = data[n:]
+		case num == 2 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			msg.Params = json.RawMessage(v)
+			data = data[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			data = data[n:]
+		}
+	}
+	if msg.Method == "" {
+		return nil, errors.New("missing method")
+	}
+	if len(msg.Params) > 0 && !json.Valid(msg.Params) {
+		return nil, errors.New("params are not valid JSON")
+	}
+	return msg, nil
+}
+
+// encodeGRPCResponse encodes the result or error of msg as a Response message.
+func encodeGRPCResponse(msg *jsonrpcMessage) []byte {
+	var enc []byte
+	if msg.Error == nil {
+		enc = protowire.AppendTag(enc, 1, protowire.BytesType)
+		return protowire.AppendBytes(enc, msg.Result)
+	}
+	var errEnc []byte
+	errEnc = protowire.AppendTag(errEnc, 1, protowire.VarintType)
+	errEnc = protowire.AppendVarint(errEnc, protowire.EncodeZigZag(int64(msg.Error.Code)))
+	errEnc = protowire.AppendTag(errEnc, 2, protowire.BytesType)
+	errEnc = protowire.AppendString(errEnc, msg.Error.Message)
+	if msg.Error.Data != nil {
+		if data, err := json.Marshal(msg.Error.Data); err == nil {
+			errEnc = protowire.AppendTag(errEnc, 3, protowire.BytesType)
+			errEnc = protowire.AppendBytes(errEnc, data)
+		}
+	}
+	enc = protowire.AppendTag(enc, 2, protowire.BytesType)
+	return protowire.AppendBytes(enc, errEnc)
+}
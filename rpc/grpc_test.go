@@ -0,0 +1,179 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"golang.org/x/net/http2"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type grpcTestResponse struct {
+	result  string
+	code    int
+	message string
+}
+
+// grpcTestCall sends a request to the gRPC endpoint and decodes all response messages.
+func grpcTestCall(t *testing.T, addr, path, method, params string) ([]grpcTestResponse, http.Header) {
+	t.Helper()
+	return grpcTestStream(context.Background(), t, addr, path, method, params, -1)
+}
+
+// grpcTestStream sends a request to the gRPC endpoint and decodes up to max response
+// messages. The trailer is returned only if the stream ended.
+func grpcTestStream(ctx context.Context, t *testing.T, addr, path, method, params string, max int) ([]grpcTestResponse, http.Header) {
+	t.Helper()
+
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, cfg *tls.Config) (net.Conn, error) {
+			return new(net.Dialer).DialContext(ctx, network, addr)
+		},
+	}}
+	var msg []byte
+	msg = protowire.AppendTag(msg, 1, protowire.BytesType)
+	msg = protowire.AppendString(msg, method)
+	msg = protowire.AppendTag(msg, 2, protowire.BytesType)
+	msg = protowire.AppendString(msg, params)
+	body := binary.BigEndian.AppendUint32([]byte{0}, uint32(len(msg)))
+	body = append(body, msg...)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://"+addr+path, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("content-type", "application/grpc")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var msgs []grpcTestResponse
+	for len(msgs) != max {
+		var header [5]byte
+		if _, err := io.ReadFull(resp.Body, header[:]); err == io.EOF {
+			return msgs, resp.Trailer
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		data := make([]byte, binary.BigEndian.Uint32(header[1:]))
+		if _, err := io.ReadFull(resp.Body, data); err != nil {
+			t.Fatal(err)
+		}
+		msgs = append(msgs, decodeGRPCTestResponse(t, data))
+	}
+	return msgs, nil
+}
+
+func decodeGRPCTestResponse(t *testing.T, data []byte) (resp grpcTestResponse) {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		data = data[n:]
+		switch {
+		case num == 1 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(data)
+			resp.result, data = string(v), data[n:]
+		case num == 2 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(data)
+			resp.code, resp.message = decodeGRPCTestError(t, v)
+			data = data[n:]
+		default:
+			t.Fatalf("unexpected response field %d type %d", num, typ)
+		}
+	}
+	return resp
+}
+
+func decodeGRPCTestError(t *testing.T, data []byte) (code int, message string) {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		data = data[n:]
+		switch {
+		case num == 1 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(data)
+			code, data = int(protowire.DecodeZigZag(v)), data[n:]
+		case num == 2 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(data)
+			message, data = string(v), data[n:]
+		case num == 3 && typ == protowire.BytesType:
+			_, n := protowire.ConsumeBytes(data)
+			data = data[n:]
+		default:
+			t.Fatalf("unexpected error field %d type %d", num, typ)
+		}
+	}
+	return code, message
+}
+
+func TestGRPC(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer()
+	defer server.Stop()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	go ServeGRPC(lis, server)
+	addr := lis.Addr().String()
+
+	// Unary calls.
+	msgs, trailer := grpcTestCall(t, addr, grpcCallPath, "test_echo", `["x", 3, {"S": "y"}]`)
+	if len(msgs) != 1 || msgs[0].result != `{"String":"x","Int":3,"Args":{"S":"y"}}` {
+		t.Fatalf("wrong response %+v", msgs)
+	}
+	if status := trailer.Get("grpc-status"); status != "0" {
+		t.Fatalf("wrong status %q", status)
+	}
+	msgs, _ = grpcTestCall(t, addr, grpcCallPath, "test_returnError", `[]`)
+	if len(msgs) != 1 || msgs[0].code != 444 || msgs[0].message != "testError" {
+		t.Fatalf("wrong error response %+v", msgs)
+	}
+	msgs, _ = grpcTestCall(t, addr, grpcCallPath, "nftest_subscribe", `["someSubscription", 1, 1]`)
+	if len(msgs) != 1 || msgs[0].code == 0 {
+		t.Fatalf("subscription over unary call didn't fail: %+v", msgs)
+	}
+
+	// Subscriptions stream the notifications until the client cancels.
+	ctx, cancel := context.WithCancel(context.Background())
+	msgs, _ = grpcTestStream(ctx, t, addr, grpcSubscribePath, "nftest_subscribe", `["someSubscription", 3, 10]`, 3)
+	cancel()
+	if len(msgs) != 3 || msgs[0].result != "10" || msgs[1].result != "11" || msgs[2].result != "12" {
+		t.Fatalf("wrong notifications %+v", msgs)
+	}
+	msgs, _ = grpcTestCall(t, addr, grpcSubscribePath, "test_echo", `["x", 3, {"S": "y"}]`)
+	if len(msgs) != 1 || msgs[0].code == 0 {
+		t.Fatalf("non-subscription method didn't fail: %+v", msgs)
+	}
+
+	// Unknown methods of the gRPC service.
+	_, trailer = grpcTestCall(t, addr, "/geth.rpc.JSONRPC/Other", "test_echo", `[]`)
+	if status := trailer.Get("grpc-status"); status != "12" {
+		t.Fatalf("wrong status for unknown gRPC method %q", status)
+	}
+}
@@ -65,6 +65,15 @@ type handler struct {
 	allowSubscribe       bool
 	batchRequestLimit    int
 	batchResponseMaxSize int
+	largeResponseLog     int                                                         // responses larger than this are logged (0 = disabled)
+	streamResultLimit    int                                                         // maximum size of streamed results (0 = unlimited)
+	callLog              *callLogger                                                 // logs all method calls if set
+	batchDisabled        bool                                                        // rejects all batch requests if set
+	middleware           []func(Handler) Handler                                     // wraps method calls, outermost first
+	transformResult      func(method string, result json.RawMessage) json.RawMessage // rewrites successful results if set
+	callMetrics          func(CallInfo)                                              // invoked for every handled call if set
+	transfers            *subscriptionTransfers                                      // set if subscriptions can move between connections
+	callSem              chan struct{}                                               // limits concurrently executing calls if set
 
 	subLock    sync.Mutex
 	serverSubs map[ID]*Subscription
@@ -75,7 +84,7 @@ type callProc struct {
 	notifiers []*Notifier
 }
 
-func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *serviceRegistry, batchRequestLimit, batchResponseMaxSize int) *handler {
+func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *serviceRegistry, batchRequestLimit, batchResponseMaxSize, largeResponseLog, streamResultLimit int) *handler {
 	rootCtx, cancelRoot := context.WithCancel(connCtx)
 	h := &handler{
 		reg:                  reg,
@@ -90,6 +99,8 @@ func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *
 		log:                  log.Root(),
 		batchRequestLimit:    batchRequestLimit,
 		batchResponseMaxSize: batchResponseMaxSize,
+		largeResponseLog:     largeResponseLog,
+		streamResultLimit:    streamResultLimit,
 	}
 	if conn.remoteAddr() != "" {
 		h.log = h.log.New("conn", conn.remoteAddr())
@@ -169,6 +180,15 @@ func (b *batchCallBuffer) doWrite(ctx context.Context, conn jsonWriter, isErrorR
 
 // handleBatch executes all messages in a batch and returns the responses.
 func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
+	// Reject all batches if they are disabled. The items may not have been decoded,
+	// so there is no request ID to respond to.
+	if h.batchDisabled {
+		h.startCallProc(func(cp *callProc) {
+			resp := errorMessage(&invalidRequestError{errMsgBatchDisabled})
+			h.conn.writeJSON(cp.ctx, resp, true)
+		})
+		return
+	}
 	// Emit error response for empty batches:
 	if len(msgs) == 0 {
 		h.startCallProc(func(cp *callProc) {
@@ -228,7 +248,21 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 				break
 			}
 			resp := h.handleCallMsg(cp, msg)
+			if resp != nil && resp.stream != nil {
+				// Streamed results can't be interleaved with the other responses
+				// of the batch, so they are buffered.
+				if result, err := resp.stream.result(); err != nil {
+					resp = msg.errorResponse(err)
+				} else {
+					resp = &jsonrpcMessage{Version: vsn, ID: msg.ID, Result: result}
+				}
+			}
 			callBuffer.pushResponse(resp)
+			if resp != nil {
+				// The batch response is written as a whole, so the size of the
+				// individual responses is taken from their encoded result.
+				h.reportSizes(msg, len(resp.Result))
+			}
 			if resp != nil && h.batchResponseMaxSize != 0 {
 				responseBytes += len(resp.Result)
 				if responseBytes > h.batchResponseMaxSize {
@@ -243,7 +277,11 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 		}
 
 		h.addSubscriptions(cp.notifiers)
-		callBuffer.write(cp.ctx, h.conn)
+		ctx, size := withWriteSize(cp.ctx)
+		callBuffer.write(ctx, h.conn)
+		if h.largeResponseLog != 0 && *size > h.largeResponseLog {
+			h.log.Debug("Large RPC batch response", "calls", len(calls), "size", *size)
+		}
 		for _, n := range cp.notifiers {
 			n.activate()
 		}
@@ -303,8 +341,18 @@ func (h *handler) handleNonBatchCall(cp *callProc, msg *jsonrpcMessage) {
 	h.addSubscriptions(cp.notifiers)
 	if answer != nil {
 		responded.Do(func() {
-			h.conn.writeJSON(cp.ctx, answer, false)
+			ctx, size := withWriteSize(cp.ctx)
+			if answer.stream != nil {
+				h.conn.writeJSON(ctx, answer.stream, false)
+			} else {
+				h.conn.writeJSON(ctx, answer, false)
+			}
+			h.reportSizes(msg, *size)
 		})
+		if answer.stream != nil {
+			// Release the result reader in case the call timed out.
+			answer.stream.close()
+		}
 	}
 	for _, n := range cp.notifiers {
 		n.activate()
@@ -358,34 +406,66 @@ func (h *handler) cancelAllRequests(err error, inflightReq *requestOp) {
 }
 
 func (h *handler) addSubscriptions(nn []*Notifier) {
+	var added []*Notifier
 	h.subLock.Lock()
-	defer h.subLock.Unlock()
-
 	for _, n := range nn {
 		if sub := n.takeSubscription(); sub != nil {
 			h.serverSubs[sub.ID] = sub
+			added = append(added, n)
+		}
+	}
+	h.subLock.Unlock()
+
+	if h.transfers != nil {
+		for _, n := range added {
+			h.transfers.attach(h, n)
 		}
 	}
 }
 
 // cancelServerSubscriptions removes all subscriptions and closes their error channels.
+// If subscription transfers are enabled, the subscriptions are detached instead.
 func (h *handler) cancelServerSubscriptions(err error) {
+	var detached []*Notifier
 	h.subLock.Lock()
-	defer h.subLock.Unlock()
-
 	for id, s := range h.serverSubs {
-		s.err <- err
-		close(s.err)
 		delete(h.serverSubs, id)
+		if h.transfers != nil {
+			detached = append(detached, s.notifier)
+		} else {
+			s.cancel(err)
+		}
+	}
+	h.subLock.Unlock()
+
+	for _, n := range detached {
+		h.transfers.detach(h, n, err)
+	}
+}
+
+// setConcurrencyLimit limits the number of concurrently executing call procs.
+// A limit of zero disables the limit.
+func (h *handler) setConcurrencyLimit(n int) {
+	if n > 0 {
+		h.callSem = make(chan struct{}, n)
 	}
 }
 
 // startCallProc runs fn in a new goroutine and starts tracking it in the h.calls wait group.
+// If the number of concurrent call procs is limited, fn waits until a slot is available.
 func (h *handler) startCallProc(fn func(*callProc)) {
 	h.callWG.Add(1)
 	go func() {
-		ctx, cancel := context.WithCancel(h.rootCtx)
 		defer h.callWG.Done()
+		if h.callSem != nil {
+			select {
+			case h.callSem <- struct{}{}:
+				defer func() { <-h.callSem }()
+			case <-h.rootCtx.Done():
+				return
+			}
+		}
+		ctx, cancel := context.WithCancel(h.rootCtx)
 		defer cancel()
 		fn(&callProc{ctx: ctx})
 	}()
@@ -465,12 +545,18 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 	start := time.Now()
 	switch {
 	case msg.isNotification():
-		h.handleCall(ctx, msg)
+		resp := h.handleCallWithMiddleware(ctx, msg)
+		if resp.stream != nil {
+			resp.stream.close()
+		}
+		h.reportCall(ctx.ctx, msg, resp, start)
 		h.log.Debug("Served "+msg.Method, "duration", time.Since(start))
 		return nil
 
 	case msg.isCall():
-		resp := h.handleCall(ctx, msg)
+		resp := h.handleCallWithMiddleware(ctx, msg)
+		h.applyResultTransform(msg, resp)
+		h.reportCall(ctx.ctx, msg, resp, start)
 		var logctx []any
 		logctx = append(logctx, "reqid", idForLog{msg.ID}, "duration", time.Since(start))
 		if resp.Error != nil {
@@ -497,6 +583,14 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 	if msg.isSubscribe() {
 		return h.handleSubscribe(cp, msg)
 	}
+	if h.transfers != nil {
+		switch msg.Method {
+		case subscriptionTokenMethod:
+			return h.handleSubscriptionToken(msg)
+		case resumeSubscriptionMethod:
+			return h.handleResumeSubscription(cp, msg)
+		}
+	}
 	var callb *callback
 	if msg.isUnsubscribe() {
 		callb = h.unsubscribeCb
@@ -506,6 +600,9 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 	if callb == nil {
 		return msg.errorResponse(&methodNotFoundError{method: msg.Method})
 	}
+	if h.callLog != nil {
+		h.callLog.logCall(cp.ctx, msg, callb)
+	}
 
 	args, err := parsePositionalArguments(msg.Params, callb.argTypes)
 	if err != nil {
@@ -526,7 +623,6 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 		rpcServingTimer.UpdateSince(start)
 		updateServeTimeHistogram(msg.Method, answer.Error == nil, time.Since(start))
 	}
-
 	return answer
 }
 
@@ -546,6 +642,9 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage) *jsonrpcMes
 	if callb == nil {
 		return msg.errorResponse(&subscriptionNotFoundError{namespace, name})
 	}
+	if h.callLog != nil {
+		h.callLog.logCall(cp.ctx, msg, callb)
+	}
 
 	// Parse subscription name arg too, but remove it before calling the callback.
 	argTypes := append([]reflect.Type{stringType}, callb.argTypes...)
@@ -569,20 +668,35 @@ func (h *handler) runMethod(ctx context.Context, msg *jsonrpcMessage, callb *cal
 	if err != nil {
 		return msg.errorResponse(err)
 	}
+	if callb.isStream {
+		stream := newStreamResponse(msg, result, callb.isArray, h.streamResultLimit)
+		if stream.tooLarge() {
+			stream.close()
+			return msg.errorResponse(&internalServerError{errcodeResponseTooLarge, errMsgResponseTooLarge})
+		}
+		return &jsonrpcMessage{Version: vsn, ID: msg.ID, stream: stream}
+	}
+	if versioned, ok := result.(VersionedResult); ok {
+		return msg.versionedResponse(versioned)
+	}
 	return msg.response(result)
 }
 
 // unsubscribe is the callback function for all *_unsubscribe calls.
 func (h *handler) unsubscribe(ctx context.Context, id ID) (bool, error) {
 	h.subLock.Lock()
-	defer h.subLock.Unlock()
-
 	s := h.serverSubs[id]
 	if s == nil {
+		h.subLock.Unlock()
 		return false, ErrSubscriptionNotFound
 	}
 	close(s.err)
 	delete(h.serverSubs, id)
+	h.subLock.Unlock()
+
+	if h.transfers != nil {
+		h.transfers.remove(s.notifier)
+	}
 	return true, nil
 }
 
@@ -19,6 +19,7 @@ package rpc
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -126,7 +127,8 @@ func DialHTTP(endpoint string) (*Client, error) {
 }
 
 // DialHTTPWithClient creates a new RPC client that connects to an RPC server over HTTP
-// using the provided HTTP Client.
+// using the provided HTTP Client. NewHTTPClient can be used to create a client with
+// tuned connection pooling and HTTP/2 support.
 //
 // Deprecated: use DialOptions and the WithHTTPClient option.
 func DialHTTPWithClient(endpoint string, client *http.Client) (*Client, error) {
@@ -142,6 +144,51 @@ func DialHTTPWithClient(endpoint string, client *http.Client) (*Client, error) {
 	return newClient(context.Background(), &cfg, fn)
 }
 
+// HTTPClientConfig configures connection pooling and protocol negotiation of the
+// http.Client created by NewHTTPClient.
+//
+// Note that the RPC client sends each call, and each batch, as a single HTTP request. The
+// number of connections opened to an endpoint is therefore bounded by the number of
+// concurrent calls in flight, not by the number of items in a batch. Backends issuing
+// many concurrent calls should raise MaxIdleConnsPerHost so connections are reused
+// instead of being closed after every request. With HTTP/2 enabled, concurrent calls
+// are multiplexed over a single connection when the server supports it.
+type HTTPClientConfig struct {
+	MaxIdleConns        int           // maximum idle connections across all hosts (0 = no limit)
+	MaxIdleConnsPerHost int           // maximum idle connections per host (0 = http.DefaultMaxIdleConnsPerHost)
+	MaxConnsPerHost     int           // maximum total connections per host (0 = no limit)
+	IdleConnTimeout     time.Duration // time an idle connection is kept open (0 = no limit)
+	EnableHTTP2         bool          // attempt HTTP/2 for TLS endpoints
+}
+
+// DefaultHTTPClientConfig contains conservative connection pooling settings, matching
+// the behavior of http.DefaultTransport.
+var DefaultHTTPClientConfig = HTTPClientConfig{
+	MaxIdleConns:        100,
+	MaxIdleConnsPerHost: http.DefaultMaxIdleConnsPerHost,
+	IdleConnTimeout:     90 * time.Second,
+	EnableHTTP2:         true,
+}
+
+// NewHTTPClient creates a http.Client suitable for use with the WithHTTPClient option,
+// using the given connection pooling settings.
+//
+// HTTP/2 is only negotiated for https:// endpoints. Plain http:// endpoints always use
+// HTTP/1.1 regardless of EnableHTTP2.
+func NewHTTPClient(config HTTPClientConfig) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = config.MaxIdleConns
+	transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+	transport.MaxConnsPerHost = config.MaxConnsPerHost
+	transport.IdleConnTimeout = config.IdleConnTimeout
+	transport.ForceAttemptHTTP2 = config.EnableHTTP2
+	if !config.EnableHTTP2 {
+		// A non-nil, empty TLSNextProto map disables HTTP/2.
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+	return &http.Client{Transport: transport}
+}
+
 func newClientTransportHTTP(endpoint string, cfg *clientConfig) reconnectFunc {
 	headers := make(http.Header, 2+len(cfg.httpHeaders))
 	headers.Set("accept", contentType)
@@ -255,9 +302,15 @@ type httpServerConn struct {
 
 func (s *Server) newHTTPServerConn(r *http.Request, w http.ResponseWriter) ServerCodec {
 	body := io.LimitReader(r.Body, int64(s.httpBodyLimit))
-	conn := &httpServerConn{Reader: body, Writer: w, r: r}
+	out := &countingWriter{w: w}
+	conn := &httpServerConn{Reader: body, Writer: out, r: r}
 
 	encoder := func(v any, isErrorResponse bool) error {
+		if s, ok := v.(*streamResponse); ok {
+			// Streamed results are written without Content-Length, the HTTP server
+			// sends them using chunked transfer encoding.
+			return s.writeTo(conn)
+		}
 		if !isErrorResponse {
 			return json.NewEncoder(conn).Encode(v)
 		}
@@ -281,7 +334,7 @@ func (s *Server) newHTTPServerConn(r *http.Request, w http.ResponseWriter) Serve
 		// the final chunk is missing.
 		w.Header().Set("transfer-encoding", "identity")
 
-		_, err = w.Write(encdata)
+		_, err = out.Write(encdata)
 		if f, ok := w.(http.Flusher); ok {
 			f.Flush()
 		}
@@ -291,7 +344,9 @@ func (s *Server) newHTTPServerConn(r *http.Request, w http.ResponseWriter) Serve
 	dec := json.NewDecoder(conn)
 	dec.UseNumber()
 
-	return NewFuncCodec(conn, encoder, dec.Decode)
+	codec := NewFuncCodec(conn, encoder, dec.Decode).(*jsonCodec)
+	codec.out = out
+	return codec
 }
 
 // Close does nothing and always returns nil.
@@ -317,7 +372,8 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create request-scoped context.
+	// Create request-scoped context. Method calls run in a context derived from the
+	// request context, so they are canceled when the client disconnects.
 	connInfo := PeerInfo{Transport: "http", RemoteAddr: r.RemoteAddr}
 	connInfo.HTTP.Version = r.Proto
 	connInfo.HTTP.Host = r.Host
@@ -22,7 +22,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func confirmStatusCode(t *testing.T, got, want int) {
@@ -263,3 +265,99 @@ func TestNewContextWithHeaders(t *testing.T) {
 		t.Error("call failed:", err)
 	}
 }
+
+func TestHTTPClientConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, enable := range []bool{true, false} {
+		var proto atomic.Value
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proto.Store(r.Proto)
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{}`))
+		}))
+		server.EnableHTTP2 = true
+		server.StartTLS()
+
+		config := DefaultHTTPClientConfig
+		config.EnableHTTP2 = enable
+		config.MaxConnsPerHost = 4
+		httpClient := NewHTTPClient(config)
+		transport := httpClient.Transport.(*http.Transport)
+		if transport.MaxConnsPerHost != 4 {
+			t.Fatalf("wrong MaxConnsPerHost: %d", transport.MaxConnsPerHost)
+		}
+		// Trust the test server certificate.
+		transport.TLSClientConfig = server.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+
+		client, err := DialOptions(context.Background(), server.URL, WithHTTPClient(httpClient))
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		if err := client.Call(nil, "test"); err != ErrNoResult {
+			t.Fatalf("call failed: %v", err)
+		}
+		want := "HTTP/1.1"
+		if enable {
+			want = "HTTP/2.0"
+		}
+		if have := proto.Load(); have != want {
+			t.Errorf("EnableHTTP2=%t: wrong protocol %v, want %s", enable, have, want)
+		}
+		client.Close()
+		server.Close()
+	}
+}
+
+type cancelTestService struct {
+	started  chan struct{}
+	canceled chan struct{}
+}
+
+func (s *cancelTestService) Wait(ctx context.Context) error {
+	close(s.started)
+	<-ctx.Done()
+	close(s.canceled)
+	return ctx.Err()
+}
+
+// This test checks that the context of a method call is canceled when the HTTP
+// client disconnects.
+func TestHTTPClientDisconnectCancelsCall(t *testing.T) {
+	t.Parallel()
+
+	var (
+		server  = NewServer()
+		service = &cancelTestService{started: make(chan struct{}), canceled: make(chan struct{})}
+	)
+	defer server.Stop()
+	if err := server.RegisterName("cancel", service); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	client, err := DialHTTP(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- client.CallContext(ctx, nil, "cancel_wait") }()
+
+	select {
+	case <-service.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("call did not start")
+	}
+	cancel()
+	select {
+	case <-service.canceled:
+	case <-time.After(time.Second):
+		t.Fatal("call context not canceled after client disconnect")
+	}
+	if err := <-errc; err == nil {
+		t.Fatal("expected error from canceled call")
+	}
+}
@@ -18,7 +18,9 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
 	"net"
+	"reflect"
 )
 
 // DialInProc attaches an in-process connection to the given RPC server.
@@ -32,3 +34,58 @@ func DialInProc(handler *Server) *Client {
 	})
 	return c
 }
+
+// DialInProcDirect is like DialInProc, but method calls made through the client skip
+// JSON encoding where possible. When all arguments of a call have exactly the parameter
+// types of the method, they are passed to the method directly. Likewise, the result is
+// stored without encoding if its type matches the method's result type, and converted
+// through JSON otherwise. All other calls, as well as batches and subscriptions, are
+// sent to the server as JSON.
+//
+// Direct calls run on the calling goroutine. Since no copy is made, arguments and
+// results may share memory with the service and must not be modified. Calls are always
+// sent as JSON if the server has middleware, a result transform, a call logger or call
+// metrics configured.
+func DialInProcDirect(handler *Server) *Client {
+	c := DialInProc(handler)
+	c.direct = handler
+	return c
+}
+
+// callDirect invokes the callback of method without encoding its arguments and result.
+// It returns false if the call can't be performed this way.
+func (s *Server) callDirect(ctx context.Context, result interface{}, method string, args []interface{}) (bool, error) {
+	if !s.run.Load() || s.middleware != nil || s.transformResult != nil || s.callLog != nil || s.callMetrics != nil {
+		return false, nil
+	}
+	cb := s.services.callback(method)
+	if cb == nil || cb.isSubscribe || cb.isStream || len(args) != len(cb.argTypes) {
+		return false, nil
+	}
+	argvals := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		if arg == nil || reflect.TypeOf(arg) != cb.argTypes[i] {
+			return false, nil
+		}
+		argvals[i] = reflect.ValueOf(arg)
+	}
+
+	ctx = context.WithValue(ctx, peerInfoContextKey{}, PeerInfo{Transport: "inproc"})
+	ctx = withConnContext(ctx, s.connInit)
+	res, err := cb.call(ctx, method, argvals)
+	if err != nil {
+		return true, errorMessage(err).Error
+	}
+	if result == nil {
+		return true, nil
+	}
+	if rv := reflect.ValueOf(result).Elem(); res != nil && reflect.TypeOf(res) == rv.Type() {
+		rv.Set(reflect.ValueOf(res))
+		return true, nil
+	}
+	enc, err := json.Marshal(res)
+	if err != nil {
+		return true, err
+	}
+	return true, json.Unmarshal(enc, result)
+}
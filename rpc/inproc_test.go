@@ -0,0 +1,106 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDialInProcDirect(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer()
+	defer server.Stop()
+	client := DialInProcDirect(server)
+	defer client.Close()
+
+	// Calls with matching types are not sent through the codec.
+	var info PeerInfo
+	if err := client.Call(&info, "test_peerInfo"); err != nil {
+		t.Fatal(err)
+	}
+	if info.Transport != "inproc" {
+		t.Fatalf("call with matching types not direct, transport %q", info.Transport)
+	}
+
+	// Results are shared with the service.
+	args := &echoArgs{S: "x"}
+	var res echoResult
+	if err := client.Call(&res, "test_echo", "s", 1, args); err != nil {
+		t.Fatal(err)
+	}
+	if want := (echoResult{"s", 1, args}); res != want {
+		t.Fatalf("wrong result %v, want %v", res, want)
+	}
+
+	// Results of other types are converted through JSON.
+	var resMap map[string]any
+	if err := client.Call(&resMap, "test_echo", "s", 1, args); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"String": "s", "Int": 1.0, "Args": map[string]any{"S": "x"}}
+	if !reflect.DeepEqual(resMap, want) {
+		t.Fatalf("wrong converted result %v, want %v", resMap, want)
+	}
+
+	// Arguments of other types use the codec.
+	res = echoResult{}
+	if err := client.Call(&res, "test_echo", "s", int64(1), args); err != nil {
+		t.Fatal(err)
+	}
+	if res.Int != 1 || res.Args == args {
+		t.Fatalf("wrong result of JSON call: %v", res)
+	}
+
+	// Errors are reported like errors of JSON calls.
+	err := client.Call(nil, "test_returnError")
+	var rpcErr Error
+	var dataErr DataError
+	if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != 444 {
+		t.Fatalf("wrong error: %v", err)
+	}
+	if !errors.As(err, &dataErr) || dataErr.ErrorData() != "testError data" {
+		t.Fatalf("wrong error data: %v", err)
+	}
+}
+
+func TestDialInProcDirectMiddleware(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer()
+	defer server.Stop()
+	var calls int
+	server.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) *Response {
+			calls++
+			return next(ctx, req)
+		}
+	})
+	client := DialInProcDirect(server)
+	defer client.Close()
+
+	var info PeerInfo
+	if err := client.Call(&info, "test_peerInfo"); err != nil {
+		t.Fatal(err)
+	}
+	if info.Transport == "inproc" || calls != 1 {
+		t.Fatalf("call bypassed middleware")
+	}
+}
@@ -35,10 +35,48 @@ func (s *Server) ServeListener(l net.Listener) error {
 			return err
 		}
 		log.Trace("Accepted RPC connection", "conn", conn.RemoteAddr())
-		go s.ServeCodec(NewCodec(conn), 0)
+		go s.serveConn(conn)
 	}
 }
 
+// serveConn serves JSON-RPC on an accepted connection, if it is authorized.
+func (s *Server) serveConn(conn net.Conn) {
+	if s.ipcAuthorizer != nil && !s.authorizeIPC(conn) {
+		conn.Close()
+		return
+	}
+	s.ServeCodec(NewCodec(conn), 0)
+}
+
+// PeerCredentials are the credentials of the process on the other end of an IPC
+// connection, as reported by the operating system.
+type PeerCredentials struct {
+	PID int32
+	UID uint32
+	GID uint32
+}
+
+// IPCAuthorizer decides whether a connection from the given peer may be served.
+type IPCAuthorizer func(cred PeerCredentials) bool
+
+// authorizeIPC checks the peer credentials of conn against the configured authorizer.
+func (s *Server) authorizeIPC(conn net.Conn) bool {
+	cred, err := peerCredentials(conn)
+	if err != nil {
+		if s.ipcFailClosed {
+			log.Warn("Rejected IPC connection, peer credentials unavailable", "err", err)
+			return false
+		}
+		log.Warn("Accepting IPC connection without peer credentials", "err", err)
+		return true
+	}
+	if !s.ipcAuthorizer(cred) {
+		log.Warn("Rejected unauthorized IPC connection", "pid", cred.PID, "uid", cred.UID, "gid", cred.GID)
+		return false
+	}
+	return true
+}
+
 // DialIPC create a new IPC client that connects to the given endpoint. On Unix it assumes
 // the endpoint is the full path to a unix socket, and Windows the endpoint is an
 // identifier for a named pipe.
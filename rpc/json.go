@@ -26,6 +26,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -66,6 +67,14 @@ type jsonrpcMessage struct {
 	Params  json.RawMessage `json:"params,omitempty"`
 	Error   *jsonError      `json:"error,omitempty"`
 	Result  json.RawMessage `json:"result,omitempty"`
+
+	// Conditional requests, see VersionedResult.
+	IfNoneMatch   *string `json:"ifNoneMatch,omitempty"`
+	ResultVersion string  `json:"version,omitempty"`
+	NotModified   bool    `json:"notModified,omitempty"`
+
+	stream *streamResponse // streamed result, set instead of Result
+	size   int             // encoded size of a received message, zero if unknown
 }
 
 func (msg *jsonrpcMessage) isNotification() bool {
@@ -184,7 +193,10 @@ type jsonCodec struct {
 	decode  decodeFunc       // decoder to allow multiple transports
 	encMu   sync.Mutex       // guards the encoder
 	encode  encodeFunc       // encoder to allow multiple transports
+	out     *countingWriter  // counts the bytes written by encode, nil if unknown
 	conn    deadlineCloser
+
+	skipBatch atomic.Bool // if set, batch requests are not decoded
 }
 
 type encodeFunc = func(v interface{}, isErrorResponse bool) error
@@ -210,14 +222,36 @@ func NewFuncCodec(conn deadlineCloser, encode encodeFunc, decode decodeFunc) Ser
 // NewCodec creates a codec on the given connection. If conn implements ConnRemoteAddr, log
 // messages will use it to include the remote address of the connection.
 func NewCodec(conn Conn) ServerCodec {
-	enc := json.NewEncoder(conn)
+	out := &countingWriter{w: conn}
+	enc := json.NewEncoder(out)
 	dec := json.NewDecoder(conn)
 	dec.UseNumber()
 
 	encode := func(v interface{}, isErrorResponse bool) error {
+		if s, ok := v.(*streamResponse); ok {
+			return s.writeTo(out)
+		}
 		return enc.Encode(v)
 	}
-	return NewFuncCodec(conn, encode, dec.Decode)
+	codec := NewFuncCodec(conn, encode, dec.Decode).(*jsonCodec)
+	codec.out = out
+	return codec
+}
+
+// writeSizeKey is the context key of the counter receiving the size of a message
+// written by writeJSON.
+type writeSizeKey struct{}
+
+// withWriteSize returns a context which makes writeJSON report the number of bytes
+// written to the connection. The counter stays at -1 if the codec can't count them.
+func withWriteSize(ctx context.Context) (context.Context, *int) {
+	size := -1
+	return context.WithValue(ctx, writeSizeKey{}, &size), &size
+}
+
+// disableBatch makes the codec skip decoding the items of batch requests.
+func (c *jsonCodec) disableBatch() {
+	c.skipBatch.Store(true)
 }
 
 func (c *jsonCodec) peerInfo() PeerInfo {
@@ -236,6 +270,10 @@ func (c *jsonCodec) readBatch() (messages []*jsonrpcMessage, batch bool, err err
 	if err := c.decode(&rawmsg); err != nil {
 		return nil, false, err
 	}
+	if c.skipBatch.Load() && isBatch(rawmsg) {
+		// Batches are rejected by the handler, don't bother decoding the items.
+		return nil, true, nil
+	}
 	messages, batch = parseMessage(rawmsg)
 	for i, msg := range messages {
 		if msg == nil {
@@ -256,7 +294,20 @@ func (c *jsonCodec) writeJSON(ctx context.Context, v interface{}, isErrorRespons
 		deadline = time.Now().Add(defaultWriteTimeout)
 	}
 	c.conn.SetWriteDeadline(deadline)
-	return c.encode(v, isErrorResponse)
+	var start int
+	if c.out != nil {
+		start = c.out.n
+	}
+	err := c.encode(v, isErrorResponse)
+	if size, ok := ctx.Value(writeSizeKey{}).(*int); ok && c.out != nil {
+		*size = c.out.n - start
+	}
+	if _, ok := v.(*streamResponse); ok && err != nil {
+		// A streamed response may have been written partially, which leaves the
+		// connection in an undefined state. Drop it.
+		c.close()
+	}
+	return err
 }
 
 func (c *jsonCodec) close() {
@@ -279,14 +330,21 @@ func parseMessage(raw json.RawMessage) ([]*jsonrpcMessage, bool) {
 	if !isBatch(raw) {
 		msgs := []*jsonrpcMessage{{}}
 		json.Unmarshal(raw, &msgs[0])
+		if msgs[0] != nil {
+			msgs[0].size = len(raw)
+		}
 		return msgs, false
 	}
 	dec := json.NewDecoder(bytes.NewReader(raw))
 	dec.Token() // skip '['
 	var msgs []*jsonrpcMessage
 	for dec.More() {
+		start := dec.InputOffset()
 		msgs = append(msgs, new(jsonrpcMessage))
 		dec.Decode(&msgs[len(msgs)-1])
+		if msg := msgs[len(msgs)-1]; msg != nil {
+			msg.size = int(dec.InputOffset() - start)
+		}
 	}
 	return msgs, true
 }
@@ -17,6 +17,7 @@
 package rpc
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -31,9 +32,55 @@ var (
 	// serveTimeHistName is the prefix of the per-request serving time histograms.
 	serveTimeHistName = "rpc/duration"
 
+	// requestSizeHistName and responseSizeHistName are the prefixes of the per-method
+	// payload size histograms.
+	requestSizeHistName  = "rpc/size/request"
+	responseSizeHistName = "rpc/size/response"
+
 	rpcServingTimer = metrics.NewRegisteredTimer("rpc/duration/all", nil)
 )
 
+// CallInfo describes a method call handled by the server.
+type CallInfo struct {
+	Method    string
+	Transport string        // transport of the connection, see PeerInfo
+	Duration  time.Duration // time taken to handle the call
+	ReqBytes  int           // size of the call parameters
+	RespBytes int           // size of the result, zero for errors and streamed results
+	Error     error         // error response of the call, nil if successful
+}
+
+// WithMetrics installs a callback which is invoked for every method call handled by
+// the server, including failed calls and each call in a batch. The callback is invoked
+// on the goroutine handling the call after the response has been created, so it should
+// return quickly. Passing nil removes the callback.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) WithMetrics(fn func(info CallInfo)) {
+	s.callMetrics = fn
+}
+
+// reportCall invokes the call metrics callback for a handled call, if set.
+func (h *handler) reportCall(ctx context.Context, msg, resp *jsonrpcMessage, start time.Time) {
+	if h.callMetrics == nil {
+		return
+	}
+	info := CallInfo{
+		Method:    msg.Method,
+		Transport: PeerInfoFromContext(ctx).Transport,
+		Duration:  time.Since(start),
+		ReqBytes:  len(msg.Params),
+	}
+	if resp != nil {
+		info.RespBytes = len(resp.Result)
+		if resp.Error != nil {
+			info.Error = resp.Error
+		}
+	}
+	h.callMetrics(info)
+}
+
 // updateServeTimeHistogram tracks the serving time of a remote RPC call.
 func updateServeTimeHistogram(method string, success bool, elapsed time.Duration) {
 	note := "success"
@@ -48,3 +95,29 @@ func updateServeTimeHistogram(method string, success bool, elapsed time.Duration
 	}
 	metrics.GetOrRegisterHistogramLazy(h, nil, sampler).Update(elapsed.Nanoseconds())
 }
+
+// reportSizes records the request and response sizes of a method call in the size
+// histograms, and logs the response if it exceeds the large response threshold. The
+// request size is the encoded size of the call message as received. A negative
+// respSize means the size of the response is unknown.
+func (h *handler) reportSizes(msg *jsonrpcMessage, respSize int) {
+	if h.largeResponseLog != 0 && respSize > h.largeResponseLog {
+		h.log.Debug("Large RPC response", "method", msg.Method, "reqid", idForLog{msg.ID}, "size", respSize)
+	}
+	if !metrics.Enabled() || !msg.isCall() {
+		return
+	}
+	sampler := func() metrics.Sample {
+		return metrics.ResettingSample(
+			metrics.NewExpDecaySample(1028, 0.015),
+		)
+	}
+	if msg.size > 0 {
+		req := fmt.Sprintf("%s/%s", requestSizeHistName, msg.Method)
+		metrics.GetOrRegisterHistogramLazy(req, nil, sampler).Update(int64(msg.size))
+	}
+	if respSize >= 0 {
+		resp := fmt.Sprintf("%s/%s", responseSizeHistName, msg.Method)
+		metrics.GetOrRegisterHistogramLazy(resp, nil, sampler).Update(int64(respSize))
+	}
+}
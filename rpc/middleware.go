@@ -0,0 +1,133 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Request is a method call processed by a Handler.
+type Request struct {
+	ID     json.RawMessage // nil for notifications
+	Method string
+	Params json.RawMessage
+}
+
+// Response is the result of a method call. If Error is set, the call failed and
+// Result is ignored. Errors implementing Error and DataError control the error code
+// and data of the response.
+//
+// Result is nil for streamed results. The stream and the version of a conditional
+// request (see VersionedResult) are only sent when the Response returned by the
+// dispatcher is passed on unmodified.
+type Response struct {
+	Result json.RawMessage
+	Error  error
+
+	stream      *streamResponse
+	version     string
+	notModified bool
+}
+
+// Handler processes a method call. The context is the context of the method call
+// and carries the PeerInfo and values set by the connection initializer.
+type Handler func(ctx context.Context, req *Request) *Response
+
+// Use adds a middleware around the dispatch of method calls. The middleware receives
+// the next handler in the chain and returns the handler which replaces it. It can
+// inspect or modify the request and context before calling next, change the response,
+// or short-circuit the call by returning a response without calling next.
+//
+// Middlewares run in the order they were added, i.e. the first middleware is the
+// outermost one and sees the call first. Responses of subscription calls must not be
+// replaced, because the subscription is created when next returns.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) Use(middleware func(next Handler) Handler) {
+	s.middleware = append(s.middleware, middleware)
+}
+
+// SetResponseTransformer sets a function which rewrites the results of successful
+// method calls before they are sent, e.g. to redact fields. The function receives the
+// requested method name and the encoded result and returns the result to send.
+//
+// Error responses are passed through unchanged, use a middleware (see Use) to rewrite
+// errors. The transformer is not applied to streamed results, subscription requests and
+// conditional requests answered as not modified. The version of a conditional request
+// (see VersionedResult) is computed before the transformation.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetResponseTransformer(fn func(method string, result json.RawMessage) json.RawMessage) {
+	s.transformResult = fn
+}
+
+// applyResultTransform applies the response transformer to the answer of a call.
+func (h *handler) applyResultTransform(msg, answer *jsonrpcMessage) {
+	if h.transformResult == nil || answer.Error != nil || answer.stream != nil || answer.NotModified || msg.isSubscribe() {
+		return
+	}
+	answer.Result = h.transformResult(msg.Method, answer.Result)
+	if answer.Result == nil {
+		answer.Result = null
+	}
+}
+
+// handleCallWithMiddleware runs the method call through the middleware chain.
+func (h *handler) handleCallWithMiddleware(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
+	if len(h.middleware) == 0 {
+		return h.handleCall(cp, msg)
+	}
+	var next Handler = func(ctx context.Context, req *Request) *Response {
+		call := *msg
+		call.Method, call.Params = req.Method, req.Params
+		cp.ctx = ctx
+		answer := h.handleCall(cp, &call)
+		if answer.Error != nil {
+			return &Response{Error: answer.Error}
+		}
+		return &Response{
+			Result:      answer.Result,
+			stream:      answer.stream,
+			version:     answer.ResultVersion,
+			notModified: answer.NotModified,
+		}
+	}
+	for i := len(h.middleware) - 1; i >= 0; i-- {
+		next = h.middleware[i](next)
+	}
+	resp := next(cp.ctx, &Request{ID: msg.ID, Method: msg.Method, Params: msg.Params})
+	switch {
+	case resp == nil:
+		return msg.errorResponse(&internalServerError{errcodeDefault, "middleware returned no response"})
+	case resp.Error != nil:
+		return msg.errorResponse(resp.Error)
+	case resp.stream != nil:
+		return &jsonrpcMessage{Version: vsn, ID: msg.ID, stream: resp.stream}
+	}
+	answer := &jsonrpcMessage{Version: vsn, ID: msg.ID, Result: resp.Result}
+	if answer.Result == nil {
+		answer.Result = null
+	}
+	// The result version is only valid for the request it was created for.
+	if msg.IfNoneMatch != nil {
+		answer.ResultVersion, answer.NotModified = resp.version, resp.notModified
+	}
+	return answer
+}
@@ -0,0 +1,187 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type middlewareTestError struct{}
+
+func (middlewareTestError) Error() string  { return "blocked" }
+func (middlewareTestError) ErrorCode() int { return -32001 }
+
+func TestServerMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		server = newTestServer()
+		mu     sync.Mutex
+		trace  []string
+	)
+	record := func(s string) {
+		mu.Lock()
+		defer mu.Unlock()
+		trace = append(trace, s)
+	}
+	defer server.Stop()
+
+	// The first middleware is the outermost one.
+	server.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) *Response {
+			record("outer " + req.Method)
+			if req.Method == "test_blocked" {
+				return &Response{Error: middlewareTestError{}}
+			}
+			resp := next(ctx, req)
+			record("outer done")
+			return resp
+		}
+	})
+	server.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) *Response {
+			record("inner " + req.Method)
+			if req.Method == "test_rewrite" {
+				req.Method, req.Params = "test_repeat", json.RawMessage(`["x", 2]`)
+			}
+			if req.Method == "test_override" {
+				return &Response{Result: json.RawMessage(`"overridden"`)}
+			}
+			return next(ctx, req)
+		}
+	})
+
+	client := DialInProc(server)
+	defer client.Close()
+
+	var result string
+	if err := client.Call(&result, "test_repeat", "a", 3); err != nil || result != "aaa" {
+		t.Fatalf("wrong result %q, err %v", result, err)
+	}
+	if err := client.Call(&result, "test_rewrite"); err != nil || result != "xx" {
+		t.Fatalf("wrong result of rewritten call %q, err %v", result, err)
+	}
+	if err := client.Call(&result, "test_override"); err != nil || result != "overridden" {
+		t.Fatalf("wrong result of overridden call %q, err %v", result, err)
+	}
+	err := client.Call(&result, "test_blocked")
+	var rpcErr Error
+	if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != -32001 || err.Error() != "blocked" {
+		t.Fatalf("wrong error for blocked call: %v", err)
+	}
+	want := []string{
+		"outer test_repeat", "inner test_repeat", "outer done",
+		"outer test_rewrite", "inner test_rewrite", "outer done",
+		"outer test_override", "inner test_override", "outer done",
+		"outer test_blocked",
+	}
+	if !reflect.DeepEqual(trace, want) {
+		t.Fatalf("wrong middleware trace:\n%q\nwant:\n%q", trace, want)
+	}
+}
+
+// Tests that middleware applies to HTTP requests and sees the peer info.
+func TestServerMiddlewareHTTP(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer()
+	defer server.Stop()
+	server.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) *Response {
+			info := PeerInfoFromContext(ctx)
+			if info.Transport != "http" {
+				return &Response{Error: errors.New("wrong transport " + info.Transport)}
+			}
+			return next(ctx, req)
+		}
+	})
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	client, err := DialHTTP(httpsrv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	var result string
+	if err := client.Call(&result, "test_repeat", "a", 2); err != nil || result != "aa" {
+		t.Fatalf("wrong result %q, err %v", result, err)
+	}
+}
+
+func TestServerResponseTransformer(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer()
+	defer server.Stop()
+	var (
+		mu      sync.Mutex
+		methods []string
+	)
+	server.SetResponseTransformer(func(method string, result json.RawMessage) json.RawMessage {
+		mu.Lock()
+		methods = append(methods, method)
+		mu.Unlock()
+		if method != "test_echo" {
+			return result
+		}
+		// Redact the arguments.
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(result, &fields); err != nil {
+			t.Error(err)
+			return result
+		}
+		delete(fields, "Args")
+		enc, _ := json.Marshal(fields)
+		return enc
+	})
+	client := DialInProc(server)
+	defer client.Close()
+
+	var echo map[string]any
+	if err := client.Call(&echo, "test_echo", "x", 1, &echoArgs{S: "secret"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]any{"String": "x", "Int": float64(1)}; !reflect.DeepEqual(echo, want) {
+		t.Fatalf("wrong transformed result %v", echo)
+	}
+	var repeat string
+	if err := client.Call(&repeat, "test_repeat", "a", 2); err != nil || repeat != "aa" {
+		t.Fatalf("wrong result %q, err %v", repeat, err)
+	}
+	// Errors and subscriptions are passed through.
+	if err := client.Call(nil, "test_returnError"); err == nil || err.Error() != (testError{}).Error() {
+		t.Fatalf("wrong error %v", err)
+	}
+	sub, err := client.Subscribe(context.Background(), "nftest", make(chan int), "someSubscription", 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub.Unsubscribe()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"test_echo", "test_repeat", "nftest_unsubscribe"}; !reflect.DeepEqual(methods, want) {
+		t.Fatalf("wrong transformed methods %q", methods)
+	}
+}
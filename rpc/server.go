@@ -18,9 +18,12 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
+	"log/slog"
 	"net"
+	"strings"
 	"sync"
 	"sync/atomic"
 
@@ -54,14 +57,29 @@ type Server struct {
 	batchItemLimit     int
 	batchResponseLimit int
 	httpBodyLimit      int
+	largeResponseLog   int
+	streamResultLimit  int
+	concurrencyLimit   int
+	wsConfig           WebsocketConfig
+	connInit           func(PeerInfo) context.Context
+	callLog            *callLogger
+	batchDisabled      bool
+	middleware         []func(Handler) Handler
+	transformResult    func(method string, result json.RawMessage) json.RawMessage
+	callMetrics        func(CallInfo)
+	subTransfers       *subscriptionTransfers
+
+	ipcAuthorizer IPCAuthorizer
+	ipcFailClosed bool
 }
 
 // NewServer creates a new server instance with no registered handlers.
 func NewServer() *Server {
 	server := &Server{
-		idgen:         randomIDGenerator(),
-		codecs:        make(map[ServerCodec]struct{}),
-		httpBodyLimit: defaultBodyLimit,
+		idgen:             randomIDGenerator(),
+		codecs:            make(map[ServerCodec]struct{}),
+		httpBodyLimit:     defaultBodyLimit,
+		streamResultLimit: defaultStreamLimit,
 	}
 	server.run.Store(true)
 	// Register the default service providing meta information about the RPC service such
@@ -82,6 +100,94 @@ func (s *Server) SetBatchLimits(itemLimit, maxResponseSize int) {
 	s.batchResponseLimit = maxResponseSize
 }
 
+// DisableBatch makes the server reject all batch requests. A batch is answered with a
+// single 'invalid request' error without processing any of its items. Requests which
+// are not part of a batch are served normally.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) DisableBatch() {
+	s.batchDisabled = true
+}
+
+// SetLargeResponseLogThreshold enables debug logging of method call responses which
+// are larger than the given number of bytes, as written to the connection. A threshold
+// of zero disables it.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetLargeResponseLogThreshold(size int) {
+	s.largeResponseLog = size
+}
+
+// SetStreamResultLimit sets the maximum size of method results which are streamed from
+// an io.Reader or RawBytes. Results exceeding the limit are aborted with an error. A limit
+// of zero disables the check.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetStreamResultLimit(limit int) {
+	s.streamResultLimit = limit
+}
+
+// SetConcurrencyLimit sets the maximum number of calls executing concurrently on a
+// single connection. Further calls wait until a running call has finished. Responses
+// are still sent in the order calls complete. A batch counts as a single call because
+// its items are executed one after another. A limit of zero, the default, means no
+// limit.
+//
+// For HTTP, every request is a separate connection.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetConcurrencyLimit(n int) {
+	s.concurrencyLimit = n
+}
+
+// SetConnInitializer installs a callback which is invoked once for every new connection.
+// The values of the returned context, e.g. authentication claims, are available in the
+// context of all method handlers called on the connection. Values set by this package,
+// like the peer info and the client for reverse calls, can't be overridden. The returned
+// context is only used for value lookups, its deadline and cancellation are ignored.
+//
+// For HTTP, every request is a separate connection.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetConnInitializer(init func(info PeerInfo) context.Context) {
+	s.connInit = init
+}
+
+// SetCallLogger enables logging of all method calls, including subscriptions, at the
+// given level. Log entries contain the method name, information about the caller and
+// the call arguments. Arguments of calls into the given namespaces are redacted. This
+// also applies when a method of a redacted namespace is registered under another name.
+// Passing a nil logger disables call logging.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetCallLogger(logger log.Logger, level slog.Level, redactNamespaces []string) {
+	if logger == nil {
+		s.callLog = nil
+		s.services.setRedacted(nil)
+		return
+	}
+	s.callLog = newCallLogger(logger, level, &s.services, redactNamespaces)
+}
+
+// SetIPCAuthorizer installs a callback which is consulted with the peer credentials of
+// every connection accepted by ServeListener on a unix domain socket. Connections which
+// are rejected by the authorizer are closed before any request is processed.
+//
+// On platforms where peer credentials are unavailable, connections are accepted with a
+// warning unless 'failClosed' is set, in which case they are rejected.
+//
+// This method should be called before processing any requests via ServeListener.
+func (s *Server) SetIPCAuthorizer(auth IPCAuthorizer, failClosed bool) {
+	s.ipcAuthorizer = auth
+	s.ipcFailClosed = failClosed
+}
+
 // SetHTTPBodyLimit sets the size limit for HTTP requests.
 //
 // This method should be called before processing any requests via ServeHTTP.
@@ -97,6 +203,38 @@ func (s *Server) RegisterName(name string, receiver interface{}) error {
 	return s.services.registerName(name, receiver)
 }
 
+// RegisterNameUnique is like RegisterName, but returns an error if any method or
+// subscription of the receiver is already registered under the given name. Nothing is
+// registered in that case.
+func (s *Server) RegisterNameUnique(name string, receiver interface{}) error {
+	return s.services.registerNameUnique(name, receiver)
+}
+
+// AliasOption configures an alias registered using RegisterAlias.
+type AliasOption func(*alias)
+
+// WithDeprecationWarning makes the alias log a warning the first time it is called.
+func WithDeprecationWarning() AliasOption {
+	return func(a *alias) { a.warn = true }
+}
+
+// WithModuleListing makes the namespace of the alias appear in the rpc_modules list.
+func WithModuleListing() AliasOption {
+	return func(a *alias) { a.listed = true }
+}
+
+// RegisterAlias makes calls to oldNamespace_oldMethod dispatch to the registered method
+// newNamespace_newMethod. Method names are given as they appear in RPC requests. An error
+// is returned if the target method is not registered, or if a method with the old name
+// exists. Aliases only apply to method calls, not to subscriptions.
+func (s *Server) RegisterAlias(oldNamespace, oldMethod, newNamespace, newMethod string, opts ...AliasOption) error {
+	a := new(alias)
+	for _, opt := range opts {
+		opt(a)
+	}
+	return s.services.registerAlias(oldNamespace+serviceMethodSeparator+oldMethod, newNamespace+serviceMethodSeparator+newMethod, a)
+}
+
 // ServeCodec reads incoming requests from codec, calls the appropriate callback and writes
 // the response back using the given codec. It will block until the codec is closed or the
 // server is stopped. In either case the codec is closed.
@@ -109,17 +247,35 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 		return
 	}
 	defer s.untrackCodec(codec)
+	s.configureCodec(codec)
 
 	cfg := &clientConfig{
 		idgen:              s.idgen,
 		batchItemLimit:     s.batchItemLimit,
 		batchResponseLimit: s.batchResponseLimit,
+		largeResponseLog:   s.largeResponseLog,
+		streamResultLimit:  s.streamResultLimit,
+		concurrencyLimit:   s.concurrencyLimit,
+		connInit:           s.connInit,
+		callLog:            s.callLog,
+		batchDisabled:      s.batchDisabled,
+		middleware:         s.middleware,
+		transformResult:    s.transformResult,
+		callMetrics:        s.callMetrics,
+		subTransfers:       s.subTransfers,
 	}
 	c := initClient(codec, &s.services, cfg)
 	<-codec.closed()
 	c.Close()
 }
 
+// configureCodec applies server settings which are handled by the codec.
+func (s *Server) configureCodec(codec ServerCodec) {
+	if c, ok := codec.(interface{ disableBatch() }); ok && s.batchDisabled {
+		c.disableBatch()
+	}
+}
+
 func (s *Server) trackCodec(codec ServerCodec) bool {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -147,9 +303,17 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 		return
 	}
 
-	h := newHandler(ctx, codec, s.idgen, &s.services, s.batchItemLimit, s.batchResponseLimit)
+	ctx = withConnContext(ctx, s.connInit)
+	h := newHandler(ctx, codec, s.idgen, &s.services, s.batchItemLimit, s.batchResponseLimit, s.largeResponseLog, s.streamResultLimit)
 	h.allowSubscribe = false
+	h.callLog = s.callLog
+	h.batchDisabled = s.batchDisabled
+	h.middleware = s.middleware
+	h.transformResult = s.transformResult
+	h.callMetrics = s.callMetrics
+	h.setConcurrencyLimit(s.concurrencyLimit)
 	defer h.close(io.EOF, nil)
+	s.configureCodec(codec)
 
 	reqs, batch, err := codec.readBatch()
 	if err != nil {
@@ -189,6 +353,9 @@ func (s *Server) Stop() {
 
 	if s.run.CompareAndSwap(true, false) {
 		log.Debug("RPC server shutting down")
+		if s.subTransfers != nil {
+			s.subTransfers.stop(ErrClientQuit)
+		}
 		for codec := range s.codecs {
 			codec.close()
 		}
@@ -210,6 +377,11 @@ func (s *RPCService) Modules() map[string]string {
 	for name := range s.server.services.services {
 		modules[name] = "1.0"
 	}
+	for name, a := range s.server.services.aliases {
+		if ns, _, _ := strings.Cut(name, serviceMethodSeparator); a.listed && modules[ns] == "" {
+			modules[ns] = "1.0"
+		}
+	}
 	return modules
 }
 
@@ -220,7 +392,7 @@ func (s *RPCService) Modules() map[string]string {
 // the current method call.
 type PeerInfo struct {
 	// Transport is name of the protocol used by the client.
-	// This can be "http", "ws" or "ipc".
+	// This can be "http", "ws", "ipc" or "grpc".
 	Transport string
 
 	// Address of client. This will usually contain the IP address and port.
@@ -247,3 +419,31 @@ func PeerInfoFromContext(ctx context.Context) PeerInfo {
 	info, _ := ctx.Value(peerInfoContextKey{}).(PeerInfo)
 	return info
 }
+
+// connContext is the context of a connection with a connection initializer. Values are
+// looked up in the connection context first, then in the context returned by the
+// initializer.
+type connContext struct {
+	context.Context
+	values context.Context
+}
+
+func (c *connContext) Value(key any) any {
+	if v := c.Context.Value(key); v != nil {
+		return v
+	}
+	return c.values.Value(key)
+}
+
+// withConnContext runs the connection initializer and attaches the values of the
+// returned context to ctx.
+func withConnContext(ctx context.Context, init func(PeerInfo) context.Context) context.Context {
+	if init == nil {
+		return ctx
+	}
+	values := init(PeerInfoFromContext(ctx))
+	if values == nil {
+		return ctx
+	}
+	return &connContext{Context: ctx, values: values}
+}
@@ -19,13 +19,25 @@ package rpc
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 )
 
 func TestServerRegisterName(t *testing.T) {
@@ -163,6 +175,250 @@ func TestServerShortLivedConn(t *testing.T) {
 	}
 }
 
+func TestServerIPCAuthorizer(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("peer credentials are only supported on linux")
+	}
+	t.Parallel()
+
+	for _, allow := range []bool{true, false} {
+		server := newTestServer()
+		seen := make(chan PeerCredentials, 1)
+		server.SetIPCAuthorizer(func(cred PeerCredentials) bool {
+			seen <- cred
+			return allow
+		}, true)
+
+		endpoint := filepath.Join(t.TempDir(), "geth.ipc")
+		listener, err := ipcListen(endpoint)
+		if err != nil {
+			t.Fatal("can't listen:", err)
+		}
+		go server.ServeListener(listener)
+
+		client, err := DialIPC(context.Background(), endpoint)
+		if err != nil {
+			t.Fatal("can't dial:", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		var result map[string]string
+		err = client.CallContext(ctx, &result, "rpc_modules")
+		cancel()
+		if allow && err != nil {
+			t.Fatalf("authorized call failed: %v", err)
+		}
+		if !allow && err == nil {
+			t.Fatal("unauthorized call succeeded")
+		}
+		if cred := <-seen; cred.UID != uint32(os.Getuid()) || cred.PID != int32(os.Getpid()) {
+			t.Fatalf("wrong peer credentials: %+v", cred)
+		}
+		client.Close()
+		listener.Close()
+		server.Stop()
+	}
+}
+
+type connValueKey struct{}
+
+type connValueService struct{}
+
+func (s *connValueService) Value(ctx context.Context) (string, error) {
+	v, _ := ctx.Value(connValueKey{}).(string)
+	if info := PeerInfoFromContext(ctx); info.Transport == "" {
+		return "", fmt.Errorf("peer info overridden")
+	}
+	return v, nil
+}
+
+func (s *connValueService) HasClient(ctx context.Context) bool {
+	_, ok := ClientFromContext(ctx)
+	return ok
+}
+
+func TestServerConnInitializer(t *testing.T) {
+	t.Parallel()
+
+	var count atomic.Int32
+	server := NewServer()
+	defer server.Stop()
+	server.SetConnInitializer(func(info PeerInfo) context.Context {
+		ctx := context.WithValue(context.Background(), connValueKey{}, fmt.Sprintf("%s-%d", info.Transport, count.Add(1)))
+		return context.WithValue(ctx, peerInfoContextKey{}, PeerInfo{})
+	})
+	if err := server.RegisterName("conn", new(connValueService)); err != nil {
+		t.Fatal(err)
+	}
+	call := func(c *Client, method string, result any) {
+		t.Helper()
+		if err := c.Call(result, method); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The values are kept per connection.
+	c1, c2 := DialInProc(server), DialInProc(server)
+	defer c1.Close()
+	defer c2.Close()
+	var v1, v2, v3 string
+	call(c1, "conn_value", &v1)
+	call(c2, "conn_value", &v2)
+	call(c1, "conn_value", &v3)
+	if v1 == "" || v2 == "" || v1 == v2 || v1 != v3 {
+		t.Fatalf("wrong connection values: %q %q %q", v1, v2, v3)
+	}
+	var hasClient bool
+	call(c1, "conn_hasClient", &hasClient)
+	if !hasClient {
+		t.Fatal("client missing from context")
+	}
+
+	// Every HTTP request is a new connection.
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+	hc, err := DialHTTP(httpsrv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer hc.Close()
+	call(hc, "conn_value", &v1)
+	call(hc, "conn_value", &v2)
+	if !strings.HasPrefix(v1, "http-") || v1 == v2 {
+		t.Fatalf("wrong HTTP connection values: %q %q", v1, v2)
+	}
+}
+
+func TestServerCallLogger(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("nftest", new(notificationTestService)); err != nil {
+		t.Fatal(err)
+	}
+	// The same service is reachable through two namespaces.
+	if err := server.RegisterName("secret", new(testService)); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.RegisterName("alias", new(testService)); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	server.SetCallLogger(log.NewLogger(log.JSONHandler(&buf)), log.LevelInfo, []string{"secret"})
+
+	client := DialInProc(server)
+	defer client.Close()
+	var (
+		str string
+		num int
+	)
+	if err := client.Call(&num, "nftest_echo", 42); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Call(&str, "secret_repeat", "hunter2", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Call(&str, "alias_repeat", "hunter2", 1); err != nil {
+		t.Fatal(err)
+	}
+	// Methods added to a redacted namespace after enabling the logger are covered
+	// as well, also under their other names.
+	if err := server.RegisterName("secret", new(notificationTestService)); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Call(&num, "nftest_echo", 42); err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []map[string]any
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var entry map[string]any
+		if err := dec.Decode(&entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	want := []struct{ method, args string }{
+		{"nftest_echo", "[42]"},
+		{"secret_repeat", redactedArgs},
+		{"alias_repeat", redactedArgs},
+		{"nftest_echo", redactedArgs},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("wrong number of log entries: have %d, want %d", len(entries), len(want))
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e["method"] != w.method || e["args"] != w.args || e["transport"] != "ipc" {
+			t.Errorf("wrong log entry %d: %v", i, e)
+		}
+	}
+}
+
+func TestServerDisableBatch(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer()
+	defer server.Stop()
+	server.DisableBatch()
+
+	var (
+		batch  = `[{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["x",1]},{"jsonrpc":"2.0","id":2,"method":"rpc_modules"}]`
+		single = `{"jsonrpc":"2.0","id":3,"method":"test_echo","params":["x",1]}`
+	)
+	check := func(transport, batchResp, singleResp string) {
+		t.Helper()
+		var resp jsonrpcMessage
+		if err := json.Unmarshal([]byte(batchResp), &resp); err != nil {
+			t.Fatalf("%s: invalid batch response %q: %v", transport, batchResp, err)
+		}
+		if resp.Error == nil || resp.Error.Code != -32600 || resp.Error.Message != errMsgBatchDisabled {
+			t.Errorf("%s: wrong batch response %s", transport, batchResp)
+		}
+		resp = jsonrpcMessage{}
+		if err := json.Unmarshal([]byte(singleResp), &resp); err != nil {
+			t.Fatalf("%s: invalid response %q: %v", transport, singleResp, err)
+		}
+		if resp.Error != nil || string(resp.ID) != "3" {
+			t.Errorf("%s: wrong response %s", transport, singleResp)
+		}
+	}
+
+	// Stream connection.
+	p1, p2 := net.Pipe()
+	defer p2.Close()
+	go server.ServeCodec(NewCodec(p1), 0)
+	p2.SetDeadline(time.Now().Add(5 * time.Second))
+	reader := bufio.NewReader(p2)
+	var resps []string
+	for _, req := range []string{batch, single} {
+		if _, err := io.WriteString(p2, req+"\n"); err != nil {
+			t.Fatal(err)
+		}
+		resp, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		resps = append(resps, resp)
+	}
+	check("ipc", resps[0], resps[1])
+
+	// HTTP.
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+	post := func(body string) string {
+		resp, err := http.Post(httpsrv.URL, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return string(data)
+	}
+	check("http", post(batch), post(single))
+}
+
 func TestServerBatchResponseSizeLimit(t *testing.T) {
 	t.Parallel()
 
@@ -202,3 +458,251 @@ func TestServerBatchResponseSizeLimit(t *testing.T) {
 		}
 	}
 }
+
+type concurrencyTestService struct {
+	running, peak atomic.Int32
+}
+
+func (s *concurrencyTestService) Slow() {
+	n := s.running.Add(1)
+	for {
+		peak := s.peak.Load()
+		if n <= peak || s.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	s.running.Add(-1)
+}
+
+func TestServerConcurrencyLimit(t *testing.T) {
+	t.Parallel()
+
+	const limit = 4
+	var (
+		server  = NewServer()
+		service = new(concurrencyTestService)
+	)
+	defer server.Stop()
+	server.SetConcurrencyLimit(limit)
+	if err := server.RegisterName("conc", service); err != nil {
+		t.Fatal(err)
+	}
+	client := DialInProc(server)
+	defer client.Close()
+
+	// Send many calls on the connection at once.
+	var (
+		errc  = make(chan error, 1000)
+		batch = make([]BatchElem, 100)
+	)
+	for i := 0; i < cap(errc); i++ {
+		go func() { errc <- client.Call(nil, "conc_slow") }()
+	}
+	for i := range batch {
+		batch[i] = BatchElem{Method: "conc_slow", Result: new(any)}
+	}
+	if err := client.BatchCall(batch); err != nil {
+		t.Fatal("error sending batch:", err)
+	}
+	for i := range batch {
+		if batch[i].Error != nil {
+			t.Fatalf("batch elem %d has unexpected error: %v", i, batch[i].Error)
+		}
+	}
+	for i := 0; i < cap(errc); i++ {
+		if err := <-errc; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if peak := service.peak.Load(); peak > limit {
+		t.Fatalf("%d calls executed concurrently, limit is %d", peak, limit)
+	} else if peak < 2 {
+		t.Fatal("calls were not executed concurrently")
+	}
+}
+
+func TestServerCallMetrics(t *testing.T) {
+	t.Parallel()
+
+	var (
+		server = newTestServer()
+		mu     sync.Mutex
+		calls  = make(map[string]CallInfo)
+		count  int
+	)
+	defer server.Stop()
+	server.WithMetrics(func(info CallInfo) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls[info.Method] = info
+		count++
+	})
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+	client, err := DialHTTP(httpsrv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	batch := []BatchElem{
+		{Method: "test_echo", Args: []any{"x", 1}, Result: new(echoResult)},
+		{Method: "test_returnError", Result: new(any)},
+		{Method: "test_notExist", Result: new(any)},
+	}
+	if err := client.BatchCall(batch); err != nil {
+		t.Fatal("error sending batch:", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if count != len(batch) {
+		t.Fatalf("wrong number of callbacks: got %d, want %d", count, len(batch))
+	}
+	for _, elem := range batch {
+		info, ok := calls[elem.Method]
+		if !ok {
+			t.Fatalf("no callback for %s", elem.Method)
+		}
+		if info.Transport != "http" {
+			t.Errorf("%s: wrong transport %q", elem.Method, info.Transport)
+		}
+		if (info.Error != nil) != (elem.Error != nil) {
+			t.Errorf("%s: wrong error %v, call error is %v", elem.Method, info.Error, elem.Error)
+		}
+	}
+	if info := calls["test_echo"]; info.ReqBytes == 0 || info.RespBytes == 0 {
+		t.Errorf("test_echo: missing request/response size: %+v", info)
+	}
+	if info := calls["test_returnError"]; info.RespBytes != 0 {
+		t.Errorf("test_returnError: non-zero response size %d", info.RespBytes)
+	}
+}
+
+func TestServerRegisterAlias(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer()
+	defer server.Stop()
+	if err := server.RegisterAlias("old", "echo", "test", "echo", WithDeprecationWarning()); err != nil {
+		t.Fatal(err)
+	}
+	client := DialInProc(server)
+	defer client.Close()
+
+	// Alias and canonical name return the same result.
+	var canonical, aliased echoResult
+	if err := client.Call(&canonical, "test_echo", "x", 3, &echoArgs{S: "y"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Call(&aliased, "old_echo", "x", 3, &echoArgs{S: "y"}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(canonical, aliased) {
+		t.Fatalf("alias result %v differs from canonical result %v", aliased, canonical)
+	}
+
+	// Unknown aliases are reported as missing methods.
+	err := client.Call(nil, "old_repeat")
+	if err == nil || err.Error() != (&methodNotFoundError{method: "old_repeat"}).Error() {
+		t.Fatalf("wrong error for unknown alias: %v", err)
+	}
+
+	// Only the listed alias namespace appears in rpc_modules.
+	var modules map[string]string
+	if err := client.Call(&modules, "rpc_modules"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := modules["old"]; ok {
+		t.Error("unlisted alias namespace in rpc_modules")
+	}
+	if err := server.RegisterAlias("listed", "echo", "test", "echo", WithModuleListing()); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Call(&modules, "rpc_modules"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := modules["listed"]; !ok {
+		t.Error("listed alias namespace missing in rpc_modules")
+	}
+
+	// Registration errors.
+	if err := server.RegisterAlias("old", "x", "test", "notExist"); err == nil {
+		t.Error("no error for alias of unknown method")
+	}
+	if err := server.RegisterAlias("test", "echo", "test", "repeat"); err == nil {
+		t.Error("no error for alias shadowing registered method")
+	}
+}
+
+// syncBuffer is a bytes.Buffer which can be written concurrently.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// This test checks that the size histograms and the large response log measure the
+// bytes sent over the connection, including streamed results.
+func TestServerSizeMetrics(t *testing.T) {
+	// Not parallel because the metrics switch and the default logger are global.
+	metrics.Enable()
+	var logs syncBuffer
+	defer log.SetDefault(log.Root())
+	log.SetDefault(log.NewLogger(log.JSONHandler(&logs)))
+
+	server := newStreamTestServer(0)
+	defer server.Stop()
+	if err := server.RegisterName("sizetest", new(testService)); err != nil {
+		t.Fatal(err)
+	}
+	server.SetLargeResponseLogThreshold(1000)
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	tests := []struct {
+		method, params string
+		logged         bool
+	}{
+		{"sizetest_echo", `["x",1]`, false},
+		{"sizetest_repeat", `["x",2000]`, true},
+		{"stream_data", `[2000]`, true},
+	}
+	for _, test := range tests {
+		req := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":%q,"params":%s}`, test.method, test.params)
+		resp, err := http.Post(httpsrv.URL, "application/json", strings.NewReader(req))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reqHist := metrics.GetOrRegisterHistogram(requestSizeHistName+"/"+test.method, nil, nil).Snapshot()
+		if reqHist.Count() != 1 || reqHist.Max() != int64(len(req)) {
+			t.Errorf("%s: wrong request size histogram: count %d, max %d, want %d", test.method, reqHist.Count(), reqHist.Max(), len(req))
+		}
+		respHist := metrics.GetOrRegisterHistogram(responseSizeHistName+"/"+test.method, nil, nil).Snapshot()
+		if respHist.Count() != 1 || respHist.Max() != int64(len(body)) {
+			t.Errorf("%s: wrong response size histogram: count %d, max %d, want %d", test.method, respHist.Count(), respHist.Max(), len(body))
+		}
+		want := fmt.Sprintf(`"method":%q,"reqid":"1","size":%d}`, test.method, len(body))
+		if logged := strings.Contains(logs.String(), want); logged != test.logged {
+			t.Errorf("%s: large response logged %t, want %t\n%s", test.method, logged, test.logged, logs.String())
+		}
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"unicode"
 
 	"github.com/ethereum/go-ethereum/log"
@@ -38,6 +39,18 @@ var (
 type serviceRegistry struct {
 	mu       sync.Mutex
 	services map[string]service
+	aliases  map[string]*alias // alternative method names, keyed by full name
+
+	redactNS  map[string]struct{}                  // namespaces whose call arguments are redacted
+	redactFns atomic.Pointer[map[uintptr]struct{}] // functions of all methods in redactNS
+}
+
+// alias is an alternative name of a registered method.
+type alias struct {
+	target string      // full name of the method
+	listed bool        // whether the alias namespace appears in rpc_modules
+	warn   bool        // whether a deprecation warning is logged on first use
+	warned atomic.Bool // set when the warning was logged
 }
 
 // service represents a registered object.
@@ -55,9 +68,21 @@ type callback struct {
 	hasCtx      bool           // method's first argument is a context (not included in argTypes)
 	errPos      int            // err return idx, of -1 when method cannot return error
 	isSubscribe bool           // true if this is a subscription callback
+	isStream    bool           // true if the result is streamed into the response
+	isArray     bool           // true if the streamed result is a ResultStream
 }
 
 func (r *serviceRegistry) registerName(name string, rcvr interface{}) error {
+	return r.register(name, rcvr, false)
+}
+
+// registerNameUnique is like registerName, but fails without registering anything if
+// any of the receiver's methods or subscriptions already exists under the given name.
+func (r *serviceRegistry) registerNameUnique(name string, rcvr interface{}) error {
+	return r.register(name, rcvr, true)
+}
+
+func (r *serviceRegistry) register(name string, rcvr interface{}, unique bool) error {
 	rcvrVal := reflect.ValueOf(rcvr)
 	if name == "" {
 		return fmt.Errorf("no service name for type %s", rcvrVal.Type().String())
@@ -72,6 +97,17 @@ func (r *serviceRegistry) registerName(name string, rcvr interface{}) error {
 	if r.services == nil {
 		r.services = make(map[string]service)
 	}
+	if unique {
+		svc := r.services[name]
+		for method, cb := range callbacks {
+			if cb.isSubscribe && svc.subscriptions[method] != nil {
+				return fmt.Errorf("subscription %s%s%s is already registered", name, serviceMethodSeparator, method)
+			}
+			if !cb.isSubscribe && svc.callbacks[method] != nil {
+				return fmt.Errorf("method %s%s%s is already registered", name, serviceMethodSeparator, method)
+			}
+		}
+	}
 	svc, ok := r.services[name]
 	if !ok {
 		svc = service{
@@ -88,18 +124,65 @@ func (r *serviceRegistry) registerName(name string, rcvr interface{}) error {
 			svc.callbacks[name] = cb
 		}
 	}
+	if len(r.redactNS) > 0 {
+		r.updateRedacted()
+	}
+	return nil
+}
+
+func (r *serviceRegistry) registerAlias(name, target string, a *alias) error {
+	oldNS, oldMethod, found := strings.Cut(name, serviceMethodSeparator)
+	if !found || oldNS == "" || oldMethod == "" {
+		return fmt.Errorf("invalid alias name %q", name)
+	}
+	newNS, newMethod, _ := strings.Cut(target, serviceMethodSeparator)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.services[newNS].callbacks[newMethod] == nil {
+		return fmt.Errorf("method %s is not registered", target)
+	}
+	if r.services[oldNS].callbacks[oldMethod] != nil {
+		return fmt.Errorf("method %s is already registered", name)
+	}
+	if r.aliases == nil {
+		r.aliases = make(map[string]*alias)
+	}
+	a.target = target
+	r.aliases[name] = a
 	return nil
 }
 
 // callback returns the callback corresponding to the given RPC method name.
 func (r *serviceRegistry) callback(method string) *callback {
+	cb, a := r.lookupCallback(method)
+	if a != nil && a.warn && a.warned.CompareAndSwap(false, true) {
+		log.Warn("Deprecated RPC method called", "method", method, "replacement", a.target)
+	}
+	return cb
+}
+
+// lookupCallback resolves a method name to its callback. If the method was found
+// through an alias, the alias is returned as well.
+func (r *serviceRegistry) lookupCallback(method string) (*callback, *alias) {
 	before, after, found := strings.Cut(method, serviceMethodSeparator)
 	if !found {
-		return nil
+		return nil, nil
 	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	return r.services[before].callbacks[after]
+	if cb := r.services[before].callbacks[after]; cb != nil {
+		return cb, nil
+	}
+	a := r.aliases[method]
+	if a == nil {
+		return nil, nil
+	}
+	before, after, _ = strings.Cut(a.target, serviceMethodSeparator)
+	if cb := r.services[before].callbacks[after]; cb != nil {
+		return cb, a
+	}
+	return nil, nil
 }
 
 // subscription returns a subscription callback in the given service.
@@ -109,6 +192,44 @@ func (r *serviceRegistry) subscription(service, name string) *callback {
 	return r.services[service].subscriptions[name]
 }
 
+// setRedacted sets the namespaces whose methods have their call arguments redacted.
+func (r *serviceRegistry) setRedacted(namespaces []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.redactNS = make(map[string]struct{}, len(namespaces))
+	for _, ns := range namespaces {
+		r.redactNS[ns] = struct{}{}
+	}
+	r.updateRedacted()
+}
+
+// updateRedacted recomputes the set of redacted functions. It must be called with
+// r.mu held whenever the redacted namespaces or their methods change.
+func (r *serviceRegistry) updateRedacted() {
+	fns := make(map[uintptr]struct{})
+	for ns := range r.redactNS {
+		svc := r.services[ns]
+		for _, cb := range svc.callbacks {
+			fns[cb.fn.Pointer()] = struct{}{}
+		}
+		for _, cb := range svc.subscriptions {
+			fns[cb.fn.Pointer()] = struct{}{}
+		}
+	}
+	r.redactFns.Store(&fns)
+}
+
+// isRedacted reports whether a callback or subscription with the given function is
+// registered in any of the redacted namespaces. It doesn't take the registry lock.
+func (r *serviceRegistry) isRedacted(fn uintptr) bool {
+	fns := r.redactFns.Load()
+	if fns == nil {
+		return false
+	}
+	_, ok := (*fns)[fn]
+	return ok
+}
+
 // suitableCallbacks iterates over the methods of the given type. It determines if a method
 // satisfies the criteria for an RPC callback or a subscription callback and adds it to the
 // collection of callbacks. See server documentation for a summary of these criteria.
@@ -157,6 +278,8 @@ func newCallback(receiver, fn reflect.Value) *callback {
 		}
 		c.errPos = 1
 	}
+	c.isStream = len(outs) > 0 && c.errPos != 0 && isStreamResult(outs[0])
+	c.isArray = c.isStream && outs[0] == resultStreamType
 	return c
 }
 
@@ -0,0 +1,288 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// defaultStreamLimit is the default maximum size of a streamed method result.
+const defaultStreamLimit = 128 * 1024 * 1024
+
+var (
+	readerType       = reflect.TypeOf((*io.Reader)(nil)).Elem()
+	rawBytesType     = reflect.TypeOf(RawBytes{})
+	resultStreamType = reflect.TypeOf((*ResultStream)(nil)).Elem()
+)
+
+// RawBytes is a method result which is streamed into the response instead of being
+// encoded in memory. Methods declaring an io.Reader result are treated the same way as
+// methods returning RawBytes with the default hex encoding.
+//
+// If the reader implements io.Closer, it is closed after the result has been written.
+type RawBytes struct {
+	Reader io.Reader
+	Base64 bool // encode the result as a base64 string instead of 0x-prefixed hex
+}
+
+// ResultStream is a method result which is streamed into the response as a JSON array,
+// encoding one element at a time. Clients receive a regular array result. Methods must
+// declare ResultStream as their result type to stream it. A nil stream is encoded as
+// JSON null.
+//
+// If the stream fails before any part of the response has been sent, an error
+// response is sent instead. Otherwise, the connection is closed. Elements are only
+// streamed if the response is the only message written, results in batches are
+// buffered in memory. If the stream implements io.Closer, it is closed after the
+// result has been written.
+type ResultStream interface {
+	// Next returns the next element of the result. It returns io.EOF after the
+	// last element.
+	Next() (any, error)
+}
+
+// streamResponse is the response to a call of a method with a streamed result.
+//
+// Codecs which know about streamed responses write them directly to the connection
+// using writeTo. All other codecs fall back to encoding the response in memory via
+// MarshalJSON.
+type streamResponse struct {
+	id     json.RawMessage
+	data   RawBytes
+	elems  ResultStream // set instead of data for array results
+	array  bool         // true if the result is an array
+	limit  int          // maximum number of result bytes, zero means unlimited
+	closer sync.Once
+}
+
+// isStreamResult reports whether t is a result type which is streamed.
+func isStreamResult(t reflect.Type) bool {
+	return t == readerType || t == rawBytesType || t == resultStreamType
+}
+
+// newStreamResponse creates the response to msg for the result of a streaming method.
+// If array is true, the result is a ResultStream.
+func newStreamResponse(msg *jsonrpcMessage, result interface{}, array bool, limit int) *streamResponse {
+	s := &streamResponse{id: msg.ID, array: array, limit: limit}
+	if array {
+		s.elems, _ = result.(ResultStream)
+		return s
+	}
+	switch r := result.(type) {
+	case RawBytes:
+		s.data = r
+	case io.Reader:
+		s.data = RawBytes{Reader: r}
+	}
+	return s
+}
+
+// close releases the reader of the result. It is safe to call close multiple times.
+func (s *streamResponse) close() {
+	s.closer.Do(func() {
+		if c, ok := s.data.Reader.(io.Closer); ok {
+			c.Close()
+		}
+		if c, ok := s.elems.(io.Closer); ok {
+			c.Close()
+		}
+	})
+}
+
+// tooLarge reports whether the result is known to exceed the size limit before
+// reading it. This is only possible for readers which report their length.
+func (s *streamResponse) tooLarge() bool {
+	l, ok := s.data.Reader.(interface{ Len() int })
+	return ok && s.limit != 0 && l.Len() > s.limit
+}
+
+// reader returns the result reader, limited to one byte more than the allowed size so
+// oversized results can be detected.
+func (s *streamResponse) reader() io.Reader {
+	if s.data.Reader == nil {
+		return strings.NewReader("")
+	}
+	if s.limit == 0 {
+		return s.data.Reader
+	}
+	return io.LimitReader(s.data.Reader, int64(s.limit)+1)
+}
+
+// writeTo streams the JSON encoding of the response into w. The response is written
+// incrementally, so an error returned by this method may leave a partial message in w.
+func (s *streamResponse) writeTo(w io.Writer) error {
+	defer s.close()
+	if s.array {
+		return s.writeArrayTo(w)
+	}
+
+	buf := bufio.NewWriter(w)
+	buf.WriteString(`{"jsonrpc":"` + vsn + `","id":`)
+	buf.Write(s.id)
+	buf.WriteString(`,"result":"`)
+
+	var (
+		n   int64
+		err error
+	)
+	if s.data.Base64 {
+		enc := base64.NewEncoder(base64.StdEncoding, buf)
+		if n, err = io.Copy(enc, s.reader()); err == nil {
+			err = enc.Close()
+		}
+	} else {
+		buf.WriteString("0x")
+		n, err = io.Copy(hex.NewEncoder(buf), s.reader())
+	}
+	if err != nil {
+		return err
+	}
+	if s.limit != 0 && n > int64(s.limit) {
+		return &internalServerError{errcodeResponseTooLarge, errMsgResponseTooLarge}
+	}
+	buf.WriteString("\"}\n")
+	return buf.Flush()
+}
+
+// writeArrayTo streams the response for an array result into w. If the result stream
+// fails before anything was written to w, the error response is written instead.
+func (s *streamResponse) writeArrayTo(w io.Writer) error {
+	var (
+		cw   = &countingWriter{w: w}
+		buf  = bufio.NewWriter(cw)
+		size int
+	)
+	buf.WriteString(`{"jsonrpc":"` + vsn + `","id":`)
+	buf.Write(s.id)
+	buf.WriteString(`,"result":`)
+	err := s.encodeArray(func(b []byte) error {
+		if size += len(b); s.limit != 0 && size > s.limit {
+			return &internalServerError{errcodeResponseTooLarge, errMsgResponseTooLarge}
+		}
+		_, err := buf.Write(b)
+		return err
+	})
+	if err != nil {
+		if cw.n > 0 {
+			return err
+		}
+		resp := errorMessage(err)
+		resp.ID = s.id
+		return json.NewEncoder(w).Encode(resp)
+	}
+	buf.WriteString("}\n")
+	return buf.Flush()
+}
+
+// encodeArray encodes the elements of the result stream as a JSON array, passing the
+// output to write piece by piece.
+func (s *streamResponse) encodeArray(write func([]byte) error) error {
+	if s.elems == nil {
+		return write([]byte("null"))
+	}
+	sep := []byte{'['}
+	for {
+		elem, err := s.elems.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		enc, err := json.Marshal(elem)
+		if err != nil {
+			return &internalServerError{errcodeMarshalError, err.Error()}
+		}
+		if err := write(sep); err != nil {
+			return err
+		}
+		if err := write(enc); err != nil {
+			return err
+		}
+		sep = []byte{','}
+	}
+	if sep[0] == '[' {
+		// The stream was empty.
+		return write([]byte("[]"))
+	}
+	return write([]byte{']'})
+}
+
+// result reads the entire result into memory and returns its JSON encoding. This is
+// used when the response can't be streamed, i.e. in batches.
+func (s *streamResponse) result() (json.RawMessage, error) {
+	defer s.close()
+	if s.array {
+		var (
+			buf  bytes.Buffer
+			size int
+		)
+		err := s.encodeArray(func(b []byte) error {
+			if size += len(b); s.limit != 0 && size > s.limit {
+				return &internalServerError{errcodeResponseTooLarge, errMsgResponseTooLarge}
+			}
+			buf.Write(b)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	data, err := io.ReadAll(s.reader())
+	if err != nil {
+		return nil, err
+	}
+	if s.limit != 0 && len(data) > s.limit {
+		return nil, &internalServerError{errcodeResponseTooLarge, errMsgResponseTooLarge}
+	}
+	if s.data.Base64 {
+		return json.Marshal(data)
+	}
+	return json.Marshal(hexutil.Bytes(data))
+}
+
+// MarshalJSON encodes the complete response in memory.
+func (s *streamResponse) MarshalJSON() ([]byte, error) {
+	result, err := s.result()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&jsonrpcMessage{Version: vsn, ID: s.id, Result: result})
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (cw *countingWriter) Write(b []byte) (int, error) {
+	n, err := cw.w.Write(b)
+	cw.n += n
+	return n, err
+}
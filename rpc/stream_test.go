@@ -0,0 +1,338 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+type streamService struct{}
+
+// streamData returns n bytes of test data.
+func streamData(n int) []byte {
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	return data
+}
+
+func (s *streamService) Data(n int) io.Reader {
+	return bytes.NewReader(streamData(n))
+}
+
+func (s *streamService) Base64(n int) RawBytes {
+	return RawBytes{Reader: bytes.NewReader(streamData(n)), Base64: true}
+}
+
+// Unsized returns a reader which doesn't report its length.
+func (s *streamService) Unsized(n int) (io.Reader, error) {
+	return io.LimitReader(bytes.NewReader(streamData(n)), int64(n)), nil
+}
+
+// intStream yields the integers 0 to n-1. If fail is non-zero, the stream fails
+// after yielding fail elements.
+type intStream struct {
+	next, n, fail int
+}
+
+func (s *intStream) Next() (any, error) {
+	switch {
+	case s.fail != 0 && s.next == s.fail:
+		return nil, errors.New("stream failed")
+	case s.next == s.n:
+		return nil, io.EOF
+	}
+	s.next++
+	return s.next - 1, nil
+}
+
+func (s *streamService) Ints(n int) ResultStream {
+	return &intStream{n: n}
+}
+
+func (s *streamService) FailingInts(n, fail int) ResultStream {
+	return &intStream{n: n, fail: fail}
+}
+
+func (s *streamService) NilInts() ResultStream {
+	return nil
+}
+
+func newStreamTestServer(limit int) *Server {
+	server := NewServer()
+	server.SetStreamResultLimit(limit)
+	if err := server.RegisterName("stream", new(streamService)); err != nil {
+		panic(err)
+	}
+	return server
+}
+
+func TestStreamResult(t *testing.T) {
+	t.Parallel()
+
+	server := newStreamTestServer(0)
+	defer server.Stop()
+	var (
+		httpsrv = httptest.NewServer(server)
+		wssrv   = httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	)
+	defer httpsrv.Close()
+	defer wssrv.Close()
+
+	clients := map[string]func() (*Client, error){
+		"inproc": func() (*Client, error) { return DialInProc(server), nil },
+		"http":   func() (*Client, error) { return DialHTTP(httpsrv.URL) },
+		"ws": func() (*Client, error) {
+			return DialWebsocket(context.Background(), "ws:"+strings.TrimPrefix(wssrv.URL, "http:"), "")
+		},
+	}
+	for name, dial := range clients {
+		client, err := dial()
+		if err != nil {
+			t.Fatalf("%s: can't dial: %v", name, err)
+		}
+		for _, n := range []int{0, 100, 1024 * 1024} {
+			var hexres hexutil.Bytes
+			if err := client.Call(&hexres, "stream_data", n); err != nil {
+				t.Fatalf("%s: call failed: %v", name, err)
+			}
+			if !bytes.Equal(hexres, streamData(n)) {
+				t.Fatalf("%s: wrong hex result for %d bytes", name, n)
+			}
+			var b64res []byte
+			if err := client.Call(&b64res, "stream_base64", n); err != nil {
+				t.Fatalf("%s: call failed: %v", name, err)
+			}
+			if !bytes.Equal(b64res, streamData(n)) {
+				t.Fatalf("%s: wrong base64 result for %d bytes", name, n)
+			}
+		}
+		// Streamed results are buffered in batches.
+		var res1, res2 hexutil.Bytes
+		batch := []BatchElem{
+			{Method: "stream_data", Args: []any{10}, Result: &res1},
+			{Method: "stream_unsized", Args: []any{20}, Result: &res2},
+		}
+		if err := client.BatchCall(batch); err != nil {
+			t.Fatalf("%s: batch failed: %v", name, err)
+		}
+		if batch[0].Error != nil || batch[1].Error != nil {
+			t.Fatalf("%s: batch element failed: %v, %v", name, batch[0].Error, batch[1].Error)
+		}
+		if !bytes.Equal(res1, streamData(10)) || !bytes.Equal(res2, streamData(20)) {
+			t.Fatalf("%s: wrong batch results", name)
+		}
+		client.Close()
+	}
+}
+
+func TestStreamResultHTTPChunked(t *testing.T) {
+	t.Parallel()
+
+	server := newStreamTestServer(0)
+	defer server.Stop()
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"stream_data","params":[1048576]}`
+	resp, err := http.Post(httpsrv.URL, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if len(resp.TransferEncoding) == 0 || resp.TransferEncoding[0] != "chunked" {
+		t.Fatalf("response not chunked, transfer encoding %v", resp.TransferEncoding)
+	}
+	var msg jsonrpcMessage
+	if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+		t.Fatal(err)
+	}
+	var result hexutil.Bytes
+	if err := result.UnmarshalJSON(msg.Result); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(result, streamData(1048576)) {
+		t.Fatal("wrong result")
+	}
+}
+
+func TestStreamResultLimit(t *testing.T) {
+	t.Parallel()
+
+	server := newStreamTestServer(100)
+	defer server.Stop()
+
+	client := DialInProc(server)
+	defer client.Close()
+
+	var result hexutil.Bytes
+	if err := client.Call(&result, "stream_data", 100); err != nil {
+		t.Fatalf("call within limit failed: %v", err)
+	}
+	// Results of known length are rejected before streaming.
+	err := client.Call(&result, "stream_data", 101)
+	if re, ok := err.(Error); !ok || re.ErrorCode() != errcodeResponseTooLarge {
+		t.Fatalf("wrong error for oversized result: %v", err)
+	}
+	// Other results abort the response.
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+	httpclient, err := DialHTTP(httpsrv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer httpclient.Close()
+	if err := httpclient.Call(&result, "stream_unsized", 101); err == nil {
+		t.Fatal("no error for oversized unsized result")
+	}
+}
+
+func TestStreamResultArray(t *testing.T) {
+	t.Parallel()
+
+	server := newStreamTestServer(0)
+	defer server.Stop()
+	var (
+		httpsrv = httptest.NewServer(server)
+		wssrv   = httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	)
+	defer httpsrv.Close()
+	defer wssrv.Close()
+
+	clients := map[string]func() (*Client, error){
+		"inproc": func() (*Client, error) { return DialInProc(server), nil },
+		"http":   func() (*Client, error) { return DialHTTP(httpsrv.URL) },
+		"ws": func() (*Client, error) {
+			return DialWebsocket(context.Background(), "ws:"+strings.TrimPrefix(wssrv.URL, "http:"), "")
+		},
+	}
+	for name, dial := range clients {
+		client, err := dial()
+		if err != nil {
+			t.Fatalf("%s: can't dial: %v", name, err)
+		}
+		for _, n := range []int{0, 1, 1000000} {
+			var res []int
+			if err := client.Call(&res, "stream_ints", n); err != nil {
+				t.Fatalf("%s: call failed: %v", name, err)
+			}
+			if len(res) != n {
+				t.Fatalf("%s: wrong result length %d, want %d", name, len(res), n)
+			}
+			for i := range res {
+				if res[i] != i {
+					t.Fatalf("%s: wrong result element %d: %d", name, i, res[i])
+				}
+			}
+		}
+		var raw json.RawMessage
+		if err := client.Call(&raw, "stream_nilInts"); err != nil || string(raw) != "null" {
+			t.Fatalf("%s: wrong result for nil stream: %s, %v", name, raw, err)
+		}
+		// A failure before anything was sent results in an error response.
+		if err := client.Call(&raw, "stream_failingInts", 10, 5); err == nil || err.Error() != "stream failed" {
+			t.Fatalf("%s: wrong error for failed stream: %v", name, err)
+		}
+		// Streamed results are buffered in batches.
+		var res1, res2 []int
+		batch := []BatchElem{
+			{Method: "stream_ints", Args: []any{3}, Result: &res1},
+			{Method: "stream_failingInts", Args: []any{3, 2}, Result: &res2},
+		}
+		if err := client.BatchCall(batch); err != nil {
+			t.Fatalf("%s: batch failed: %v", name, err)
+		}
+		if batch[0].Error != nil || len(res1) != 3 {
+			t.Fatalf("%s: wrong batch result %v, %v", name, res1, batch[0].Error)
+		}
+		if batch[1].Error == nil || batch[1].Error.Error() != "stream failed" {
+			t.Fatalf("%s: wrong batch error: %v", name, batch[1].Error)
+		}
+		client.Close()
+	}
+}
+
+// progressWriter records the number of elements produced by stream when the first
+// write happens.
+type progressWriter struct {
+	stream   *intStream
+	progress int
+	buf      bytes.Buffer
+}
+
+func (w *progressWriter) Write(b []byte) (int, error) {
+	if w.buf.Len() == 0 {
+		w.progress = w.stream.next
+	}
+	return w.buf.Write(b)
+}
+
+// Tests that array results are written while the elements are produced, instead of
+// being encoded in memory first.
+func TestStreamResultArrayIncremental(t *testing.T) {
+	const n = 1000000
+	var (
+		msg    = &jsonrpcMessage{ID: json.RawMessage("1")}
+		stream = &intStream{n: n}
+		w      = &progressWriter{stream: stream}
+	)
+	if err := newStreamResponse(msg, ResultStream(stream), true, 0).writeTo(w); err != nil {
+		t.Fatal(err)
+	}
+	if w.progress == 0 || w.progress >= n/100 {
+		t.Fatalf("first write after %d of %d elements", w.progress, n)
+	}
+	var resp struct{ Result []int }
+	if err := json.Unmarshal(w.buf.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Result) != n || resp.Result[n-1] != n-1 {
+		t.Fatal("wrong result")
+	}
+
+	// Failures after the first write abort the response.
+	stream = &intStream{n: n, fail: n / 2}
+	w = &progressWriter{stream: stream}
+	if err := newStreamResponse(msg, ResultStream(stream), true, 0).writeTo(w); err == nil {
+		t.Fatal("no error for failed stream")
+	}
+	// The limit is applied to the encoded elements. Exceeding it before the first
+	// write results in an error response.
+	stream = &intStream{n: n}
+	w = &progressWriter{stream: stream}
+	if err := newStreamResponse(msg, ResultStream(stream), true, 1000).writeTo(w); err != nil {
+		t.Fatal(err)
+	}
+	var errResp jsonrpcMessage
+	if err := json.Unmarshal(w.buf.Bytes(), &errResp); err != nil {
+		t.Fatal(err)
+	}
+	if errResp.Error == nil || errResp.Error.Code != errcodeResponseTooLarge {
+		t.Fatalf("wrong response for oversized result: %s", w.buf.Bytes())
+	}
+}
@@ -108,6 +108,12 @@ type Notifier struct {
 	buffer       []any
 	callReturned bool
 	activated    bool
+
+	// for subscription transfers
+	token        string // transfer token, guarded by the server's subscriptionTransfers
+	transferable bool   // set once the subscription has a transfer token
+	detached     bool   // set while the subscription isn't bound to a connection
+	overflow     bool   // set if notifications were dropped while detached
 }
 
 // CreateSubscription returns a new subscription that is coupled to the
@@ -123,7 +129,7 @@ func (n *Notifier) CreateSubscription() *Subscription {
 	} else if n.callReturned {
 		panic("can't create subscription after subscribe call has returned")
 	}
-	n.sub = &Subscription{ID: n.h.idgen(), namespace: n.namespace, err: make(chan error, 1)}
+	n.sub = &Subscription{ID: n.h.idgen(), namespace: n.namespace, err: make(chan error, 1), notifier: n}
 	return n.sub
 }
 
@@ -139,12 +145,52 @@ func (n *Notifier) Notify(id ID, data any) error {
 		panic("Notify with wrong ID")
 	}
 	if n.activated {
-		return n.send(n.sub, data)
+		err := n.send(n.sub, data)
+		if err == nil || !n.transferable {
+			return err
+		}
+		// The connection is lost, keep the notification for the next one.
+		n.activated, n.detached = false, true
+	}
+	if n.detached && len(n.buffer) >= maxDetachedNotifications {
+		n.overflow = true
+		return errTransferOverflow
 	}
 	n.buffer = append(n.buffer, data)
 	return nil
 }
 
+// detach stops sending notifications, buffering them until the subscription is
+// bound to a new connection.
+func (n *Notifier) detach() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.activated, n.detached = false, true
+}
+
+// setTransferable marks the subscription as transferable.
+func (n *Notifier) setTransferable() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.transferable = true
+}
+
+// rebind binds the notifier to the given handler. Notifications are buffered until
+// the notifier is activated again. It returns false if notifications were lost while
+// the notifier was detached.
+func (n *Notifier) rebind(h *handler) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.overflow {
+		return false
+	}
+	n.h, n.activated, n.detached = h, false, false
+	return true
+}
+
 // takeSubscription returns the subscription (if one has been created). No subscription can
 // be created after this call.
 func (n *Notifier) takeSubscription() *Subscription {
@@ -161,11 +207,13 @@ func (n *Notifier) activate() error {
 	n.mu.Lock()
 	defer n.mu.Unlock()
 
-	for _, data := range n.buffer {
+	for i, data := range n.buffer {
 		if err := n.send(n.sub, data); err != nil {
+			n.buffer = n.buffer[i:]
 			return err
 		}
 	}
+	n.buffer = nil
 	n.activated = true
 	return nil
 }
@@ -188,6 +236,13 @@ type Subscription struct {
 	ID        ID
 	namespace string
 	err       chan error // closed on unsubscribe
+	notifier  *Notifier
+}
+
+// cancel ends the subscription with the given error.
+func (s *Subscription) cancel(err error) {
+	s.err <- err
+	close(s.err)
 }
 
 // Err returns a channel that is closed when the client send an unsubscribe request.
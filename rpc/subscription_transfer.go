@@ -0,0 +1,246 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+)
+
+const (
+	subscriptionTokenMethod  = "rpc_subscriptionToken"
+	resumeSubscriptionMethod = "rpc_resumeSubscription"
+
+	// maxDetachedNotifications is the number of notifications buffered for a
+	// subscription while it isn't bound to a connection. A subscription missing
+	// notifications can't be resumed.
+	maxDetachedNotifications = 10000
+)
+
+var (
+	errTransferTokenUnknown = errors.New("unknown or expired subscription transfer token")
+	errTransferOverflow     = errors.New("subscription missed notifications while detached")
+)
+
+// SetSubscriptionTransferTimeout enables the migration of subscriptions between
+// connections. A timeout of zero disables it, which is the default.
+//
+// When enabled, every subscription gets an opaque transfer token, which is returned
+// by the rpc_subscriptionToken method on the connection owning the subscription.
+// When the connection is lost, the subscription is kept alive for the given timeout,
+// buffering its notifications. Calling rpc_resumeSubscription with the token on
+// another connection binds the subscription to that connection and delivers the
+// buffered notifications. The subscription keeps its ID. Subscriptions can also be
+// moved while the original connection is still open.
+//
+// Subscription state is held in memory by the server, so tokens are only valid on the
+// server which issued them. Behind a load balancer, clients must thus reconnect to
+// the same server instance.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetSubscriptionTransferTimeout(timeout time.Duration) {
+	if timeout == 0 {
+		s.subTransfers = nil
+		return
+	}
+	s.subTransfers = &subscriptionTransfers{timeout: timeout, subs: make(map[string]*transferableSub)}
+}
+
+// subscriptionTransfers tracks the transferable subscriptions of a server.
+type subscriptionTransfers struct {
+	timeout time.Duration
+
+	mu      sync.Mutex
+	subs    map[string]*transferableSub // by transfer token
+	stopped bool
+}
+
+type transferableSub struct {
+	n     *Notifier
+	owner *handler    // handler of the connection the subscription is bound to, nil if detached
+	timer *time.Timer // expiry timer of a detached subscription
+}
+
+// attach binds the subscription of n to the given handler, issuing a transfer token
+// if it doesn't have one yet.
+func (st *subscriptionTransfers) attach(h *handler, n *Notifier) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if n.token == "" {
+		n.token = newTransferToken()
+		st.subs[n.token] = &transferableSub{n: n}
+		n.setTransferable()
+	}
+	if ts := st.subs[n.token]; ts != nil {
+		ts.owner = h
+	}
+}
+
+// detach is called when the connection of h is closed. The subscription of n is kept
+// until it is resumed or the timeout expires.
+func (st *subscriptionTransfers) detach(h *handler, n *Notifier, err error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if n.token == "" {
+		n.sub.cancel(err) // closed before the subscription was registered
+		return
+	}
+	ts := st.subs[n.token]
+	if ts == nil || ts.owner != h {
+		return // moved to another connection in the meantime
+	}
+	if st.stopped {
+		delete(st.subs, n.token)
+		n.sub.cancel(err)
+		return
+	}
+	ts.owner = nil
+	n.detach()
+	ts.timer = time.AfterFunc(st.timeout, func() {
+		st.mu.Lock()
+		defer st.mu.Unlock()
+		if st.subs[n.token] == ts && ts.owner == nil {
+			delete(st.subs, n.token)
+			n.sub.cancel(err)
+		}
+	})
+}
+
+// take binds the subscription with the given token to h. If the subscription is still
+// bound to another connection, it is removed from that connection.
+func (st *subscriptionTransfers) take(h *handler, token string) (*Notifier, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	ts := st.subs[token]
+	if ts == nil {
+		return nil, errTransferTokenUnknown
+	}
+	if ts.owner != nil {
+		ts.owner.subLock.Lock()
+		delete(ts.owner.serverSubs, ts.n.sub.ID)
+		ts.owner.subLock.Unlock()
+	}
+	if ts.timer != nil {
+		ts.timer.Stop()
+		ts.timer = nil
+	}
+	if !ts.n.rebind(h) {
+		delete(st.subs, token)
+		ts.n.sub.cancel(errTransferOverflow)
+		return nil, errTransferOverflow
+	}
+	ts.owner = h
+	return ts.n, nil
+}
+
+// remove forgets the subscription of n.
+func (st *subscriptionTransfers) remove(n *Notifier) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	delete(st.subs, n.token)
+}
+
+// stop cancels all detached subscriptions. Subscriptions detached after this call are
+// canceled immediately.
+func (st *subscriptionTransfers) stop(err error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.stopped = true
+	for token, ts := range st.subs {
+		if ts.owner == nil {
+			ts.timer.Stop()
+			delete(st.subs, token)
+			ts.n.sub.cancel(err)
+		}
+	}
+}
+
+func newTransferToken() string {
+	token := make([]byte, 32)
+	crand.Read(token)
+	return "0x" + hex.EncodeToString(token)
+}
+
+// handleSubscriptionToken returns the transfer token of a subscription on this
+// connection.
+func (h *handler) handleSubscriptionToken(msg *jsonrpcMessage) *jsonrpcMessage {
+	args, err := parsePositionalArguments(msg.Params, []reflect.Type{reflect.TypeOf(ID(""))})
+	if err != nil {
+		return msg.errorResponse(&invalidParamsError{err.Error()})
+	}
+	h.subLock.Lock()
+	sub := h.serverSubs[args[0].Interface().(ID)]
+	h.subLock.Unlock()
+	if sub == nil {
+		return msg.errorResponse(ErrSubscriptionNotFound)
+	}
+	h.transfers.mu.Lock()
+	token := sub.notifier.token
+	h.transfers.mu.Unlock()
+	return msg.response(token)
+}
+
+// handleResumeSubscription binds the subscription with the given transfer token to
+// this connection. Like for *_subscribe calls, the notifier is activated after the
+// response is sent.
+func (h *handler) handleResumeSubscription(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
+	if !h.allowSubscribe {
+		return msg.errorResponse(ErrNotificationsUnsupported)
+	}
+	args, err := parsePositionalArguments(msg.Params, []reflect.Type{stringType})
+	if err != nil {
+		return msg.errorResponse(&invalidParamsError{err.Error()})
+	}
+	n, err := h.transfers.take(h, args[0].String())
+	if err != nil {
+		return msg.errorResponse(err)
+	}
+	cp.notifiers = append(cp.notifiers, n)
+	return msg.response(n.sub.ID)
+}
+
+// TransferToken returns the token for resuming the subscription on another connection
+// with Client.ResumeSubscription. The server must have subscription transfers enabled,
+// see Server.SetSubscriptionTransferTimeout.
+func (sub *ClientSubscription) TransferToken(ctx context.Context) (string, error) {
+	var token string
+	err := sub.client.CallContext(ctx, &token, subscriptionTokenMethod, sub.subid)
+	return token, err
+}
+
+// ResumeSubscription binds the subscription with the given transfer token to the
+// client, delivering its notifications on channel. The namespace and channel type
+// must match those of the original subscription. Notifications sent by the server
+// while the subscription had no connection are delivered first.
+func (c *Client) ResumeSubscription(ctx context.Context, namespace string, channel any, token string) (*ClientSubscription, error) {
+	msg, err := c.newMessage(resumeSubscriptionMethod, token)
+	if err != nil {
+		return nil, err
+	}
+	return c.subscribe(ctx, namespace, channel, msg)
+}
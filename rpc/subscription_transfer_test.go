@@ -0,0 +1,206 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// transferTestService has a subscription forwarding the values sent on feed.
+type transferTestService struct {
+	feed  chan int
+	ended chan struct{}
+}
+
+func newTransferTestServer(timeout time.Duration) (*Server, *transferTestService) {
+	server := NewServer()
+	server.SetSubscriptionTransferTimeout(timeout)
+	service := &transferTestService{feed: make(chan int), ended: make(chan struct{}, 1)}
+	if err := server.RegisterName("tt", service); err != nil {
+		panic(err)
+	}
+	return server, service
+}
+
+func (s *transferTestService) Events(ctx context.Context) (*Subscription, error) {
+	notifier, _ := NotifierFromContext(ctx)
+	sub := notifier.CreateSubscription()
+	go func() {
+		defer func() { s.ended <- struct{}{} }()
+		for {
+			select {
+			case v := <-s.feed:
+				notifier.Notify(sub.ID, v)
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+func expectNotifications(t *testing.T, ch chan int, values ...int) {
+	t.Helper()
+	for _, want := range values {
+		select {
+		case v := <-ch:
+			if v != want {
+				t.Fatalf("wrong notification %d, want %d", v, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("notification %d not received", want)
+		}
+	}
+}
+
+// waitDetached waits until the subscription with the given token has no connection.
+func waitDetached(t *testing.T, server *Server, token string) {
+	t.Helper()
+	for i := 0; i < 200; i++ {
+		server.subTransfers.mu.Lock()
+		ts := server.subTransfers.subs[token]
+		detached := ts != nil && ts.owner == nil
+		server.subTransfers.mu.Unlock()
+		if detached {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("subscription not detached")
+}
+
+func TestSubscriptionTransfer(t *testing.T) {
+	t.Parallel()
+
+	server, service := newTransferTestServer(time.Minute)
+	defer server.Stop()
+	ctx := context.Background()
+
+	client1 := DialInProc(server)
+	ch1 := make(chan int, 10)
+	sub1, err := client1.Subscribe(ctx, "tt", ch1, "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.feed <- 1
+	expectNotifications(t, ch1, 1)
+	token, err := sub1.TransferToken(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Drop the connection, notifications are buffered.
+	client1.Close()
+	waitDetached(t, server, token)
+	service.feed <- 2
+	service.feed <- 3
+
+	// Resume on a new connection.
+	client2 := DialInProc(server)
+	defer client2.Close()
+	ch2 := make(chan int, 10)
+	sub2, err := client2.ResumeSubscription(ctx, "tt", ch2, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub2.subid != sub1.subid {
+		t.Fatalf("resumed subscription has ID %s, want %s", sub2.subid, sub1.subid)
+	}
+	service.feed <- 4
+	expectNotifications(t, ch2, 2, 3, 4)
+
+	// Move the subscription while the connection is still open.
+	client3 := DialInProc(server)
+	defer client3.Close()
+	ch3 := make(chan int, 10)
+	sub3, err := client3.ResumeSubscription(ctx, "tt", ch3, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.feed <- 5
+	expectNotifications(t, ch3, 5)
+	if len(ch2) != 0 {
+		t.Fatal("notification delivered to previous connection")
+	}
+
+	// Unsubscribing ends the subscription and invalidates the token.
+	sub3.Unsubscribe()
+	select {
+	case <-service.ended:
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscription not ended after unsubscribe")
+	}
+	if _, err := client2.ResumeSubscription(ctx, "tt", make(chan int), token); err == nil {
+		t.Fatal("resumed subscription after unsubscribe")
+	}
+}
+
+func TestSubscriptionTransferTimeout(t *testing.T) {
+	t.Parallel()
+
+	server, service := newTransferTestServer(50 * time.Millisecond)
+	defer server.Stop()
+	ctx := context.Background()
+
+	client := DialInProc(server)
+	sub, err := client.Subscribe(ctx, "tt", make(chan int), "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := sub.TransferToken(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Close()
+	select {
+	case <-service.ended:
+	case <-time.After(2 * time.Second):
+		t.Fatal("detached subscription did not expire")
+	}
+	client = DialInProc(server)
+	defer client.Close()
+	if _, err := client.ResumeSubscription(ctx, "tt", make(chan int), token); err == nil || err.Error() != errTransferTokenUnknown.Error() {
+		t.Fatalf("wrong error resuming expired subscription: %v", err)
+	}
+}
+
+// Tests that subscriptions are canceled when the connection is lost if transfers
+// are not enabled.
+func TestSubscriptionTransferDisabled(t *testing.T) {
+	t.Parallel()
+
+	server, service := newTransferTestServer(0)
+	defer server.Stop()
+	ctx := context.Background()
+
+	client := DialInProc(server)
+	sub, err := client.Subscribe(ctx, "tt", make(chan int), "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sub.TransferToken(ctx); err == nil {
+		t.Fatal("got transfer token with transfers disabled")
+	}
+	client.Close()
+	select {
+	case <-service.ended:
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscription not ended after connection loss")
+	}
+}
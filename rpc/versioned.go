@@ -0,0 +1,120 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrNotModified is returned by Client.CallVersioned when the result of the call
+// matches the given version.
+var ErrNotModified = errors.New("result not modified")
+
+// VersionedResult can be returned by methods to support conditional requests for
+// rarely changing results.
+//
+// Clients opt in by adding an "ifNoneMatch" member holding the version of their
+// cached result to the request object, or an empty string if they don't have one.
+// The response to such a request carries the version of the result in its "version"
+// member. If the version equals the one sent by the client, the result is omitted
+// and the response has "notModified" set:
+//
+//	--> {"jsonrpc":"2.0","id":1,"method":"test_config","ifNoneMatch":"0x2a"}
+//	<-- {"jsonrpc":"2.0","id":1,"result":null,"version":"0x2a","notModified":true}
+//
+// Requests without the "ifNoneMatch" member receive the plain result.
+type VersionedResult struct {
+	// Version identifies the result. If empty, the version is derived from the
+	// hash of the encoded result.
+	Version string
+
+	Result any
+}
+
+// MarshalJSON encodes the plain result.
+func (v VersionedResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.Result)
+}
+
+// versionedResponse creates the response to a call which returned a versioned result.
+func (msg *jsonrpcMessage) versionedResponse(v VersionedResult) *jsonrpcMessage {
+	if msg.IfNoneMatch == nil {
+		return msg.response(v.Result)
+	}
+	if v.Version != "" && v.Version == *msg.IfNoneMatch {
+		return msg.notModifiedResponse(v.Version)
+	}
+	enc, err := json.Marshal(v.Result)
+	if err != nil {
+		return msg.errorResponse(&internalServerError{errcodeMarshalError, err.Error()})
+	}
+	version := v.Version
+	if version == "" {
+		hash := sha256.Sum256(enc)
+		version = "0x" + hex.EncodeToString(hash[:16])
+		if version == *msg.IfNoneMatch {
+			return msg.notModifiedResponse(version)
+		}
+	}
+	return &jsonrpcMessage{Version: vsn, ID: msg.ID, Result: enc, ResultVersion: version}
+}
+
+func (msg *jsonrpcMessage) notModifiedResponse(version string) *jsonrpcMessage {
+	return &jsonrpcMessage{Version: vsn, ID: msg.ID, Result: null, ResultVersion: version, NotModified: true}
+}
+
+// CallVersioned performs a conditional JSON-RPC call with the given arguments. The
+// version is the one returned for the cached result held by the caller, or empty if
+// there is no cached result.
+//
+// If the result has not changed since the given version, ErrNotModified is returned
+// and result is left untouched. Otherwise, the result is unmarshaled into result and
+// its new version is returned. The version is empty if the method doesn't support
+// conditional requests.
+func (c *Client) CallVersioned(ctx context.Context, result any, version string, method string, args ...any) (string, error) {
+	if result != nil && reflect.TypeOf(result).Kind() != reflect.Ptr {
+		return "", fmt.Errorf("call result parameter must be pointer or nil interface: %v", result)
+	}
+	msg, err := c.newMessage(method, args...)
+	if err != nil {
+		return "", err
+	}
+	msg.IfNoneMatch = &version
+
+	resp, err := c.sendCall(ctx, msg)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case resp.Error != nil:
+		return "", resp.Error
+	case resp.NotModified:
+		return resp.ResultVersion, ErrNotModified
+	case len(resp.Result) == 0:
+		return "", ErrNoResult
+	case result == nil:
+		return resp.ResultVersion, nil
+	default:
+		return resp.ResultVersion, json.Unmarshal(resp.Result, result)
+	}
+}
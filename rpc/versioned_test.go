@@ -0,0 +1,118 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+type versionedTestService struct{}
+
+func (s *versionedTestService) Fixed() VersionedResult {
+	return VersionedResult{Version: "v1", Result: "fixed"}
+}
+
+func (s *versionedTestService) Hashed(str string) VersionedResult {
+	return VersionedResult{Result: str}
+}
+
+func TestCallVersioned(t *testing.T) {
+	t.Parallel()
+
+	run := func(t *testing.T, client *Client) {
+		ctx := context.Background()
+
+		// Plain calls get the plain result.
+		var result string
+		if err := client.Call(&result, "ver_fixed"); err != nil || result != "fixed" {
+			t.Fatalf("wrong plain result %q, err %v", result, err)
+		}
+		// Explicit version.
+		result = ""
+		version, err := client.CallVersioned(ctx, &result, "", "ver_fixed")
+		if err != nil || result != "fixed" || version != "v1" {
+			t.Fatalf("wrong result %q, version %q, err %v", result, version, err)
+		}
+		result = ""
+		version, err = client.CallVersioned(ctx, &result, "v1", "ver_fixed")
+		if err != ErrNotModified || result != "" || version != "v1" {
+			t.Fatalf("wrong result for unmodified call %q, version %q, err %v", result, version, err)
+		}
+		// Derived version.
+		versionA, err := client.CallVersioned(ctx, &result, "", "ver_hashed", "a")
+		if err != nil || result != "a" || versionA == "" {
+			t.Fatalf("wrong result %q, version %q, err %v", result, versionA, err)
+		}
+		if _, err := client.CallVersioned(ctx, &result, versionA, "ver_hashed", "a"); err != ErrNotModified {
+			t.Fatalf("wrong error for unmodified call: %v", err)
+		}
+		versionB, err := client.CallVersioned(ctx, &result, versionA, "ver_hashed", "b")
+		if err != nil || result != "b" || versionB == versionA {
+			t.Fatalf("wrong result for modified call %q, version %q, err %v", result, versionB, err)
+		}
+		// Methods without versioned result ignore the version.
+		version, err = client.CallVersioned(ctx, &result, "v1", "test_repeat", "x", 2)
+		if err != nil || result != "xx" || version != "" {
+			t.Fatalf("wrong result for unversioned method %q, version %q, err %v", result, version, err)
+		}
+	}
+	newServer := func() *Server {
+		server := newTestServer()
+		if err := server.RegisterName("ver", new(versionedTestService)); err != nil {
+			t.Fatal(err)
+		}
+		return server
+	}
+
+	t.Run("inproc", func(t *testing.T) {
+		server := newServer()
+		defer server.Stop()
+		client := DialInProc(server)
+		defer client.Close()
+		run(t, client)
+	})
+	t.Run("http", func(t *testing.T) {
+		server := newServer()
+		defer server.Stop()
+		httpsrv := httptest.NewServer(server)
+		defer httpsrv.Close()
+		client, err := DialHTTP(httpsrv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer client.Close()
+		run(t, client)
+	})
+	t.Run("middleware", func(t *testing.T) {
+		server := newServer()
+		defer server.Stop()
+		server.Use(func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) *Response {
+				if req.Method == "test_blocked" {
+					return &Response{Error: errors.New("blocked")}
+				}
+				return next(ctx, req)
+			}
+		})
+		client := DialInProc(server)
+		defer client.Close()
+		run(t, client)
+	})
+}
@@ -17,8 +17,11 @@
 package rpc
 
 import (
+	"compress/flate"
 	"context"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -39,20 +42,71 @@ const (
 	wsPingWriteTimeout = 5 * time.Second
 	wsPongTimeout      = 30 * time.Second
 	wsDefaultReadLimit = 32 * 1024 * 1024
+
+	wsDefaultCompressionLevel = flate.BestSpeed
 )
 
 var wsBufferPool = new(sync.Pool)
 
+// WebsocketConfig contains settings of WebSocket connections. Zero values select the
+// default settings.
+type WebsocketConfig struct {
+	// MaxMessageSize is the maximum size of a message read from the peer. Connections
+	// sending larger messages are closed.
+	MaxMessageSize int64
+
+	// PingInterval is the time after which a ping is sent on idle connections to
+	// keep them alive.
+	PingInterval time.Duration
+
+	// WriteTimeout is the maximum time spent writing a single message.
+	WriteTimeout time.Duration
+
+	// Compression enables the permessage-deflate extension (RFC 7692) for clients
+	// that offer it. Connections of other clients remain uncompressed.
+	Compression bool
+
+	// CompressionLevel is the flate compression level used for outgoing messages, see
+	// package compress/flate. Zero and invalid levels select flate.BestSpeed.
+	CompressionLevel int
+}
+
+// withDefaults returns a copy of the config with zero values replaced by defaults.
+func (cfg WebsocketConfig) withDefaults() WebsocketConfig {
+	if cfg.MaxMessageSize == 0 {
+		cfg.MaxMessageSize = wsDefaultReadLimit
+	}
+	if cfg.PingInterval == 0 {
+		cfg.PingInterval = wsPingInterval
+	}
+	if cfg.WriteTimeout == 0 {
+		cfg.WriteTimeout = defaultWriteTimeout
+	}
+	if cfg.CompressionLevel == 0 || cfg.CompressionLevel < flate.HuffmanOnly || cfg.CompressionLevel > flate.BestCompression {
+		cfg.CompressionLevel = wsDefaultCompressionLevel
+	}
+	return cfg
+}
+
+// SetWebsocketConfig sets the settings of connections served by WebsocketHandler.
+//
+// This method should be called before calling WebsocketHandler.
+func (s *Server) SetWebsocketConfig(config WebsocketConfig) {
+	s.wsConfig = config
+}
+
 // WebsocketHandler returns a handler that serves JSON-RPC to WebSocket connections.
 //
 // allowedOrigins should be a comma-separated list of allowed origin URLs.
 // To allow connections with any origin, pass "*".
 func (s *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
+	config := s.wsConfig.withDefaults()
 	var upgrader = websocket.Upgrader{
-		ReadBufferSize:  wsReadBuffer,
-		WriteBufferSize: wsWriteBuffer,
-		WriteBufferPool: wsBufferPool,
-		CheckOrigin:     wsHandshakeValidator(allowedOrigins),
+		ReadBufferSize:    wsReadBuffer,
+		WriteBufferSize:   wsWriteBuffer,
+		WriteBufferPool:   wsBufferPool,
+		CheckOrigin:       wsHandshakeValidator(allowedOrigins),
+		EnableCompression: config.Compression,
 	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		conn, err := upgrader.Upgrade(w, r, nil)
@@ -60,7 +114,9 @@ func (s *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			log.Debug("WebSocket upgrade failed", "err", err)
 			return
 		}
-		codec := newWebsocketCodec(conn, r.Host, r.Header, wsDefaultReadLimit)
+		// The level is only used if compression was negotiated.
+		conn.SetCompressionLevel(config.CompressionLevel)
+		codec := newWebsocketCodec(conn, r.Host, r.Header, config)
 		s.ServeCodec(codec, 0)
 	})
 }
@@ -259,7 +315,9 @@ func newClientTransportWS(endpoint string, cfg *clientConfig) (reconnectFunc, er
 		if cfg.wsMessageSizeLimit != nil && *cfg.wsMessageSizeLimit >= 0 {
 			messageSizeLimit = *cfg.wsMessageSizeLimit
 		}
-		return newWebsocketCodec(conn, dialURL, header, messageSizeLimit), nil
+		config := WebsocketConfig{}.withDefaults()
+		config.MaxMessageSize = messageSizeLimit // zero disables the limit on the client side
+		return newWebsocketCodec(conn, dialURL, header, config), nil
 	}
 	return connect, nil
 }
@@ -283,22 +341,47 @@ func wsClientHeaders(endpoint, origin string) (string, http.Header, error) {
 
 type websocketCodec struct {
 	*jsonCodec
-	conn *websocket.Conn
-	info PeerInfo
+	conn   *websocket.Conn
+	info   PeerInfo
+	config WebsocketConfig
 
 	wg           sync.WaitGroup
 	pingReset    chan struct{}
 	pongReceived chan struct{}
 }
 
-func newWebsocketCodec(conn *websocket.Conn, host string, req http.Header, readLimit int64) ServerCodec {
-	conn.SetReadLimit(readLimit)
+func newWebsocketCodec(conn *websocket.Conn, host string, req http.Header, config WebsocketConfig) ServerCodec {
+	conn.SetReadLimit(config.MaxMessageSize)
+	out := new(countingWriter)
 	encode := func(v interface{}, isErrorResponse bool) error {
-		return conn.WriteJSON(v)
+		// Streamed results are sent as a single message, which is split into
+		// multiple frames as the write buffer fills up.
+		s, stream := v.(*streamResponse)
+		w, err := conn.NextWriter(websocket.TextMessage)
+		if err != nil {
+			if stream {
+				s.close()
+			}
+			return err
+		}
+		out.w = w
+		if stream {
+			err = s.writeTo(out)
+		} else {
+			err = json.NewEncoder(out).Encode(v)
+		}
+		if err != nil {
+			w.Close()
+			return err
+		}
+		return w.Close()
 	}
+	codec := NewFuncCodec(conn, encode, conn.ReadJSON).(*jsonCodec)
+	codec.out = out
 	wc := &websocketCodec{
-		jsonCodec:    NewFuncCodec(conn, encode, conn.ReadJSON).(*jsonCodec),
+		jsonCodec:    codec,
 		conn:         conn,
+		config:       config,
 		pingReset:    make(chan struct{}, 1),
 		pongReceived: make(chan struct{}),
 		info: PeerInfo{
@@ -332,7 +415,22 @@ func (wc *websocketCodec) peerInfo() PeerInfo {
 	return wc.info
 }
 
+func (wc *websocketCodec) readBatch() ([]*jsonrpcMessage, bool, error) {
+	msgs, batch, err := wc.jsonCodec.readBatch()
+	if errors.Is(err, websocket.ErrReadLimit) {
+		// The websocket library closes the connection with status 1009 (message too
+		// big) in this case. Make sure the reason doesn't go unnoticed.
+		log.Warn("WebSocket message exceeds size limit", "remote", wc.info.RemoteAddr, "limit", wc.config.MaxMessageSize)
+	}
+	return msgs, batch, err
+}
+
 func (wc *websocketCodec) writeJSON(ctx context.Context, v interface{}, isError bool) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, wc.config.WriteTimeout)
+		defer cancel()
+	}
 	err := wc.jsonCodec.writeJSON(ctx, v, isError)
 	if err == nil {
 		// Notify pingLoop to delay the next idle ping.
@@ -346,7 +444,7 @@ func (wc *websocketCodec) writeJSON(ctx context.Context, v interface{}, isError
 
 // pingLoop sends periodic ping frames when the connection is idle.
 func (wc *websocketCodec) pingLoop() {
-	var pingTimer = time.NewTimer(wsPingInterval)
+	var pingTimer = time.NewTimer(wc.config.PingInterval)
 	defer wc.wg.Done()
 	defer pingTimer.Stop()
 
@@ -359,7 +457,7 @@ func (wc *websocketCodec) pingLoop() {
 			if !pingTimer.Stop() {
 				<-pingTimer.C
 			}
-			pingTimer.Reset(wsPingInterval)
+			pingTimer.Reset(wc.config.PingInterval)
 
 		case <-pingTimer.C:
 			wc.jsonCodec.encMu.Lock()
@@ -367,7 +465,7 @@ func (wc *websocketCodec) pingLoop() {
 			wc.conn.WriteMessage(websocket.PingMessage, nil)
 			wc.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
 			wc.jsonCodec.encMu.Unlock()
-			pingTimer.Reset(wsPingInterval)
+			pingTimer.Reset(wc.config.PingInterval)
 
 		case <-wc.pongReceived:
 			wc.conn.SetReadDeadline(time.Time{})
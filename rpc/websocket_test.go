@@ -17,12 +17,17 @@
 package rpc
 
 import (
+	"compress/flate"
 	"context"
+	"encoding/binary"
 	"errors"
+	"fmt"
+	"hash/fnv"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -391,3 +396,209 @@ func wsPingTestHandler(t *testing.T, conn *websocket.Conn, shutdown, sendPing <-
 		}
 	}
 }
+
+// This test checks that the server applies the configured message size limit and
+// ping interval.
+func TestWebsocketServerConfig(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestServer()
+	srv.SetWebsocketConfig(WebsocketConfig{
+		MaxMessageSize: 1024,
+		PingInterval:   100 * time.Millisecond,
+	})
+	var (
+		httpsrv = httptest.NewServer(srv.WebsocketHandler([]string{"*"}))
+		wsURL   = "ws:" + strings.TrimPrefix(httpsrv.URL, "http:")
+	)
+	defer srv.Stop()
+	defer httpsrv.Close()
+
+	// Idle connections receive pings.
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("can't dial: %v", err)
+	}
+	pings := make(chan struct{}, 1)
+	conn.SetPingHandler(func(string) error {
+		select {
+		case pings <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	select {
+	case <-pings:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no ping received")
+	}
+	conn.Close()
+
+	// Messages above the limit close the connection with an error.
+	client, err := DialWebsocket(context.Background(), wsURL, "")
+	if err != nil {
+		t.Fatalf("can't dial: %v", err)
+	}
+	defer client.Close()
+	var result echoResult
+	if err := client.Call(&result, "test_echo", "x", 1); err != nil {
+		t.Fatalf("small call failed: %v", err)
+	}
+	err = client.Call(&result, "test_echo", strings.Repeat("x", 2048), 1)
+	if !websocket.IsCloseError(err, websocket.CloseMessageTooBig) {
+		t.Fatalf("wrong error for oversized message: %v", err)
+	}
+}
+
+func TestWebsocketCompressionNegotiation(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		server, client, want bool
+	}{
+		{server: false, client: false, want: false},
+		{server: false, client: true, want: false},
+		{server: true, client: false, want: false},
+		{server: true, client: true, want: true},
+	} {
+		srv := newTestServer()
+		srv.SetWebsocketConfig(WebsocketConfig{Compression: test.server, CompressionLevel: flate.BestCompression})
+		httpsrv := httptest.NewServer(srv.WebsocketHandler([]string{"*"}))
+		wsURL := "ws:" + strings.TrimPrefix(httpsrv.URL, "http:")
+
+		dialer := websocket.Dialer{EnableCompression: test.client}
+		conn, resp, err := dialer.Dial(wsURL, nil)
+		if err != nil {
+			t.Fatalf("can't dial: %v", err)
+		}
+		conn.Close()
+		ext := resp.Header.Get("Sec-Websocket-Extensions")
+		if got := strings.Contains(ext, "permessage-deflate"); got != test.want {
+			t.Errorf("server %t, client %t: wrong negotiated extensions %q", test.server, test.client, ext)
+		}
+
+		// Calls work with and without compression.
+		client, err := DialWebsocketWithDialer(context.Background(), wsURL, "", dialer)
+		if err != nil {
+			t.Fatalf("can't dial: %v", err)
+		}
+		var result echoResult
+		arg := strings.Repeat("x", 4096)
+		if err := client.Call(&result, "test_echo", arg, 1); err != nil {
+			t.Errorf("server %t, client %t: call failed: %v", test.server, test.client, err)
+		} else if result.String != arg {
+			t.Errorf("server %t, client %t: wrong result", test.server, test.client)
+		}
+		client.Close()
+		httpsrv.Close()
+		srv.Stop()
+	}
+}
+
+// headsTestService sends notifications resembling the newHeads subscription.
+type headsTestService struct{}
+
+func (s *headsTestService) NewHeads(ctx context.Context, n int) (*Subscription, error) {
+	notifier, supported := NotifierFromContext(ctx)
+	if !supported {
+		return nil, ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		for i := 0; i < n; i++ {
+			notifier.Notify(sub.ID, fakeHeader(uint64(i)))
+		}
+	}()
+	return sub, nil
+}
+
+func fakeHeader(number uint64) map[string]string {
+	hash := func(tag uint64) string {
+		h := fnv.New64a()
+		binary.Write(h, binary.BigEndian, [2]uint64{number, tag})
+		return "0x" + strings.Repeat(fmt.Sprintf("%016x", h.Sum64()), 4)
+	}
+	return map[string]string{
+		"parentHash":       hash(0),
+		"sha3Uncles":       "0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347",
+		"miner":            "0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5",
+		"stateRoot":        hash(1),
+		"transactionsRoot": hash(2),
+		"receiptsRoot":     hash(3),
+		"logsBloom":        "0x" + strings.Repeat("00", 256),
+		"difficulty":       "0x0",
+		"number":           fmt.Sprintf("%#x", number),
+		"gasLimit":         "0x1c9c380",
+		"gasUsed":          "0xa410b1",
+		"timestamp":        fmt.Sprintf("%#x", 1700000000+12*number),
+		"extraData":        "0x6265617665726275696c642e6f7267",
+		"mixHash":          hash(4),
+		"nonce":            "0x0000000000000000",
+		"baseFeePerGas":    "0x4a817c800",
+		"hash":             hash(5),
+	}
+}
+
+// countingConn counts the bytes read from a connection.
+type countingConn struct {
+	net.Conn
+	n *atomic.Int64
+}
+
+func (c countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// This benchmark reports the number of bytes received per newHeads notification,
+// with and without compression.
+func BenchmarkWebsocketNewHeads(b *testing.B) {
+	for _, compression := range []bool{false, true} {
+		b.Run(fmt.Sprintf("compression=%t", compression), func(b *testing.B) {
+			srv := NewServer()
+			defer srv.Stop()
+			srv.RegisterName("heads", new(headsTestService))
+			srv.SetWebsocketConfig(WebsocketConfig{Compression: compression})
+			httpsrv := httptest.NewServer(srv.WebsocketHandler([]string{"*"}))
+			defer httpsrv.Close()
+			wsURL := "ws:" + strings.TrimPrefix(httpsrv.URL, "http:")
+
+			var received atomic.Int64
+			dialer := websocket.Dialer{
+				EnableCompression: true,
+				NetDial: func(network, addr string) (net.Conn, error) {
+					conn, err := net.Dial(network, addr)
+					return countingConn{conn, &received}, err
+				},
+			}
+			client, err := DialWebsocketWithDialer(context.Background(), wsURL, "", dialer)
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer client.Close()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			received.Store(0)
+			ch := make(chan map[string]string, 100)
+			sub, err := client.Subscribe(context.Background(), "heads", ch, "newHeads", b.N)
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer sub.Unsubscribe()
+			for i := 0; i < b.N; i++ {
+				<-ch
+			}
+			b.StopTimer()
+			b.ReportMetric(float64(received.Load())/float64(b.N), "bytes/op")
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// listHeadSize is the number of leading list items whose keys don't sort in list
+// order: the key of item zero sorts after the keys of items 1 to 0x7f.
+const listHeadSize = 0x80
+
+// ListRootBuilder computes the root hash of a list of transactions, receipts or
+// withdrawals, as computed by types.DeriveSha, while the items are added one by one
+// in list order. The root of the items added so far is available at any time.
+//
+// Only the first 128 items are buffered. Afterwards, items are inserted into a stack
+// trie as they arrive, so the memory used by the builder doesn't grow with the list.
+type ListRootBuilder struct {
+	head   [][]byte   // items 0..0x7f, until the item at 0x80 is added
+	st     *StackTrie // receives the items once the head is complete
+	count  uint64
+	keyBuf []byte
+}
+
+// NewListRootBuilder creates a builder for an empty list.
+func NewListRootBuilder() *ListRootBuilder {
+	return &ListRootBuilder{st: NewStackTrie(nil)}
+}
+
+// Add appends an item to the list. The value must be the encoding produced by the
+// EncodeIndex method of the list, i.e. the canonical binary encoding of a
+// transaction, receipt or withdrawal. The value is copied, so the caller may reuse
+// the slice after Add returns.
+func (b *ListRootBuilder) Add(value []byte) {
+	value = common.CopyBytes(value)
+	switch {
+	case b.count < listHeadSize:
+		b.head = append(b.head, value)
+	case b.count == listHeadSize:
+		insertListHead(b.st, b.head, &b.keyBuf)
+		b.head = nil
+		fallthrough
+	default:
+		b.keyBuf = rlp.AppendUint64(b.keyBuf[:0], b.count)
+		b.st.Update(b.keyBuf, value)
+	}
+	b.count++
+}
+
+// Len returns the number of items added so far.
+func (b *ListRootBuilder) Len() int {
+	return int(b.count)
+}
+
+// Hash returns the root hash of the items added so far. More items can be added
+// afterwards.
+func (b *ListRootBuilder) Hash() common.Hash {
+	if b.head != nil {
+		// The head isn't in the stack trie yet. It's small, so just hash it
+		// separately.
+		st := NewStackTrie(nil)
+		insertListHead(st, b.head, &b.keyBuf)
+		return st.Hash()
+	}
+	return b.st.peekHash()
+}
+
+// insertListHead inserts the first items of a list into st, in key order.
+func insertListHead(st *StackTrie, head [][]byte, keyBuf *[]byte) {
+	for i := 1; i < len(head); i++ {
+		*keyBuf = rlp.AppendUint64((*keyBuf)[:0], uint64(i))
+		st.Update(*keyBuf, head[i])
+	}
+	if len(head) > 0 {
+		*keyBuf = rlp.AppendUint64((*keyBuf)[:0], 0)
+		st.Update(*keyBuf, head[0])
+	}
+}
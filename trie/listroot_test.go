@@ -0,0 +1,52 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TestListRootBuilder tests that the running root of the builder matches the root
+// computed by DeriveSha after every item.
+func TestListRootBuilder(t *testing.T) {
+	var (
+		builder = NewListRootBuilder()
+		list    types.Withdrawals
+		buf     bytes.Buffer
+	)
+	if root := builder.Hash(); root != types.EmptyRootHash {
+		t.Fatalf("wrong root of empty list %x", root)
+	}
+	for i := 0; i < 300; i++ {
+		list = append(list, &types.Withdrawal{Index: uint64(i), Validator: uint64(i), Address: common.Address{byte(i)}, Amount: uint64(i) * 1000})
+		buf.Reset()
+		list.EncodeIndex(i, &buf)
+		builder.Add(buf.Bytes())
+
+		if builder.Len() != len(list) {
+			t.Fatalf("%d items: wrong length %d", len(list), builder.Len())
+		}
+		exp := types.DeriveSha(list, NewStackTrie(nil))
+		if got := builder.Hash(); got != exp {
+			t.Fatalf("%d items: got %x exp %x", len(list), got, exp)
+		}
+	}
+}
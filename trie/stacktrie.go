@@ -395,3 +395,29 @@ func (t *StackTrie) Hash() common.Hash {
 	t.hash(n, nil)
 	return common.BytesToHash(n.val)
 }
+
+// peekHash returns the root hash of the keys inserted so far. Unlike Hash, it doesn't
+// modify the trie, so more keys can be inserted afterwards. Only the nodes which are
+// not hashed yet, i.e. the right boundary of the trie, are copied and hashed.
+func (t *StackTrie) peekHash() common.Hash {
+	n := t.root.copy()
+	tmp := &StackTrie{root: n, h: t.h}
+	tmp.hash(n, nil)
+	hash := common.BytesToHash(n.val)
+	stPool.Put(n.reset())
+	return hash
+}
+
+// copy returns a deep copy of the node and its children. Values are shared.
+func (n *stNode) copy() *stNode {
+	cpy := stPool.Get().(*stNode)
+	cpy.typ = n.typ
+	cpy.key = append(cpy.key, n.key...)
+	cpy.val = n.val
+	for i, child := range n.children {
+		if child != nil {
+			cpy.children[i] = child.copy()
+		}
+	}
+	return cpy
+}